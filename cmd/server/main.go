@@ -16,12 +16,19 @@ import (
 	"os"
 	"os/signal"
 	"time"
+	// Embed the time zone database for report time zones on hosts without one
+	_ "time/tzdata"
 
 	"github.com/tyha2404/nexo-app-api/internal/config"
 	"github.com/tyha2404/nexo-app-api/internal/db"
 	"github.com/tyha2404/nexo-app-api/internal/logger"
+	"github.com/tyha2404/nexo-app-api/internal/notify"
+	"github.com/tyha2404/nexo-app-api/internal/repository"
 	"github.com/tyha2404/nexo-app-api/internal/router"
+	"github.com/tyha2404/nexo-app-api/internal/service"
+	"github.com/tyha2404/nexo-app-api/internal/storage"
 	"github.com/tyha2404/nexo-app-api/internal/util"
+	"github.com/tyha2404/nexo-app-api/internal/worker"
 )
 
 func main() {
@@ -39,12 +46,83 @@ func main() {
 	}
 	defer logg.Sync()
 
+	if cfg.StorageSigningSecretDerived {
+		logg.Warn("STORAGE_SIGNING_SECRET is not set; download URLs are signed with a key derived from JWT_SECRET")
+	}
+
 	gormDB, err := db.NewPostgres(cfg, logg)
 	if err != nil {
 		logg.Sugar().Fatalf("failed to connect db: %v", err)
 	}
 
-	r := router.New(gormDB, logg)
+	store, err := storage.New(cfg)
+	if err != nil {
+		logg.Sugar().Fatalf("failed to init blob storage: %v", err)
+	}
+
+	r := router.New(cfg, gormDB, store, logg)
+
+	// Background jobs stop together with the server
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	accountDeletionService := service.NewAccountDeletionService(
+		repository.NewUserRepo(gormDB),
+		repository.NewAccountDeletionRepo(gormDB),
+		store,
+		cfg.AccountDeletionGracePeriod(),
+	)
+	go worker.RunPeriodically(jobCtx, "account_purge", cfg.PurgeInterval(), logg, func(ctx context.Context) error {
+		purged, err := accountDeletionService.PurgeDue(ctx)
+		if purged > 0 {
+			logg.Sugar().Infow("purged deleted accounts", "count", purged)
+		}
+		return err
+	})
+
+	trashService := service.NewTrashService(
+		repository.NewTrashRepo(gormDB),
+		repository.NewCategoryRepo(gormDB),
+		service.NewCategorySuggester(repository.NewTransactionRepository(gormDB)),
+		store,
+		cfg.TrashRetention(),
+	)
+	go worker.RunPeriodically(jobCtx, "trash_purge", cfg.PurgeInterval(), logg, func(ctx context.Context) error {
+		purged, err := trashService.PurgeExpired(ctx)
+		if purged > 0 {
+			logg.Sugar().Infow("purged expired trash", "count", purged)
+		}
+		return err
+	})
+
+	goalService := service.NewGoalService(
+		repository.NewGoalRepo(gormDB),
+		repository.NewCategoryRepo(gormDB),
+		repository.NewTagRepo(gormDB),
+		repository.NewNetWorthRepo(gormDB),
+	)
+	go worker.RunPeriodically(jobCtx, "goal_pace", cfg.PurgeInterval(), logg, func(ctx context.Context) error {
+		raised, err := goalService.CheckPace(ctx, time.Now())
+		if raised > 0 {
+			logg.Sugar().Infow("raised goal alerts", "count", raised)
+		}
+		return err
+	})
+
+	billService := service.NewBillService(
+		repository.NewBillRepo(gormDB),
+		repository.NewCategoryRepo(gormDB),
+		repository.NewTransactionRepository(gormDB),
+		notify.NewLogNotifier(logg),
+		logg,
+	)
+	go worker.RunPeriodically(jobCtx, "bill_reminders", cfg.PurgeInterval(), logg, func(ctx context.Context) error {
+		raised, err := billService.CheckDue(ctx, time.Now())
+		if raised > 0 {
+			logg.Sugar().Infow("raised bill alerts", "count", raised)
+		}
+		return err
+	})
 
 	srv := &http.Server{
 		Addr:    ":" + cfg.Port,
@@ -63,6 +141,7 @@ func main() {
 	signal.Notify(quit, os.Interrupt)
 	<-quit
 	logg.Sugar().Info("shutting down server...")
+	stopJobs()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -1,8 +1,13 @@
 package config
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -18,6 +23,33 @@ type Config struct {
 	LogLevel  string
 	JwtSecret string
 	AppEnv    string
+
+	// Blob storage used for attachments
+	StorageDriver        string
+	StorageLocalPath     string
+	StorageSigningSecret string
+	PublicBaseURL        string
+	S3Endpoint           string
+	S3Region             string
+	S3Bucket             string
+	S3AccessKey          string
+	S3SecretKey          string
+	S3UsePathStyle       bool
+	AttachmentMaxBytes   int64
+	// Set when STORAGE_SIGNING_SECRET is missing and download URLs are
+	// signed with a key derived from JwtSecret instead
+	StorageSigningSecretDerived bool
+
+	// Exports with more records than this run as a background job
+	ExportAsyncThreshold int64
+
+	// Requested account deletions are purged after this many days
+	AccountDeletionGraceDays int64
+	// Deleted transactions, costs, categories and budgets stay in the trash
+	// this many days before they are purged
+	TrashRetentionDays int64
+	// How often background maintenance jobs such as the account purge run
+	PurgeIntervalMinutes int64
 }
 
 func LoadConfig() (*Config, error) {
@@ -34,6 +66,28 @@ func LoadConfig() (*Config, error) {
 		LogLevel:  getEnv("LOG_LEVEL", "info"),
 		JwtSecret: getEnv("JWT_SECRET", "secret"),
 		AppEnv:    getEnv("APP_ENV", "dev"),
+
+		StorageDriver:        getEnv("STORAGE_DRIVER", "local"),
+		StorageLocalPath:     getEnv("STORAGE_LOCAL_PATH", "./data/attachments"),
+		StorageSigningSecret: getEnv("STORAGE_SIGNING_SECRET", ""),
+		PublicBaseURL:        getEnv("PUBLIC_BASE_URL", ""),
+		S3Endpoint:           getEnv("S3_ENDPOINT", ""),
+		S3Region:             getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:             getEnv("S3_BUCKET", ""),
+		S3AccessKey:          getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey:          getEnv("S3_SECRET_KEY", ""),
+		S3UsePathStyle:       getEnvBool("S3_USE_PATH_STYLE", true),
+		AttachmentMaxBytes:   getEnvInt64("ATTACHMENT_MAX_BYTES", 10<<20),
+
+		ExportAsyncThreshold: getEnvInt64("EXPORT_ASYNC_THRESHOLD", 10000),
+
+		AccountDeletionGraceDays: getEnvInt64("ACCOUNT_DELETION_GRACE_DAYS", 30),
+		TrashRetentionDays:       getEnvInt64("TRASH_RETENTION_DAYS", 30),
+		PurgeIntervalMinutes:     getEnvInt64("PURGE_INTERVAL_MINUTES", 60),
+	}
+
+	if c.PublicBaseURL == "" {
+		c.PublicBaseURL = "http://localhost:" + c.Port
 	}
 
 	// Security validations
@@ -45,12 +99,81 @@ func LoadConfig() (*Config, error) {
 		return nil, fmt.Errorf("JWT_SECRET must be set to a secure value (minimum 32 characters)")
 	}
 
+	// JWTs and download URLs are never signed with the same key
+	switch c.StorageSigningSecret {
+	case "":
+		c.StorageSigningSecret = deriveKey(c.JwtSecret, "storage-url")
+		c.StorageSigningSecretDerived = true
+	case c.JwtSecret:
+		return nil, fmt.Errorf("STORAGE_SIGNING_SECRET must differ from JWT_SECRET")
+	}
+
+	if c.AccountDeletionGraceDays < 0 {
+		return nil, fmt.Errorf("ACCOUNT_DELETION_GRACE_DAYS must not be negative")
+	}
+
+	if c.TrashRetentionDays < 1 {
+		return nil, fmt.Errorf("TRASH_RETENTION_DAYS must be at least 1")
+	}
+
+	if c.PurgeIntervalMinutes < 1 {
+		return nil, fmt.Errorf("PURGE_INTERVAL_MINUTES must be at least 1")
+	}
+
+	if c.StorageDriver != "local" && c.StorageDriver != "s3" {
+		return nil, fmt.Errorf("STORAGE_DRIVER must be one of: local, s3")
+	}
+
+	if c.StorageDriver == "s3" && (c.S3Endpoint == "" || c.S3Bucket == "" || c.S3AccessKey == "" || c.S3SecretKey == "") {
+		return nil, fmt.Errorf("S3_ENDPOINT, S3_BUCKET, S3_ACCESS_KEY and S3_SECRET_KEY are required for the s3 storage driver")
+	}
+
 	return c, nil
 }
 
+// deriveKey derives a separate key for purpose from secret
+func deriveKey(secret, purpose string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(purpose))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
 func getEnv(key, fallback string) string {
 	if v := os.Getenv(key); v != "" {
 		return v
 	}
 	return fallback
 }
+
+func getEnvBool(key string, fallback bool) bool {
+	if v := os.Getenv(key); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return fallback
+}
+
+func getEnvInt64(key string, fallback int64) int64 {
+	if v := os.Getenv(key); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return n
+		}
+	}
+	return fallback
+}
+
+// AccountDeletionGracePeriod is the delay between a deletion request and the purge
+func (c *Config) AccountDeletionGracePeriod() time.Duration {
+	return time.Duration(c.AccountDeletionGraceDays) * 24 * time.Hour
+}
+
+// TrashRetention is how long deleted records can still be restored
+func (c *Config) TrashRetention() time.Duration {
+	return time.Duration(c.TrashRetentionDays) * 24 * time.Hour
+}
+
+// PurgeInterval is the delay between runs of background maintenance jobs
+func (c *Config) PurgeInterval() time.Duration {
+	return time.Duration(c.PurgeIntervalMinutes) * time.Minute
+}
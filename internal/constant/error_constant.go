@@ -13,4 +13,10 @@ var (
 	ErrEmailAlreadyExists = errors.New("email already exists")
 	ErrUsernameTaken      = errors.New("username already taken")
 	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrTagAlreadyExists   = errors.New("tag already exists")
+	ErrCategoryExists     = errors.New("category already exists")
+	ErrFileTooLarge       = errors.New("file too large")
+	ErrUnsupportedMedia   = errors.New("unsupported media type")
+	ErrExportInProgress   = errors.New("export already in progress")
+	ErrTemplateExists     = errors.New("category template already exists")
 )
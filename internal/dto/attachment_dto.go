@@ -0,0 +1,14 @@
+package dto
+
+type AttachmentResponse struct {
+	ID            string  `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	UserID        string  `json:"userId" example:"550e8400-e29b-41d4-a716-446655440001"`
+	TransactionID *string `json:"transactionId,omitempty" example:"550e8400-e29b-41d4-a716-446655440002"`
+	CostID        *string `json:"costId,omitempty" example:"550e8400-e29b-41d4-a716-446655440003"`
+	FileName      string  `json:"fileName" example:"receipt.pdf"`
+	ContentType   string  `json:"contentType" example:"application/pdf"`
+	Size          int64   `json:"size" example:"48213"`
+	DownloadURL   string  `json:"downloadUrl,omitempty" example:"http://localhost:3001/api/v1/files/attachments/...?expires=...&signature=..."`
+	ExpiresAt     string  `json:"expiresAt,omitempty" example:"2024-01-15T00:15:00Z"`
+	CreatedAt     string  `json:"createdAt" example:"2024-01-15T00:00:00Z"`
+}
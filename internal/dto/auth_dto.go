@@ -14,6 +14,9 @@ type RegisterRequest struct {
 	Username string `json:"username" example:"johndoe" validate:"required,min=3,max=50,alphanum"`
 	Email    string `json:"email" example:"john@example.com" validate:"required,email,max=255"`
 	Password string `json:"password" example:"password123" validate:"required,min=8,max=128"`
+	// Locale picks the categories the account starts with and defaults to
+	// the Accept-Language header
+	Locale string `json:"locale,omitempty" example:"en" validate:"omitempty,min=2,max=16"`
 }
 
 type UserResponse struct {
@@ -22,9 +25,23 @@ type UserResponse struct {
 	Email     string `json:"email" example:"john@example.com"`
 	CreatedAt string `json:"createdAt" example:"2024-01-01T00:00:00Z"`
 	UpdatedAt string `json:"updatedAt" example:"2024-01-01T00:00:00Z"`
+	// DeletionScheduledAt is set while the account is pending deletion
+	DeletionScheduledAt *string `json:"deletionScheduledAt,omitempty" example:"2024-02-01T00:00:00Z"`
 }
 
 type UpdateUserRequest struct {
 	Username *string `json:"username,omitempty" example:"newusername" validate:"omitempty,min=3,max=50,alphanum"`
 	Email    *string `json:"email,omitempty" example:"newemail@example.com" validate:"omitempty,email,max=255"`
 }
+
+// AccountDeletionRequest confirms a self-service account deletion
+type AccountDeletionRequest struct {
+	Password string `json:"password" example:"password123" validate:"required"`
+}
+
+// AccountDeletionStatusResponse describes a pending account deletion
+type AccountDeletionStatusResponse struct {
+	Scheduled    bool    `json:"scheduled" example:"true"`
+	RequestedAt  *string `json:"requestedAt,omitempty" example:"2024-01-01T00:00:00Z"`
+	ScheduledFor *string `json:"scheduledFor,omitempty" example:"2024-01-31T00:00:00Z"`
+}
@@ -0,0 +1,243 @@
+package dto
+
+import "github.com/google/uuid"
+
+// BackupArchive is the versioned, self-describing JSON document holding
+// everything a user owns. IDs are only used to link records inside the
+// archive; a restore always assigns new ones.
+type BackupArchive struct {
+	// Format is always "nexo-backup"
+	Format string `json:"format" example:"nexo-backup"`
+	// Version is increased whenever the layout changes incompatibly
+	Version             int                        `json:"version" example:"1"`
+	CreatedAt           string                     `json:"createdAt" example:"2024-01-15T10:30:00Z"`
+	Profile             BackupProfile              `json:"profile"`
+	Categories          []BackupCategory           `json:"categories"`
+	Tags                []BackupTag                `json:"tags"`
+	Budgets             []BackupBudget             `json:"budgets"`
+	Alerts              []BackupAlert              `json:"alerts"`
+	Transactions        []BackupTransaction        `json:"transactions"`
+	Costs               []BackupCost               `json:"costs"`
+	Expenses            []BackupExpense            `json:"expenses"`
+	ImportProfiles      []BackupImportProfile      `json:"importProfiles"`
+	NetWorthItems       []BackupNetWorthItem       `json:"netWorthItems"`
+	CategorizationRules []BackupCategorizationRule `json:"categorizationRules"`
+	Goals               []BackupGoal               `json:"goals"`
+	Loans               []BackupLoan               `json:"loans"`
+	Bills               []BackupBill               `json:"bills"`
+}
+
+// BackupProfile is informational; a restore never changes the target account
+type BackupProfile struct {
+	ID        uuid.UUID `json:"id"`
+	Username  string    `json:"username" example:"johndoe"`
+	Email     string    `json:"email" example:"john@example.com"`
+	CreatedAt string    `json:"createdAt" example:"2023-01-01T00:00:00Z"`
+}
+
+type BackupCategory struct {
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name" example:"Groceries"`
+	Description *string    `json:"description,omitempty"`
+	Kind        *string    `json:"kind,omitempty" example:"EXPENSE"`
+	Icon        *string    `json:"icon,omitempty" example:"shopping_cart"`
+	Color       *string    `json:"color,omitempty" example:"#f9a825"`
+	SortOrder   int        `json:"sortOrder,omitempty" example:"10"`
+	ParentID    *uuid.UUID `json:"parentId,omitempty"`
+	ArchivedAt  *string    `json:"archivedAt,omitempty" example:"2024-06-01T00:00:00Z"`
+	CreatedAt   string     `json:"createdAt" example:"2023-01-01T00:00:00Z"`
+}
+
+type BackupTag struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name" example:"vacation"`
+	Color     *string   `json:"color,omitempty" example:"#ff8800"`
+	CreatedAt string    `json:"createdAt" example:"2023-01-01T00:00:00Z"`
+}
+
+type BackupBudget struct {
+	ID          uuid.UUID `json:"id"`
+	CategoryID  uuid.UUID `json:"categoryId"`
+	Amount      float64   `json:"amount" example:"500"`
+	PeriodType  string    `json:"periodType" example:"monthly"`
+	PeriodStart string    `json:"periodStart" example:"2024-01-01"`
+	CreatedAt   string    `json:"createdAt" example:"2023-01-01T00:00:00Z"`
+}
+
+// BackupAlert belongs to a budget, a goal or a bill
+type BackupAlert struct {
+	ID          uuid.UUID  `json:"id"`
+	BudgetID    *uuid.UUID `json:"budgetId,omitempty"`
+	GoalID      *uuid.UUID `json:"goalId,omitempty"`
+	BillID      *uuid.UUID `json:"billId,omitempty"`
+	AlertType   string     `json:"alertType" example:"over_limit"`
+	Message     string     `json:"message"`
+	TriggeredAt string     `json:"triggeredAt" example:"2024-01-20T08:00:00Z"`
+}
+
+type BackupTransaction struct {
+	ID              uuid.UUID   `json:"id"`
+	CategoryID      uuid.UUID   `json:"categoryId"`
+	Amount          float64     `json:"amount" example:"42.5"`
+	Type            string      `json:"type" example:"EXPENSE"`
+	Description     *string     `json:"description,omitempty"`
+	TransactionDate string      `json:"transactionDate" example:"2024-01-15"`
+	ExternalID      *string     `json:"externalId,omitempty"`
+	AccountID       *string     `json:"accountId,omitempty"`
+	TagIDs          []uuid.UUID `json:"tagIds,omitempty"`
+	CreatedAt       string      `json:"createdAt" example:"2024-01-15T10:30:00Z"`
+}
+
+type BackupCost struct {
+	ID         uuid.UUID   `json:"id"`
+	CategoryID uuid.UUID   `json:"categoryId"`
+	Title      string      `json:"title" example:"Internet"`
+	Amount     float64     `json:"amount" example:"29.99"`
+	Currency   string      `json:"currency" example:"EUR"`
+	IncurredAt string      `json:"incurredAt" example:"2024-01-15T00:00:00Z"`
+	TagIDs     []uuid.UUID `json:"tagIds,omitempty"`
+	CreatedAt  string      `json:"createdAt" example:"2024-01-15T10:30:00Z"`
+}
+
+type BackupExpense struct {
+	ID          uuid.UUID `json:"id"`
+	CategoryID  uuid.UUID `json:"categoryId"`
+	Amount      float64   `json:"amount" example:"12.5"`
+	Description *string   `json:"description,omitempty"`
+	ExpenseDate string    `json:"expenseDate" example:"2024-01-15"`
+	CreatedAt   string    `json:"createdAt" example:"2024-01-15T10:30:00Z"`
+}
+
+type BackupImportProfile struct {
+	Name              string `json:"name" example:"My bank"`
+	Delimiter         string `json:"delimiter" example:";"`
+	DateFormat        string `json:"dateFormat" example:"DD/MM/YYYY"`
+	DecimalSeparator  string `json:"decimalSeparator" example:","`
+	HasHeader         bool   `json:"hasHeader" example:"true"`
+	DateColumn        string `json:"dateColumn" example:"Booking date"`
+	AmountColumn      string `json:"amountColumn,omitempty"`
+	DebitColumn       string `json:"debitColumn,omitempty"`
+	CreditColumn      string `json:"creditColumn,omitempty"`
+	DescriptionColumn string `json:"descriptionColumn,omitempty"`
+	TypeColumn        string `json:"typeColumn,omitempty"`
+	CategoryColumn    string `json:"categoryColumn,omitempty"`
+}
+
+type BackupNetWorthItem struct {
+	// ID links goals to the item; archives written before goals have none
+	ID          uuid.UUID         `json:"id,omitempty"`
+	Name        string            `json:"name" example:"Family home"`
+	Kind        string            `json:"kind" example:"ASSET"`
+	Description *string           `json:"description,omitempty"`
+	Valuations  []BackupValuation `json:"valuations"`
+	CreatedAt   string            `json:"createdAt" example:"2023-01-01T00:00:00Z"`
+}
+
+type BackupValuation struct {
+	Value    float64 `json:"value" example:"350000"`
+	ValuedAt string  `json:"valuedAt" example:"2024-01-01"`
+	Note     *string `json:"note,omitempty"`
+}
+
+type BackupCategorizationRule struct {
+	ID                  uuid.UUID   `json:"id"`
+	Name                string      `json:"name" example:"Groceries"`
+	Priority            int         `json:"priority" example:"10"`
+	Enabled             bool        `json:"enabled" example:"true"`
+	DescriptionContains *string     `json:"descriptionContains,omitempty"`
+	DescriptionPattern  *string     `json:"descriptionPattern,omitempty"`
+	MinAmount           *float64    `json:"minAmount,omitempty"`
+	MaxAmount           *float64    `json:"maxAmount,omitempty"`
+	AccountID           *string     `json:"accountId,omitempty"`
+	Type                *string     `json:"type,omitempty"`
+	SetCategoryID       *uuid.UUID  `json:"setCategoryId,omitempty"`
+	SetDescription      *string     `json:"setDescription,omitempty"`
+	AddTagIDs           []uuid.UUID `json:"addTagIds,omitempty"`
+	StopProcessing      bool        `json:"stopProcessing" example:"false"`
+	CreatedAt           string      `json:"createdAt" example:"2024-01-01T00:00:00Z"`
+}
+
+type BackupGoal struct {
+	ID             uuid.UUID                `json:"id"`
+	Name           string                   `json:"name" example:"Emergency fund"`
+	TargetAmount   float64                  `json:"targetAmount" example:"5000"`
+	StartDate      string                   `json:"startDate" example:"2024-01-01"`
+	Deadline       *string                  `json:"deadline,omitempty" example:"2024-12-31"`
+	CategoryID     *uuid.UUID               `json:"categoryId,omitempty"`
+	TagID          *uuid.UUID               `json:"tagId,omitempty"`
+	NetWorthItemID *uuid.UUID               `json:"netWorthItemId,omitempty"`
+	Contributions  []BackupGoalContribution `json:"contributions"`
+	CreatedAt      string                   `json:"createdAt" example:"2024-01-01T00:00:00Z"`
+}
+
+type BackupGoalContribution struct {
+	Amount        float64 `json:"amount" example:"250"`
+	ContributedOn string  `json:"contributedOn" example:"2024-03-01"`
+	Note          *string `json:"note,omitempty"`
+}
+
+type BackupLoan struct {
+	ID           uuid.UUID  `json:"id"`
+	Name         string     `json:"name" example:"Car loan"`
+	Direction    string     `json:"direction" example:"BORROWED"`
+	Counterparty *string    `json:"counterparty,omitempty"`
+	Principal    float64    `json:"principal" example:"12000"`
+	InterestRate float64    `json:"interestRate" example:"6.5"`
+	TermMonths   int        `json:"termMonths" example:"48"`
+	Compounding  string     `json:"compounding" example:"MONTHLY"`
+	StartDate    string     `json:"startDate" example:"2024-01-15"`
+	CategoryID   *uuid.UUID `json:"categoryId,omitempty"`
+	// PaymentTransactionIDs are the transactions recorded as payments
+	PaymentTransactionIDs []uuid.UUID `json:"paymentTransactionIds,omitempty"`
+	CreatedAt             string      `json:"createdAt" example:"2024-01-15T00:00:00Z"`
+}
+
+type BackupBill struct {
+	ID               uuid.UUID           `json:"id"`
+	Payee            string              `json:"payee" example:"City Power"`
+	ExpectedAmount   float64             `json:"expectedAmount" example:"120"`
+	DueDay           int                 `json:"dueDay" example:"5"`
+	Recurrence       string              `json:"recurrence" example:"MONTHLY"`
+	StartDate        string              `json:"startDate" example:"2024-01-01"`
+	EndDate          *string             `json:"endDate,omitempty" example:"2024-12-31"`
+	RemindDaysBefore int                 `json:"remindDaysBefore" example:"3"`
+	CategoryID       *uuid.UUID          `json:"categoryId,omitempty"`
+	Note             *string             `json:"note,omitempty"`
+	Payments         []BackupBillPayment `json:"payments,omitempty"`
+	CreatedAt        string              `json:"createdAt" example:"2024-01-01T00:00:00Z"`
+}
+
+type BackupBillPayment struct {
+	DueDate       string    `json:"dueDate" example:"2024-03-05"`
+	TransactionID uuid.UUID `json:"transactionId"`
+}
+
+// RestoreRequest is sent as the JSON "request" field of the multipart upload
+type RestoreRequest struct {
+	// DryRun only reports what would be restored. Defaults to true.
+	DryRun *bool `json:"dryRun,omitempty" example:"true"`
+	// OnConflict decides what happens to categories, tags, import profiles,
+	// net worth items, categorization rules, goals and loans whose name already exists: merge reuses the existing record, rename
+	// restores a copy with a numbered name
+	OnConflict string `json:"onConflict,omitempty" example:"merge" validate:"omitempty,oneof=merge rename"`
+	// SkipDuplicates skips transactions that already exist with the same bank
+	// ID, or the same date, type, amount and description. Defaults to true.
+	SkipDuplicates *bool `json:"skipDuplicates,omitempty" example:"true"`
+}
+
+// RestoreEntityResult counts what happened to one kind of record
+type RestoreEntityResult struct {
+	Created int `json:"created" example:"120"`
+	// Merged records were matched to an existing record of the same name
+	Merged  int `json:"merged" example:"3"`
+	Skipped int `json:"skipped" example:"2"`
+}
+
+// RestoreResultResponse summarizes a dry-run or committed restore
+type RestoreResultResponse struct {
+	DryRun        bool                           `json:"dryRun" example:"true"`
+	SourceVersion int                            `json:"sourceVersion" example:"1"`
+	Entities      map[string]RestoreEntityResult `json:"entities"`
+	// Warnings explain skipped records, e.g. references to missing categories
+	Warnings []string `json:"warnings,omitempty"`
+}
@@ -0,0 +1,106 @@
+package dto
+
+import "github.com/google/uuid"
+
+type CreateBillRequest struct {
+	Payee          string  `json:"payee" example:"City Power" validate:"required,min=1,max=100"`
+	ExpectedAmount float64 `json:"expectedAmount" example:"120" validate:"gt=0"`
+	// DueDay is the day of the month the bill falls due; a day past the end of
+	// a month falls on its last day
+	DueDay int `json:"dueDay" example:"5" validate:"required,min=1,max=31"`
+	// Recurrence is ONCE, MONTHLY, QUARTERLY or YEARLY, counted from the month
+	// of the start date
+	Recurrence string `json:"recurrence" example:"MONTHLY" validate:"required,oneof=ONCE MONTHLY QUARTERLY YEARLY"`
+	// StartDate is the first day the bill can fall due; defaults to today
+	StartDate *string `json:"startDate,omitempty" example:"2024-01-01" validate:"omitempty,datetime=2006-01-02"`
+	// EndDate is the last day the bill can fall due
+	EndDate *string `json:"endDate,omitempty" example:"2024-12-31" validate:"omitempty,datetime=2006-01-02"`
+	// RemindDaysBefore is how many days ahead an unpaid bill raises an alert;
+	// defaults to 3
+	RemindDaysBefore *int `json:"remindDaysBefore,omitempty" example:"3" validate:"omitempty,min=0,max=60"`
+	// CategoryID is the category of the transactions booked when the bill is
+	// paid
+	CategoryID *uuid.UUID `json:"categoryId,omitempty" example:"550e8400-e29b-41d4-a716-446655440001"`
+	Note       *string    `json:"note,omitempty" example:"Customer no. 48213" validate:"omitempty,max=500"`
+}
+
+type UpdateBillRequest struct {
+	Payee          *string  `json:"payee,omitempty" example:"City Power" validate:"omitempty,min=1,max=100"`
+	ExpectedAmount *float64 `json:"expectedAmount,omitempty" example:"135" validate:"omitempty,gt=0"`
+	DueDay         *int     `json:"dueDay,omitempty" example:"10" validate:"omitempty,min=1,max=31"`
+	Recurrence     *string  `json:"recurrence,omitempty" example:"QUARTERLY" validate:"omitempty,oneof=ONCE MONTHLY QUARTERLY YEARLY"`
+	StartDate      *string  `json:"startDate,omitempty" example:"2024-01-01" validate:"omitempty,datetime=2006-01-02"`
+	// EndDate is a date, or an empty string to remove it
+	EndDate          *string `json:"endDate,omitempty" example:"2024-12-31" validate:"omitempty,datetime=2006-01-02"`
+	RemindDaysBefore *int    `json:"remindDaysBefore,omitempty" example:"5" validate:"omitempty,min=0,max=60"`
+	// CategoryID changes the payment category; null removes it
+	CategoryID OptionalUUID `json:"categoryId,omitempty" swaggertype:"string" example:"550e8400-e29b-41d4-a716-446655440001"`
+	// Note is a text, or an empty string to remove it
+	Note *string `json:"note,omitempty" example:"Customer no. 48213" validate:"omitempty,max=500"`
+}
+
+// PayBillRequest marks an occurrence of a bill paid. With TransactionID the
+// existing expense is linked; otherwise an expense is booked from Amount,
+// PaidOn, CategoryID and Description.
+type PayBillRequest struct {
+	// DueDate picks the occurrence; defaults to the last one due if it is
+	// unpaid, else the next unpaid one
+	DueDate       *string    `json:"dueDate,omitempty" example:"2024-03-05" validate:"omitempty,datetime=2006-01-02"`
+	TransactionID *uuid.UUID `json:"transactionId,omitempty" example:"550e8400-e29b-41d4-a716-446655440004"`
+	// Amount defaults to the expected amount
+	Amount *float64 `json:"amount,omitempty" example:"118.40" validate:"omitempty,gt=0"`
+	// PaidOn defaults to today
+	PaidOn *string `json:"paidOn,omitempty" example:"2024-03-03" validate:"omitempty,datetime=2006-01-02"`
+	// CategoryID defaults to the category of the bill
+	CategoryID *uuid.UUID `json:"categoryId,omitempty" example:"550e8400-e29b-41d4-a716-446655440001"`
+	// Description defaults to the payee
+	Description *string `json:"description,omitempty" example:"Electricity March" validate:"omitempty,max=500"`
+}
+
+type BillResponse struct {
+	ID               string  `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Payee            string  `json:"payee" example:"City Power"`
+	ExpectedAmount   float64 `json:"expectedAmount" example:"120"`
+	DueDay           int     `json:"dueDay" example:"5"`
+	Recurrence       string  `json:"recurrence" example:"MONTHLY"`
+	StartDate        string  `json:"startDate" example:"2024-01-01"`
+	EndDate          *string `json:"endDate,omitempty" example:"2024-12-31"`
+	RemindDaysBefore int     `json:"remindDaysBefore" example:"3"`
+	CategoryID       *string `json:"categoryId,omitempty" example:"550e8400-e29b-41d4-a716-446655440001"`
+	Note             *string `json:"note,omitempty" example:"Customer no. 48213"`
+	// NextDueDate is the first unpaid occurrence due today or later
+	NextDueDate *string `json:"nextDueDate,omitempty" example:"2024-04-05"`
+	// OverdueDate is the last occurrence due before today, if it is unpaid
+	OverdueDate *string `json:"overdueDate,omitempty" example:"2024-03-05"`
+	CreatedAt   string  `json:"createdAt" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt   string  `json:"updatedAt" example:"2024-01-01T00:00:00Z"`
+}
+
+// BillOccurrenceResponse is one due date of a bill
+type BillOccurrenceResponse struct {
+	BillID         string  `json:"billId" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Payee          string  `json:"payee" example:"City Power"`
+	ExpectedAmount float64 `json:"expectedAmount" example:"120"`
+	DueDate        string  `json:"dueDate" example:"2024-04-05"`
+	// DaysUntilDue is negative for an overdue occurrence
+	DaysUntilDue int `json:"daysUntilDue" example:"4"`
+	// Status is paid, due or overdue
+	Status  string               `json:"status" example:"due"`
+	Payment *BillPaymentResponse `json:"payment,omitempty"`
+}
+
+type BillPaymentResponse struct {
+	ID            string  `json:"id" example:"550e8400-e29b-41d4-a716-446655440003"`
+	DueDate       string  `json:"dueDate" example:"2024-03-05"`
+	TransactionID string  `json:"transactionId" example:"550e8400-e29b-41d4-a716-446655440004"`
+	Amount        float64 `json:"amount" example:"118.40"`
+	PaidOn        string  `json:"paidOn" example:"2024-03-03"`
+	Description   *string `json:"description,omitempty" example:"Electricity March"`
+}
+
+type BillAlertResponse struct {
+	ID          string `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	AlertType   string `json:"alertType" example:"bill_due"`
+	Message     string `json:"message" example:"City Power (120.00) is due on 2024-04-05."`
+	TriggeredAt string `json:"triggeredAt" example:"2024-04-02T06:00:00Z"`
+}
@@ -0,0 +1,91 @@
+package dto
+
+import "github.com/google/uuid"
+
+// CategorizationRuleRequest creates a rule or, on update, replaces it as a
+// whole. Conditions left out are not checked; at least one action is required.
+type CategorizationRuleRequest struct {
+	Name string `json:"name" example:"Groceries" validate:"required,min=1,max=100"`
+	// Priority orders the rules; lower values run first
+	Priority int   `json:"priority" example:"10"`
+	Enabled  *bool `json:"enabled,omitempty" example:"true"`
+	// DescriptionContains matches descriptions containing the text, ignoring case
+	DescriptionContains *string `json:"descriptionContains,omitempty" example:"REWE" validate:"omitempty,min=1,max=255"`
+	// DescriptionPattern is a regular expression matched against the
+	// description, ignoring case
+	DescriptionPattern *string  `json:"descriptionPattern,omitempty" example:"^(REWE|EDEKA)\\b" validate:"omitempty,min=1,max=500"`
+	MinAmount          *float64 `json:"minAmount,omitempty" example:"5" validate:"omitempty,gte=0"`
+	MaxAmount          *float64 `json:"maxAmount,omitempty" example:"250" validate:"omitempty,gte=0"`
+	// AccountID matches the bank account of imported transactions
+	AccountID *string `json:"accountId,omitempty" example:"DE89370400440532013000" validate:"omitempty,min=1,max=100"`
+	Type      *string `json:"type,omitempty" example:"EXPENSE" validate:"omitempty,oneof=INCOME EXPENSE"`
+
+	SetCategoryID  *uuid.UUID  `json:"setCategoryId,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	SetDescription *string     `json:"setDescription,omitempty" example:"Groceries" validate:"omitempty,min=1,max=500"`
+	AddTagIDs      []uuid.UUID `json:"addTagIds,omitempty"`
+	// StopProcessing keeps rules of lower priority from running after a match
+	StopProcessing bool `json:"stopProcessing" example:"false"`
+}
+
+type CategorizationRuleResponse struct {
+	ID                  string        `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name                string        `json:"name" example:"Groceries"`
+	Priority            int           `json:"priority" example:"10"`
+	Enabled             bool          `json:"enabled" example:"true"`
+	DescriptionContains *string       `json:"descriptionContains,omitempty" example:"REWE"`
+	DescriptionPattern  *string       `json:"descriptionPattern,omitempty" example:"^(REWE|EDEKA)\\b"`
+	MinAmount           *float64      `json:"minAmount,omitempty" example:"5"`
+	MaxAmount           *float64      `json:"maxAmount,omitempty" example:"250"`
+	AccountID           *string       `json:"accountId,omitempty" example:"DE89370400440532013000"`
+	Type                *string       `json:"type,omitempty" example:"EXPENSE"`
+	SetCategoryID       *string       `json:"setCategoryId,omitempty" example:"550e8400-e29b-41d4-a716-446655440001"`
+	SetCategoryName     *string       `json:"setCategoryName,omitempty" example:"Food"`
+	SetDescription      *string       `json:"setDescription,omitempty" example:"Groceries"`
+	AddTags             []TagResponse `json:"addTags,omitempty"`
+	StopProcessing      bool          `json:"stopProcessing" example:"false"`
+	CreatedAt           string        `json:"createdAt" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt           string        `json:"updatedAt" example:"2024-01-01T00:00:00Z"`
+}
+
+// ApplyRulesRequest runs rules over existing transactions
+type ApplyRulesRequest struct {
+	// RuleIDs limits the run to these rules, disabled ones included; by
+	// default all enabled rules run
+	RuleIDs []uuid.UUID `json:"ruleIds,omitempty"`
+	From    *string     `json:"from,omitempty" example:"2024-01-01" validate:"omitempty,datetime=2006-01-02"`
+	To      *string     `json:"to,omitempty" example:"2024-12-31" validate:"omitempty,datetime=2006-01-02"`
+	// CategoryID limits the run to transactions currently in this category,
+	// e.g. the default category of earlier imports
+	CategoryID *uuid.UUID `json:"categoryId,omitempty" example:"550e8400-e29b-41d4-a716-446655440001"`
+	// DryRun only previews the changes; it defaults to true
+	DryRun *bool `json:"dryRun,omitempty" example:"true"`
+}
+
+// ApplyRulesResponse lists the transactions the rules change
+type ApplyRulesResponse struct {
+	DryRun bool `json:"dryRun" example:"true"`
+	// Scanned is the number of transactions the rules were run against
+	Scanned int `json:"scanned" example:"840"`
+	// Affected is the number of transactions with at least one change
+	Affected int `json:"affected" example:"57"`
+	// Truncated is set when Changes only lists the first of the affected rows
+	Truncated bool                 `json:"truncated" example:"false"`
+	Changes   []RuleChangeResponse `json:"changes"`
+}
+
+// RuleChangeResponse is the change of a single transaction. Fields that stay
+// the same are omitted.
+type RuleChangeResponse struct {
+	TransactionID   string   `json:"transactionId" example:"550e8400-e29b-41d4-a716-446655440000"`
+	TransactionDate string   `json:"transactionDate" example:"2024-03-05"`
+	Amount          float64  `json:"amount" example:"42.5"`
+	Type            string   `json:"type" example:"EXPENSE"`
+	Description     *string  `json:"description,omitempty" example:"REWE SAGT DANKE 1234"`
+	NewDescription  *string  `json:"newDescription,omitempty" example:"Groceries"`
+	CategoryID      string   `json:"categoryId" example:"550e8400-e29b-41d4-a716-446655440001"`
+	CategoryName    string   `json:"categoryName,omitempty" example:"Uncategorized"`
+	NewCategoryID   *string  `json:"newCategoryId,omitempty" example:"550e8400-e29b-41d4-a716-446655440002"`
+	NewCategoryName *string  `json:"newCategoryName,omitempty" example:"Food"`
+	AddedTagIDs     []string `json:"addedTagIds,omitempty"`
+	RuleIDs         []string `json:"ruleIds"`
+}
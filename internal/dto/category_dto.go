@@ -1,13 +1,56 @@
 package dto
 
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+// OptionalUUID tells a field that is absent from a JSON object apart from one
+// that is null
+type OptionalUUID struct {
+	Set   bool
+	Value *uuid.UUID
+}
+
+func (o *OptionalUUID) UnmarshalJSON(b []byte) error {
+	o.Set = true
+	if string(b) == "null" {
+		o.Value = nil
+		return nil
+	}
+	var id uuid.UUID
+	if err := json.Unmarshal(b, &id); err != nil {
+		return err
+	}
+	o.Value = &id
+	return nil
+}
+
 type CreateCategoryRequest struct {
 	Name        string  `json:"name" example:"Food" validate:"required,min=1,max=50"`
 	Description *string `json:"description" example:"Food and groceries" validate:"omitempty,max=500"`
+	// Kind limits the category to INCOME or EXPENSE transactions; without it
+	// the category takes both
+	Kind      *string `json:"kind,omitempty" example:"EXPENSE" validate:"omitempty,oneof=INCOME EXPENSE"`
+	Icon      *string `json:"icon,omitempty" example:"restaurant" validate:"omitempty,max=50"`
+	Color     *string `json:"color,omitempty" example:"#EF6C00" validate:"omitempty,hexcolor"`
+	SortOrder int     `json:"sortOrder,omitempty" example:"10"`
+	// ParentID nests the new category under an existing one
+	ParentID *uuid.UUID `json:"parentId,omitempty" example:"550e8400-e29b-41d4-a716-446655440001"`
 }
 
 type UpdateCategoryRequest struct {
 	Name        *string `json:"name,omitempty" example:"Food & Dining" validate:"omitempty,min=1,max=50"`
 	Description *string `json:"description,omitempty" example:"Updated description" validate:"omitempty,max=500"`
+	// Kind is INCOME or EXPENSE; an empty kind, icon or color clears it
+	Kind      *string `json:"kind,omitempty" example:"EXPENSE" validate:"omitempty,oneof=INCOME EXPENSE"`
+	Icon      *string `json:"icon,omitempty" example:"restaurant" validate:"omitempty,max=50"`
+	Color     *string `json:"color,omitempty" example:"#EF6C00" validate:"omitempty,hexcolor"`
+	SortOrder *int    `json:"sortOrder,omitempty" example:"10"`
+	// ParentID moves the category under another one; null moves it to the
+	// top level
+	ParentID OptionalUUID `json:"parentId,omitempty" swaggertype:"string" example:"550e8400-e29b-41d4-a716-446655440001"`
 }
 
 type CategoryResponse struct {
@@ -15,7 +58,43 @@ type CategoryResponse struct {
 	UserID      string  `json:"userId" example:"550e8400-e29b-41d4-a716-446655440001"`
 	Name        string  `json:"name" example:"Food"`
 	Description *string `json:"description,omitempty" example:"Food and groceries"`
+	Kind        *string `json:"kind,omitempty" example:"EXPENSE"`
+	Icon        *string `json:"icon,omitempty" example:"restaurant"`
+	Color       *string `json:"color,omitempty" example:"#EF6C00"`
+	SortOrder   int     `json:"sortOrder" example:"10"`
+	ParentID    *string `json:"parentId,omitempty" example:"550e8400-e29b-41d4-a716-446655440001"`
 	CreatedAt   string  `json:"createdAt" example:"2024-01-01T00:00:00Z"`
 	UpdatedAt   string  `json:"updatedAt" example:"2024-01-01T00:00:00Z"`
 	DeletedAt   *string `json:"deletedAt,omitempty" example:"2024-01-01T00:00:00Z"`
 }
+
+// CategoryTreeResponse is a category with its subcategories nested below it
+type CategoryTreeResponse struct {
+	ID          string  `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name        string  `json:"name" example:"Restaurants"`
+	Description *string `json:"description,omitempty" example:"Eating out"`
+	Kind        *string `json:"kind,omitempty" example:"EXPENSE"`
+	Icon        *string `json:"icon,omitempty" example:"restaurant"`
+	Color       *string `json:"color,omitempty" example:"#EF6C00"`
+	SortOrder   int     `json:"sortOrder" example:"10"`
+	ParentID    *string `json:"parentId,omitempty" example:"550e8400-e29b-41d4-a716-446655440001"`
+	Archived    bool    `json:"archived" example:"false"`
+	// Depth is 0 for top-level categories
+	Depth    int                    `json:"depth" example:"1"`
+	Children []CategoryTreeResponse `json:"children"`
+}
+
+// CategoryMergeResponse counts what a merge moved onto the target category,
+// trashed records included
+type CategoryMergeResponse struct {
+	TargetID      string `json:"targetId" example:"550e8400-e29b-41d4-a716-446655440001"`
+	Transactions  int64  `json:"transactions" example:"42"`
+	Costs         int64  `json:"costs" example:"3"`
+	Budgets       int64  `json:"budgets" example:"1"`
+	Expenses      int64  `json:"expenses" example:"0"`
+	Rules         int64  `json:"rules" example:"2"`
+	Goals         int64  `json:"goals" example:"0"`
+	Loans         int64  `json:"loans" example:"0"`
+	Bills         int64  `json:"bills" example:"0"`
+	Subcategories int64  `json:"subcategories" example:"1"`
+}
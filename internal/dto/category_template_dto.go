@@ -0,0 +1,30 @@
+package dto
+
+type CreateCategoryTemplateRequest struct {
+	Locale    string  `json:"locale" example:"en" validate:"required,min=2,max=16"`
+	Name      string  `json:"name" example:"Groceries" validate:"required,min=1,max=50"`
+	Kind      string  `json:"kind" example:"EXPENSE" validate:"required,oneof=INCOME EXPENSE"`
+	Icon      *string `json:"icon,omitempty" example:"shopping_cart" validate:"omitempty,max=50"`
+	Color     *string `json:"color,omitempty" example:"#4CAF50" validate:"omitempty,hexcolor"`
+	SortOrder int     `json:"sortOrder" example:"10"`
+}
+
+type UpdateCategoryTemplateRequest struct {
+	Name      *string `json:"name,omitempty" example:"Groceries" validate:"omitempty,min=1,max=50"`
+	Kind      *string `json:"kind,omitempty" example:"EXPENSE" validate:"omitempty,oneof=INCOME EXPENSE"`
+	Icon      *string `json:"icon,omitempty" example:"shopping_cart" validate:"omitempty,max=50"`
+	Color     *string `json:"color,omitempty" example:"#4CAF50" validate:"omitempty,hexcolor"`
+	SortOrder *int    `json:"sortOrder,omitempty" example:"10"`
+}
+
+type CategoryTemplateResponse struct {
+	ID        string  `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Locale    string  `json:"locale" example:"en"`
+	Name      string  `json:"name" example:"Groceries"`
+	Kind      string  `json:"kind" example:"EXPENSE"`
+	Icon      *string `json:"icon,omitempty" example:"shopping_cart"`
+	Color     *string `json:"color,omitempty" example:"#4CAF50"`
+	SortOrder int     `json:"sortOrder" example:"10"`
+	CreatedAt string  `json:"createdAt" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt string  `json:"updatedAt" example:"2024-01-01T00:00:00Z"`
+}
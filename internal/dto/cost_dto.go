@@ -32,11 +32,12 @@ func (ct *CustomTime) UnmarshalJSON(b []byte) error {
 }
 
 type CreateCostRequest struct {
-	Title      string     `json:"title" example:"Office Supplies" validate:"required,min=1,max=255"`
-	Amount     float64    `json:"amount" example:"250.00" validate:"required,gt=0"`
-	Currency   string     `json:"currency" example:"USD" validate:"required,len=3,uppercase"`
-	IncurredAt CustomTime `json:"incurredAt" example:"2024-01-15T00:00:00Z" validate:"required"`
-	CategoryID uuid.UUID  `json:"categoryId" example:"550e8400-e29b-41d4-a716-446655440000" validate:"required,uuid"`
+	Title      string      `json:"title" example:"Office Supplies" validate:"required,min=1,max=255"`
+	Amount     float64     `json:"amount" example:"250.00" validate:"required,gt=0"`
+	Currency   string      `json:"currency" example:"USD" validate:"required,len=3,uppercase"`
+	IncurredAt CustomTime  `json:"incurredAt" example:"2024-01-15T00:00:00Z" validate:"required"`
+	CategoryID uuid.UUID   `json:"categoryId" example:"550e8400-e29b-41d4-a716-446655440000" validate:"required,uuid"`
+	TagIDs     []uuid.UUID `json:"tagIds,omitempty"`
 }
 
 type UpdateCostRequest struct {
@@ -0,0 +1,19 @@
+package dto
+
+// ExportJobResponse describes a background export and, once completed, where
+// to download its file
+type ExportJobResponse struct {
+	ID          string  `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Format      string  `json:"format" example:"xlsx"`
+	Status      string  `json:"status" example:"COMPLETED"`
+	DateFrom    *string `json:"dateFrom,omitempty" example:"2024-01-01"`
+	DateTo      *string `json:"dateTo,omitempty" example:"2024-12-31"`
+	FileName    string  `json:"fileName" example:"nexo-export-20240115.xlsx"`
+	Size        int64   `json:"size" example:"482113"`
+	RowCount    int64   `json:"rowCount" example:"25410"`
+	Error       *string `json:"error,omitempty"`
+	DownloadURL *string `json:"downloadUrl,omitempty" example:"/api/v1/exports/jobs/550e8400-e29b-41d4-a716-446655440000/download"`
+	CreatedAt   string  `json:"createdAt" example:"2024-01-15T10:30:00Z"`
+	CompletedAt *string `json:"completedAt,omitempty" example:"2024-01-15T10:31:12Z"`
+	ExpiresAt   *string `json:"expiresAt,omitempty" example:"2024-01-22T10:31:12Z"`
+}
@@ -0,0 +1,98 @@
+package dto
+
+import "github.com/google/uuid"
+
+type CreateGoalRequest struct {
+	Name         string  `json:"name" example:"Emergency fund" validate:"required,min=1,max=100"`
+	TargetAmount float64 `json:"targetAmount" example:"5000" validate:"gt=0"`
+	// StartDate defaults to today
+	StartDate *string `json:"startDate,omitempty" example:"2024-01-01" validate:"omitempty,datetime=2006-01-02"`
+	Deadline  *string `json:"deadline,omitempty" example:"2024-12-31" validate:"omitempty,datetime=2006-01-02"`
+	// CategoryID counts the transactions in the category and its
+	// subcategories towards the goal
+	CategoryID *uuid.UUID `json:"categoryId,omitempty" example:"550e8400-e29b-41d4-a716-446655440001"`
+	// TagID counts the transactions with the tag towards the goal
+	TagID *uuid.UUID `json:"tagId,omitempty" example:"550e8400-e29b-41d4-a716-446655440002"`
+	// NetWorthItemID links the asset holding the savings; its latest
+	// valuation counts towards the goal
+	NetWorthItemID *uuid.UUID `json:"netWorthItemId,omitempty" example:"550e8400-e29b-41d4-a716-446655440003"`
+}
+
+type UpdateGoalRequest struct {
+	Name         *string  `json:"name,omitempty" example:"Emergency fund" validate:"omitempty,min=1,max=100"`
+	TargetAmount *float64 `json:"targetAmount,omitempty" example:"6000" validate:"omitempty,gt=0"`
+	StartDate    *string  `json:"startDate,omitempty" example:"2024-01-01" validate:"omitempty,datetime=2006-01-02"`
+	// Deadline is a date, or an empty string to remove it
+	Deadline *string `json:"deadline,omitempty" example:"2024-12-31" validate:"omitempty,datetime=2006-01-02"`
+	// CategoryID, TagID and NetWorthItemID change the links; null removes them
+	CategoryID     OptionalUUID `json:"categoryId,omitempty" swaggertype:"string" example:"550e8400-e29b-41d4-a716-446655440001"`
+	TagID          OptionalUUID `json:"tagId,omitempty" swaggertype:"string" example:"550e8400-e29b-41d4-a716-446655440002"`
+	NetWorthItemID OptionalUUID `json:"netWorthItemId,omitempty" swaggertype:"string" example:"550e8400-e29b-41d4-a716-446655440003"`
+}
+
+// CreateGoalContributionRequest records money put towards a goal by hand; a
+// negative amount records a withdrawal
+type CreateGoalContributionRequest struct {
+	Amount float64 `json:"amount" example:"250" validate:"required"`
+	// ContributedOn defaults to today
+	ContributedOn *string `json:"contributedOn,omitempty" example:"2024-03-01" validate:"omitempty,datetime=2006-01-02"`
+	Note          *string `json:"note,omitempty" example:"March savings" validate:"omitempty,max=500"`
+}
+
+type GoalResponse struct {
+	ID             string               `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name           string               `json:"name" example:"Emergency fund"`
+	TargetAmount   float64              `json:"targetAmount" example:"5000"`
+	StartDate      string               `json:"startDate" example:"2024-01-01"`
+	Deadline       *string              `json:"deadline,omitempty" example:"2024-12-31"`
+	CategoryID     *string              `json:"categoryId,omitempty" example:"550e8400-e29b-41d4-a716-446655440001"`
+	TagID          *string              `json:"tagId,omitempty" example:"550e8400-e29b-41d4-a716-446655440002"`
+	NetWorthItemID *string              `json:"netWorthItemId,omitempty" example:"550e8400-e29b-41d4-a716-446655440003"`
+	Progress       GoalProgressResponse `json:"progress"`
+	CreatedAt      string               `json:"createdAt" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt      string               `json:"updatedAt" example:"2024-01-01T00:00:00Z"`
+}
+
+// GoalProgressResponse is how far a goal is, as of today
+type GoalProgressResponse struct {
+	// Saved is the sum of Contributed, FromTransactions and FromAccount
+	Saved       float64 `json:"saved" example:"2150"`
+	Contributed float64 `json:"contributed" example:"400"`
+	// FromTransactions nets the transactions of the goal's category and tag:
+	// expenses put money aside and add to it, income withdraws and subtracts
+	FromTransactions float64 `json:"fromTransactions" example:"750"`
+	FromAccount      float64 `json:"fromAccount" example:"1000"`
+	Remaining        float64 `json:"remaining" example:"2850"`
+	Percent          float64 `json:"percent" example:"43"`
+	// ExpectedByNow is what a steady pace from the start date would have
+	// saved by today; only set with a deadline
+	ExpectedByNow *float64 `json:"expectedByNow,omitempty" example:"3750"`
+	// MonthsLeft counts the current month as one; only set with a deadline
+	MonthsLeft *int `json:"monthsLeft,omitempty" example:"3"`
+	// RequiredMonthly is what has to be saved each remaining month to reach
+	// the target by the deadline; only set with a deadline
+	RequiredMonthly *float64 `json:"requiredMonthly,omitempty" example:"950"`
+	// Status is achieved, on_track, behind, overdue or no_deadline
+	Status string `json:"status" example:"behind"`
+}
+
+// GoalContributionResponse is a manual contribution or a transaction that
+// counts towards a goal
+type GoalContributionResponse struct {
+	// Source is manual or transaction
+	Source string `json:"source" example:"manual"`
+	ID     string `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	// Amount is negative for withdrawals, income transactions included
+	Amount        float64 `json:"amount" example:"250"`
+	ContributedOn string  `json:"contributedOn" example:"2024-03-01"`
+	// Note is the note of a manual contribution or the description of a
+	// transaction
+	Note *string `json:"note,omitempty" example:"March savings"`
+}
+
+type GoalAlertResponse struct {
+	ID          string `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	AlertType   string `json:"alertType" example:"goal_behind"`
+	Message     string `json:"message" example:"Emergency fund is behind pace: 2150.00 saved, 3750.00 expected by now. Saving 950.00 a month reaches 5000.00 by 2024-12-31."`
+	TriggeredAt string `json:"triggeredAt" example:"2024-09-01T06:00:00Z"`
+}
@@ -0,0 +1,100 @@
+package dto
+
+import "github.com/google/uuid"
+
+// CSVMapping describes the layout of a bank CSV export. Column references are
+// header names when hasHeader is true, or 1-based column indexes.
+type CSVMapping struct {
+	Delimiter         string `json:"delimiter" example:";" validate:"omitempty,max=4"`
+	DateFormat        string `json:"dateFormat" example:"DD/MM/YYYY" validate:"required,max=50"`
+	DecimalSeparator  string `json:"decimalSeparator" example:","`
+	HasHeader         bool   `json:"hasHeader" example:"true"`
+	DateColumn        string `json:"dateColumn" example:"Booking date" validate:"required,max=100"`
+	AmountColumn      string `json:"amountColumn,omitempty" example:"Amount" validate:"omitempty,max=100"`
+	DebitColumn       string `json:"debitColumn,omitempty" example:"Debit" validate:"omitempty,max=100"`
+	CreditColumn      string `json:"creditColumn,omitempty" example:"Credit" validate:"omitempty,max=100"`
+	DescriptionColumn string `json:"descriptionColumn,omitempty" example:"Text" validate:"omitempty,max=100"`
+	TypeColumn        string `json:"typeColumn,omitempty" example:"DR/CR" validate:"omitempty,max=100"`
+	CategoryColumn    string `json:"categoryColumn,omitempty" example:"Category" validate:"omitempty,max=100"`
+}
+
+// CSVImportRequest is sent as the JSON "request" field of the multipart upload
+type CSVImportRequest struct {
+	ProfileID *uuid.UUID  `json:"profileId,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Mapping   *CSVMapping `json:"mapping,omitempty"`
+	// SaveProfileAs saves the mapping under this name once the import is
+	// committed; previews save nothing
+	SaveProfileAs     *string   `json:"saveProfileAs,omitempty" example:"My bank" validate:"omitempty,min=1,max=100"`
+	DefaultCategoryID uuid.UUID `json:"defaultCategoryId" example:"550e8400-e29b-41d4-a716-446655440001" validate:"required"`
+	DryRun            *bool     `json:"dryRun,omitempty" example:"true"`
+	SkipInvalid       bool      `json:"skipInvalid" example:"false"`
+}
+
+// StatementImportRequest is sent as the JSON "request" field when uploading
+// an OFX, QFX, QIF, camt.053 or MT940 statement
+type StatementImportRequest struct {
+	DefaultCategoryID uuid.UUID `json:"defaultCategoryId" example:"550e8400-e29b-41d4-a716-446655440001" validate:"required"`
+	DryRun            *bool     `json:"dryRun,omitempty" example:"true"`
+	SkipInvalid       bool      `json:"skipInvalid" example:"false"`
+	// DateOrder is only used by QIF, whose dates do not state their field order
+	DateOrder string `json:"dateOrder,omitempty" example:"MDY" validate:"omitempty,oneof=MDY DMY YMD"`
+}
+
+// ImportRowResponse is the preview of a single statement entry after the
+// categorization rules have run
+type ImportRowResponse struct {
+	Row         int      `json:"row" example:"2"`
+	Date        string   `json:"date,omitempty" example:"2024-01-15"`
+	Amount      float64  `json:"amount" example:"42.50"`
+	Type        string   `json:"type,omitempty" example:"EXPENSE"`
+	Description *string  `json:"description,omitempty" example:"CARD PAYMENT SUPERMARKET"`
+	CategoryID  string   `json:"categoryId,omitempty" example:"550e8400-e29b-41d4-a716-446655440001"`
+	TagIDs      []string `json:"tagIds,omitempty"`
+	// RuleIDs lists the categorization rules that matched the entry
+	RuleIDs    []string `json:"ruleIds,omitempty"`
+	ExternalID string   `json:"externalId,omitempty" example:"20240115-0001"`
+	Duplicate  bool     `json:"duplicate" example:"false"`
+	// DuplicateOf is the existing transaction a duplicate row matched, unless
+	// it only matched on the bank ID
+	DuplicateOf string   `json:"duplicateOf,omitempty" example:"550e8400-e29b-41d4-a716-446655440002"`
+	Errors      []string `json:"errors,omitempty"`
+}
+
+// ImportResultResponse summarizes a dry-run preview or a committed import
+type ImportResultResponse struct {
+	DryRun        bool    `json:"dryRun" example:"true"`
+	ProfileID     *string `json:"profileId,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	AccountID     string  `json:"accountId,omitempty" example:"987654321"`
+	TotalRows     int     `json:"totalRows" example:"120"`
+	ValidRows     int     `json:"validRows" example:"118"`
+	InvalidRows   int     `json:"invalidRows" example:"2"`
+	DuplicateRows int     `json:"duplicateRows" example:"5"`
+	ImportedRows  int     `json:"importedRows" example:"0"`
+	// BalanceCheck is only set for formats reporting opening and closing balances
+	BalanceCheck *BalanceCheckResponse `json:"balanceCheck,omitempty"`
+	Rows         []ImportRowResponse   `json:"rows"`
+}
+
+// BalanceCheckResponse compares the closing balance reported by the bank with
+// the one computed from the opening balance and the statement entries, and
+// the opening balance with the one stored for the account
+type BalanceCheckResponse struct {
+	Currency               string  `json:"currency,omitempty" example:"EUR"`
+	OpeningBalance         float64 `json:"openingBalance" example:"1000.00"`
+	OpeningDate            string  `json:"openingDate" example:"2024-01-01"`
+	ClosingBalance         float64 `json:"closingBalance" example:"1049.75"`
+	ClosingDate            string  `json:"closingDate" example:"2024-01-31"`
+	ComputedClosingBalance float64 `json:"computedClosingBalance" example:"1049.75"`
+	// Difference is the reported minus the computed closing balance
+	Difference float64 `json:"difference" example:"0"`
+	Matches    bool    `json:"matches" example:"true"`
+	// StoredOpeningBalance is the balance of the account computed from the
+	// transactions already imported for it and dated before the statement.
+	// It is only set when the account has such transactions.
+	StoredOpeningBalance *float64 `json:"storedOpeningBalance,omitempty" example:"1000.00"`
+	// OpeningDifference is the reported opening minus the stored balance; a
+	// difference means statements are missing in between or were imported
+	// only in part
+	OpeningDifference *float64 `json:"openingDifference,omitempty" example:"0"`
+	OpeningMatches    *bool    `json:"openingMatches,omitempty" example:"true"`
+}
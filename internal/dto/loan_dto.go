@@ -0,0 +1,117 @@
+package dto
+
+import "github.com/google/uuid"
+
+type CreateLoanRequest struct {
+	Name string `json:"name" example:"Car loan" validate:"required,min=1,max=100"`
+	// Direction is BORROWED for money owed by the user or LENT for money
+	// owed to the user
+	Direction    string  `json:"direction" example:"BORROWED" validate:"required,oneof=BORROWED LENT"`
+	Counterparty *string `json:"counterparty,omitempty" example:"City Bank" validate:"omitempty,max=100"`
+	Principal    float64 `json:"principal" example:"12000" validate:"gt=0"`
+	// InterestRate is the nominal annual rate in percent
+	InterestRate float64 `json:"interestRate" example:"6.5" validate:"gte=0,lt=1000"`
+	TermMonths   int     `json:"termMonths" example:"48" validate:"required,min=1,max=600"`
+	// Compounding is DAILY, MONTHLY or ANNUALLY; defaults to MONTHLY
+	Compounding *string `json:"compounding,omitempty" example:"MONTHLY" validate:"omitempty,oneof=DAILY MONTHLY ANNUALLY"`
+	// StartDate is when the money was paid out; the first instalment is due a
+	// month later. Defaults to today.
+	StartDate *string `json:"startDate,omitempty" example:"2024-01-15" validate:"omitempty,datetime=2006-01-02"`
+	// CategoryID is the default category of the payment transactions
+	CategoryID *uuid.UUID `json:"categoryId,omitempty" example:"550e8400-e29b-41d4-a716-446655440001"`
+}
+
+// UpdateLoanRequest changes a loan. The direction is fixed, since it decides
+// the type of the existing payment transactions.
+type UpdateLoanRequest struct {
+	Name *string `json:"name,omitempty" example:"Car loan" validate:"omitempty,min=1,max=100"`
+	// Counterparty is a name, or an empty string to remove it
+	Counterparty *string  `json:"counterparty,omitempty" example:"City Bank" validate:"omitempty,max=100"`
+	Principal    *float64 `json:"principal,omitempty" example:"12000" validate:"omitempty,gt=0"`
+	InterestRate *float64 `json:"interestRate,omitempty" example:"5.9" validate:"omitempty,gte=0,lt=1000"`
+	TermMonths   *int     `json:"termMonths,omitempty" example:"60" validate:"omitempty,min=1,max=600"`
+	Compounding  *string  `json:"compounding,omitempty" example:"DAILY" validate:"omitempty,oneof=DAILY MONTHLY ANNUALLY"`
+	StartDate    *string  `json:"startDate,omitempty" example:"2024-01-15" validate:"omitempty,datetime=2006-01-02"`
+	// CategoryID changes the default payment category; null removes it
+	CategoryID OptionalUUID `json:"categoryId,omitempty" swaggertype:"string" example:"550e8400-e29b-41d4-a716-446655440001"`
+}
+
+// CreateLoanPaymentRequest records a repayment. It is booked as an EXPENSE
+// transaction on a borrowed loan and as an INCOME transaction on a lent one.
+type CreateLoanPaymentRequest struct {
+	Amount float64 `json:"amount" example:"284.58" validate:"gt=0"`
+	// PaidOn defaults to today
+	PaidOn *string `json:"paidOn,omitempty" example:"2024-02-15" validate:"omitempty,datetime=2006-01-02"`
+	// CategoryID defaults to the category of the loan
+	CategoryID  *uuid.UUID `json:"categoryId,omitempty" example:"550e8400-e29b-41d4-a716-446655440001"`
+	Description *string    `json:"description,omitempty" example:"February instalment" validate:"omitempty,max=500"`
+}
+
+type LoanResponse struct {
+	ID           string  `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name         string  `json:"name" example:"Car loan"`
+	Direction    string  `json:"direction" example:"BORROWED"`
+	Counterparty *string `json:"counterparty,omitempty" example:"City Bank"`
+	Principal    float64 `json:"principal" example:"12000"`
+	InterestRate float64 `json:"interestRate" example:"6.5"`
+	TermMonths   int     `json:"termMonths" example:"48"`
+	Compounding  string  `json:"compounding" example:"MONTHLY"`
+	StartDate    string  `json:"startDate" example:"2024-01-15"`
+	// EndDate is when the last scheduled instalment is due
+	EndDate    string  `json:"endDate" example:"2028-01-15"`
+	CategoryID *string `json:"categoryId,omitempty" example:"550e8400-e29b-41d4-a716-446655440001"`
+	// MonthlyPayment is the scheduled instalment
+	MonthlyPayment float64             `json:"monthlyPayment" example:"284.58"`
+	Balance        LoanBalanceResponse `json:"balance"`
+	CreatedAt      string              `json:"createdAt" example:"2024-01-15T00:00:00Z"`
+	UpdatedAt      string              `json:"updatedAt" example:"2024-01-15T00:00:00Z"`
+}
+
+// LoanBalanceResponse is what is left of a loan, as of today
+type LoanBalanceResponse struct {
+	PaymentsMade  int     `json:"paymentsMade" example:"6"`
+	PrincipalPaid float64 `json:"principalPaid" example:"1404.12"`
+	InterestPaid  float64 `json:"interestPaid" example:"303.36"`
+	// RemainingPrincipal is the principal not repaid yet
+	RemainingPrincipal float64 `json:"remainingPrincipal" example:"10595.88"`
+	// AccruedInterest is the interest owed since the last payment together
+	// with any interest earlier payments did not cover
+	AccruedInterest float64 `json:"accruedInterest" example:"21.14"`
+	// RemainingBalance is what it takes to settle the loan today
+	RemainingBalance float64 `json:"remainingBalance" example:"10617.02"`
+	// Status is active or paid_off
+	Status string `json:"status" example:"active"`
+}
+
+// LoanScheduleResponse is the amortization schedule of a loan as agreed,
+// regardless of the payments actually made
+type LoanScheduleResponse struct {
+	MonthlyPayment float64                  `json:"monthlyPayment" example:"284.58"`
+	TotalPayment   float64                  `json:"totalPayment" example:"13659.81"`
+	TotalInterest  float64                  `json:"totalInterest" example:"1659.81"`
+	Instalments    []LoanInstalmentResponse `json:"instalments"`
+}
+
+type LoanInstalmentResponse struct {
+	Number    int     `json:"number" example:"1"`
+	DueDate   string  `json:"dueDate" example:"2024-02-15"`
+	Payment   float64 `json:"payment" example:"284.58"`
+	Principal float64 `json:"principal" example:"219.58"`
+	Interest  float64 `json:"interest" example:"65"`
+	// Balance is the principal left after the instalment
+	Balance float64 `json:"balance" example:"11780.42"`
+}
+
+// LoanPaymentResponse is a recorded payment with its share of principal and
+// interest
+type LoanPaymentResponse struct {
+	ID            string  `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	TransactionID string  `json:"transactionId" example:"550e8400-e29b-41d4-a716-446655440004"`
+	Amount        float64 `json:"amount" example:"284.58"`
+	PaidOn        string  `json:"paidOn" example:"2024-02-15"`
+	Description   *string `json:"description,omitempty" example:"February instalment"`
+	Principal     float64 `json:"principal" example:"219.58"`
+	Interest      float64 `json:"interest" example:"65"`
+	// Balance is the principal left after the payment
+	Balance float64 `json:"balance" example:"11780.42"`
+}
@@ -0,0 +1,45 @@
+package dto
+
+type CreateNetWorthItemRequest struct {
+	Name        string  `json:"name" example:"Family home" validate:"required,min=1,max=100"`
+	Kind        string  `json:"kind" example:"ASSET" validate:"required,oneof=ASSET LIABILITY"`
+	Description *string `json:"description,omitempty" example:"Bought in 2019"`
+	// Value optionally records a first valuation dated ValuedAt, or today
+	Value    *float64 `json:"value,omitempty" example:"350000" validate:"omitempty,gte=0"`
+	ValuedAt *string  `json:"valuedAt,omitempty" example:"2024-01-01" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type UpdateNetWorthItemRequest struct {
+	Name        *string `json:"name,omitempty" example:"Family home" validate:"omitempty,min=1,max=100"`
+	Description *string `json:"description,omitempty" example:"Bought in 2019"`
+}
+
+// CreateValuationRequest records the value of an item on a date. Liabilities
+// are valued with the positive amount owed. A second valuation on the same
+// date replaces the first.
+type CreateValuationRequest struct {
+	Value    float64 `json:"value" example:"355000" validate:"gte=0"`
+	ValuedAt string  `json:"valuedAt" example:"2024-06-30" validate:"required,datetime=2006-01-02"`
+	Note     *string `json:"note,omitempty" example:"Agent estimate"`
+}
+
+type NetWorthItemResponse struct {
+	ID          string  `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name        string  `json:"name" example:"Family home"`
+	Kind        string  `json:"kind" example:"ASSET"`
+	Description *string `json:"description,omitempty" example:"Bought in 2019"`
+	// CurrentValue and ValuedAt come from the most recent valuation
+	CurrentValue *float64 `json:"currentValue,omitempty" example:"355000"`
+	ValuedAt     *string  `json:"valuedAt,omitempty" example:"2024-06-30"`
+	CreatedAt    string   `json:"createdAt" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt    string   `json:"updatedAt" example:"2024-01-01T00:00:00Z"`
+}
+
+type ValuationResponse struct {
+	ID        string  `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	ItemID    string  `json:"itemId" example:"550e8400-e29b-41d4-a716-446655440001"`
+	Value     float64 `json:"value" example:"355000"`
+	ValuedAt  string  `json:"valuedAt" example:"2024-06-30"`
+	Note      *string `json:"note,omitempty" example:"Agent estimate"`
+	CreatedAt string  `json:"createdAt" example:"2024-06-30T10:00:00Z"`
+}
@@ -25,6 +25,8 @@ type ListTransactionRequest struct {
 	EndDate    *string `json:"endDate,omitempty" example:"2024-12-31"`
 	MinAmount  *string `json:"minAmount,omitempty" example:"0"`
 	MaxAmount  *string `json:"maxAmount,omitempty" example:"1000"`
+	Tags       *string `json:"tags,omitempty" example:"550e8400-e29b-41d4-a716-446655440000,550e8400-e29b-41d4-a716-446655440001"`
+	TagMode    *string `json:"tagMode,omitempty" example:"any" validate:"omitempty,oneof=any all"`
 }
 
 // ListTransactionResponse represents the response for listing transactions
@@ -54,6 +56,8 @@ type ListCostRequest struct {
 	EndDate    *string `json:"endDate,omitempty" example:"2024-12-31"`
 	MinAmount  *string `json:"minAmount,omitempty" example:"0"`
 	MaxAmount  *string `json:"maxAmount,omitempty" example:"1000"`
+	Tags       *string `json:"tags,omitempty" example:"550e8400-e29b-41d4-a716-446655440000,550e8400-e29b-41d4-a716-446655440001"`
+	TagMode    *string `json:"tagMode,omitempty" example:"any" validate:"omitempty,oneof=any all"`
 }
 
 // ListCostResponse represents the response for listing costs
@@ -0,0 +1,207 @@
+package dto
+
+// SummaryReportResponse holds income and expense totals per period
+type SummaryReportResponse struct {
+	From     string `json:"from" example:"2024-01-01"`
+	To       string `json:"to" example:"2024-12-31"`
+	GroupBy  string `json:"groupBy" example:"month"`
+	TimeZone string `json:"timeZone" example:"Europe/Berlin"`
+	// Totals covers the whole range
+	Totals SummaryTotalsResponse `json:"totals"`
+	// Buckets lists every period of the range in order, including periods
+	// without activity
+	Buckets []SummaryBucketResponse `json:"buckets"`
+}
+
+// SummaryTotalsResponse sums income and expenses
+type SummaryTotalsResponse struct {
+	Income           float64 `json:"income" example:"3200"`
+	Expense          float64 `json:"expense" example:"2450.75"`
+	Net              float64 `json:"net" example:"749.25"`
+	TransactionCount int64   `json:"transactionCount" example:"58"`
+}
+
+// SummaryBucketResponse sums income and expenses of a single period
+type SummaryBucketResponse struct {
+	// Period names the bucket, e.g. 2024, 2024-03, 2024-W10 or 2024-03-05
+	Period string `json:"period" example:"2024-03"`
+	// Start and End are the first and last day of the period, inclusive
+	Start string `json:"start" example:"2024-03-01"`
+	End   string `json:"end" example:"2024-03-31"`
+	// StartTime is the beginning of the period in the requested time zone
+	StartTime        string  `json:"startTime" example:"2024-03-01T00:00:00+01:00"`
+	Income           float64 `json:"income" example:"3200"`
+	Expense          float64 `json:"expense" example:"2450.75"`
+	Net              float64 `json:"net" example:"749.25"`
+	TransactionCount int64   `json:"transactionCount" example:"58"`
+}
+
+// CategoryReportResponse breaks the activity of a period down by category
+// and compares it with the previous period of the same length
+type CategoryReportResponse struct {
+	// Type is EXPENSE or INCOME; expense reports include costs
+	Type string `json:"type" example:"EXPENSE"`
+	// RollUp reports whether subcategories are added to their top-level category
+	RollUp   bool   `json:"rollUp" example:"false"`
+	From     string `json:"from" example:"2024-03-01"`
+	To       string `json:"to" example:"2024-03-31"`
+	TimeZone string `json:"timeZone" example:"Europe/Berlin"`
+	// PreviousFrom and PreviousTo are the period compared against: the same
+	// number of calendar months for whole months, otherwise of days, directly
+	// before From
+	PreviousFrom  string                  `json:"previousFrom" example:"2024-02-01"`
+	PreviousTo    string                  `json:"previousTo" example:"2024-02-29"`
+	Total         float64                 `json:"total" example:"2450.75"`
+	PreviousTotal float64                 `json:"previousTotal" example:"2100"`
+	Change        ChangeResponse          `json:"change"`
+	Categories    []CategoryShareResponse `json:"categories"`
+}
+
+// CategoryShareResponse is one category's part of a category report. Categories
+// with activity only in the previous period are listed with a zero total.
+type CategoryShareResponse struct {
+	CategoryID string `json:"categoryId" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name       string `json:"name" example:"Dining"`
+	// ParentID is set for subcategories unless the report is rolled up
+	ParentID         *string `json:"parentId,omitempty" example:"550e8400-e29b-41d4-a716-446655440001"`
+	Total            float64 `json:"total" example:"390"`
+	TransactionTotal float64 `json:"transactionTotal" example:"340"`
+	CostTotal        float64 `json:"costTotal" example:"50"`
+	Count            int64   `json:"count" example:"14"`
+	// Share is the percentage of the report total
+	Share         float64        `json:"share" example:"15.91"`
+	PreviousTotal float64        `json:"previousTotal" example:"300"`
+	Change        ChangeResponse `json:"change"`
+}
+
+// ChangeResponse compares a value with the value of the previous period
+type ChangeResponse struct {
+	Delta float64 `json:"delta" example:"90"`
+	// Percent is omitted when the previous value is zero
+	Percent *float64 `json:"percent,omitempty" example:"30"`
+}
+
+// ForecastResponse projects the daily balance of the coming days
+type ForecastResponse struct {
+	// AsOf is today in the requested time zone; the series starts the day after
+	AsOf         string `json:"asOf" example:"2024-03-10"`
+	Days         int    `json:"days" example:"30"`
+	LookbackDays int    `json:"lookbackDays" example:"90"`
+	TimeZone     string `json:"timeZone" example:"Europe/Berlin"`
+	// StartingBalance is the provided balance or, without one, income minus
+	// expenses of all transactions up to today
+	StartingBalance float64 `json:"startingBalance" example:"1520.4"`
+	BalanceSource   string  `json:"balanceSource" example:"transactions" enums:"transactions,provided"`
+	EndBalance      float64 `json:"endBalance" example:"830.1"`
+	// LowestBalance is the lowest expected balance of the series
+	LowestBalance     float64 `json:"lowestBalance" example:"-120.5"`
+	LowestBalanceDate string  `json:"lowestBalanceDate" example:"2024-03-31"`
+	// ShortfallDate is the first day the expected balance drops below zero
+	ShortfallDate *string `json:"shortfallDate,omitempty" example:"2024-03-29"`
+	// Confidence is the probability the balance stays within a point's bounds
+	Confidence float64 `json:"confidence" example:"0.8"`
+	// DailyDiscretionary is the expected spending per day outside recurring items
+	DailyDiscretionary float64                     `json:"dailyDiscretionary" example:"41.3"`
+	Recurring          []ForecastRecurringResponse `json:"recurring"`
+	Baseline           []ForecastBaselineResponse  `json:"baseline"`
+	Series             []ForecastPointResponse     `json:"series"`
+}
+
+// ForecastRecurringResponse is a transaction detected in the history that
+// repeats at a regular cadence
+type ForecastRecurringResponse struct {
+	Type         string  `json:"type" example:"EXPENSE"`
+	CategoryID   string  `json:"categoryId" example:"550e8400-e29b-41d4-a716-446655440000"`
+	CategoryName string  `json:"categoryName" example:"Housing"`
+	Description  string  `json:"description" example:"Rent"`
+	Amount       float64 `json:"amount" example:"950"`
+	Cadence      string  `json:"cadence" example:"monthly" enums:"weekly,biweekly,monthly,quarterly,yearly"`
+	// DayOfMonth is not set for weekly and biweekly transactions, which
+	// repeat every 7 or 14 days after LastDate
+	DayOfMonth  int    `json:"dayOfMonth,omitempty" example:"1"`
+	Occurrences int    `json:"occurrences" example:"6"`
+	LastDate    string `json:"lastDate" example:"2024-03-01"`
+}
+
+// ForecastBaselineResponse is the average daily discretionary spending of a category
+type ForecastBaselineResponse struct {
+	CategoryID   string  `json:"categoryId" example:"550e8400-e29b-41d4-a716-446655440000"`
+	CategoryName string  `json:"categoryName" example:"Dining"`
+	DailyAverage float64 `json:"dailyAverage" example:"12.4"`
+}
+
+// ForecastPointResponse is the projected balance at the end of a day
+type ForecastPointResponse struct {
+	Date    string  `json:"date" example:"2024-03-11"`
+	Balance float64 `json:"balance" example:"1479.1"`
+	Lower   float64 `json:"lower" example:"1402.7"`
+	Upper   float64 `json:"upper" example:"1555.5"`
+	// Income and Expense are the recurring items due on the day
+	Income        float64 `json:"income" example:"0"`
+	Expense       float64 `json:"expense" example:"0"`
+	Discretionary float64 `json:"discretionary" example:"41.3"`
+}
+
+// NetWorthReportResponse tracks net worth over time
+type NetWorthReportResponse struct {
+	From     string `json:"from" example:"2024-01-01"`
+	To       string `json:"to" example:"2024-12-31"`
+	GroupBy  string `json:"groupBy" example:"month"`
+	TimeZone string `json:"timeZone" example:"Europe/Berlin"`
+	// Current is the net worth at the end of the range
+	Current NetWorthPointResponse `json:"current"`
+	// Change compares Current with the first point of the series
+	Change ChangeResponse `json:"change"`
+	// Items lists every asset and liability with its value at the end of the range
+	Items []NetWorthItemValueResponse `json:"items"`
+	// Series holds the net worth at the end of each period
+	Series []NetWorthPointResponse `json:"series"`
+}
+
+// NetWorthPointResponse is the net worth on one day. Cash is the net of all
+// transactions up to that day; assets and liabilities carry their most
+// recent valuation forward.
+type NetWorthPointResponse struct {
+	Date        string  `json:"date" example:"2024-03-31"`
+	Cash        float64 `json:"cash" example:"8200"`
+	Assets      float64 `json:"assets" example:"370000"`
+	Liabilities float64 `json:"liabilities" example:"240000"`
+	NetWorth    float64 `json:"netWorth" example:"138200"`
+}
+
+// NetWorthItemValueResponse is the value of an asset or liability on a day
+type NetWorthItemValueResponse struct {
+	ID   string `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name string `json:"name" example:"Mortgage"`
+	Kind string `json:"kind" example:"LIABILITY"`
+	// Value is omitted for items not valued by then
+	Value    *float64 `json:"value,omitempty" example:"240000"`
+	ValuedAt *string  `json:"valuedAt,omitempty" example:"2024-03-01"`
+}
+
+// BudgetReportResponse compares the user's budgets with the spending of
+// their current period
+type BudgetReportResponse struct {
+	AsOf     string                 `json:"asOf" example:"2024-03-10"`
+	TimeZone string                 `json:"timeZone" example:"Europe/Berlin"`
+	Budgets  []BudgetStatusResponse `json:"budgets"`
+}
+
+// BudgetStatusResponse is the spending against one budget. A budget covers
+// its category and all of its subcategories.
+type BudgetStatusResponse struct {
+	BudgetID     string `json:"budgetId" example:"550e8400-e29b-41d4-a716-446655440000"`
+	CategoryID   string `json:"categoryId" example:"550e8400-e29b-41d4-a716-446655440001"`
+	CategoryName string `json:"categoryName" example:"Food"`
+	// Subcategories is the number of descendants whose spending is included
+	Subcategories int     `json:"subcategories" example:"2"`
+	PeriodType    string  `json:"periodType" example:"monthly"`
+	PeriodStart   string  `json:"periodStart" example:"2024-03-01"`
+	PeriodEnd     string  `json:"periodEnd" example:"2024-03-31"`
+	Amount        float64 `json:"amount" example:"500"`
+	Spent         float64 `json:"spent" example:"412.3"`
+	Remaining     float64 `json:"remaining" example:"87.7"`
+	PercentUsed   float64 `json:"percentUsed" example:"82.46"`
+	// Status is ok, approaching_limit from 80% or over_limit
+	Status string `json:"status" example:"approaching_limit" enums:"ok,approaching_limit,over_limit"`
+}
@@ -0,0 +1,26 @@
+package dto
+
+import "github.com/google/uuid"
+
+// SearchResultResponse is a transaction or cost matching a search
+type SearchResultResponse struct {
+	// Kind is transaction or cost
+	Kind   string    `json:"kind" example:"transaction"`
+	ID     uuid.UUID `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Date   string    `json:"date" example:"2024-03-12T00:00:00Z"`
+	Amount float64   `json:"amount" example:"59.99"`
+	// Currency is only set for costs
+	Currency *string `json:"currency,omitempty" example:"EUR"`
+	// Type is only set for transactions
+	Type *string `json:"type,omitempty" example:"EXPENSE"`
+	// Text is the transaction description or the cost title
+	Text *string `json:"text,omitempty" example:"AMAZON EU S.A R.L. order 302-1234567"`
+	// Highlight is the text with the matched words wrapped in <mark> tags
+	Highlight    string        `json:"highlight" example:"<mark>AMAZON</mark> EU S.A R.L. order 302-1234567"`
+	CategoryID   uuid.UUID     `json:"categoryId" example:"550e8400-e29b-41d4-a716-446655440001"`
+	CategoryName string        `json:"categoryName" example:"Shopping"`
+	Tags         []TagResponse `json:"tags,omitempty"`
+	// MatchedOn lists where the words were found: text, category and tag
+	MatchedOn []string `json:"matchedOn,omitempty" example:"text"`
+	Rank      float64  `json:"rank" example:"0.1216"`
+}
@@ -0,0 +1,48 @@
+package dto
+
+import "github.com/google/uuid"
+
+// SubscriptionsResponse lists the subscriptions detected in the expenses
+type SubscriptionsResponse struct {
+	// MonthlyTotal and AnnualTotal add up the annual cost of the
+	// subscriptions that are not dismissed
+	MonthlyTotal  float64                `json:"monthlyTotal" example:"27.98"`
+	AnnualTotal   float64                `json:"annualTotal" example:"335.76"`
+	Subscriptions []SubscriptionResponse `json:"subscriptions"`
+}
+
+// SubscriptionResponse is a merchant charged about the same amount at a
+// regular cadence
+type SubscriptionResponse struct {
+	// ID identifies the detection; it stays the same as new charges arrive
+	ID           string `json:"id" example:"5b1d3c7e-8f2a-5e4b-9c6d-1a2b3c4d5e6f"`
+	Description  string `json:"description" example:"Netflix"`
+	CategoryID   string `json:"categoryId" example:"550e8400-e29b-41d4-a716-446655440001"`
+	CategoryName string `json:"categoryName" example:"Entertainment"`
+	// Amount is the median charge
+	Amount  float64 `json:"amount" example:"15.99"`
+	Cadence string  `json:"cadence" example:"monthly" enums:"weekly,biweekly,monthly,quarterly,yearly"`
+	// DayOfMonth is not set for weekly and biweekly subscriptions
+	DayOfMonth     int    `json:"dayOfMonth,omitempty" example:"12"`
+	Occurrences    int    `json:"occurrences" example:"6"`
+	LastChargeDate string `json:"lastChargeDate" example:"2024-03-12"`
+	// NextChargeDate is the estimated date of the next charge, today or later
+	NextChargeDate string `json:"nextChargeDate" example:"2024-04-12"`
+	// AnnualCost is Amount times the charges in a year
+	AnnualCost float64 `json:"annualCost" example:"191.88"`
+	// Status is detected, confirmed or dismissed
+	Status string `json:"status" example:"detected"`
+	// BillID is the bill a confirmed subscription was turned into
+	BillID *string `json:"billId,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+}
+
+// ConfirmSubscriptionRequest turns a detected subscription into a bill of
+// the same cadence that starts with the next charge
+type ConfirmSubscriptionRequest struct {
+	// Payee defaults to the description of the charges
+	Payee *string `json:"payee,omitempty" example:"Netflix" validate:"omitempty,min=1,max=100"`
+	// RemindDaysBefore defaults to 3
+	RemindDaysBefore *int `json:"remindDaysBefore,omitempty" example:"3" validate:"omitempty,min=0,max=60"`
+	// CategoryID defaults to the category of the charges
+	CategoryID *uuid.UUID `json:"categoryId,omitempty" example:"550e8400-e29b-41d4-a716-446655440001"`
+}
@@ -0,0 +1,38 @@
+package dto
+
+import "github.com/google/uuid"
+
+type CreateTagRequest struct {
+	Name  string  `json:"name" example:"vacation-2026" validate:"required,min=1,max=50"`
+	Color *string `json:"color,omitempty" example:"#FF8800" validate:"omitempty,hexcolor"`
+}
+
+type UpdateTagRequest struct {
+	Name  *string `json:"name,omitempty" example:"tax-deductible" validate:"omitempty,min=1,max=50"`
+	Color *string `json:"color,omitempty" example:"#00AA55" validate:"omitempty,hexcolor"`
+}
+
+// MergeTagRequest moves every link of the source tag onto the target tag
+type MergeTagRequest struct {
+	TargetID uuid.UUID `json:"targetId" example:"550e8400-e29b-41d4-a716-446655440000" validate:"required"`
+}
+
+type TagResponse struct {
+	ID        string  `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	UserID    string  `json:"userId" example:"550e8400-e29b-41d4-a716-446655440001"`
+	Name      string  `json:"name" example:"vacation-2026"`
+	Color     *string `json:"color,omitempty" example:"#FF8800"`
+	CreatedAt string  `json:"createdAt" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt string  `json:"updatedAt" example:"2024-01-01T00:00:00Z"`
+}
+
+// TagTotalResponse holds the aggregated amounts linked to a single tag
+type TagTotalResponse struct {
+	TagID            string  `json:"tagId" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name             string  `json:"name" example:"vacation-2026"`
+	Income           float64 `json:"income" example:"0"`
+	Expense          float64 `json:"expense" example:"1250.40"`
+	TransactionCount int64   `json:"transactionCount" example:"12"`
+	CostTotal        float64 `json:"costTotal" example:"300.00"`
+	CostCount        int64   `json:"costCount" example:"2"`
+}
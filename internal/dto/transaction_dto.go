@@ -7,31 +7,83 @@ import (
 )
 
 type CreateTransactionRequest struct {
-	CategoryID      uuid.UUID `json:"categoryId" example:"550e8400-e29b-41d4-a716-446655440000" validate:"required,uuid"`
-	Amount          float64   `json:"amount" example:"100.50" validate:"required,gt=0"`
-	Type            string    `json:"type" example:"EXPENSE" validate:"required,oneof=INCOME EXPENSE"`
-	Description     *string   `json:"description" example:"Grocery shopping" validate:"omitempty,max=500"`
-	TransactionDate time.Time `json:"transactionDate" example:"2024-01-15T00:00:00Z" validate:"required"`
+	// CategoryID may be left out when a categorization rule assigns one
+	CategoryID      uuid.UUID   `json:"categoryId" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Amount          float64     `json:"amount" example:"100.50" validate:"required,gt=0"`
+	Type            string      `json:"type" example:"EXPENSE" validate:"required,oneof=INCOME EXPENSE"`
+	Description     *string     `json:"description" example:"Grocery shopping" validate:"omitempty,max=500"`
+	TransactionDate time.Time   `json:"transactionDate" example:"2024-01-15T00:00:00Z" validate:"required"`
+	TagIDs          []uuid.UUID `json:"tagIds,omitempty"`
 }
 
 type UpdateTransactionRequest struct {
-	CategoryID      *uuid.UUID `json:"categoryId,omitempty" example:"550e8400-e29b-41d4-a716-446655440000" validate:"omitempty,uuid"`
-	Amount          *float64   `json:"amount,omitempty" example:"150.00" validate:"omitempty,gt=0"`
-	Type            *string    `json:"type,omitempty" example:"INCOME" validate:"omitempty,oneof=INCOME EXPENSE"`
-	Description     *string    `json:"description,omitempty" example:"Updated description" validate:"omitempty,max=500"`
-	TransactionDate *time.Time `json:"transactionDate,omitempty" example:"2024-01-20T00:00:00Z"`
+	CategoryID      *uuid.UUID  `json:"categoryId,omitempty" example:"550e8400-e29b-41d4-a716-446655440000" validate:"omitempty,uuid"`
+	Amount          *float64    `json:"amount,omitempty" example:"150.00" validate:"omitempty,gt=0"`
+	Type            *string     `json:"type,omitempty" example:"INCOME" validate:"omitempty,oneof=INCOME EXPENSE"`
+	Description     *string     `json:"description,omitempty" example:"Updated description" validate:"omitempty,max=500"`
+	TransactionDate *time.Time  `json:"transactionDate,omitempty" example:"2024-01-20T00:00:00Z"`
+	TagIDs          []uuid.UUID `json:"tagIds,omitempty"`
 }
 
 type TransactionResponse struct {
-	ID              uuid.UUID  `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
-	UserID          uuid.UUID  `json:"userId" example:"550e8400-e29b-41d4-a716-446655440001"`
-	CategoryID      uuid.UUID  `json:"categoryId" example:"550e8400-e29b-41d4-a716-446655440000"`
-	CategoryName    string     `json:"categoryName,omitempty" example:"Food"`
-	Amount          float64    `json:"amount" example:"100.50"`
-	Type            string     `json:"type" example:"EXPENSE"`
-	Description     *string    `json:"description,omitempty" example:"Grocery shopping"`
-	TransactionDate string     `json:"transactionDate" example:"2024-01-15T00:00:00Z"`
-	CreatedAt       string     `json:"createdAt" example:"2024-01-15T00:00:00Z"`
-	UpdatedAt       string     `json:"updatedAt" example:"2024-01-15T00:00:00Z"`
-	DeletedAt       *string    `json:"deletedAt,omitempty" example:"2024-01-20T00:00:00Z"`
+	ID              uuid.UUID     `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	UserID          uuid.UUID     `json:"userId" example:"550e8400-e29b-41d4-a716-446655440001"`
+	CategoryID      uuid.UUID     `json:"categoryId" example:"550e8400-e29b-41d4-a716-446655440000"`
+	CategoryName    string        `json:"categoryName,omitempty" example:"Food"`
+	Amount          float64       `json:"amount" example:"100.50"`
+	Type            string        `json:"type" example:"EXPENSE"`
+	Description     *string       `json:"description,omitempty" example:"Grocery shopping"`
+	AccountID       *string       `json:"accountId,omitempty" example:"DE89370400440532013000"`
+	Tags            []TagResponse `json:"tags,omitempty"`
+	TransactionDate string        `json:"transactionDate" example:"2024-01-15T00:00:00Z"`
+	CreatedAt       string        `json:"createdAt" example:"2024-01-15T00:00:00Z"`
+	UpdatedAt       string        `json:"updatedAt" example:"2024-01-15T00:00:00Z"`
+	DeletedAt       *string       `json:"deletedAt,omitempty" example:"2024-01-20T00:00:00Z"`
+}
+
+// SuggestCategoryRequest describes a transaction to suggest a category for
+type SuggestCategoryRequest struct {
+	Description string   `json:"description" example:"REWE SAGT DANKE" validate:"required,min=1,max=500"`
+	Amount      *float64 `json:"amount,omitempty" example:"42.50" validate:"omitempty,gt=0"`
+	Type        *string  `json:"type,omitempty" example:"EXPENSE" validate:"omitempty,oneof=INCOME EXPENSE"`
+	// Limit is the number of suggestions returned; it defaults to 3
+	Limit int `json:"limit,omitempty" example:"3" validate:"omitempty,min=1,max=10"`
+}
+
+// CategorySuggestionsResponse ranks the user's categories, most likely first
+type CategorySuggestionsResponse struct {
+	// TrainedOn is the number of past transactions the suggestions learned from
+	TrainedOn   int                          `json:"trainedOn" example:"842"`
+	Suggestions []CategorySuggestionResponse `json:"suggestions"`
+}
+
+type CategorySuggestionResponse struct {
+	CategoryID   uuid.UUID `json:"categoryId" example:"550e8400-e29b-41d4-a716-446655440000"`
+	CategoryName string    `json:"categoryName" example:"Food"`
+	// Confidence is the estimated probability of the category, from 0 to 1
+	Confidence float64 `json:"confidence" example:"0.87"`
+}
+
+// DuplicatesResponse lists groups of transactions that look like the same
+// payment: same type and amount, close dates and similar descriptions
+type DuplicatesResponse struct {
+	From       string                   `json:"from" example:"2024-01-01"`
+	To         string                   `json:"to" example:"2024-03-31"`
+	WindowDays int                      `json:"windowDays" example:"3"`
+	Groups     []DuplicateGroupResponse `json:"groups"`
+}
+
+type DuplicateGroupResponse struct {
+	Type   string  `json:"type" example:"EXPENSE"`
+	Amount float64 `json:"amount" example:"42.50"`
+	// SuggestedKeepID is the transaction carrying the most information: a bank
+	// ID first, then the most tags, then the oldest
+	SuggestedKeepID uuid.UUID             `json:"suggestedKeepId" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Transactions    []TransactionResponse `json:"transactions"`
+}
+
+// MergeTransactionsRequest folds duplicates into the transaction of the path.
+// Their tags and attachments move over and they are moved to the trash.
+type MergeTransactionsRequest struct {
+	DuplicateIDs []uuid.UUID `json:"duplicateIds" validate:"required,min=1,max=50"`
 }
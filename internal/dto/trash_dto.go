@@ -0,0 +1,35 @@
+package dto
+
+// TrashItemResponse is a deleted record that can still be restored
+type TrashItemResponse struct {
+	// Type is one of transactions, costs, categories or budgets
+	Type string `json:"type" example:"transactions"`
+	ID   string `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	// Label is the description, title or name shown for the record; budgets
+	// use the name of their category
+	Label      string   `json:"label" example:"Groceries at Aldi"`
+	Amount     *float64 `json:"amount,omitempty" example:"42.5"`
+	CategoryID *string  `json:"categoryId,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	DeletedAt  string   `json:"deletedAt" example:"2024-01-20T08:00:00Z"`
+	// PurgeAt is when the record is permanently deleted
+	PurgeAt string `json:"purgeAt" example:"2024-02-19T08:00:00Z"`
+}
+
+// RestoreTrashRequest is the optional body of a restore. It only applies to
+// categories, whose name may have been reused since they were deleted.
+type RestoreTrashRequest struct {
+	// Name restores the category under a new name
+	Name *string `json:"name,omitempty" example:"Groceries (old)" validate:"omitempty,min=1,max=50"`
+	// OnConflict decides what happens when an active category already has the
+	// same name: fail (the default) rejects the restore, rename restores it
+	// with a numbered name
+	OnConflict string `json:"onConflict,omitempty" example:"rename" validate:"omitempty,oneof=fail rename"`
+}
+
+// RestoredItemResponse identifies a record that was taken out of the trash
+type RestoredItemResponse struct {
+	Type string `json:"type" example:"categories"`
+	ID   string `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	// Name is set for categories and reflects any rename
+	Name *string `json:"name,omitempty" example:"Groceries (2)"`
+}
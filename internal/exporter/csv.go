@@ -0,0 +1,74 @@
+package exporter
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"io"
+	"strings"
+	"time"
+)
+
+// csvWriter writes every section as its own CSV file inside a zip archive
+type csvWriter struct {
+	zip     *zip.Writer
+	current *csv.Writer
+	row     []string
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{zip: zip.NewWriter(w)}
+}
+
+func (c *csvWriter) BeginSection(name string, columns []string) error {
+	if err := c.flush(); err != nil {
+		return err
+	}
+	f, err := c.zip.CreateHeader(&zip.FileHeader{
+		Name:     name + ".csv",
+		Method:   zip.Deflate,
+		Modified: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	c.current = csv.NewWriter(f)
+	c.row = make([]string, len(columns))
+	return c.current.Write(columns)
+}
+
+func (c *csvWriter) WriteRow(values []any) error {
+	for i := range c.row {
+		c.row[i] = ""
+		if i < len(values) {
+			c.row[i] = formatText(values[i])
+			if _, isText := values[i].(string); isText {
+				c.row[i] = escapeFormula(c.row[i])
+			}
+		}
+	}
+	return c.current.Write(c.row)
+}
+
+func (c *csvWriter) Close() error {
+	if err := c.flush(); err != nil {
+		return err
+	}
+	return c.zip.Close()
+}
+
+func (c *csvWriter) flush() error {
+	if c.current == nil {
+		return nil
+	}
+	c.current.Flush()
+	return c.current.Error()
+}
+
+// escapeFormula stops spreadsheet applications from evaluating user text
+// such as descriptions as formulas when the CSV is opened
+func escapeFormula(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
@@ -0,0 +1,100 @@
+package exporter
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// jsonWriter writes a single object with one array per section:
+// {"exportedAt": "...", "transactions": [{...}], "costs": [...]}
+type jsonWriter struct {
+	w        *bufio.Writer
+	columns  [][]byte
+	started  bool
+	firstRow bool
+	inArray  bool
+}
+
+func newJSONWriter(w io.Writer) *jsonWriter {
+	return &jsonWriter{w: bufio.NewWriter(w)}
+}
+
+func (j *jsonWriter) begin() error {
+	if j.started {
+		return nil
+	}
+	j.started = true
+	_, err := j.w.WriteString(`{"exportedAt":"` + time.Now().UTC().Format(time.RFC3339) + `"`)
+	return err
+}
+
+func (j *jsonWriter) BeginSection(name string, columns []string) error {
+	if err := j.begin(); err != nil {
+		return err
+	}
+	if j.inArray {
+		if err := j.w.WriteByte(']'); err != nil {
+			return err
+		}
+	}
+
+	key, _ := json.Marshal(name)
+	j.w.WriteByte(',')
+	j.w.Write(key)
+	if _, err := j.w.WriteString(":["); err != nil {
+		return err
+	}
+
+	j.columns = make([][]byte, len(columns))
+	for i, c := range columns {
+		j.columns[i], _ = json.Marshal(c)
+	}
+	j.inArray = true
+	j.firstRow = true
+	return nil
+}
+
+func (j *jsonWriter) WriteRow(values []any) error {
+	if !j.firstRow {
+		j.w.WriteByte(',')
+	}
+	j.firstRow = false
+
+	j.w.WriteByte('{')
+	for i, key := range j.columns {
+		if i > 0 {
+			j.w.WriteByte(',')
+		}
+		j.w.Write(key)
+		j.w.WriteByte(':')
+
+		var v any
+		if i < len(values) {
+			v = values[i]
+		}
+		if t, ok := v.(time.Time); ok {
+			v = formatTime(t)
+		}
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		j.w.Write(encoded)
+	}
+	return j.w.WriteByte('}')
+}
+
+func (j *jsonWriter) Close() error {
+	if err := j.begin(); err != nil {
+		return err
+	}
+	if j.inArray {
+		j.w.WriteByte(']')
+	}
+	if err := j.w.WriteByte('}'); err != nil {
+		return err
+	}
+	return j.w.Flush()
+}
@@ -0,0 +1,104 @@
+// Package exporter streams tabular data into downloadable files. Records are
+// written section by section so an export never needs to hold the user's
+// whole data set in memory.
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Supported export formats
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+	FormatXLSX = "xlsx"
+)
+
+// Writer receives the exported sections one after another. Row values must
+// be nil, string, bool, float64, int64 or time.Time.
+type Writer interface {
+	// BeginSection starts a new table, closing the previous one
+	BeginSection(name string, columns []string) error
+
+	// WriteRow appends a row to the current section; values follow the
+	// column order given to BeginSection
+	WriteRow(values []any) error
+
+	// Close finishes the file. It does not close the underlying io.Writer.
+	Close() error
+}
+
+// NewWriter returns a Writer producing the given format on w
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w), nil
+	case FormatJSON:
+		return newJSONWriter(w), nil
+	case FormatXLSX:
+		return newXLSXWriter(w), nil
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+// ContentType returns the MIME type of files produced for format. CSV
+// exports are a zip archive with one file per section.
+func ContentType(format string) string {
+	switch format {
+	case FormatJSON:
+		return "application/json"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "application/zip"
+	}
+}
+
+// FileExtension returns the file name extension for format
+func FileExtension(format string) string {
+	switch format {
+	case FormatJSON:
+		return ".json"
+	case FormatXLSX:
+		return ".xlsx"
+	default:
+		return ".zip"
+	}
+}
+
+// IsSupported reports whether format is a known export format
+func IsSupported(format string) bool {
+	return format == FormatCSV || format == FormatJSON || format == FormatXLSX
+}
+
+// formatText renders a value for the text based formats
+func formatText(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case bool:
+		return strconv.FormatBool(val)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case int64:
+		return strconv.FormatInt(val, 10)
+	case time.Time:
+		return formatTime(val)
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// formatTime prints dates without a time part as YYYY-MM-DD
+func formatTime(t time.Time) string {
+	if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0 {
+		return t.Format("2006-01-02")
+	}
+	return t.UTC().Format(time.RFC3339)
+}
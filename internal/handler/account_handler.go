@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/tyha2404/nexo-app-api/internal/dto"
+	"github.com/tyha2404/nexo-app-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type AccountHandler struct {
+	svc          service.AccountDeletionService
+	log          *zap.Logger
+	errorHandler *ErrorHandler
+	validator    *Validator
+}
+
+func NewAccountHandler(svc service.AccountDeletionService, log *zap.Logger) *AccountHandler {
+	return &AccountHandler{
+		svc:          svc,
+		log:          log,
+		errorHandler: NewErrorHandler(log),
+		validator:    NewValidator(),
+	}
+}
+
+// RequestDeletion handles a self-service account deletion request
+// @Summary Request account deletion
+// @Description Schedule the current account for deletion. After the grace period the account and all of its data are permanently purged; until then the deletion can be cancelled. Requires the account password.
+// @Tags account
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.AccountDeletionRequest true "Password confirmation"
+// @Success 202 {object} response.BaseResponse[dto.AccountDeletionStatusResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /account/deletion [post]
+func (h *AccountHandler) RequestDeletion(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "account_deletion_request")
+		return
+	}
+
+	var req dto.AccountDeletionRequest
+	if err := h.validator.ValidateRequest(r, &req); err != nil {
+		h.errorHandler.HandleValidationError(w, err, "account_deletion_request")
+		return
+	}
+
+	status, err := h.svc.RequestDeletion(r.Context(), user.ID, req)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "account_deletion_request")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusAccepted, status)
+}
+
+// GetDeletion handles fetching the account deletion status
+// @Summary Get account deletion status
+// @Description Show whether the current account is scheduled for deletion and when it will be purged
+// @Tags account
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.BaseResponse[dto.AccountDeletionStatusResponse]
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /account/deletion [get]
+func (h *AccountHandler) GetDeletion(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "account_deletion_get")
+		return
+	}
+
+	status, err := h.svc.GetStatus(r.Context(), user.ID)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "account_deletion_get")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, status)
+}
+
+// CancelDeletion handles cancelling a pending account deletion
+// @Summary Cancel account deletion
+// @Description Cancel a pending account deletion during its grace period
+// @Tags account
+// @Security BearerAuth
+// @Success 204 {string} string "No Content"
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /account/deletion [delete]
+func (h *AccountHandler) CancelDeletion(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "account_deletion_cancel")
+		return
+	}
+
+	if err := h.svc.CancelDeletion(r.Context(), user.ID); err != nil {
+		h.errorHandler.HandleError(w, err, "account_deletion_cancel")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,266 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/constant"
+	"github.com/tyha2404/nexo-app-api/internal/service"
+	"github.com/tyha2404/nexo-app-api/internal/storage"
+	"go.uber.org/zap"
+)
+
+// multipartOverhead is the allowance for multipart boundaries and form fields
+// on top of the file size limit
+const multipartOverhead = 1 << 20
+
+type AttachmentHandler struct {
+	svc          service.AttachmentService
+	verifier     storage.URLVerifier
+	maxBytes     int64
+	log          *zap.Logger
+	errorHandler *ErrorHandler
+}
+
+// NewAttachmentHandler creates an AttachmentHandler. verifier may be nil when
+// the blob store serves its own signed URLs.
+func NewAttachmentHandler(svc service.AttachmentService, verifier storage.URLVerifier, maxBytes int64, log *zap.Logger) *AttachmentHandler {
+	return &AttachmentHandler{
+		svc:          svc,
+		verifier:     verifier,
+		maxBytes:     maxBytes,
+		log:          log,
+		errorHandler: NewErrorHandler(log),
+	}
+}
+
+// Upload handles uploading a receipt or document
+// @Summary Upload an attachment
+// @Description Upload a receipt photo or PDF and link it to a transaction or cost. The content type is sniffed from the file.
+// @Tags attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "Image (JPEG, PNG, GIF, WebP) or PDF"
+// @Param transactionId formData string false "Transaction to attach to"
+// @Param costId formData string false "Cost to attach to"
+// @Success 201 {object} response.BaseResponse[dto.AttachmentResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 413 {object} response.ErrorResponse
+// @Failure 415 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /attachments [post]
+func (h *AttachmentHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "attachment_upload")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxBytes+multipartOverhead)
+	if err := r.ParseMultipartForm(multipartOverhead); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			h.errorHandler.HandleError(w, constant.ErrFileTooLarge, "attachment_upload")
+			return
+		}
+		h.errorHandler.HandleDecodeError(w, err, "attachment_upload")
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		h.errorHandler.HandleValidationError(w, fmt.Errorf("file is required"), "attachment_upload")
+		return
+	}
+	defer file.Close()
+
+	target, err := parseAttachmentTarget(r.FormValue("transactionId"), r.FormValue("costId"))
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "attachment_upload")
+		return
+	}
+
+	attachment, err := h.svc.Upload(r.Context(), user.ID, target, header.Filename, header.Size, file)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "attachment_upload")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusCreated, attachment)
+}
+
+// List handles listing the attachments of a transaction or cost
+// @Summary List attachments
+// @Description List the attachments of a transaction or cost
+// @Tags attachments
+// @Produce json
+// @Security BearerAuth
+// @Param transactionId query string false "Transaction ID"
+// @Param costId query string false "Cost ID"
+// @Success 200 {object} response.BaseResponse[[]dto.AttachmentResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /attachments [get]
+func (h *AttachmentHandler) List(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "attachment_list")
+		return
+	}
+
+	target, err := parseAttachmentTarget(r.URL.Query().Get("transactionId"), r.URL.Query().Get("costId"))
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "attachment_list")
+		return
+	}
+
+	attachments, err := h.svc.List(r.Context(), user.ID, target)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "attachment_list")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, attachments)
+}
+
+// Get handles retrieving an attachment with a signed download URL
+// @Summary Get an attachment
+// @Description Get attachment metadata and a signed, expiring download URL
+// @Tags attachments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Attachment ID"
+// @Success 200 {object} response.BaseResponse[dto.AttachmentResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /attachments/{id} [get]
+func (h *AttachmentHandler) Get(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "attachment_get")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "attachment_get")
+		return
+	}
+
+	attachment, err := h.svc.GetAttachment(r.Context(), user.ID, id)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "attachment_get")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, attachment)
+}
+
+// Delete handles deleting an attachment
+// @Summary Delete an attachment
+// @Description Delete an attachment and its stored file
+// @Tags attachments
+// @Security BearerAuth
+// @Param id path string true "Attachment ID"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /attachments/{id} [delete]
+func (h *AttachmentHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "attachment_delete")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "attachment_delete")
+		return
+	}
+
+	if err := h.svc.DeleteAttachment(r.Context(), user.ID, id); err != nil {
+		h.errorHandler.HandleError(w, err, "attachment_delete")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Download streams a file addressed by a signed URL issued by the local store
+// @Summary Download a file
+// @Description Download an attachment through a signed, expiring URL
+// @Tags attachments
+// @Produce octet-stream
+// @Param key path string true "Storage key"
+// @Param expires query int true "Expiry as Unix timestamp"
+// @Param signature query string true "URL signature"
+// @Success 200 {file} file
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /files/{key} [get]
+func (h *AttachmentHandler) Download(w http.ResponseWriter, r *http.Request) {
+	if h.verifier == nil {
+		h.errorHandler.HandleError(w, constant.ErrNotFound, "attachment_download")
+		return
+	}
+
+	key := chi.URLParam(r, "*")
+	expires, err := strconv.ParseInt(r.URL.Query().Get("expires"), 10, 64)
+	if err != nil {
+		h.errorHandler.HandleError(w, constant.ErrUnauthorized, "attachment_download")
+		return
+	}
+	if err := h.verifier.Verify(key, expires, r.URL.Query().Get("signature")); err != nil {
+		h.log.Warn("rejected file download", zap.String("key", key), zap.Error(err))
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	attachment, content, err := h.svc.OpenByStorageKey(r.Context(), key)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "attachment_download")
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", attachment.FileName))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, content); err != nil {
+		h.log.Error("failed to stream file", zap.String("key", key), zap.Error(err))
+	}
+}
+
+// parseAttachmentTarget builds the attachment owner from raw IDs
+func parseAttachmentTarget(transactionID, costID string) (service.AttachmentTarget, error) {
+	var target service.AttachmentTarget
+	if transactionID != "" {
+		id, err := uuid.Parse(transactionID)
+		if err != nil {
+			return target, constant.ErrInvalidInput
+		}
+		target.TransactionID = &id
+	}
+	if costID != "" {
+		id, err := uuid.Parse(costID)
+		if err != nil {
+			return target, constant.ErrInvalidInput
+		}
+		target.CostID = &id
+	}
+	return target, nil
+}
@@ -2,6 +2,8 @@ package handler
 
 import (
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/tyha2404/nexo-app-api/internal/dto"
 	"github.com/tyha2404/nexo-app-api/internal/model"
@@ -61,7 +63,7 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 
 	// Prepare and send loginResponse
 	loginResponse := dto.LoginResponse{
-		User:  user,
+		User:  toUserResponse(user),
 		Token: token,
 	}
 
@@ -70,11 +72,12 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 
 // Register handles the registration of a new user record
 // @Summary Register a new user
-// @Description Create a new user
+// @Description Create a new user, starting with the default categories of the locale in the request or the Accept-Language header
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param request body dto.RegisterRequest true "User registration data"
+// @Param Accept-Language header string false "Preferred language, used when the request has no locale"
 // @Success 201 {object} model.User
 // @Failure 400 {string} string "Invalid request payload"
 // @Failure 500 {string} string "Failed to create user"
@@ -92,7 +95,12 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		Password: req.Password,
 	}
 
-	createdUser, err := h.svc.Register(r.Context(), user)
+	locale := req.Locale
+	if locale == "" {
+		locale = preferredLanguage(r.Header.Get("Accept-Language"))
+	}
+
+	createdUser, err := h.svc.Register(r.Context(), user, locale)
 	if err != nil {
 		h.errorHandler.HandleError(w, err, "register")
 		return
@@ -120,3 +128,30 @@ func (h *AuthHandler) WhoAmI(w http.ResponseWriter, r *http.Request) {
 
 	h.errorHandler.HandleSuccess(w, http.StatusOK, user)
 }
+
+// preferredLanguage returns the first language of an Accept-Language header
+func preferredLanguage(header string) string {
+	first, _, _ := strings.Cut(header, ",")
+	tag, _, _ := strings.Cut(first, ";")
+	tag = strings.TrimSpace(tag)
+	if tag == "*" {
+		return ""
+	}
+	return tag
+}
+
+// toUserResponse converts a user model into its public response shape
+func toUserResponse(u *model.User) dto.UserResponse {
+	resp := dto.UserResponse{
+		ID:        u.ID.String(),
+		Username:  u.Username,
+		Email:     u.Email,
+		CreatedAt: u.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: u.UpdatedAt.Format(time.RFC3339),
+	}
+	if u.DeletionScheduledAt != nil {
+		scheduled := u.DeletionScheduledAt.Format(time.RFC3339)
+		resp.DeletionScheduledAt = &scheduled
+	}
+	return resp
+}
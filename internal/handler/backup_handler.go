@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/tyha2404/nexo-app-api/internal/dto"
+	"github.com/tyha2404/nexo-app-api/internal/service"
+	"go.uber.org/zap"
+)
+
+// restoreMaxBytes caps the size of an uploaded backup archive
+const restoreMaxBytes = 100 << 20
+
+type BackupHandler struct {
+	svc          service.BackupService
+	log          *zap.Logger
+	errorHandler *ErrorHandler
+	validator    *Validator
+}
+
+func NewBackupHandler(svc service.BackupService, log *zap.Logger) *BackupHandler {
+	return &BackupHandler{
+		svc:          svc,
+		log:          log,
+		errorHandler: NewErrorHandler(log),
+		validator:    NewValidator(),
+	}
+}
+
+// Backup handles downloading the complete account archive
+// @Summary Download a backup
+// @Description Download a versioned JSON archive of everything the current user owns: profile, categories, tags, budgets, alerts, transactions, costs, expenses and import profiles. The archive can be restored into this or another instance and doubles as the data portability export.
+// @Tags backup
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.BackupArchive
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /backup [get]
+func (h *BackupHandler) Backup(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "backup")
+		return
+	}
+
+	fileName := "nexo-backup-" + time.Now().Format("20060102") + ".json"
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+
+	// The status is already sent, so a failure can only cut the file short
+	if err := h.svc.Backup(r.Context(), user.ID, w); err != nil {
+		h.log.Error("failed to stream backup", zap.String("user_id", user.ID.String()), zap.Error(err))
+	}
+}
+
+// Restore handles restoring a backup archive into the current account
+// @Summary Restore a backup
+// @Description Restore a backup archive into the current account. Every record gets a new ID and references are remapped. Categories, tags and import profiles whose name already exists are merged or renamed depending on onConflict; transactions already present are skipped. Returns a dry-run summary unless dryRun is false, in which case everything is written in a single database transaction.
+// @Tags backup
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "Backup archive"
+// @Param request formData string false "JSON encoded dto.RestoreRequest"
+// @Success 200 {object} response.BaseResponse[dto.RestoreResultResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 413 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /backup/restore [post]
+func (h *BackupHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "backup_restore")
+		return
+	}
+
+	file, err := OpenMultipartFile(w, r, restoreMaxBytes)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "backup_restore")
+		return
+	}
+	defer file.Close()
+	defer r.MultipartForm.RemoveAll()
+
+	var req dto.RestoreRequest
+	if raw := r.FormValue("request"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &req); err != nil {
+			h.errorHandler.HandleDecodeError(w, err, "backup_restore")
+			return
+		}
+	}
+	if err := h.validator.ValidateStruct(&req); err != nil {
+		h.errorHandler.HandleValidationError(w, err, "backup_restore")
+		return
+	}
+
+	result, err := h.svc.Restore(r.Context(), user.ID, file, req)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "backup_restore")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, result)
+}
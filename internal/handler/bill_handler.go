@@ -0,0 +1,367 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/tyha2404/nexo-app-api/internal/dto"
+	"github.com/tyha2404/nexo-app-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type BillHandler struct {
+	svc          service.BillService
+	log          *zap.Logger
+	errorHandler *ErrorHandler
+	validator    *Validator
+}
+
+func NewBillHandler(svc service.BillService, log *zap.Logger) *BillHandler {
+	return &BillHandler{
+		svc:          svc,
+		log:          log,
+		errorHandler: NewErrorHandler(log),
+		validator:    NewValidator(),
+	}
+}
+
+// Create handles the creation of a bill
+// @Summary Create a bill
+// @Description Track a bill that falls due on a day of the month, once or every month, quarter or year. Unpaid bills raise an alert a few days before they are due and once they are overdue.
+// @Tags bills
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param bill body dto.CreateBillRequest true "Bill"
+// @Success 201 {object} response.BaseResponse[dto.BillResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /bills [post]
+func (h *BillHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "bill_create")
+		return
+	}
+
+	var req dto.CreateBillRequest
+	if err := h.validator.ValidateRequest(r, &req); err != nil {
+		h.errorHandler.HandleValidationError(w, err, "bill_create")
+		return
+	}
+
+	bill, err := h.svc.CreateBill(r.Context(), user.ID, req)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "bill_create")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusCreated, bill)
+}
+
+// List handles listing bills
+// @Summary List bills
+// @Description List the current user's bills with their next unpaid due date
+// @Tags bills
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.BaseResponse[[]dto.BillResponse]
+// @Failure 500 {object} response.ErrorResponse
+// @Router /bills [get]
+func (h *BillHandler) List(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "bill_list")
+		return
+	}
+
+	bills, err := h.svc.ListBills(r.Context(), user.ID)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "bill_list")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, bills)
+}
+
+// Upcoming handles listing the bills due soon
+// @Summary List upcoming bills
+// @Description List the bill occurrences due from today over the next days, paid or not, together with the last occurrence of each bill if it is overdue, by due date
+// @Tags bills
+// @Produce json
+// @Security BearerAuth
+// @Param days query int false "Number of days ahead, today included (max 366)" default(30)
+// @Success 200 {object} response.BaseResponse[[]dto.BillOccurrenceResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /bills/upcoming [get]
+func (h *BillHandler) Upcoming(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "bill_upcoming")
+		return
+	}
+
+	occurrences, err := h.svc.Upcoming(r.Context(), user.ID, ParseQueryInt(r, "days", 30))
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "bill_upcoming")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, occurrences)
+}
+
+// Get handles retrieving a single bill
+// @Summary Get a bill
+// @Description Get a bill with its next unpaid due date and its overdue occurrence, if any
+// @Tags bills
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Bill ID"
+// @Success 200 {object} response.BaseResponse[dto.BillResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /bills/{id} [get]
+func (h *BillHandler) Get(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "bill_get")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "bill_get")
+		return
+	}
+
+	bill, err := h.svc.GetBill(r.Context(), user.ID, id)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "bill_get")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, bill)
+}
+
+// Update handles updating a bill
+// @Summary Update a bill
+// @Description Change the payee, amount, due day, recurrence or reminder of a bill
+// @Tags bills
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Bill ID"
+// @Param bill body dto.UpdateBillRequest true "Fields to update"
+// @Success 200 {object} response.BaseResponse[dto.BillResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /bills/{id} [put]
+func (h *BillHandler) Update(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "bill_update")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "bill_update")
+		return
+	}
+
+	var req dto.UpdateBillRequest
+	if err := h.validator.ValidateRequest(r, &req); err != nil {
+		h.errorHandler.HandleValidationError(w, err, "bill_update")
+		return
+	}
+
+	bill, err := h.svc.UpdateBill(r.Context(), user.ID, id, req)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "bill_update")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, bill)
+}
+
+// Delete handles deleting a bill
+// @Summary Delete a bill
+// @Description Delete a bill with its payments and alerts. The transactions that paid it are kept.
+// @Tags bills
+// @Security BearerAuth
+// @Param id path string true "Bill ID"
+// @Success 204
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /bills/{id} [delete]
+func (h *BillHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "bill_delete")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "bill_delete")
+		return
+	}
+
+	if err := h.svc.DeleteBill(r.Context(), user.ID, id); err != nil {
+		h.errorHandler.HandleError(w, err, "bill_delete")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Pay handles marking a bill paid
+// @Summary Mark a bill paid
+// @Description Mark an occurrence of a bill paid, by default the last one due if it is unpaid, else the next unpaid one. With transactionId an existing expense is linked; otherwise an expense is booked in the bill's category for the expected amount.
+// @Tags bills
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Bill ID"
+// @Param payment body dto.PayBillRequest true "Payment"
+// @Success 201 {object} response.BaseResponse[dto.BillPaymentResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /bills/{id}/payments [post]
+func (h *BillHandler) Pay(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "bill_pay")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "bill_pay")
+		return
+	}
+
+	var req dto.PayBillRequest
+	if err := h.validator.ValidateRequest(r, &req); err != nil {
+		h.errorHandler.HandleValidationError(w, err, "bill_pay")
+		return
+	}
+
+	payment, err := h.svc.Pay(r.Context(), user.ID, id, req)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "bill_pay")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusCreated, payment)
+}
+
+// ListPayments handles listing the payments of a bill
+// @Summary List bill payments
+// @Description List the payments of a bill with their transactions, most recent first. Payments whose transaction is in the trash do not count.
+// @Tags bills
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Bill ID"
+// @Success 200 {object} response.BaseResponse[[]dto.BillPaymentResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /bills/{id}/payments [get]
+func (h *BillHandler) ListPayments(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "bill_payment_list")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "bill_payment_list")
+		return
+	}
+
+	payments, err := h.svc.ListPayments(r.Context(), user.ID, id)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "bill_payment_list")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, payments)
+}
+
+// DeletePayment handles marking a bill unpaid again
+// @Summary Delete a bill payment
+// @Description Mark the occurrence unpaid again. The transaction is kept.
+// @Tags bills
+// @Security BearerAuth
+// @Param id path string true "Bill ID"
+// @Param paymentId path string true "Payment ID"
+// @Success 204
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /bills/{id}/payments/{paymentId} [delete]
+func (h *BillHandler) DeletePayment(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "bill_payment_delete")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "bill_payment_delete")
+		return
+	}
+	paymentID, err := ParseUUIDFromPath(r, "paymentId")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "bill_payment_delete")
+		return
+	}
+
+	if err := h.svc.DeletePayment(r.Context(), user.ID, id, paymentID); err != nil {
+		h.errorHandler.HandleError(w, err, "bill_payment_delete")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListAlerts handles listing the alerts of a bill
+// @Summary List bill alerts
+// @Description List the alerts raised when the bill was due soon or overdue, most recent first
+// @Tags bills
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Bill ID"
+// @Success 200 {object} response.BaseResponse[[]dto.BillAlertResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /bills/{id}/alerts [get]
+func (h *BillHandler) ListAlerts(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "bill_alert_list")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "bill_alert_list")
+		return
+	}
+
+	alerts, err := h.svc.ListAlerts(r.Context(), user.ID, id)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "bill_alert_list")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, alerts)
+}
@@ -0,0 +1,226 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/tyha2404/nexo-app-api/internal/dto"
+	"github.com/tyha2404/nexo-app-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type CategorizationRuleHandler struct {
+	svc          service.CategorizationRuleService
+	log          *zap.Logger
+	errorHandler *ErrorHandler
+	validator    *Validator
+}
+
+func NewCategorizationRuleHandler(svc service.CategorizationRuleService, log *zap.Logger) *CategorizationRuleHandler {
+	return &CategorizationRuleHandler{
+		svc:          svc,
+		log:          log,
+		errorHandler: NewErrorHandler(log),
+		validator:    NewValidator(),
+	}
+}
+
+// Create handles the creation of a new categorization rule
+// @Summary Create a categorization rule
+// @Description Create a rule that assigns a category, tags or a new description to matching transactions. Rules run in priority order when transactions are created or imported.
+// @Tags categorization-rules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param rule body dto.CategorizationRuleRequest true "Rule object"
+// @Success 201 {object} response.BaseResponse[dto.CategorizationRuleResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /categorization-rules [post]
+func (h *CategorizationRuleHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "rule_create")
+		return
+	}
+
+	var req dto.CategorizationRuleRequest
+	if err := h.validator.ValidateRequest(r, &req); err != nil {
+		h.errorHandler.HandleValidationError(w, err, "rule_create")
+		return
+	}
+
+	rule, err := h.svc.CreateRule(r.Context(), user.ID, req)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "rule_create")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusCreated, rule)
+}
+
+// Get handles retrieving a single categorization rule by ID
+// @Summary Get a categorization rule by ID
+// @Description Get a categorization rule by its ID
+// @Tags categorization-rules
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Rule ID"
+// @Success 200 {object} response.BaseResponse[dto.CategorizationRuleResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /categorization-rules/{id} [get]
+func (h *CategorizationRuleHandler) Get(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "rule_get")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "rule_get")
+		return
+	}
+
+	rule, err := h.svc.GetRule(r.Context(), user.ID, id)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "rule_get")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, rule)
+}
+
+// List handles retrieving all categorization rules of the current user
+// @Summary List categorization rules
+// @Description Get all categorization rules of the current user in the order they run
+// @Tags categorization-rules
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.BaseResponse[[]dto.CategorizationRuleResponse]
+// @Failure 500 {object} response.ErrorResponse
+// @Router /categorization-rules [get]
+func (h *CategorizationRuleHandler) List(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "rule_list")
+		return
+	}
+
+	rules, err := h.svc.ListRules(r.Context(), user.ID)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "rule_list")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, rules)
+}
+
+// Update handles replacing a categorization rule
+// @Summary Update a categorization rule
+// @Description Replace all conditions and actions of an existing rule
+// @Tags categorization-rules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Rule ID"
+// @Param rule body dto.CategorizationRuleRequest true "Rule object"
+// @Success 200 {object} response.BaseResponse[dto.CategorizationRuleResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /categorization-rules/{id} [put]
+func (h *CategorizationRuleHandler) Update(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "rule_update")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "rule_update")
+		return
+	}
+
+	var req dto.CategorizationRuleRequest
+	if err := h.validator.ValidateRequest(r, &req); err != nil {
+		h.errorHandler.HandleValidationError(w, err, "rule_update")
+		return
+	}
+
+	rule, err := h.svc.UpdateRule(r.Context(), user.ID, id, req)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "rule_update")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, rule)
+}
+
+// Delete handles deleting a categorization rule by ID
+// @Summary Delete a categorization rule
+// @Description Delete a categorization rule; transactions it already changed stay as they are
+// @Tags categorization-rules
+// @Security BearerAuth
+// @Param id path string true "Rule ID"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /categorization-rules/{id} [delete]
+func (h *CategorizationRuleHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "rule_delete")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "rule_delete")
+		return
+	}
+
+	if err := h.svc.DeleteRule(r.Context(), user.ID, id); err != nil {
+		h.errorHandler.HandleError(w, err, "rule_delete")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Apply handles running rules over existing transactions
+// @Summary Apply categorization rules retroactively
+// @Description Run rules over existing transactions. By default the changes are only previewed; set dryRun to false to write them.
+// @Tags categorization-rules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.ApplyRulesRequest true "Rules, date range and dry-run flag"
+// @Success 200 {object} response.BaseResponse[dto.ApplyRulesResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /categorization-rules/apply [post]
+func (h *CategorizationRuleHandler) Apply(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "rule_apply")
+		return
+	}
+
+	var req dto.ApplyRulesRequest
+	if err := h.validator.ValidateRequest(r, &req); err != nil {
+		h.errorHandler.HandleValidationError(w, err, "rule_apply")
+		return
+	}
+
+	result, err := h.svc.ApplyRules(r.Context(), user.ID, req)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "rule_apply")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, result)
+}
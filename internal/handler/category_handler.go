@@ -1,7 +1,9 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -16,17 +18,18 @@ import (
 )
 
 type CategoryHandler struct {
-	svc service.CategoryService
-	log *zap.Logger
+	svc       service.CategoryService
+	log       *zap.Logger
+	validator *Validator
 }
 
 func NewCategoryHandler(svc service.CategoryService, log *zap.Logger) *CategoryHandler {
-	return &CategoryHandler{svc: svc, log: log}
+	return &CategoryHandler{svc: svc, log: log, validator: NewValidator()}
 }
 
 // Create handles the creation of a new category record
 // @Summary Create a new category
-// @Description Create a new category
+// @Description Create a new category. A kind of INCOME or EXPENSE limits the category to transactions of that type.
 // @Tags categories
 // @Accept json
 // @Produce json
@@ -51,12 +54,31 @@ func (h *CategoryHandler) Create(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid request payload", http.StatusBadRequest)
 		return
 	}
+	if err := h.validator.ValidateStruct(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if req.ParentID != nil {
+		if err := h.svc.CheckParent(r.Context(), user.ID, uuid.Nil, *req.ParentID); err != nil {
+			h.writeCategoryError(w, err, "failed to check parent category")
+			return
+		}
+	}
 
 	category := &model.Category{
 		Name:        req.Name,
 		Description: req.Description,
+		Icon:        req.Icon,
+		Color:       req.Color,
+		SortOrder:   req.SortOrder,
+		ParentID:    req.ParentID,
 		UserID:      user.ID,
 	}
+	if req.Kind != nil {
+		kind := model.TransactionType(*req.Kind)
+		category.Kind = &kind
+	}
 
 	category, err := h.svc.Create(r.Context(), category)
 	if err != nil {
@@ -119,16 +141,25 @@ func (h *CategoryHandler) Get(w http.ResponseWriter, r *http.Request) {
 
 // List handles retrieving a paginated list of categories
 // @Summary List categories
-// @Description Get a paginated list of categories
+// @Description Get a paginated list of the current user's categories ordered by name. Archived categories are left out unless includeArchived is set.
 // @Tags categories
 // @Produce json
 // @Security BearerAuth
 // @Param limit query int false "Limit"
 // @Param offset query int false "Offset"
+// @Param includeArchived query bool false "Include archived categories"
 // @Success 200 {array} model.Category
+// @Failure 401 {string} string "Unauthorized"
 // @Failure 500 {string} string "Failed to list categories"
 // @Router /categories [get]
 func (h *CategoryHandler) List(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(constant.UserContextKey).(model.User)
+	if !ok || user.ID == uuid.Nil {
+		h.log.Error("User ID not found in context")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
 	limitStr := r.URL.Query().Get("limit")
 	offsetStr := r.URL.Query().Get("offset")
 
@@ -147,7 +178,9 @@ func (h *CategoryHandler) List(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	categories, err := h.svc.List(r.Context(), limit, offset)
+	includeArchived, _ := strconv.ParseBool(r.URL.Query().Get("includeArchived"))
+
+	categories, total, err := h.svc.ListCategories(r.Context(), user.ID, includeArchived, limit, offset)
 	if err != nil {
 		h.log.Error("failed to list categories", zap.Error(err))
 		http.Error(w, "Failed to list categories", http.StatusInternalServerError)
@@ -159,7 +192,7 @@ func (h *CategoryHandler) List(w http.ResponseWriter, r *http.Request) {
 		Status:  http.StatusOK,
 		Success: true,
 		Items:   categories,
-		Total:   len(categories),
+		Total:   int(total),
 		Page:    offset,
 		Limit:   limit,
 	}); err != nil {
@@ -169,15 +202,15 @@ func (h *CategoryHandler) List(w http.ResponseWriter, r *http.Request) {
 
 // Update handles updating an existing category
 // @Summary Update a category
-// @Description Update an existing category
+// @Description Update one of the current user's categories. A kind can only be set while the category holds no transactions of the other type, trashed ones included.
 // @Tags categories
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Category ID"
-// @Param category body model.Category true "Category object"
+// @Param category body dto.UpdateCategoryRequest true "Fields to update"
 // @Success 200 {object} model.Category
-// @Failure 400 {string} string "Invalid category ID or payload"
+// @Failure 400 {string} string "Invalid category ID or payload, or a kind that contradicts the category's transactions"
 // @Failure 404 {string} string "Category not found"
 // @Failure 500 {string} string "Failed to update category"
 // @Router /categories/{id} [put]
@@ -189,12 +222,23 @@ func (h *CategoryHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	user, ok := r.Context().Value(constant.UserContextKey).(model.User)
+	if !ok || user.ID == uuid.Nil {
+		h.log.Error("User ID not found in context")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
 	var req dto.UpdateCategoryRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		h.log.Error("failed to decode request body", zap.Error(err))
 		http.Error(w, "Invalid request payload", http.StatusBadRequest)
 		return
 	}
+	if err := h.validator.ValidateStruct(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	// Build updates map from non-nil fields
 	updates := make(map[string]interface{})
@@ -204,28 +248,38 @@ func (h *CategoryHandler) Update(w http.ResponseWriter, r *http.Request) {
 	if req.Description != nil {
 		updates["description"] = *req.Description
 	}
+	// An empty kind, icon or color clears it
+	for column, value := range map[string]*string{"kind": req.Kind, "icon": req.Icon, "color": req.Color} {
+		if value == nil {
+			continue
+		}
+		if *value == "" {
+			updates[column] = nil
+		} else {
+			updates[column] = *value
+		}
+	}
+	if req.SortOrder != nil {
+		updates["sort_order"] = *req.SortOrder
+	}
+	if req.ParentID.Set {
+		if req.ParentID.Value != nil {
+			if err := h.svc.CheckParent(r.Context(), user.ID, id, *req.ParentID.Value); err != nil {
+				h.writeCategoryError(w, err, "failed to check parent category")
+				return
+			}
+		}
+		updates["parent_id"] = req.ParentID.Value
+	}
 
 	if len(updates) == 0 {
 		http.Error(w, "No fields to update", http.StatusBadRequest)
 		return
 	}
 
-	// Update specific fields
-	if err := h.svc.UpdateFields(r.Context(), id, updates); err != nil {
-		if err == constant.ErrNotFound {
-			http.Error(w, "Category not found", http.StatusNotFound)
-			return
-		}
-		h.log.Error("failed to update category", zap.Error(err))
-		http.Error(w, "Failed to update category", http.StatusInternalServerError)
-		return
-	}
-
-	// Get the updated category to return in response
-	updatedCategory, err := h.svc.Get(r.Context(), id)
+	updatedCategory, err := h.svc.UpdateCategory(r.Context(), user.ID, id, updates)
 	if err != nil {
-		h.log.Error("failed to get updated category", zap.Error(err))
-		http.Error(w, "Failed to get updated category", http.StatusInternalServerError)
+		h.writeCategoryError(w, err, "failed to update category")
 		return
 	}
 
@@ -241,12 +295,14 @@ func (h *CategoryHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 // Delete handles deleting a category by ID
 // @Summary Delete a category
-// @Description Delete a category by its ID
+// @Description Delete one of the current user's categories by its ID. Its subcategories move up to its parent. With reassignTo its transactions, costs, budgets, rules, goals, loans, bills and subcategories, trashed ones included, are first moved to that category in the same transaction.
 // @Tags categories
 // @Security BearerAuth
 // @Param id path string true "Category ID"
+// @Param reassignTo query string false "ID of the category to move everything filed under the deleted one to"
 // @Success 204 {string} string "No Content"
 // @Failure 400 {string} string "Invalid category ID"
+// @Failure 401 {string} string "Unauthorized"
 // @Failure 404 {string} string "Category not found"
 // @Failure 500 {string} string "Failed to delete category"
 // @Router /categories/{id} [delete]
@@ -258,15 +314,193 @@ func (h *CategoryHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.svc.Delete(r.Context(), id); err != nil {
-		if err == constant.ErrNotFound {
-			http.Error(w, "Category not found", http.StatusNotFound)
+	user, ok := r.Context().Value(constant.UserContextKey).(model.User)
+	if !ok || user.ID == uuid.Nil {
+		h.log.Error("User ID not found in context")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if reassignTo := r.URL.Query().Get("reassignTo"); reassignTo != "" {
+		targetID, err := uuid.Parse(reassignTo)
+		if err != nil {
+			http.Error(w, "Invalid reassignTo category ID", http.StatusBadRequest)
 			return
 		}
-		h.log.Error("failed to delete category", zap.Error(err))
-		http.Error(w, "Failed to delete category", http.StatusInternalServerError)
+		if _, err := h.svc.MergeInto(r.Context(), user.ID, id, targetID); err != nil {
+			h.writeCategoryError(w, err, "failed to delete category")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if err := h.svc.DeleteCategory(r.Context(), user.ID, id); err != nil {
+		h.writeCategoryError(w, err, "failed to delete category")
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// MergeInto handles merging a category into another one
+// @Summary Merge a category into another
+// @Description Move the transactions, costs, budgets, expenses, categorization rules, goals, loans, bills and subcategories of a category, trashed ones included, onto the target category and delete the category, all in one transaction. A target nested below the category takes its place in the tree.
+// @Tags categories
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID of the category to merge"
+// @Param target path string true "ID of the category to merge into"
+// @Success 200 {object} response.BaseResponse[dto.CategoryMergeResponse]
+// @Failure 400 {string} string "Invalid category ID or target"
+// @Failure 404 {string} string "Category not found"
+// @Failure 500 {string} string "Failed to merge category"
+// @Router /categories/{id}/merge-into/{target} [post]
+func (h *CategoryHandler) MergeInto(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(constant.UserContextKey).(model.User)
+	if !ok || user.ID == uuid.Nil {
+		h.log.Error("User ID not found in context")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid category ID", http.StatusBadRequest)
+		return
+	}
+	targetID, err := uuid.Parse(chi.URLParam(r, "target"))
+	if err != nil {
+		http.Error(w, "Invalid target category ID", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.svc.MergeInto(r.Context(), user.ID, id, targetID)
+	if err != nil {
+		h.writeCategoryError(w, err, "failed to merge category")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response.BaseResponse[dto.CategoryMergeResponse]{
+		Status:  http.StatusOK,
+		Success: true,
+		Data:    *result,
+	}); err != nil {
+		h.log.Error("failed to encode response", zap.Error(err))
+	}
+}
+
+// Archive handles archiving a category
+// @Summary Archive a category
+// @Description Hide a category and its subcategories from category lists and pickers while keeping their transactions and reports. New transactions cannot be filed under an archived category.
+// @Tags categories
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Category ID"
+// @Success 200 {object} model.Category
+// @Failure 400 {string} string "Invalid category ID"
+// @Failure 404 {string} string "Category not found"
+// @Failure 500 {string} string "Failed to archive category"
+// @Router /categories/{id}/archive [post]
+func (h *CategoryHandler) Archive(w http.ResponseWriter, r *http.Request) {
+	h.setArchived(w, r, h.svc.Archive, "failed to archive category")
+}
+
+// Unarchive handles bringing back an archived category
+// @Summary Unarchive a category
+// @Description Bring back an archived category with its subcategories and any archived parents
+// @Tags categories
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Category ID"
+// @Success 200 {object} model.Category
+// @Failure 400 {string} string "Invalid category ID"
+// @Failure 404 {string} string "Category not found"
+// @Failure 500 {string} string "Failed to unarchive category"
+// @Router /categories/{id}/unarchive [post]
+func (h *CategoryHandler) Unarchive(w http.ResponseWriter, r *http.Request) {
+	h.setArchived(w, r, h.svc.Unarchive, "failed to unarchive category")
+}
+
+func (h *CategoryHandler) setArchived(w http.ResponseWriter, r *http.Request, apply func(ctx context.Context, userID, id uuid.UUID) (*model.Category, error), failure string) {
+	user, ok := r.Context().Value(constant.UserContextKey).(model.User)
+	if !ok || user.ID == uuid.Nil {
+		h.log.Error("User ID not found in context")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid category ID", http.StatusBadRequest)
+		return
+	}
+
+	category, err := apply(r.Context(), user.ID, id)
+	if err != nil {
+		h.writeCategoryError(w, err, failure)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response.BaseResponse[model.Category]{
+		Status:  http.StatusOK,
+		Success: true,
+		Data:    *category,
+	}); err != nil {
+		h.log.Error("failed to encode response", zap.Error(err))
+	}
+}
+
+// Tree handles listing the category hierarchy
+// @Summary List categories as a tree
+// @Description List the current user's categories with their subcategories nested below them, ordered by name. Archived categories are left out unless includeArchived is set.
+// @Tags categories
+// @Produce json
+// @Security BearerAuth
+// @Param includeArchived query bool false "Include archived categories"
+// @Success 200 {object} response.BaseResponse[[]dto.CategoryTreeResponse]
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Failed to list categories"
+// @Router /categories/tree [get]
+func (h *CategoryHandler) Tree(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(constant.UserContextKey).(model.User)
+	if !ok || user.ID == uuid.Nil {
+		h.log.Error("User ID not found in context")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	includeArchived, _ := strconv.ParseBool(r.URL.Query().Get("includeArchived"))
+
+	tree, err := h.svc.Tree(r.Context(), user.ID, includeArchived)
+	if err != nil {
+		h.log.Error("failed to build category tree", zap.Error(err))
+		http.Error(w, "Failed to list categories", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response.BaseResponse[[]dto.CategoryTreeResponse]{
+		Status:  http.StatusOK,
+		Success: true,
+		Data:    tree,
+	}); err != nil {
+		h.log.Error("failed to encode response", zap.Error(err))
+	}
+}
+
+// writeCategoryError maps service errors to responses; failure is logged
+// for unexpected errors
+func (h *CategoryHandler) writeCategoryError(w http.ResponseWriter, err error, failure string) {
+	switch {
+	case errors.Is(err, constant.ErrInvalidInput):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, constant.ErrNotFound):
+		http.Error(w, "Category not found", http.StatusNotFound)
+	default:
+		h.log.Error(failure, zap.Error(err))
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
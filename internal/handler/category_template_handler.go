@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/tyha2404/nexo-app-api/internal/dto"
+	"github.com/tyha2404/nexo-app-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type CategoryTemplateHandler struct {
+	svc          service.CategoryTemplateService
+	log          *zap.Logger
+	errorHandler *ErrorHandler
+	validator    *Validator
+}
+
+func NewCategoryTemplateHandler(svc service.CategoryTemplateService, log *zap.Logger) *CategoryTemplateHandler {
+	return &CategoryTemplateHandler{
+		svc:          svc,
+		log:          log,
+		errorHandler: NewErrorHandler(log),
+		validator:    NewValidator(),
+	}
+}
+
+// Create handles the creation of a category template
+// @Summary Create a category template
+// @Description Add a category that new users of a locale start with. Once a locale has templates they replace its built-in defaults. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param template body dto.CreateCategoryTemplateRequest true "Category template"
+// @Success 201 {object} response.BaseResponse[dto.CategoryTemplateResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /admin/category-templates [post]
+func (h *CategoryTemplateHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateCategoryTemplateRequest
+	if err := h.validator.ValidateRequest(r, &req); err != nil {
+		h.errorHandler.HandleValidationError(w, err, "category_template_create")
+		return
+	}
+
+	template, err := h.svc.Create(r.Context(), req)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "category_template_create")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusCreated, template)
+}
+
+// List handles listing category templates
+// @Summary List category templates
+// @Description List the stored category templates, optionally of one locale. Locales without stored templates use the built-in defaults, which are not listed. Admin only.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param locale query string false "Locale, e.g. en or pt-BR"
+// @Success 200 {object} response.BaseResponse[[]dto.CategoryTemplateResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /admin/category-templates [get]
+func (h *CategoryTemplateHandler) List(w http.ResponseWriter, r *http.Request) {
+	templates, err := h.svc.List(r.Context(), r.URL.Query().Get("locale"))
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "category_template_list")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, templates)
+}
+
+// Update handles updating a category template
+// @Summary Update a category template
+// @Description Change a category template. Categories already seeded for existing users are not affected. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Template ID"
+// @Param template body dto.UpdateCategoryTemplateRequest true "Fields to update"
+// @Success 200 {object} response.BaseResponse[dto.CategoryTemplateResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /admin/category-templates/{id} [put]
+func (h *CategoryTemplateHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "category_template_update")
+		return
+	}
+
+	var req dto.UpdateCategoryTemplateRequest
+	if err := h.validator.ValidateRequest(r, &req); err != nil {
+		h.errorHandler.HandleValidationError(w, err, "category_template_update")
+		return
+	}
+
+	template, err := h.svc.Update(r.Context(), id, req)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "category_template_update")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, template)
+}
+
+// Delete handles deleting a category template
+// @Summary Delete a category template
+// @Description Delete a category template. A locale whose last template is deleted falls back to the built-in defaults. Admin only.
+// @Tags admin
+// @Security BearerAuth
+// @Param id path string true "Template ID"
+// @Success 204
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /admin/category-templates/{id} [delete]
+func (h *CategoryTemplateHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "category_template_delete")
+		return
+	}
+
+	if err := h.svc.Delete(r.Context(), id); err != nil {
+		h.errorHandler.HandleError(w, err, "category_template_delete")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -3,6 +3,8 @@ package handler
 import (
 	"net/http"
 
+	"github.com/google/uuid"
+
 	"github.com/tyha2404/nexo-app-api/internal/constant"
 	"github.com/tyha2404/nexo-app-api/internal/dto"
 	"github.com/tyha2404/nexo-app-api/internal/model"
@@ -61,6 +63,9 @@ func (h *CostHandler) Create(w http.ResponseWriter, r *http.Request) {
 		CategoryID: req.CategoryID,
 		UserID:     user.ID,
 	}
+	for _, tagID := range req.TagIDs {
+		cost.Tags = append(cost.Tags, model.Tag{ID: tagID})
+	}
 
 	createdCost, err := h.svc.Create(r.Context(), cost)
 	if err != nil {
@@ -122,6 +127,8 @@ func (h *CostHandler) Get(w http.ResponseWriter, r *http.Request) {
 // @Param offset query int false "Offset"
 // @Param startDate query string false "Start date filter (YYYY-MM-DD)"
 // @Param endDate query string false "End date filter (YYYY-MM-DD)"
+// @Param tags query string false "Comma-separated tag IDs"
+// @Param tagMode query string false "Match any or all of the tags" Enums(any, all)
 // @Success 200 {array} model.Cost
 // @Failure 500 {string} string "Failed to list costs"
 // @Router /costs [get]
@@ -136,7 +143,7 @@ func (h *CostHandler) List(w http.ResponseWriter, r *http.Request) {
 	limit := ParseQueryIntWithValidation(r, "limit", 10, 1)
 	offset := ParseQueryIntWithValidation(r, "offset", 0, 0)
 
-	filters := BuildFilterMap(r, []string{"startDate", "endDate"})
+	filters := BuildFilterMap(r, []string{"startDate", "endDate", "tags", "tagMode"})
 
 	costs, err := h.svc.ListWithCategory(r.Context(), user.ID, limit, offset, filters)
 	if err != nil {
@@ -149,7 +156,7 @@ func (h *CostHandler) List(w http.ResponseWriter, r *http.Request) {
 
 // Update handles updating an existing cost
 // @Summary Update a cost
-// @Description Update an existing cost
+// @Description Update an existing cost. When tags are given, their IDs replace the tags of the cost; an empty list removes them all.
 // @Tags costs
 // @Accept json
 // @Produce json
@@ -162,6 +169,12 @@ func (h *CostHandler) List(w http.ResponseWriter, r *http.Request) {
 // @Failure 500 {string} string "Failed to update cost"
 // @Router /costs/{id} [put]
 func (h *CostHandler) Update(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "cost_update")
+		return
+	}
+
 	id, err := ParseUUIDFromPath(r, "id")
 	if err != nil {
 		h.errorHandler.HandleError(w, err, "cost_update")
@@ -175,11 +188,40 @@ func (h *CostHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 	req.ID = id
 
+	// Verify user has access to this cost
+	existing, err := h.svc.Get(r.Context(), id)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "cost_update")
+		return
+	}
+	if existing.UserID != user.ID {
+		h.errorHandler.HandleError(w, constant.ErrUnauthorized, "cost_update")
+		return
+	}
+	req.UserID = user.ID
+
+	// Tags are only linked by ID and must belong to the user, so they are
+	// replaced separately instead of being saved with the cost
+	var tagIDs []uuid.UUID
+	if req.Tags != nil {
+		tagIDs = make([]uuid.UUID, 0, len(req.Tags))
+		for _, t := range req.Tags {
+			tagIDs = append(tagIDs, t.ID)
+		}
+	}
+	req.Tags = nil
+
 	updatedCost, err := h.svc.Update(r.Context(), &req)
 	if err != nil {
 		h.errorHandler.HandleError(w, err, "cost_update")
 		return
 	}
+	if tagIDs != nil {
+		if err := h.svc.ReplaceTags(r.Context(), user.ID, updatedCost, tagIDs); err != nil {
+			h.errorHandler.HandleError(w, err, "cost_update")
+			return
+		}
+	}
 
 	h.errorHandler.HandleSuccess(w, http.StatusOK, *updatedCost)
 }
@@ -47,6 +47,24 @@ func (e *ErrorHandler) HandleError(w http.ResponseWriter, err error, operation s
 	case errors.Is(err, constant.ErrUsernameTaken):
 		statusCode = http.StatusConflict
 		message = "Username already taken"
+	case errors.Is(err, constant.ErrTagAlreadyExists):
+		statusCode = http.StatusConflict
+		message = "Tag already exists"
+	case errors.Is(err, constant.ErrCategoryExists):
+		statusCode = http.StatusConflict
+		message = "Category already exists"
+	case errors.Is(err, constant.ErrTemplateExists):
+		statusCode = http.StatusConflict
+		message = "Category template already exists"
+	case errors.Is(err, constant.ErrExportInProgress):
+		statusCode = http.StatusConflict
+		message = "An export is already in progress"
+	case errors.Is(err, constant.ErrFileTooLarge):
+		statusCode = http.StatusRequestEntityTooLarge
+		message = "File too large"
+	case errors.Is(err, constant.ErrUnsupportedMedia):
+		statusCode = http.StatusUnsupportedMediaType
+		message = "Unsupported media type"
 	default:
 		statusCode = http.StatusInternalServerError
 		message = "Internal server error"
@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tyha2404/nexo-app-api/internal/constant"
+	"github.com/tyha2404/nexo-app-api/internal/exporter"
+	"github.com/tyha2404/nexo-app-api/internal/repository"
+	"github.com/tyha2404/nexo-app-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type ExportHandler struct {
+	svc          service.ExportService
+	log          *zap.Logger
+	errorHandler *ErrorHandler
+}
+
+func NewExportHandler(svc service.ExportService, log *zap.Logger) *ExportHandler {
+	return &ExportHandler{
+		svc:          svc,
+		log:          log,
+		errorHandler: NewErrorHandler(log),
+	}
+}
+
+// Export handles a full data export
+// @Summary Export all data
+// @Description Export the current user's categories, budgets, transactions and costs. CSV exports are a zip archive with one file per section, XLSX exports have one sheet per section. The date range and tag filters apply to transactions and costs. Small exports are streamed directly; large ones, or any export with async=true, are queued as a background job and answered with 202 and the job to poll.
+// @Tags exports
+// @Produce octet-stream
+// @Produce json
+// @Security BearerAuth
+// @Param format query string true "Export format" Enums(csv, json, xlsx)
+// @Param from query string false "Start date, inclusive (YYYY-MM-DD)"
+// @Param to query string false "End date, inclusive (YYYY-MM-DD)"
+// @Param tags query string false "Comma-separated tag IDs"
+// @Param tagMode query string false "Match any or all of the tags" Enums(any, all)
+// @Param async query bool false "Always run the export as a background job"
+// @Success 200 {file} file
+// @Success 202 {object} response.BaseResponse[dto.ExportJobResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /exports [get]
+func (h *ExportHandler) Export(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "export")
+		return
+	}
+
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if !exporter.IsSupported(format) {
+		h.errorHandler.HandleError(w, fmt.Errorf("%w: format must be one of csv, json, xlsx", constant.ErrInvalidInput), "export")
+		return
+	}
+
+	filter, err := parseExportFilter(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "export")
+		return
+	}
+
+	async, _ := strconv.ParseBool(r.URL.Query().Get("async"))
+	if !async {
+		async, err = h.svc.ShouldRunAsync(r.Context(), user.ID, filter)
+		if err != nil {
+			h.errorHandler.HandleError(w, err, "export")
+			return
+		}
+	}
+
+	if async {
+		job, err := h.svc.StartJob(r.Context(), user.ID, format, filter)
+		if err != nil {
+			h.errorHandler.HandleError(w, err, "export")
+			return
+		}
+		h.errorHandler.HandleSuccess(w, http.StatusAccepted, job)
+		return
+	}
+
+	fileName := "nexo-export-" + time.Now().Format("20060102") + exporter.FileExtension(format)
+	w.Header().Set("Content-Type", exporter.ContentType(format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+
+	// The status is already sent, so a failure can only cut the file short
+	if err := h.svc.Export(r.Context(), user.ID, format, filter, w); err != nil {
+		h.log.Error("failed to stream export", zap.String("user_id", user.ID.String()), zap.Error(err))
+	}
+}
+
+// ListJobs handles listing background exports
+// @Summary List export jobs
+// @Description List the current user's background exports, newest first
+// @Tags exports
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.BaseResponse[[]dto.ExportJobResponse]
+// @Failure 500 {object} response.ErrorResponse
+// @Router /exports/jobs [get]
+func (h *ExportHandler) ListJobs(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "export_list_jobs")
+		return
+	}
+
+	jobs, err := h.svc.ListJobs(r.Context(), user.ID)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "export_list_jobs")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, jobs)
+}
+
+// GetJob handles fetching the status of a background export
+// @Summary Get an export job
+// @Description Get the status of a background export; completed jobs include a download URL
+// @Tags exports
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Export job ID"
+// @Success 200 {object} response.BaseResponse[dto.ExportJobResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /exports/jobs/{id} [get]
+func (h *ExportHandler) GetJob(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "export_get_job")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "export_get_job")
+		return
+	}
+
+	job, err := h.svc.GetJob(r.Context(), user.ID, id)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "export_get_job")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, job)
+}
+
+// DownloadJob handles downloading the file of a completed export
+// @Summary Download an export
+// @Description Download the file produced by a completed background export
+// @Tags exports
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param id path string true "Export job ID"
+// @Success 200 {file} file
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /exports/jobs/{id}/download [get]
+func (h *ExportHandler) DownloadJob(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "export_download")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "export_download")
+		return
+	}
+
+	job, content, err := h.svc.OpenJobFile(r.Context(), user.ID, id)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "export_download")
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", exporter.ContentType(job.Format))
+	w.Header().Set("Content-Length", strconv.FormatInt(job.Size, 10))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", job.FileName))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, content); err != nil {
+		h.log.Error("failed to stream export file", zap.String("job_id", id.String()), zap.Error(err))
+	}
+}
+
+// DeleteJob handles deleting a background export and its file
+// @Summary Delete an export job
+// @Description Delete a finished background export and its file
+// @Tags exports
+// @Security BearerAuth
+// @Param id path string true "Export job ID"
+// @Success 204
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /exports/jobs/{id} [delete]
+func (h *ExportHandler) DeleteJob(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "export_delete_job")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "export_delete_job")
+		return
+	}
+
+	if err := h.svc.DeleteJob(r.Context(), user.ID, id); err != nil {
+		h.errorHandler.HandleError(w, err, "export_delete_job")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseExportFilter reads the date range and the list filters shared with
+// the transaction and cost list endpoints
+func parseExportFilter(r *http.Request) (repository.ExportFilter, error) {
+	from, err := ParseQueryDate(r, "from")
+	if err != nil {
+		return repository.ExportFilter{}, err
+	}
+	to, err := ParseQueryDate(r, "to")
+	if err != nil {
+		return repository.ExportFilter{}, err
+	}
+	if to != nil {
+		// Make the end date inclusive
+		end := to.AddDate(0, 0, 1)
+		to = &end
+	}
+
+	return repository.ExportFilter{
+		From:    from,
+		To:      to,
+		Filters: BuildFilterMap(r, []string{"tags", "tagMode"}),
+	}, nil
+}
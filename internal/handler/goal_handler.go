@@ -0,0 +1,340 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/tyha2404/nexo-app-api/internal/dto"
+	"github.com/tyha2404/nexo-app-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type GoalHandler struct {
+	svc          service.GoalService
+	log          *zap.Logger
+	errorHandler *ErrorHandler
+	validator    *Validator
+}
+
+func NewGoalHandler(svc service.GoalService, log *zap.Logger) *GoalHandler {
+	return &GoalHandler{
+		svc:          svc,
+		log:          log,
+		errorHandler: NewErrorHandler(log),
+		validator:    NewValidator(),
+	}
+}
+
+// Create handles the creation of a savings goal
+// @Summary Create a savings goal
+// @Description Create a savings target, optionally with a deadline. Manual contributions, transactions in the linked category or with the linked tag from the start date on, and the latest value of the linked asset all count towards it.
+// @Tags goals
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param goal body dto.CreateGoalRequest true "Savings goal"
+// @Success 201 {object} response.BaseResponse[dto.GoalResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /goals [post]
+func (h *GoalHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "goal_create")
+		return
+	}
+
+	var req dto.CreateGoalRequest
+	if err := h.validator.ValidateRequest(r, &req); err != nil {
+		h.errorHandler.HandleValidationError(w, err, "goal_create")
+		return
+	}
+
+	goal, err := h.svc.CreateGoal(r.Context(), user.ID, req)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "goal_create")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusCreated, goal)
+}
+
+// List handles listing savings goals
+// @Summary List savings goals
+// @Description List the current user's savings goals with their progress, soonest deadline first
+// @Tags goals
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.BaseResponse[[]dto.GoalResponse]
+// @Failure 500 {object} response.ErrorResponse
+// @Router /goals [get]
+func (h *GoalHandler) List(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "goal_list")
+		return
+	}
+
+	goals, err := h.svc.ListGoals(r.Context(), user.ID)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "goal_list")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, goals)
+}
+
+// Get handles retrieving a single savings goal
+// @Summary Get a savings goal
+// @Description Get a savings goal with its progress and the monthly saving needed to reach it by the deadline
+// @Tags goals
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Goal ID"
+// @Success 200 {object} response.BaseResponse[dto.GoalResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /goals/{id} [get]
+func (h *GoalHandler) Get(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "goal_get")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "goal_get")
+		return
+	}
+
+	goal, err := h.svc.GetGoal(r.Context(), user.ID, id)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "goal_get")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, goal)
+}
+
+// Update handles updating a savings goal
+// @Summary Update a savings goal
+// @Description Change the target, dates or links of a savings goal
+// @Tags goals
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Goal ID"
+// @Param goal body dto.UpdateGoalRequest true "Fields to update"
+// @Success 200 {object} response.BaseResponse[dto.GoalResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /goals/{id} [put]
+func (h *GoalHandler) Update(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "goal_update")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "goal_update")
+		return
+	}
+
+	var req dto.UpdateGoalRequest
+	if err := h.validator.ValidateRequest(r, &req); err != nil {
+		h.errorHandler.HandleValidationError(w, err, "goal_update")
+		return
+	}
+
+	goal, err := h.svc.UpdateGoal(r.Context(), user.ID, id, req)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "goal_update")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, goal)
+}
+
+// Delete handles deleting a savings goal
+// @Summary Delete a savings goal
+// @Description Delete a savings goal with its manual contributions and alerts. Linked transactions are kept.
+// @Tags goals
+// @Security BearerAuth
+// @Param id path string true "Goal ID"
+// @Success 204
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /goals/{id} [delete]
+func (h *GoalHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "goal_delete")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "goal_delete")
+		return
+	}
+
+	if err := h.svc.DeleteGoal(r.Context(), user.ID, id); err != nil {
+		h.errorHandler.HandleError(w, err, "goal_delete")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddContribution handles recording money put towards a goal
+// @Summary Add a contribution
+// @Description Record money put towards a savings goal by hand; a negative amount records a withdrawal
+// @Tags goals
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Goal ID"
+// @Param contribution body dto.CreateGoalContributionRequest true "Contribution"
+// @Success 201 {object} response.BaseResponse[dto.GoalContributionResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /goals/{id}/contributions [post]
+func (h *GoalHandler) AddContribution(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "goal_contribution_create")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "goal_contribution_create")
+		return
+	}
+
+	var req dto.CreateGoalContributionRequest
+	if err := h.validator.ValidateRequest(r, &req); err != nil {
+		h.errorHandler.HandleValidationError(w, err, "goal_contribution_create")
+		return
+	}
+
+	contribution, err := h.svc.AddContribution(r.Context(), user.ID, id, req)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "goal_contribution_create")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusCreated, contribution)
+}
+
+// ListContributions handles listing what counts towards a goal
+// @Summary List contributions
+// @Description List the manual contributions of a savings goal together with the linked transactions counting towards it, most recent first
+// @Tags goals
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Goal ID"
+// @Success 200 {object} response.BaseResponse[[]dto.GoalContributionResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /goals/{id}/contributions [get]
+func (h *GoalHandler) ListContributions(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "goal_contribution_list")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "goal_contribution_list")
+		return
+	}
+
+	contributions, err := h.svc.ListContributions(r.Context(), user.ID, id)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "goal_contribution_list")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, contributions)
+}
+
+// DeleteContribution handles deleting a manual contribution
+// @Summary Delete a contribution
+// @Description Delete a manual contribution of a savings goal
+// @Tags goals
+// @Security BearerAuth
+// @Param id path string true "Goal ID"
+// @Param contributionId path string true "Contribution ID"
+// @Success 204
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /goals/{id}/contributions/{contributionId} [delete]
+func (h *GoalHandler) DeleteContribution(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "goal_contribution_delete")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "goal_contribution_delete")
+		return
+	}
+	contributionID, err := ParseUUIDFromPath(r, "contributionId")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "goal_contribution_delete")
+		return
+	}
+
+	if err := h.svc.DeleteContribution(r.Context(), user.ID, id, contributionID); err != nil {
+		h.errorHandler.HandleError(w, err, "goal_contribution_delete")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListAlerts handles listing the alerts of a goal
+// @Summary List goal alerts
+// @Description List the alerts raised when the savings goal fell behind pace or missed its deadline, most recent first
+// @Tags goals
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Goal ID"
+// @Success 200 {object} response.BaseResponse[[]dto.GoalAlertResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /goals/{id}/alerts [get]
+func (h *GoalHandler) ListAlerts(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "goal_alert_list")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "goal_alert_list")
+		return
+	}
+
+	alerts, err := h.svc.ListAlerts(r.Context(), user.ID, id)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "goal_alert_list")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, alerts)
+}
@@ -2,8 +2,12 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -60,6 +64,58 @@ func ParseQueryIntWithValidation(r *http.Request, key string, defaultValue int,
 	return value
 }
 
+// ParseQueryDate extracts an optional YYYY-MM-DD date from query parameters.
+// It returns nil when the parameter is absent.
+func ParseQueryDate(r *http.Request, key string) (*time.Time, error) {
+	valueStr := r.URL.Query().Get(key)
+	if valueStr == "" {
+		return nil, nil
+	}
+
+	value, err := time.Parse("2006-01-02", valueStr)
+	if err != nil {
+		return nil, constant.ErrInvalidInput
+	}
+
+	return &value, nil
+}
+
+// ParseQueryTimeZone extracts an optional IANA time zone name from query
+// parameters. It returns UTC when the parameter is absent.
+func ParseQueryTimeZone(r *http.Request, key string) (*time.Location, error) {
+	name := r.URL.Query().Get(key)
+	if name == "" {
+		return time.UTC, nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unknown time zone %q", constant.ErrInvalidInput, name)
+	}
+
+	return loc, nil
+}
+
+// OpenMultipartFile parses a multipart form of at most maxBytes and opens its
+// "file" part. The caller must remove the form's temporary files.
+func OpenMultipartFile(w http.ResponseWriter, r *http.Request, maxBytes int64) (multipart.File, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+	if err := r.ParseMultipartForm(maxBytes); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return nil, constant.ErrFileTooLarge
+		}
+		return nil, fmt.Errorf("%w: %v", constant.ErrInvalidInput, err)
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		r.MultipartForm.RemoveAll()
+		return nil, fmt.Errorf("%w: file is required", constant.ErrInvalidInput)
+	}
+	return file, nil
+}
+
 // DecodeJSONBody decodes JSON request body into the provided struct
 func DecodeJSONBody(r *http.Request, v interface{}) error {
 	return json.NewDecoder(r.Body).Decode(v)
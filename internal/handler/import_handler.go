@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/tyha2404/nexo-app-api/internal/dto"
+	"github.com/tyha2404/nexo-app-api/internal/service"
+	"go.uber.org/zap"
+)
+
+// importMaxBytes caps the size of an uploaded statement file
+const importMaxBytes = 10 << 20
+
+type ImportHandler struct {
+	svc          service.ImportService
+	log          *zap.Logger
+	errorHandler *ErrorHandler
+	validator    *Validator
+}
+
+func NewImportHandler(svc service.ImportService, log *zap.Logger) *ImportHandler {
+	return &ImportHandler{
+		svc:          svc,
+		log:          log,
+		errorHandler: NewErrorHandler(log),
+		validator:    NewValidator(),
+	}
+}
+
+// ImportCSV handles previewing or committing a CSV bank export
+// @Summary Import transactions from CSV
+// @Description Parse a bank CSV export with a column mapping or saved profile. Returns a dry-run preview with per-row errors and duplicates unless dryRun is false, in which case all rows are committed in a single database transaction. Rows no rule or recognized category covers go to the default category; a row of the type the default category is not meant for is invalid. A mapping given with saveProfileAs is only saved as a profile on a committed import.
+// @Tags imports
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "CSV file"
+// @Param request formData string true "JSON encoded dto.CSVImportRequest"
+// @Success 200 {object} response.BaseResponse[dto.ImportResultResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 413 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /imports/csv [post]
+func (h *ImportHandler) ImportCSV(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "import_csv")
+		return
+	}
+
+	file, err := OpenMultipartFile(w, r, importMaxBytes)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "import_csv")
+		return
+	}
+	defer file.Close()
+	defer r.MultipartForm.RemoveAll()
+
+	var req dto.CSVImportRequest
+	if err := json.Unmarshal([]byte(r.FormValue("request")), &req); err != nil {
+		h.errorHandler.HandleDecodeError(w, err, "import_csv")
+		return
+	}
+	if err := h.validator.ValidateStruct(&req); err != nil {
+		h.errorHandler.HandleValidationError(w, err, "import_csv")
+		return
+	}
+
+	result, err := h.svc.ImportCSV(r.Context(), user.ID, file, req)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "import_csv")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, result)
+}
+
+// ImportStatement handles previewing or committing an OFX, QFX, QIF, camt.053 or MT940 statement
+// @Summary Import a bank statement
+// @Description Parse an OFX 1.x/2.x, QFX, QIF, ISO 20022 camt.053 or SWIFT MT940 statement. INCOME or EXPENSE is derived from the sign of each amount and bank transaction IDs (FITID, AcctSvcrRef, MT940 references combined with date and amount) make re-imports idempotent. Categories are assigned as for CSV imports. For formats with opening and closing balances the preview reports whether the entries add up to the closing balance and whether the opening balance matches the balance of the account's transactions imported so far. Returns a dry-run preview unless dryRun is false.
+// @Tags imports
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param format path string true "Statement format" Enums(ofx, qfx, qif, camt053, mt940)
+// @Param file formData file true "Statement file"
+// @Param request formData string true "JSON encoded dto.StatementImportRequest"
+// @Success 200 {object} response.BaseResponse[dto.ImportResultResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 413 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /imports/{format} [post]
+func (h *ImportHandler) ImportStatement(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "import_statement")
+		return
+	}
+
+	file, err := OpenMultipartFile(w, r, importMaxBytes)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "import_statement")
+		return
+	}
+	defer file.Close()
+	defer r.MultipartForm.RemoveAll()
+
+	var req dto.StatementImportRequest
+	if err := json.Unmarshal([]byte(r.FormValue("request")), &req); err != nil {
+		h.errorHandler.HandleDecodeError(w, err, "import_statement")
+		return
+	}
+	if err := h.validator.ValidateStruct(&req); err != nil {
+		h.errorHandler.HandleValidationError(w, err, "import_statement")
+		return
+	}
+
+	format := strings.ToLower(chi.URLParam(r, "format"))
+	result, err := h.svc.ImportStatement(r.Context(), user.ID, format, file, req)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "import_statement")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, result)
+}
+
+// ListProfiles handles listing saved CSV import profiles
+// @Summary List import profiles
+// @Description List the current user's saved CSV column mappings
+// @Tags imports
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.BaseResponse[[]model.ImportProfile]
+// @Failure 500 {object} response.ErrorResponse
+// @Router /imports/profiles [get]
+func (h *ImportHandler) ListProfiles(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "import_profile_list")
+		return
+	}
+
+	profiles, err := h.svc.ListProfiles(r.Context(), user.ID)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "import_profile_list")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, profiles)
+}
+
+// DeleteProfile handles deleting a saved CSV import profile
+// @Summary Delete an import profile
+// @Description Delete a saved CSV column mapping
+// @Tags imports
+// @Security BearerAuth
+// @Param id path string true "Profile ID"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /imports/profiles/{id} [delete]
+func (h *ImportHandler) DeleteProfile(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "import_profile_delete")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "import_profile_delete")
+		return
+	}
+
+	if err := h.svc.DeleteProfile(r.Context(), user.ID, id); err != nil {
+		h.errorHandler.HandleError(w, err, "import_profile_delete")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
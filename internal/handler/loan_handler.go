@@ -0,0 +1,340 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/tyha2404/nexo-app-api/internal/dto"
+	"github.com/tyha2404/nexo-app-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type LoanHandler struct {
+	svc          service.LoanService
+	log          *zap.Logger
+	errorHandler *ErrorHandler
+	validator    *Validator
+}
+
+func NewLoanHandler(svc service.LoanService, log *zap.Logger) *LoanHandler {
+	return &LoanHandler{
+		svc:          svc,
+		log:          log,
+		errorHandler: NewErrorHandler(log),
+		validator:    NewValidator(),
+	}
+}
+
+// Create handles the creation of a loan
+// @Summary Create a loan
+// @Description Track money borrowed or lent, repaid in monthly instalments over the term
+// @Tags loans
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param loan body dto.CreateLoanRequest true "Loan"
+// @Success 201 {object} response.BaseResponse[dto.LoanResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /loans [post]
+func (h *LoanHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "loan_create")
+		return
+	}
+
+	var req dto.CreateLoanRequest
+	if err := h.validator.ValidateRequest(r, &req); err != nil {
+		h.errorHandler.HandleValidationError(w, err, "loan_create")
+		return
+	}
+
+	loan, err := h.svc.CreateLoan(r.Context(), user.ID, req)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "loan_create")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusCreated, loan)
+}
+
+// List handles listing loans
+// @Summary List loans
+// @Description List the current user's loans with their remaining balance
+// @Tags loans
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.BaseResponse[[]dto.LoanResponse]
+// @Failure 500 {object} response.ErrorResponse
+// @Router /loans [get]
+func (h *LoanHandler) List(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "loan_list")
+		return
+	}
+
+	loans, err := h.svc.ListLoans(r.Context(), user.ID)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "loan_list")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, loans)
+}
+
+// Get handles retrieving a single loan
+// @Summary Get a loan
+// @Description Get a loan with the principal and interest paid so far and the remaining balance
+// @Tags loans
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Loan ID"
+// @Success 200 {object} response.BaseResponse[dto.LoanResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /loans/{id} [get]
+func (h *LoanHandler) Get(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "loan_get")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "loan_get")
+		return
+	}
+
+	loan, err := h.svc.GetLoan(r.Context(), user.ID, id)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "loan_get")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, loan)
+}
+
+// Update handles updating a loan
+// @Summary Update a loan
+// @Description Change the terms of a loan. The split of recorded payments is worked out again with the new terms.
+// @Tags loans
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Loan ID"
+// @Param loan body dto.UpdateLoanRequest true "Fields to update"
+// @Success 200 {object} response.BaseResponse[dto.LoanResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /loans/{id} [put]
+func (h *LoanHandler) Update(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "loan_update")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "loan_update")
+		return
+	}
+
+	var req dto.UpdateLoanRequest
+	if err := h.validator.ValidateRequest(r, &req); err != nil {
+		h.errorHandler.HandleValidationError(w, err, "loan_update")
+		return
+	}
+
+	loan, err := h.svc.UpdateLoan(r.Context(), user.ID, id, req)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "loan_update")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, loan)
+}
+
+// Delete handles deleting a loan
+// @Summary Delete a loan
+// @Description Delete a loan. Its payment transactions are kept.
+// @Tags loans
+// @Security BearerAuth
+// @Param id path string true "Loan ID"
+// @Success 204
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /loans/{id} [delete]
+func (h *LoanHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "loan_delete")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "loan_delete")
+		return
+	}
+
+	if err := h.svc.DeleteLoan(r.Context(), user.ID, id); err != nil {
+		h.errorHandler.HandleError(w, err, "loan_delete")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Schedule handles retrieving the amortization schedule of a loan
+// @Summary Get the amortization schedule
+// @Description Get the monthly instalments of a loan as agreed, each split into principal and interest
+// @Tags loans
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Loan ID"
+// @Success 200 {object} response.BaseResponse[dto.LoanScheduleResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /loans/{id}/schedule [get]
+func (h *LoanHandler) Schedule(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "loan_schedule")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "loan_schedule")
+		return
+	}
+
+	schedule, err := h.svc.GetSchedule(r.Context(), user.ID, id)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "loan_schedule")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, schedule)
+}
+
+// AddPayment handles recording a loan repayment
+// @Summary Record a payment
+// @Description Book a repayment as a transaction linked to the loan, an expense on a borrowed loan and income on a lent one. The payment covers the interest accrued since the previous payment first, then the principal.
+// @Tags loans
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Loan ID"
+// @Param payment body dto.CreateLoanPaymentRequest true "Payment"
+// @Success 201 {object} response.BaseResponse[dto.LoanPaymentResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /loans/{id}/payments [post]
+func (h *LoanHandler) AddPayment(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "loan_payment_create")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "loan_payment_create")
+		return
+	}
+
+	var req dto.CreateLoanPaymentRequest
+	if err := h.validator.ValidateRequest(r, &req); err != nil {
+		h.errorHandler.HandleValidationError(w, err, "loan_payment_create")
+		return
+	}
+
+	payment, err := h.svc.AddPayment(r.Context(), user.ID, id, req)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "loan_payment_create")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusCreated, payment)
+}
+
+// ListPayments handles listing the payments of a loan
+// @Summary List payments
+// @Description List the payments of a loan in date order, each split into principal and interest. Payments whose transaction is in the trash do not count.
+// @Tags loans
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Loan ID"
+// @Success 200 {object} response.BaseResponse[[]dto.LoanPaymentResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /loans/{id}/payments [get]
+func (h *LoanHandler) ListPayments(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "loan_payment_list")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "loan_payment_list")
+		return
+	}
+
+	payments, err := h.svc.ListPayments(r.Context(), user.ID, id)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "loan_payment_list")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, payments)
+}
+
+// DeletePayment handles deleting a loan payment
+// @Summary Delete a payment
+// @Description Delete a payment of a loan and move its transaction to the trash
+// @Tags loans
+// @Security BearerAuth
+// @Param id path string true "Loan ID"
+// @Param paymentId path string true "Payment ID"
+// @Success 204
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /loans/{id}/payments/{paymentId} [delete]
+func (h *LoanHandler) DeletePayment(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "loan_payment_delete")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "loan_payment_delete")
+		return
+	}
+	paymentID, err := ParseUUIDFromPath(r, "paymentId")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "loan_payment_delete")
+		return
+	}
+
+	if err := h.svc.DeletePayment(r.Context(), user.ID, id, paymentID); err != nil {
+		h.errorHandler.HandleError(w, err, "loan_payment_delete")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
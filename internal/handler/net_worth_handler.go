@@ -0,0 +1,306 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/tyha2404/nexo-app-api/internal/dto"
+	"github.com/tyha2404/nexo-app-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type NetWorthHandler struct {
+	svc          service.NetWorthService
+	log          *zap.Logger
+	errorHandler *ErrorHandler
+	validator    *Validator
+}
+
+func NewNetWorthHandler(svc service.NetWorthService, log *zap.Logger) *NetWorthHandler {
+	return &NetWorthHandler{
+		svc:          svc,
+		log:          log,
+		errorHandler: NewErrorHandler(log),
+		validator:    NewValidator(),
+	}
+}
+
+// CreateItem handles the creation of an asset or liability
+// @Summary Create an asset or liability
+// @Description Create a manually valued asset, such as a house, car or investment, or a liability, such as a mortgage or loan, optionally with a first valuation
+// @Tags net-worth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param item body dto.CreateNetWorthItemRequest true "Asset or liability"
+// @Success 201 {object} response.BaseResponse[dto.NetWorthItemResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /net-worth/items [post]
+func (h *NetWorthHandler) CreateItem(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "net_worth_item_create")
+		return
+	}
+
+	var req dto.CreateNetWorthItemRequest
+	if err := h.validator.ValidateRequest(r, &req); err != nil {
+		h.errorHandler.HandleValidationError(w, err, "net_worth_item_create")
+		return
+	}
+
+	item, err := h.svc.CreateItem(r.Context(), user.ID, req)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "net_worth_item_create")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusCreated, item)
+}
+
+// ListItems handles listing assets and liabilities
+// @Summary List assets and liabilities
+// @Description List the current user's assets and liabilities with their most recent valuation
+// @Tags net-worth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.BaseResponse[[]dto.NetWorthItemResponse]
+// @Failure 500 {object} response.ErrorResponse
+// @Router /net-worth/items [get]
+func (h *NetWorthHandler) ListItems(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "net_worth_item_list")
+		return
+	}
+
+	items, err := h.svc.ListItems(r.Context(), user.ID)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "net_worth_item_list")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, items)
+}
+
+// GetItem handles retrieving a single asset or liability
+// @Summary Get an asset or liability
+// @Description Get an asset or liability with its most recent valuation
+// @Tags net-worth
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Item ID"
+// @Success 200 {object} response.BaseResponse[dto.NetWorthItemResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /net-worth/items/{id} [get]
+func (h *NetWorthHandler) GetItem(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "net_worth_item_get")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "net_worth_item_get")
+		return
+	}
+
+	item, err := h.svc.GetItem(r.Context(), user.ID, id)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "net_worth_item_get")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, item)
+}
+
+// UpdateItem handles updating an asset or liability
+// @Summary Update an asset or liability
+// @Description Rename an asset or liability or change its description; values are changed by adding a valuation
+// @Tags net-worth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Item ID"
+// @Param item body dto.UpdateNetWorthItemRequest true "Fields to update"
+// @Success 200 {object} response.BaseResponse[dto.NetWorthItemResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /net-worth/items/{id} [put]
+func (h *NetWorthHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "net_worth_item_update")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "net_worth_item_update")
+		return
+	}
+
+	var req dto.UpdateNetWorthItemRequest
+	if err := h.validator.ValidateRequest(r, &req); err != nil {
+		h.errorHandler.HandleValidationError(w, err, "net_worth_item_update")
+		return
+	}
+
+	item, err := h.svc.UpdateItem(r.Context(), user.ID, id, req)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "net_worth_item_update")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, item)
+}
+
+// DeleteItem handles deleting an asset or liability
+// @Summary Delete an asset or liability
+// @Description Delete an asset or liability together with its valuation history
+// @Tags net-worth
+// @Security BearerAuth
+// @Param id path string true "Item ID"
+// @Success 204
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /net-worth/items/{id} [delete]
+func (h *NetWorthHandler) DeleteItem(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "net_worth_item_delete")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "net_worth_item_delete")
+		return
+	}
+
+	if err := h.svc.DeleteItem(r.Context(), user.ID, id); err != nil {
+		h.errorHandler.HandleError(w, err, "net_worth_item_delete")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddValuation handles recording the value of an asset or liability
+// @Summary Add a valuation
+// @Description Record the value of an asset or liability on a date. The value holds until the next valuation; a valuation on a date that already has one replaces it.
+// @Tags net-worth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Item ID"
+// @Param valuation body dto.CreateValuationRequest true "Valuation"
+// @Success 201 {object} response.BaseResponse[dto.ValuationResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /net-worth/items/{id}/valuations [post]
+func (h *NetWorthHandler) AddValuation(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "net_worth_valuation_create")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "net_worth_valuation_create")
+		return
+	}
+
+	var req dto.CreateValuationRequest
+	if err := h.validator.ValidateRequest(r, &req); err != nil {
+		h.errorHandler.HandleValidationError(w, err, "net_worth_valuation_create")
+		return
+	}
+
+	valuation, err := h.svc.AddValuation(r.Context(), user.ID, id, req)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "net_worth_valuation_create")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusCreated, valuation)
+}
+
+// ListValuations handles listing the valuation history of an item
+// @Summary List valuations
+// @Description List the valuation history of an asset or liability, most recent first
+// @Tags net-worth
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Item ID"
+// @Success 200 {object} response.BaseResponse[[]dto.ValuationResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /net-worth/items/{id}/valuations [get]
+func (h *NetWorthHandler) ListValuations(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "net_worth_valuation_list")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "net_worth_valuation_list")
+		return
+	}
+
+	valuations, err := h.svc.ListValuations(r.Context(), user.ID, id)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "net_worth_valuation_list")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, valuations)
+}
+
+// DeleteValuation handles deleting a valuation
+// @Summary Delete a valuation
+// @Description Delete one valuation of an asset or liability
+// @Tags net-worth
+// @Security BearerAuth
+// @Param id path string true "Item ID"
+// @Param valuationId path string true "Valuation ID"
+// @Success 204
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /net-worth/items/{id}/valuations/{valuationId} [delete]
+func (h *NetWorthHandler) DeleteValuation(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "net_worth_valuation_delete")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "net_worth_valuation_delete")
+		return
+	}
+	valuationID, err := ParseUUIDFromPath(r, "valuationId")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "net_worth_valuation_delete")
+		return
+	}
+
+	if err := h.svc.DeleteValuation(r.Context(), user.ID, id, valuationID); err != nil {
+		h.errorHandler.HandleError(w, err, "net_worth_valuation_delete")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,317 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/tyha2404/nexo-app-api/internal/constant"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"github.com/tyha2404/nexo-app-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type ReportHandler struct {
+	svc          service.ReportService
+	log          *zap.Logger
+	errorHandler *ErrorHandler
+}
+
+func NewReportHandler(svc service.ReportService, log *zap.Logger) *ReportHandler {
+	return &ReportHandler{
+		svc:          svc,
+		log:          log,
+		errorHandler: NewErrorHandler(log),
+	}
+}
+
+// TagTotals handles the per-tag totals report
+// @Summary Per-tag totals
+// @Description Sum income, expenses and costs linked to each tag of the current user
+// @Tags reports
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "Start date, inclusive (YYYY-MM-DD)"
+// @Param to query string false "End date, inclusive (YYYY-MM-DD)"
+// @Success 200 {object} response.BaseResponse[[]dto.TagTotalResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /reports/tags [get]
+func (h *ReportHandler) TagTotals(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "report_tag_totals")
+		return
+	}
+
+	from, err := ParseQueryDate(r, "from")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "report_tag_totals")
+		return
+	}
+	to, err := ParseQueryDate(r, "to")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "report_tag_totals")
+		return
+	}
+	if to != nil {
+		// Make the end date inclusive
+		end := to.AddDate(0, 0, 1)
+		to = &end
+	}
+
+	totals, err := h.svc.TagTotals(r.Context(), user.ID, from, to)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "report_tag_totals")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, totals)
+}
+
+// Summary handles the income and expense summary report
+// @Summary Income and expense summary
+// @Description Sum the current user's income, expenses and net per day, week, month or year. Every period of the range is listed, periods without transactions as zeros. Transaction dates are calendar dates in the user's time zone; tz sets the zone of the returned period start times and of "today", which ends the range when to is omitted. Without from the range covers twelve periods. Weeks start on Monday.
+// @Tags reports
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "Start date, inclusive (YYYY-MM-DD)"
+// @Param to query string false "End date, inclusive (YYYY-MM-DD)"
+// @Param groupBy query string false "Period length, defaults to month" Enums(day, week, month, year)
+// @Param tz query string false "IANA time zone, defaults to UTC" example(Europe/Berlin)
+// @Success 200 {object} response.BaseResponse[dto.SummaryReportResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /reports/summary [get]
+func (h *ReportHandler) Summary(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "report_summary")
+		return
+	}
+
+	from, err := ParseQueryDate(r, "from")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "report_summary")
+		return
+	}
+	to, err := ParseQueryDate(r, "to")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "report_summary")
+		return
+	}
+
+	groupBy := r.URL.Query().Get("groupBy")
+	if groupBy == "" {
+		groupBy = service.GroupByMonth
+	}
+
+	loc, err := ParseQueryTimeZone(r, "tz")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "report_summary")
+		return
+	}
+
+	report, err := h.svc.Summary(r.Context(), user.ID, from, to, groupBy, loc)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "report_summary")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, report)
+}
+
+// Categories handles the category breakdown report
+// @Summary Spending by category
+// @Description Break the current user's expenses or income down by category with each category's share of the total, compared with the previous period of the same length. A range of whole calendar months is compared with the same number of months before it, any other range with the same number of days. Expense reports include costs. Without from and to the report covers the current month. With rollUp the activity of subcategories is added to their top-level category.
+// @Tags reports
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "Start date, inclusive (YYYY-MM-DD)"
+// @Param to query string false "End date, inclusive (YYYY-MM-DD)"
+// @Param type query string false "Transaction type, defaults to EXPENSE" Enums(EXPENSE, INCOME)
+// @Param rollUp query bool false "Add subcategories to their top-level category"
+// @Param tz query string false "IANA time zone, defaults to UTC" example(Europe/Berlin)
+// @Success 200 {object} response.BaseResponse[dto.CategoryReportResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /reports/categories [get]
+func (h *ReportHandler) Categories(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "report_categories")
+		return
+	}
+
+	from, err := ParseQueryDate(r, "from")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "report_categories")
+		return
+	}
+	to, err := ParseQueryDate(r, "to")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "report_categories")
+		return
+	}
+
+	txType := strings.ToUpper(r.URL.Query().Get("type"))
+	if txType == "" {
+		txType = string(model.TransactionTypeExpense)
+	}
+
+	loc, err := ParseQueryTimeZone(r, "tz")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "report_categories")
+		return
+	}
+
+	rollUp, _ := strconv.ParseBool(r.URL.Query().Get("rollUp"))
+
+	report, err := h.svc.Categories(r.Context(), user.ID, from, to, txType, rollUp, loc)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "report_categories")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, report)
+}
+
+// Forecast handles the cash-flow forecast
+// @Summary Cash-flow forecast
+// @Description Project the current user's balance at the end of each of the next days. The projection starts from the given balance, or the net of all transactions, applies weekly, biweekly, monthly, quarterly and yearly recurring income and expenses detected in the history, and subtracts the average daily discretionary spending of the lookback window. Lower and upper bound each day's balance with 80% confidence, based on how much daily spending varied.
+// @Tags reports
+// @Produce json
+// @Security BearerAuth
+// @Param days query int false "Days to project, 1 to 365, defaults to 30"
+// @Param lookbackDays query int false "Days of history the discretionary baseline is averaged over, 30 to 365, defaults to 90"
+// @Param balance query number false "Current balance to start from"
+// @Param tz query string false "IANA time zone, defaults to UTC" example(Europe/Berlin)
+// @Success 200 {object} response.BaseResponse[dto.ForecastResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /reports/forecast [get]
+func (h *ReportHandler) Forecast(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "report_forecast")
+		return
+	}
+
+	days := ParseQueryInt(r, "days", 30)
+	lookbackDays := ParseQueryInt(r, "lookbackDays", 90)
+	var balance *float64
+	if v := r.URL.Query().Get("balance"); v != "" {
+		b, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			h.errorHandler.HandleError(w, fmt.Errorf("%w: balance must be a number", constant.ErrInvalidInput), "report_forecast")
+			return
+		}
+		balance = &b
+	}
+
+	loc, err := ParseQueryTimeZone(r, "tz")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "report_forecast")
+		return
+	}
+
+	forecast, err := h.svc.Forecast(r.Context(), user.ID, days, lookbackDays, balance, loc)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "report_forecast")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, forecast)
+}
+
+// NetWorth handles the net worth report
+// @Summary Net worth over time
+// @Description Track the current user's net worth at the end of each day, week, month or year: cash, the net of all transactions, plus assets minus liabilities at their most recent valuation. Without from the range covers twelve periods, without to it ends today.
+// @Tags reports
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "Start date, inclusive (YYYY-MM-DD)"
+// @Param to query string false "End date, inclusive (YYYY-MM-DD)"
+// @Param groupBy query string false "Period length, defaults to month" Enums(day, week, month, year)
+// @Param tz query string false "IANA time zone, defaults to UTC" example(Europe/Berlin)
+// @Success 200 {object} response.BaseResponse[dto.NetWorthReportResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /reports/net-worth [get]
+func (h *ReportHandler) NetWorth(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "report_net_worth")
+		return
+	}
+
+	from, err := ParseQueryDate(r, "from")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "report_net_worth")
+		return
+	}
+	to, err := ParseQueryDate(r, "to")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "report_net_worth")
+		return
+	}
+
+	groupBy := r.URL.Query().Get("groupBy")
+	if groupBy == "" {
+		groupBy = service.GroupByMonth
+	}
+
+	loc, err := ParseQueryTimeZone(r, "tz")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "report_net_worth")
+		return
+	}
+
+	report, err := h.svc.NetWorth(r.Context(), user.ID, from, to, groupBy, loc)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "report_net_worth")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, report)
+}
+
+// Budgets handles the budget status report
+// @Summary Budget status
+// @Description Compare each of the current user's budgets with the expenses and costs of its current period. A budget covers its category and all of its subcategories. Monthly budgets restart on the day of month they started on, yearly ones on their start date. Budgets starting after date are left out.
+// @Tags reports
+// @Produce json
+// @Security BearerAuth
+// @Param date query string false "Day whose budget periods are reported (YYYY-MM-DD), defaults to today"
+// @Param tz query string false "IANA time zone, defaults to UTC" example(Europe/Berlin)
+// @Success 200 {object} response.BaseResponse[dto.BudgetReportResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /reports/budgets [get]
+func (h *ReportHandler) Budgets(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "report_budgets")
+		return
+	}
+
+	date, err := ParseQueryDate(r, "date")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "report_budgets")
+		return
+	}
+	loc, err := ParseQueryTimeZone(r, "tz")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "report_budgets")
+		return
+	}
+
+	report, err := h.svc.Budgets(r.Context(), user.ID, date, loc)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "report_budgets")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, report)
+}
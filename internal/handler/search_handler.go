@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/tyha2404/nexo-app-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type SearchHandler struct {
+	svc          service.SearchService
+	log          *zap.Logger
+	errorHandler *ErrorHandler
+}
+
+func NewSearchHandler(svc service.SearchService, log *zap.Logger) *SearchHandler {
+	return &SearchHandler{
+		svc:          svc,
+		log:          log,
+		errorHandler: NewErrorHandler(log),
+	}
+}
+
+// Search handles full-text search over transactions and costs
+// @Summary Search transactions and costs
+// @Description Search the current user's transaction descriptions, cost titles, category names and tag names, best matches first. Words match as prefixes and must all appear in the same field, "quoted words" match as a phrase and -word excludes a word. Matched words are wrapped in <mark> tags in the highlight. The query may also hold filters: amount>50, amount>=50, amount<50, amount<=50, amount:50, amount:10..50, category:food (a case-insensitive name prefix, also cat:), tag:travel, type:income or type:expense, in:transactions or in:costs, from:2024-03-01 and to:2024-03-31. Filter values with spaces are quoted, e.g. category:"eating out". Costs count as expenses.
+// @Tags search
+// @Produce json
+// @Security BearerAuth
+// @Param q query string true "Search words and filters, e.g. amazon amount>50 from:2024-03-01"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Results per page (max 100)" default(20)
+// @Success 200 {object} response.PaginationResponse[dto.SearchResultResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /search [get]
+func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "search")
+		return
+	}
+
+	page := ParseQueryIntWithValidation(r, "page", 1, 1)
+	limit := ParseQueryIntWithValidation(r, "limit", 20, 1)
+
+	results, total, err := h.svc.Search(r.Context(), user.ID, r.URL.Query().Get("q"), page, limit)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "search")
+		return
+	}
+
+	if limit > 100 {
+		limit = 100
+	}
+	h.errorHandler.HandlePaginatedSuccess(w, http.StatusOK, results, int(total), page, limit)
+}
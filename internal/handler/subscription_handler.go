@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/tyha2404/nexo-app-api/internal/dto"
+	"github.com/tyha2404/nexo-app-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type SubscriptionHandler struct {
+	svc          service.SubscriptionService
+	log          *zap.Logger
+	errorHandler *ErrorHandler
+	validator    *Validator
+}
+
+func NewSubscriptionHandler(svc service.SubscriptionService, log *zap.Logger) *SubscriptionHandler {
+	return &SubscriptionHandler{
+		svc:          svc,
+		log:          log,
+		errorHandler: NewErrorHandler(log),
+		validator:    NewValidator(),
+	}
+}
+
+// List handles listing detected subscriptions
+// @Summary List subscriptions
+// @Description Detect subscriptions in the current user's expenses: merchants charged about the same amount every week, two weeks, month, quarter or year, often enough and recently enough to still be running. Each comes with its estimated next charge and annual cost.
+// @Tags insights
+// @Produce json
+// @Security BearerAuth
+// @Param includeDismissed query bool false "Include dismissed subscriptions" default(false)
+// @Success 200 {object} response.BaseResponse[dto.SubscriptionsResponse]
+// @Failure 500 {object} response.ErrorResponse
+// @Router /insights/subscriptions [get]
+func (h *SubscriptionHandler) List(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "subscription_list")
+		return
+	}
+
+	includeDismissed, _ := strconv.ParseBool(r.URL.Query().Get("includeDismissed"))
+	subscriptions, err := h.svc.List(r.Context(), user.ID, includeDismissed)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "subscription_list")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, subscriptions)
+}
+
+// Confirm handles turning a subscription into a bill
+// @Summary Confirm a subscription
+// @Description Turn a detected monthly, quarterly or yearly subscription into a bill of the same cadence that starts with its next charge, so that it is tracked and reminded like any other bill. Deleting the bill makes the subscription show up as detected again.
+// @Tags insights
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Subscription ID"
+// @Param subscription body dto.ConfirmSubscriptionRequest true "Bill settings; send {} for the defaults"
+// @Success 201 {object} response.BaseResponse[dto.BillResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /insights/subscriptions/{id}/confirm [post]
+func (h *SubscriptionHandler) Confirm(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "subscription_confirm")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "subscription_confirm")
+		return
+	}
+
+	var req dto.ConfirmSubscriptionRequest
+	if err := h.validator.ValidateRequest(r, &req); err != nil {
+		h.errorHandler.HandleValidationError(w, err, "subscription_confirm")
+		return
+	}
+
+	bill, err := h.svc.Confirm(r.Context(), user.ID, id, req)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "subscription_confirm")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusCreated, bill)
+}
+
+// Dismiss handles dismissing a subscription
+// @Summary Dismiss a subscription
+// @Description Hide a detected subscription from the list and its totals
+// @Tags insights
+// @Security BearerAuth
+// @Param id path string true "Subscription ID"
+// @Success 204
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /insights/subscriptions/{id}/dismiss [post]
+func (h *SubscriptionHandler) Dismiss(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "subscription_dismiss")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "subscription_dismiss")
+		return
+	}
+
+	if err := h.svc.Dismiss(r.Context(), user.ID, id); err != nil {
+		h.errorHandler.HandleError(w, err, "subscription_dismiss")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Undismiss handles showing a dismissed subscription again
+// @Summary Undo a dismissal
+// @Description Show a dismissed subscription in the list again
+// @Tags insights
+// @Security BearerAuth
+// @Param id path string true "Subscription ID"
+// @Success 204
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /insights/subscriptions/{id}/dismiss [delete]
+func (h *SubscriptionHandler) Undismiss(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "subscription_undismiss")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "subscription_undismiss")
+		return
+	}
+
+	if err := h.svc.Undismiss(r.Context(), user.ID, id); err != nil {
+		h.errorHandler.HandleError(w, err, "subscription_undismiss")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
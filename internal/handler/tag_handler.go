@@ -0,0 +1,236 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/tyha2404/nexo-app-api/internal/dto"
+	"github.com/tyha2404/nexo-app-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type TagHandler struct {
+	svc          service.TagService
+	log          *zap.Logger
+	errorHandler *ErrorHandler
+	validator    *Validator
+}
+
+func NewTagHandler(svc service.TagService, log *zap.Logger) *TagHandler {
+	return &TagHandler{
+		svc:          svc,
+		log:          log,
+		errorHandler: NewErrorHandler(log),
+		validator:    NewValidator(),
+	}
+}
+
+// Create handles the creation of a new tag
+// @Summary Create a new tag
+// @Description Create a new user-scoped tag
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param tag body dto.CreateTagRequest true "Tag object"
+// @Success 201 {object} response.BaseResponse[dto.TagResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /tags [post]
+func (h *TagHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "tag_create")
+		return
+	}
+
+	var req dto.CreateTagRequest
+	if err := h.validator.ValidateRequest(r, &req); err != nil {
+		h.errorHandler.HandleValidationError(w, err, "tag_create")
+		return
+	}
+
+	tag, err := h.svc.CreateTag(r.Context(), user.ID, req)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "tag_create")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusCreated, tag)
+}
+
+// Get handles retrieving a single tag by ID
+// @Summary Get a tag by ID
+// @Description Get a tag by its ID
+// @Tags tags
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tag ID"
+// @Success 200 {object} response.BaseResponse[dto.TagResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /tags/{id} [get]
+func (h *TagHandler) Get(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "tag_get")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "tag_get")
+		return
+	}
+
+	tag, err := h.svc.GetTag(r.Context(), user.ID, id)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "tag_get")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, tag)
+}
+
+// List handles retrieving all tags of the current user
+// @Summary List tags
+// @Description Get all tags of the current user ordered by name
+// @Tags tags
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.BaseResponse[[]dto.TagResponse]
+// @Failure 500 {object} response.ErrorResponse
+// @Router /tags [get]
+func (h *TagHandler) List(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "tag_list")
+		return
+	}
+
+	tags, err := h.svc.ListTags(r.Context(), user.ID)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "tag_list")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, tags)
+}
+
+// Update handles renaming or recoloring a tag
+// @Summary Update a tag
+// @Description Rename or recolor an existing tag
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tag ID"
+// @Param tag body dto.UpdateTagRequest true "Tag fields to update"
+// @Success 200 {object} response.BaseResponse[dto.TagResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /tags/{id} [put]
+func (h *TagHandler) Update(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "tag_update")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "tag_update")
+		return
+	}
+
+	var req dto.UpdateTagRequest
+	if err := h.validator.ValidateRequest(r, &req); err != nil {
+		h.errorHandler.HandleValidationError(w, err, "tag_update")
+		return
+	}
+
+	tag, err := h.svc.UpdateTag(r.Context(), user.ID, id, req)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "tag_update")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, tag)
+}
+
+// Merge handles folding one tag into another
+// @Summary Merge a tag into another
+// @Description Move all transactions and costs of the tag onto the target tag and delete it
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Source tag ID"
+// @Param request body dto.MergeTagRequest true "Merge target"
+// @Success 200 {object} response.BaseResponse[dto.TagResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /tags/{id}/merge [post]
+func (h *TagHandler) Merge(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "tag_merge")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "tag_merge")
+		return
+	}
+
+	var req dto.MergeTagRequest
+	if err := h.validator.ValidateRequest(r, &req); err != nil {
+		h.errorHandler.HandleValidationError(w, err, "tag_merge")
+		return
+	}
+
+	tag, err := h.svc.MergeTags(r.Context(), user.ID, id, req.TargetID)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "tag_merge")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, tag)
+}
+
+// Delete handles deleting a tag by ID
+// @Summary Delete a tag
+// @Description Delete a tag and unlink it from all transactions and costs
+// @Tags tags
+// @Security BearerAuth
+// @Param id path string true "Tag ID"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /tags/{id} [delete]
+func (h *TagHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "tag_delete")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "tag_delete")
+		return
+	}
+
+	if err := h.svc.DeleteTag(r.Context(), user.ID, id); err != nil {
+		h.errorHandler.HandleError(w, err, "tag_delete")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
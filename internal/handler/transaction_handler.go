@@ -2,6 +2,7 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -58,6 +59,10 @@ func (h *TransactionHandler) CreateTransaction(w http.ResponseWriter, r *http.Re
 	userID := r.Context().Value(constant.UserContextKey).(model.User).ID
 	transaction, err := h.transactionService.CreateTransaction(r.Context(), userID, req)
 	if err != nil {
+		if errors.Is(err, constant.ErrInvalidInput) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		h.log.Error("failed to create transaction", zap.Error(err))
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -119,6 +124,8 @@ func (h *TransactionHandler) GetTransaction(w http.ResponseWriter, r *http.Reque
 // @Security BearerAuth
 // @Param page query int false "Page number"
 // @Param limit query int false "Page limit"
+// @Param tags query string false "Comma-separated tag IDs"
+// @Param tagMode query string false "Match any or all of the tags" Enums(any, all)
 // @Success 200 {object} response.PaginationResponse[dto.TransactionResponse]
 // @Failure 500 {object} response.ErrorResponse
 // @Router /transactions [get]
@@ -133,8 +140,10 @@ func (h *TransactionHandler) ListTransactions(w http.ResponseWriter, r *http.Req
 		limit = 10
 	}
 
+	filters := BuildFilterMap(r, []string{"tags", "tagMode"})
+
 	userID := r.Context().Value(constant.UserContextKey).(model.User).ID
-	transactions, total, err := h.transactionService.ListTransactions(r.Context(), userID, page, limit)
+	transactions, total, err := h.transactionService.ListTransactions(r.Context(), userID, page, limit, filters)
 	if err != nil {
 		h.log.Error("failed to list transactions", zap.Error(err))
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -187,6 +196,10 @@ func (h *TransactionHandler) UpdateTransaction(w http.ResponseWriter, r *http.Re
 	userID := r.Context().Value(constant.UserContextKey).(model.User).ID
 	transaction, err := h.transactionService.UpdateTransaction(r.Context(), userID, id, req)
 	if err != nil {
+		if errors.Is(err, constant.ErrInvalidInput) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		h.log.Error("failed to update transaction", zap.Error(err))
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -228,3 +241,146 @@ func (h *TransactionHandler) DeleteTransaction(w http.ResponseWriter, r *http.Re
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{"message": "Transaction deleted successfully"})
 }
+
+// SuggestCategory ranks the user's categories for a transaction description
+// @Summary Suggest a category
+// @Description Rank the user's categories for a description and amount, learned from their own categorized transactions
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.SuggestCategoryRequest true "Transaction to categorize"
+// @Success 200 {object} response.BaseResponse[dto.CategorySuggestionsResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /transactions/suggest-category [post]
+func (h *TransactionHandler) SuggestCategory(w http.ResponseWriter, r *http.Request) {
+	var req dto.SuggestCategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Error("failed to decode request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	userID := r.Context().Value(constant.UserContextKey).(model.User).ID
+	suggestions, err := h.transactionService.SuggestCategory(r.Context(), userID, req)
+	if err != nil {
+		h.log.Error("failed to suggest category", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response.BaseResponse[dto.CategorySuggestionsResponse]{
+		Status:  http.StatusOK,
+		Success: true,
+		Data:    *suggestions,
+	})
+}
+
+// FindDuplicates lists groups of transactions that look like the same payment
+// @Summary List likely duplicate transactions
+// @Description Group transactions with the same type and amount, close dates and similar descriptions for review
+// @Tags transactions
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "Start date (YYYY-MM-DD), defaults to 90 days before to"
+// @Param to query string false "End date (YYYY-MM-DD), defaults to today"
+// @Param windowDays query int false "Maximum days between duplicates (0-14)" default(3)
+// @Success 200 {object} response.BaseResponse[dto.DuplicatesResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /transactions/duplicates [get]
+func (h *TransactionHandler) FindDuplicates(w http.ResponseWriter, r *http.Request) {
+	from, err := ParseQueryDate(r, "from")
+	if err != nil {
+		http.Error(w, "Invalid from date, expected YYYY-MM-DD", http.StatusBadRequest)
+		return
+	}
+	to, err := ParseQueryDate(r, "to")
+	if err != nil {
+		http.Error(w, "Invalid to date, expected YYYY-MM-DD", http.StatusBadRequest)
+		return
+	}
+	windowDays := ParseQueryInt(r, "windowDays", 3)
+
+	userID := r.Context().Value(constant.UserContextKey).(model.User).ID
+	duplicates, err := h.transactionService.FindDuplicates(r.Context(), userID, from, to, windowDays)
+	if err != nil {
+		if errors.Is(err, constant.ErrInvalidInput) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		h.log.Error("failed to find duplicate transactions", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response.BaseResponse[dto.DuplicatesResponse]{
+		Status:  http.StatusOK,
+		Success: true,
+		Data:    *duplicates,
+	})
+}
+
+// MergeTransactions keeps one transaction and folds its duplicates into it
+// @Summary Merge duplicate transactions
+// @Description Keep the transaction and move the tags and attachments of the duplicates onto it. The duplicates are moved to the trash.
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID of the transaction to keep"
+// @Param request body dto.MergeTransactionsRequest true "Duplicates to merge"
+// @Success 200 {object} response.BaseResponse[dto.TransactionResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /transactions/{id}/merge [post]
+func (h *TransactionHandler) MergeTransactions(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid ID format", http.StatusBadRequest)
+		return
+	}
+
+	var req dto.MergeTransactionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Error("failed to decode request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	userID := r.Context().Value(constant.UserContextKey).(model.User).ID
+	transaction, err := h.transactionService.MergeTransactions(r.Context(), userID, id, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, constant.ErrInvalidInput):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, constant.ErrNotFound):
+			http.Error(w, "Transaction not found", http.StatusNotFound)
+		default:
+			h.log.Error("failed to merge transactions", zap.Error(err))
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response.BaseResponse[dto.TransactionResponse]{
+		Status:  http.StatusOK,
+		Success: true,
+		Data:    *transaction,
+	})
+}
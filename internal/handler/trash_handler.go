@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/tyha2404/nexo-app-api/internal/dto"
+	"github.com/tyha2404/nexo-app-api/internal/service"
+	"go.uber.org/zap"
+)
+
+// maxTrashPageSize caps the page size of the trash listing
+const maxTrashPageSize = 100
+
+type TrashHandler struct {
+	svc          service.TrashService
+	log          *zap.Logger
+	errorHandler *ErrorHandler
+	validator    *Validator
+}
+
+func NewTrashHandler(svc service.TrashService, log *zap.Logger) *TrashHandler {
+	return &TrashHandler{
+		svc:          svc,
+		log:          log,
+		errorHandler: NewErrorHandler(log),
+		validator:    NewValidator(),
+	}
+}
+
+// List handles listing deleted records
+// @Summary List the trash
+// @Description List the current user's deleted transactions, costs, categories and budgets, most recently deleted first. Each item shows when it will be permanently purged.
+// @Tags trash
+// @Produce json
+// @Security BearerAuth
+// @Param type query string false "Only list records of this type" Enums(transactions, costs, categories, budgets)
+// @Param page query int false "Page number"
+// @Param limit query int false "Page limit, at most 100"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /trash [get]
+func (h *TrashHandler) List(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "trash_list")
+		return
+	}
+
+	page := ParseQueryIntWithValidation(r, "page", 1, 1)
+	limit := min(ParseQueryIntWithValidation(r, "limit", 20, 1), maxTrashPageSize)
+
+	items, total, err := h.svc.List(r.Context(), user.ID, r.URL.Query().Get("type"), page, limit)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "trash_list")
+		return
+	}
+
+	h.errorHandler.HandlePaginatedSuccess(w, http.StatusOK, items, int(total), page, limit)
+}
+
+// Restore handles taking a record out of the trash
+// @Summary Restore a deleted record
+// @Description Undo the deletion of a transaction, cost, category or budget. Transactions, costs and budgets can only be restored while their category is active. A category whose name has been reused since it was deleted is rejected with 409 unless the body asks for a new name or onConflict=rename.
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param type path string true "Record type" Enums(transactions, costs, categories, budgets)
+// @Param id path string true "Record ID"
+// @Param request body dto.RestoreTrashRequest false "Category name conflict handling"
+// @Success 200 {object} response.BaseResponse[dto.RestoredItemResponse]
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /trash/{type}/{id}/restore [post]
+func (h *TrashHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	user, err := GetUserFromContext(r)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "trash_restore")
+		return
+	}
+
+	id, err := ParseUUIDFromPath(r, "id")
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "trash_restore")
+		return
+	}
+
+	// The body is optional
+	var req dto.RestoreTrashRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.errorHandler.HandleDecodeError(w, err, "trash_restore")
+		return
+	}
+	if err := h.validator.ValidateStruct(&req); err != nil {
+		h.errorHandler.HandleValidationError(w, err, "trash_restore")
+		return
+	}
+
+	restored, err := h.svc.Restore(r.Context(), user.ID, chi.URLParam(r, "type"), id, req)
+	if err != nil {
+		h.errorHandler.HandleError(w, err, "trash_restore")
+		return
+	}
+
+	h.errorHandler.HandleSuccess(w, http.StatusOK, restored)
+}
@@ -15,12 +15,13 @@ import (
 )
 
 type UserHandler struct {
-	svc service.UserService
-	log *zap.Logger
+	svc         service.UserService
+	deletionSvc service.AccountDeletionService
+	log         *zap.Logger
 }
 
-func NewUserHandler(svc service.UserService, log *zap.Logger) *UserHandler {
-	return &UserHandler{svc: svc, log: log}
+func NewUserHandler(svc service.UserService, deletionSvc service.AccountDeletionService, log *zap.Logger) *UserHandler {
+	return &UserHandler{svc: svc, deletionSvc: deletionSvc, log: log}
 }
 
 // Create handles the creation of a new user record
@@ -203,11 +204,14 @@ func (h *UserHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 // Delete handles deleting a user by ID
 // @Summary Delete a user
-// @Description Delete a user by its ID
+// @Description Permanently delete a user and all of their data right away, skipping the grace period of self-service deletion. Admin only.
 // @Tags users
+// @Security BearerAuth
 // @Param id path string true "User ID"
 // @Success 204 {string} string "No Content"
 // @Failure 400 {string} string "Invalid user ID"
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
 // @Failure 404 {string} string "User not found"
 // @Failure 500 {string} string "Failed to delete user"
 // @Router /users/{id} [delete]
@@ -219,7 +223,7 @@ func (h *UserHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.svc.Delete(r.Context(), id); err != nil {
+	if err := h.deletionSvc.PurgeNow(r.Context(), id); err != nil {
 		if err == constant.ErrNotFound {
 			http.Error(w, "User not found", http.StatusNotFound)
 			return
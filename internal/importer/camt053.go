@@ -0,0 +1,224 @@
+package importer
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// camtDocument maps the parts of an ISO 20022 camt.053 (BankToCustomerStatement)
+// message that are needed for an import. Elements are matched by local name so
+// every camt.053.001.xx namespace version is accepted.
+type camtDocument struct {
+	Statements []camtStatement `xml:"BkToCstmrStmt>Stmt"`
+}
+
+type camtStatement struct {
+	IBAN     string        `xml:"Acct>Id>IBAN"`
+	OtherID  string        `xml:"Acct>Id>Othr>Id"`
+	Currency string        `xml:"Acct>Ccy"`
+	Balances []camtBalance `xml:"Bal"`
+	Entries  []camtEntry   `xml:"Ntry"`
+}
+
+type camtBalance struct {
+	Code    string     `xml:"Tp>CdOrPrtry>Cd"`
+	Amount  camtAmount `xml:"Amt"`
+	Credit  string     `xml:"CdtDbtInd"`
+	Date    string     `xml:"Dt>Dt"`
+	DateTme string     `xml:"Dt>DtTm"`
+}
+
+type camtAmount struct {
+	Value    string `xml:",chardata"`
+	Currency string `xml:"Ccy,attr"`
+}
+
+type camtEntry struct {
+	Reference      string         `xml:"NtryRef"`
+	Amount         camtAmount     `xml:"Amt"`
+	Credit         string         `xml:"CdtDbtInd"`
+	Reversal       bool           `xml:"RvslInd"`
+	Status         camtStatus     `xml:"Sts"`
+	BookingDate    string         `xml:"BookgDt>Dt"`
+	BookingDateTme string         `xml:"BookgDt>DtTm"`
+	ValueDate      string         `xml:"ValDt>Dt"`
+	ServicerRef    string         `xml:"AcctSvcrRef"`
+	AdditionalInfo string         `xml:"AddtlNtryInf"`
+	Details        []camtTxDetail `xml:"NtryDtls>TxDtls"`
+}
+
+// camtStatus holds the entry status, which is a plain code up to
+// camt.053.001.02 and wrapped in <Cd> in later versions
+type camtStatus struct {
+	Value string `xml:",chardata"`
+	Code  string `xml:"Cd"`
+}
+
+type camtTxDetail struct {
+	EndToEndID   string   `xml:"Refs>EndToEndId"`
+	CreditorName string   `xml:"RltdPties>Cdtr>Nm"`
+	CreditorPty  string   `xml:"RltdPties>Cdtr>Pty>Nm"`
+	DebtorName   string   `xml:"RltdPties>Dbtr>Nm"`
+	DebtorPty    string   `xml:"RltdPties>Dbtr>Pty>Nm"`
+	Unstructured []string `xml:"RmtInf>Ustrd"`
+}
+
+// ParseCAMT053 reads an ISO 20022 camt.053 statement. Pending entries are
+// skipped because the bank may still change or cancel them. When the file
+// holds several statements their entries are concatenated, the opening
+// balance is taken from the first one and the closing balance from the last.
+func ParseCAMT053(r io.Reader) (*Statement, error) {
+	var doc camtDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid camt.053 document: %w", err)
+	}
+	if len(doc.Statements) == 0 {
+		return nil, errors.New("invalid camt.053 document: no Stmt element")
+	}
+
+	stmt := &Statement{}
+	row := 0
+	for i, s := range doc.Statements {
+		if stmt.AccountID == "" {
+			stmt.AccountID = s.IBAN
+			if stmt.AccountID == "" {
+				stmt.AccountID = s.OtherID
+			}
+			stmt.Currency = s.Currency
+		}
+
+		for _, b := range s.Balances {
+			balance, err := b.balance()
+			if err != nil {
+				continue
+			}
+			switch b.Code {
+			case "OPBD", "PRCD":
+				if i == 0 && stmt.OpeningBalance == nil {
+					stmt.OpeningBalance = balance
+				}
+			case "CLBD":
+				stmt.ClosingBalance = balance
+			}
+		}
+
+		for _, e := range s.Entries {
+			if e.pending() {
+				continue
+			}
+			row++
+			stmt.Entries = append(stmt.Entries, e.entry(row))
+		}
+	}
+
+	if stmt.Currency == "" && stmt.ClosingBalance != nil {
+		for _, b := range doc.Statements[len(doc.Statements)-1].Balances {
+			if b.Code == "CLBD" {
+				stmt.Currency = b.Amount.Currency
+			}
+		}
+	}
+
+	return stmt, nil
+}
+
+func (b camtBalance) balance() (*Balance, error) {
+	amount, err := ParseAmount(b.Amount.Value, ".")
+	if err != nil {
+		return nil, err
+	}
+	if b.Credit == "DBIT" {
+		amount = -amount
+	}
+	date, err := camtDate(b.Date, b.DateTme)
+	if err != nil {
+		return nil, err
+	}
+	return &Balance{Amount: amount, Date: date}, nil
+}
+
+// pending reports whether the entry is not booked yet
+func (e camtEntry) pending() bool {
+	status := strings.TrimSpace(e.Status.Code)
+	if status == "" {
+		status = strings.TrimSpace(e.Status.Value)
+	}
+	return status == "PDNG" || status == "INFO"
+}
+
+func (e camtEntry) entry(row int) Entry {
+	entry := Entry{Row: row}
+
+	date, err := camtDate(e.BookingDate, e.BookingDateTme)
+	if err != nil && e.ValueDate != "" {
+		date, err = camtDate(e.ValueDate, "")
+	}
+	if err != nil {
+		entry.Errors = append(entry.Errors, "booking date is missing or invalid")
+	} else {
+		entry.Date = date
+	}
+
+	amount, err := ParseAmount(e.Amount.Value, ".")
+	if err != nil {
+		entry.Errors = append(entry.Errors, err.Error())
+	} else {
+		// CdtDbtInd gives the actual direction, reversals included; the
+		// reversal indicator only says that the entry undoes an earlier one
+		if e.Credit == "DBIT" {
+			amount = -amount
+		}
+		entry.Amount = amount
+	}
+
+	entry.ExternalID = e.ServicerRef
+	if entry.ExternalID == "" {
+		entry.ExternalID = e.Reference
+	}
+
+	var parts []string
+	for _, d := range e.Details {
+		name := firstNonEmpty(d.CreditorName, d.CreditorPty)
+		if entry.Amount > 0 {
+			name = firstNonEmpty(d.DebtorName, d.DebtorPty)
+		}
+		if name != "" {
+			parts = append(parts, name)
+		}
+		parts = append(parts, d.Unstructured...)
+		if entry.ExternalID == "" && d.EndToEndID != "" && d.EndToEndID != "NOTPROVIDED" {
+			entry.ExternalID = d.EndToEndID
+		}
+	}
+	if len(parts) == 0 && e.AdditionalInfo != "" {
+		parts = append(parts, e.AdditionalInfo)
+	}
+	if e.Reversal {
+		parts = append([]string{"Reversal:"}, parts...)
+	}
+	entry.Description = strings.Join(strings.Fields(strings.Join(parts, " ")), " ")
+	return entry
+}
+
+func camtDate(date, dateTime string) (time.Time, error) {
+	if date = strings.TrimSpace(date); date != "" {
+		return time.Parse("2006-01-02", date)
+	}
+	if dateTime = strings.TrimSpace(dateTime); len(dateTime) >= 10 {
+		return time.Parse("2006-01-02", dateTime[:10])
+	}
+	return time.Time{}, errors.New("date is missing")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,98 @@
+package importer
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+const camt053Statement = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.08">
+<BkToCstmrStmt><Stmt>
+  <Acct><Id><IBAN>DE89370400440532013000</IBAN></Id><Ccy>EUR</Ccy></Acct>
+  <Bal><Tp><CdOrPrtry><Cd>OPBD</Cd></CdOrPrtry></Tp><Amt Ccy="EUR">1000.00</Amt><CdtDbtInd>CRDT</CdtDbtInd><Dt><Dt>2024-01-01</Dt></Dt></Bal>
+  <Bal><Tp><CdOrPrtry><Cd>CLBD</Cd></CdOrPrtry></Tp><Amt Ccy="EUR">1021.30</Amt><CdtDbtInd>CRDT</CdtDbtInd><Dt><Dt>2024-01-31</Dt></Dt></Bal>
+  <Ntry>
+    <Amt Ccy="EUR">45.20</Amt><CdtDbtInd>DBIT</CdtDbtInd><Sts><Cd>BOOK</Cd></Sts>
+    <BookgDt><Dt>2024-01-05</Dt></BookgDt><AcctSvcrRef>REF-1</AcctSvcrRef>
+    <NtryDtls><TxDtls>
+      <RltdPties><Cdtr><Pty><Nm>ACME Market</Nm></Pty></Cdtr></RltdPties>
+      <RmtInf><Ustrd>Weekly shop</Ustrd></RmtInf>
+    </TxDtls></NtryDtls>
+  </Ntry>
+  <Ntry>
+    <Amt Ccy="EUR">45.20</Amt><CdtDbtInd>CRDT</CdtDbtInd><RvslInd>true</RvslInd><Sts><Cd>BOOK</Cd></Sts>
+    <BookgDt><DtTm>2024-01-06T10:00:00</DtTm></BookgDt><AcctSvcrRef>REF-2</AcctSvcrRef>
+    <NtryDtls><TxDtls>
+      <RltdPties><Dbtr><Nm>ACME Market</Nm></Dbtr></RltdPties>
+    </TxDtls></NtryDtls>
+  </Ntry>
+  <Ntry>
+    <Amt Ccy="EUR">21.30</Amt><CdtDbtInd>CRDT</CdtDbtInd><Sts>BOOK</Sts>
+    <ValDt><Dt>2024-01-20</Dt></ValDt>
+    <AddtlNtryInf>Interest</AddtlNtryInf>
+    <NtryDtls><TxDtls><Refs><EndToEndId>E2E-9</EndToEndId></Refs></TxDtls></NtryDtls>
+  </Ntry>
+  <Ntry>
+    <Amt Ccy="EUR">500.00</Amt><CdtDbtInd>DBIT</CdtDbtInd><Sts><Cd>PDNG</Cd></Sts>
+    <BookgDt><Dt>2024-01-30</Dt></BookgDt>
+  </Ntry>
+</Stmt></BkToCstmrStmt>
+</Document>`
+
+func TestParseCAMT053(t *testing.T) {
+	stmt, err := ParseCAMT053(strings.NewReader(camt053Statement))
+	if err != nil {
+		t.Fatalf("ParseCAMT053: %v", err)
+	}
+	if stmt.AccountID != "DE89370400440532013000" || stmt.Currency != "EUR" {
+		t.Errorf("account = %q, currency = %q", stmt.AccountID, stmt.Currency)
+	}
+	if stmt.OpeningBalance == nil || stmt.OpeningBalance.Amount != 1000 ||
+		stmt.ClosingBalance == nil || stmt.ClosingBalance.Amount != 1021.30 {
+		t.Fatalf("balances = %+v, %+v", stmt.OpeningBalance, stmt.ClosingBalance)
+	}
+	if len(stmt.Entries) != 3 {
+		t.Fatalf("got %d entries, want 3 (pending entries are skipped)", len(stmt.Entries))
+	}
+
+	tests := []struct {
+		amount      float64
+		date        string
+		externalID  string
+		description string
+	}{
+		{-45.20, "2024-01-05", "REF-1", "ACME Market Weekly shop"},
+		// The reversal is a credit as CdtDbtInd says, not flipped to a debit
+		{45.20, "2024-01-06", "REF-2", "Reversal: ACME Market"},
+		{21.30, "2024-01-20", "E2E-9", "Interest"},
+	}
+	for i, tt := range tests {
+		e := stmt.Entries[i]
+		if !e.Valid() || e.Row != i+1 || e.Amount != tt.amount || e.Date.Format("2006-01-02") != tt.date ||
+			e.ExternalID != tt.externalID || e.Description != tt.description {
+			t.Errorf("entry %d = %+v, want %+v", i, e, tt)
+		}
+	}
+
+	// Opening balance plus the booked entries gives the closing balance
+	total := stmt.OpeningBalance.Amount
+	for _, e := range stmt.Entries {
+		total += e.Amount
+	}
+	if math.Abs(total-stmt.ClosingBalance.Amount) > 0.005 {
+		t.Errorf("opening + entries = %.2f, closing = %.2f", total, stmt.ClosingBalance.Amount)
+	}
+	if !stmt.ClosingBalance.Date.Equal(time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("closing date = %s", stmt.ClosingBalance.Date)
+	}
+}
+
+func TestParseCAMT053Invalid(t *testing.T) {
+	for _, input := range []string{"not xml", `<Document><BkToCstmrStmt></BkToCstmrStmt></Document>`} {
+		if _, err := ParseCAMT053(strings.NewReader(input)); err == nil {
+			t.Errorf("ParseCAMT053(%q): expected an error", input)
+		}
+	}
+}
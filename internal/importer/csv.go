@@ -0,0 +1,315 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// CSVOptions describes how to read a bank's CSV export. Column references are
+// either a header name (when HasHeader is set) or a 1-based column index.
+type CSVOptions struct {
+	Delimiter         string
+	DateFormat        string
+	DecimalSeparator  string
+	HasHeader         bool
+	DateColumn        string
+	AmountColumn      string
+	DebitColumn       string
+	CreditColumn      string
+	DescriptionColumn string
+	TypeColumn        string
+	CategoryColumn    string
+}
+
+// Validate checks that the options describe a readable layout
+func (o CSVOptions) Validate() error {
+	if o.Delimiter != "" && utf8.RuneCountInString(o.Delimiter) != 1 {
+		return errors.New("delimiter must be a single character")
+	}
+	if o.DecimalSeparator != "" && o.DecimalSeparator != "." && o.DecimalSeparator != "," {
+		return errors.New("decimalSeparator must be '.' or ','")
+	}
+	if o.DateFormat == "" {
+		return errors.New("dateFormat is required")
+	}
+	if o.DateColumn == "" {
+		return errors.New("dateColumn is required")
+	}
+	if o.AmountColumn == "" && o.DebitColumn == "" && o.CreditColumn == "" {
+		return errors.New("amountColumn or debitColumn/creditColumn is required")
+	}
+	return nil
+}
+
+// csvColumns holds resolved 0-based column positions, -1 when unmapped
+type csvColumns struct {
+	date, amount, debit, credit, description, kind, category int
+}
+
+// ParseCSV reads r according to opts. Rows that cannot be parsed are returned
+// with their Errors populated rather than failing the whole file.
+func ParseCSV(r io.Reader, opts CSVOptions) (*Statement, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(r)
+	reader.Comma = ','
+	if opts.Delimiter != "" {
+		reader.Comma, _ = utf8.DecodeRuneInString(opts.Delimiter)
+	}
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	var header []string
+	if opts.HasHeader {
+		record, err := reader.Read()
+		if err != nil {
+			if err == io.EOF {
+				return &Statement{}, nil
+			}
+			return nil, fmt.Errorf("read header: %w", err)
+		}
+		header = record
+		if len(header) > 0 {
+			header[0] = strings.TrimPrefix(header[0], "\ufeff")
+		}
+	}
+
+	cols, err := resolveColumns(opts, header)
+	if err != nil {
+		return nil, err
+	}
+	layout := DateLayout(opts.DateFormat)
+
+	stmt := &Statement{}
+	row := 0
+	if opts.HasHeader {
+		row = 1
+	}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		row++
+		if err != nil {
+			stmt.Entries = append(stmt.Entries, Entry{Row: row, Errors: []string{err.Error()}})
+			continue
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+		stmt.Entries = append(stmt.Entries, parseCSVRecord(row, record, cols, layout, opts.DecimalSeparator))
+	}
+
+	return stmt, nil
+}
+
+func parseCSVRecord(row int, record []string, cols csvColumns, layout, decimalSeparator string) Entry {
+	entry := Entry{Row: row}
+	field := func(i int) string {
+		if i < 0 || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	if raw := field(cols.date); raw == "" {
+		entry.Errors = append(entry.Errors, "date is missing")
+	} else if t, err := time.Parse(layout, raw); err != nil {
+		entry.Errors = append(entry.Errors, fmt.Sprintf("invalid date %q", raw))
+	} else {
+		entry.Date = t
+	}
+
+	amount, err := entryAmount(field(cols.amount), field(cols.debit), field(cols.credit), cols, decimalSeparator)
+	if err != nil {
+		entry.Errors = append(entry.Errors, err.Error())
+	} else {
+		entry.Amount = amount
+	}
+
+	if cols.kind >= 0 {
+		switch normalizeKind(field(cols.kind)) {
+		case "in":
+			entry.Amount = math.Abs(entry.Amount)
+		case "out":
+			entry.Amount = -math.Abs(entry.Amount)
+		case "":
+			// Fall back to the sign of the amount
+		default:
+			entry.Errors = append(entry.Errors, fmt.Sprintf("unknown type %q", field(cols.kind)))
+		}
+	}
+
+	entry.Description = field(cols.description)
+	entry.CategoryName = field(cols.category)
+	return entry
+}
+
+// entryAmount derives the signed amount from either a single amount column or
+// separate debit/credit columns
+func entryAmount(amount, debit, credit string, cols csvColumns, decimalSeparator string) (float64, error) {
+	if cols.amount >= 0 {
+		if amount == "" {
+			return 0, errors.New("amount is missing")
+		}
+		return ParseAmount(amount, decimalSeparator)
+	}
+
+	var total float64
+	if debit != "" {
+		v, err := ParseAmount(debit, decimalSeparator)
+		if err != nil {
+			return 0, err
+		}
+		total -= math.Abs(v)
+	}
+	if credit != "" {
+		v, err := ParseAmount(credit, decimalSeparator)
+		if err != nil {
+			return 0, err
+		}
+		total += math.Abs(v)
+	}
+	if debit == "" && credit == "" {
+		return 0, errors.New("amount is missing")
+	}
+	return total, nil
+}
+
+// ParseAmount parses a localized amount such as "1.234,56", "-12.50",
+// "(12.50)" or "12.50-". decimalSeparator defaults to ".".
+func ParseAmount(raw, decimalSeparator string) (float64, error) {
+	s := strings.TrimSpace(raw)
+	negative := false
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		negative = true
+		s = s[1 : len(s)-1]
+	}
+	if strings.HasSuffix(s, "-") {
+		negative = true
+		s = strings.TrimSuffix(s, "-")
+	}
+
+	thousands := ","
+	if decimalSeparator == "," {
+		thousands = "."
+	}
+
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case string(r) == decimalSeparator || (decimalSeparator == "" && r == '.'):
+			b.WriteRune('.')
+		case r == '-':
+			negative = !negative
+		case r == '+', string(r) == thousands, r == ' ', r == '\u00a0', r == '\'':
+			// Signs and grouping characters carry no value
+		case strings.ContainsRune("$€£¥₫", r) || (r >= 'A' && r <= 'Z'):
+			// Currency symbols and codes
+		default:
+			return 0, fmt.Errorf("invalid amount %q", raw)
+		}
+	}
+
+	v, err := strconv.ParseFloat(b.String(), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", raw)
+	}
+	if negative {
+		v = -v
+	}
+	return v, nil
+}
+
+// DateLayout converts a user facing pattern such as "DD/MM/YYYY" into a Go
+// time layout. Patterns that already are Go layouts are returned unchanged.
+func DateLayout(format string) string {
+	if strings.Contains(format, "2006") || strings.Contains(format, "06") && strings.Contains(format, "01") {
+		return format
+	}
+	replacer := strings.NewReplacer(
+		"YYYY", "2006", "yyyy", "2006",
+		"YY", "06", "yy", "06",
+		"MMM", "Jan",
+		"MM", "01",
+		"DD", "02", "dd", "02",
+		"M", "1",
+		"D", "2", "d", "2",
+	)
+	return replacer.Replace(format)
+}
+
+func resolveColumns(opts CSVOptions, header []string) (csvColumns, error) {
+	var cols csvColumns
+	var err error
+	resolve := func(ref string, required bool, name string) int {
+		if err != nil {
+			return -1
+		}
+		if ref == "" {
+			if required {
+				err = fmt.Errorf("%s is required", name)
+			}
+			return -1
+		}
+		if n, convErr := strconv.Atoi(ref); convErr == nil {
+			if n < 1 {
+				err = fmt.Errorf("%s must be a 1-based column index", name)
+				return -1
+			}
+			return n - 1
+		}
+		for i, h := range header {
+			if strings.EqualFold(strings.TrimSpace(h), strings.TrimSpace(ref)) {
+				return i
+			}
+		}
+		err = fmt.Errorf("%s %q not found in header", name, ref)
+		return -1
+	}
+
+	cols.date = resolve(opts.DateColumn, true, "dateColumn")
+	cols.amount = resolve(opts.AmountColumn, false, "amountColumn")
+	cols.debit = resolve(opts.DebitColumn, false, "debitColumn")
+	cols.credit = resolve(opts.CreditColumn, false, "creditColumn")
+	cols.description = resolve(opts.DescriptionColumn, false, "descriptionColumn")
+	cols.kind = resolve(opts.TypeColumn, false, "typeColumn")
+	cols.category = resolve(opts.CategoryColumn, false, "categoryColumn")
+	return cols, err
+}
+
+// normalizeKind maps the many ways banks spell direction to "in" or "out"
+func normalizeKind(raw string) string {
+	switch strings.ToUpper(strings.TrimSpace(raw)) {
+	case "":
+		return ""
+	case "CR", "C", "CREDIT", "INCOME", "IN", "DEPOSIT":
+		return "in"
+	case "DR", "D", "DEBIT", "EXPENSE", "OUT", "WITHDRAWAL", "PAYMENT":
+		return "out"
+	default:
+		return "?"
+	}
+}
+
+func isBlankRecord(record []string) bool {
+	for _, f := range record {
+		if strings.TrimSpace(f) != "" {
+			return false
+		}
+	}
+	return true
+}
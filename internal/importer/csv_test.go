@@ -0,0 +1,137 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		raw     string
+		decimal string
+		want    float64
+		wantErr bool
+	}{
+		{"12.50", ".", 12.5, false},
+		{"-12.50", ".", -12.5, false},
+		{"1,234.56", ".", 1234.56, false},
+		{"1.234,56", ",", 1234.56, false},
+		{"(12.50)", ".", -12.5, false},
+		{"12.50-", ".", -12.5, false},
+		{"€ 1 234,00", ",", 1234, false},
+		{"+7", "", 7, false},
+		{"USD 99.99", ".", 99.99, false},
+		{"12..5", ".", 0, true},
+		{"abc", ".", 0, true},
+		{"", ".", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseAmount(tt.raw, tt.decimal)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseAmount(%q) err = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseAmount(%q) = %v, want %v", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestDateLayout(t *testing.T) {
+	tests := map[string]string{
+		"DD/MM/YYYY":  "02/01/2006",
+		"YYYY-MM-DD":  "2006-01-02",
+		"M/D/YY":      "1/2/06",
+		"DD MMM YYYY": "02 Jan 2006",
+		"2006-01-02":  "2006-01-02",
+	}
+	for format, want := range tests {
+		if got := DateLayout(format); got != want {
+			t.Errorf("DateLayout(%q) = %q, want %q", format, got, want)
+		}
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	date := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+
+	t.Run("header with amount and type columns", func(t *testing.T) {
+		input := "\ufeffDate;Text;Amount;Kind;Category\n" +
+			"05.01.2024;Salary;2.500,00;CR;Income\n" +
+			"06.01.2024;Groceries;45,20;DR;Food\n" +
+			";;;;\n" +
+			"31.02.2024;Broken;1,00;XX;\n"
+		stmt, err := ParseCSV(strings.NewReader(input), CSVOptions{
+			Delimiter:         ";",
+			DateFormat:        "DD.MM.YYYY",
+			DecimalSeparator:  ",",
+			HasHeader:         true,
+			DateColumn:        "date",
+			AmountColumn:      "Amount",
+			DescriptionColumn: "Text",
+			TypeColumn:        "Kind",
+			CategoryColumn:    "Category",
+		})
+		if err != nil {
+			t.Fatalf("ParseCSV: %v", err)
+		}
+		if len(stmt.Entries) != 3 {
+			t.Fatalf("got %d entries, want 3 (blank rows are skipped)", len(stmt.Entries))
+		}
+
+		salary, groceries, broken := stmt.Entries[0], stmt.Entries[1], stmt.Entries[2]
+		if !salary.Valid() || salary.Row != 2 || !salary.Date.Equal(date("2024-01-05")) || salary.Amount != 2500 ||
+			salary.Description != "Salary" || salary.CategoryName != "Income" {
+			t.Errorf("salary = %+v", salary)
+		}
+		if !groceries.Valid() || groceries.Amount != -45.2 {
+			t.Errorf("groceries = %+v", groceries)
+		}
+		if broken.Valid() || broken.Row != 5 || len(broken.Errors) != 2 {
+			t.Errorf("broken = %+v, want an invalid date and an unknown type", broken)
+		}
+	})
+
+	t.Run("debit and credit columns by index", func(t *testing.T) {
+		input := "2024-03-01,Rent,800.00,\n2024-03-02,Refund,,19.99\n2024-03-03,Nothing,,\n"
+		stmt, err := ParseCSV(strings.NewReader(input), CSVOptions{
+			DateFormat:        "YYYY-MM-DD",
+			DateColumn:        "1",
+			DescriptionColumn: "2",
+			DebitColumn:       "3",
+			CreditColumn:      "4",
+		})
+		if err != nil {
+			t.Fatalf("ParseCSV: %v", err)
+		}
+		want := []float64{-800, 19.99}
+		for i, amount := range want {
+			if e := stmt.Entries[i]; !e.Valid() || e.Amount != amount {
+				t.Errorf("entry %d = %+v, want amount %v", i, e, amount)
+			}
+		}
+		if e := stmt.Entries[2]; e.Valid() {
+			t.Errorf("entry without amount is valid: %+v", e)
+		}
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		tests := []CSVOptions{
+			{DateColumn: "1", AmountColumn: "2"},
+			{DateFormat: "YYYY-MM-DD", AmountColumn: "2"},
+			{DateFormat: "YYYY-MM-DD", DateColumn: "1"},
+			{DateFormat: "YYYY-MM-DD", DateColumn: "1", AmountColumn: "2", Delimiter: ";;"},
+			{DateFormat: "YYYY-MM-DD", DateColumn: "0", AmountColumn: "2"},
+			{DateFormat: "YYYY-MM-DD", DateColumn: "Missing", AmountColumn: "Amount", HasHeader: true},
+		}
+		for i, opts := range tests {
+			if _, err := ParseCSV(strings.NewReader("Date,Amount\n2024-01-01,1\n"), opts); err == nil {
+				t.Errorf("options %d: expected an error", i)
+			}
+		}
+	})
+}
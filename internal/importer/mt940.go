@@ -0,0 +1,222 @@
+package importer
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// mt940Line matches the first line of a :61: statement line:
+// value date, optional entry date, debit/credit mark, optional funds code,
+// amount, transaction type, customer reference and optional bank reference.
+var mt940Line = regexp.MustCompile(`^(\d{6})(\d{4})?(RC|RD|C|D)([A-Z])?(\d+,\d*)([NFS][A-Z0-9]{3})([^/]*)(?://(.*))?$`)
+
+// mt940Balance matches :60F:, :60M:, :62F: and :62M: balances
+var mt940Balance = regexp.MustCompile(`^([CD])(\d{6})([A-Z]{3})(\d+,\d*)$`)
+
+type mt940Field struct {
+	tag   string
+	value string
+}
+
+// ParseMT940 reads a SWIFT MT940 customer statement. Files with several
+// statements (one per :20: block) are merged: the opening balance comes from
+// the first one and the closing balance from the last.
+func ParseMT940(r io.Reader) (*Statement, error) {
+	fields, err := mt940Fields(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, errors.New("invalid MT940 file: no fields found")
+	}
+
+	stmt := &Statement{}
+	var current *Entry
+	row := 0
+	for _, f := range fields {
+		switch f.tag {
+		case "25":
+			if stmt.AccountID == "" {
+				stmt.AccountID = strings.TrimSpace(f.value)
+			}
+		case "60F", "60M":
+			balance, currency, err := parseMT940Balance(f.value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid MT940 opening balance: %w", err)
+			}
+			if stmt.OpeningBalance == nil {
+				stmt.OpeningBalance = balance
+				stmt.Currency = currency
+			}
+		case "62F", "62M":
+			balance, _, err := parseMT940Balance(f.value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid MT940 closing balance: %w", err)
+			}
+			stmt.ClosingBalance = balance
+		case "61":
+			row++
+			stmt.Entries = append(stmt.Entries, parseMT940Entry(row, f.value))
+			current = &stmt.Entries[len(stmt.Entries)-1]
+		case "86":
+			// :86: belongs to the statement line right before it
+			if current != nil {
+				if description := mt940Description(f.value); description != "" {
+					current.Description = description
+				}
+				current = nil
+			}
+		}
+	}
+
+	if stmt.OpeningBalance == nil && stmt.ClosingBalance == nil && len(stmt.Entries) == 0 {
+		return nil, errors.New("invalid MT940 file: no statement found")
+	}
+	return stmt, nil
+}
+
+// mt940Fields splits the file into tagged fields. A field continues on the
+// following lines until the next line starting with a tag. SWIFT envelope
+// blocks ({1:...}{4:) and the trailing "-}" are ignored.
+func mt940Fields(r io.Reader) ([]mt940Field, error) {
+	var fields []mt940Field
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		line = strings.TrimPrefix(line, "\ufeff")
+		if i := strings.Index(line, "{4:"); i >= 0 {
+			line = line[i+3:]
+		}
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || trimmed == "-" || trimmed == "-}" || strings.HasPrefix(trimmed, "{") {
+			continue
+		}
+
+		if strings.HasPrefix(line, ":") {
+			if end := strings.Index(line[1:], ":"); end > 0 {
+				fields = append(fields, mt940Field{tag: line[1 : end+1], value: line[end+2:]})
+				continue
+			}
+		}
+		if len(fields) > 0 {
+			fields[len(fields)-1].value += "\n" + line
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read MT940 file: %w", err)
+	}
+	return fields, nil
+}
+
+func parseMT940Balance(value string) (*Balance, string, error) {
+	m := mt940Balance.FindStringSubmatch(strings.TrimSpace(value))
+	if m == nil {
+		return nil, "", fmt.Errorf("unexpected value %q", value)
+	}
+	date, err := time.Parse("060102", m[2])
+	if err != nil {
+		return nil, "", err
+	}
+	amount, err := ParseAmount(m[4], ",")
+	if err != nil {
+		return nil, "", err
+	}
+	if m[1] == "D" {
+		amount = -amount
+	}
+	return &Balance{Amount: amount, Date: date}, m[3], nil
+}
+
+func parseMT940Entry(row int, value string) Entry {
+	entry := Entry{Row: row}
+
+	first, rest, _ := strings.Cut(value, "\n")
+	m := mt940Line.FindStringSubmatch(strings.TrimSpace(first))
+	if m == nil {
+		entry.Errors = append(entry.Errors, fmt.Sprintf("unrecognized statement line %q", strings.TrimSpace(first)))
+		return entry
+	}
+
+	valueDate, err := time.Parse("060102", m[1])
+	if err != nil {
+		entry.Errors = append(entry.Errors, "invalid value date")
+	} else {
+		entry.Date = valueDate
+		// The entry (booking) date has no year; it can fall into the year
+		// before or after the value date around new year
+		if m[2] != "" {
+			if booked, err := time.Parse("0102", m[2]); err == nil {
+				booked = time.Date(valueDate.Year(), booked.Month(), booked.Day(), 0, 0, 0, 0, time.UTC)
+				if d := booked.Sub(valueDate); d > 180*24*time.Hour {
+					booked = booked.AddDate(-1, 0, 0)
+				} else if d < -180*24*time.Hour {
+					booked = booked.AddDate(1, 0, 0)
+				}
+				entry.Date = booked
+			}
+		}
+	}
+
+	amount, err := ParseAmount(m[5], ",")
+	if err != nil {
+		entry.Errors = append(entry.Errors, err.Error())
+	} else {
+		// D and RC (reversal of credit) take money out of the account
+		if m[3] == "D" || m[3] == "RC" {
+			amount = -amount
+		}
+		entry.Amount = amount
+	}
+
+	// Banks often leave the customer reference at NONREF or reuse it, and
+	// some reuse their own reference too, so neither identifies a booking on
+	// its own. Combined with the dates, mark and amount they do; without any
+	// reference there is no reliable ID at all.
+	bankRef := strings.TrimSpace(m[8])
+	customerRef := strings.TrimSpace(m[7])
+	if customerRef == "NONREF" {
+		customerRef = ""
+	}
+	if bankRef != "" || customerRef != "" {
+		entry.ExternalID = strings.Join([]string{m[1] + m[2], m[3], m[5], customerRef, bankRef}, "/")
+	}
+
+	// The optional second line carries supplementary details
+	entry.Description = strings.Join(strings.Fields(rest), " ")
+	return entry
+}
+
+// mt940Description flattens the :86: field. Many banks structure it with
+// ?NN subfields, where 20-29 and 60-63 hold the purpose and 32-33 the
+// counterparty name; unstructured text is used as is.
+func mt940Description(value string) string {
+	value = strings.ReplaceAll(value, "\n", "")
+	if !strings.Contains(value, "?") {
+		return strings.Join(strings.Fields(value), " ")
+	}
+
+	var name, purpose []string
+	for _, part := range strings.Split(value, "?")[1:] {
+		if len(part) < 2 {
+			continue
+		}
+		code, text := part[:2], strings.TrimSpace(part[2:])
+		if text == "" {
+			continue
+		}
+		switch {
+		case code == "32" || code == "33":
+			name = append(name, text)
+		case (code >= "20" && code <= "29") || (code >= "60" && code <= "63"):
+			purpose = append(purpose, text)
+		}
+	}
+	return strings.Join(strings.Fields(strings.Join(append(name, purpose...), " ")), " ")
+}
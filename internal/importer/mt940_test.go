@@ -0,0 +1,110 @@
+package importer
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+const mt940Statement = "{1:F01BANKDEFFXXXX0000000000}{2:O9401200240131BANKDEFFXXXX00000000002401311200N}{4:\r\n" +
+	":20:STMT240131\r\n" +
+	":25:37040044/0532013000\r\n" +
+	":28C:1/1\r\n" +
+	":60F:C231231EUR1000,00\r\n" +
+	":61:2401050105D45,20NMSCNONREF//BK-7781\r\n" +
+	"Card payment\r\n" +
+	":86:105?00Kartenzahlung?20Weekly shop?32ACME Market\r\n" +
+	":61:2401100110C2500,00NTRFSALARY-JAN\r\n" +
+	":86:Salary January\r\n" +
+	":61:2401150115D45,20NMSCNONREF\r\n" +
+	":61:2401200120RC12,00NMSCNONREF//BK-9001\r\n" +
+	":62F:C240131EUR3397,60\r\n" +
+	"-}"
+
+func TestParseMT940(t *testing.T) {
+	stmt, err := ParseMT940(strings.NewReader(mt940Statement))
+	if err != nil {
+		t.Fatalf("ParseMT940: %v", err)
+	}
+	if stmt.AccountID != "37040044/0532013000" || stmt.Currency != "EUR" {
+		t.Errorf("account = %q, currency = %q", stmt.AccountID, stmt.Currency)
+	}
+	if stmt.OpeningBalance == nil || stmt.OpeningBalance.Amount != 1000 ||
+		stmt.ClosingBalance == nil || stmt.ClosingBalance.Amount != 3397.60 {
+		t.Fatalf("balances = %+v, %+v", stmt.OpeningBalance, stmt.ClosingBalance)
+	}
+
+	tests := []struct {
+		amount      float64
+		date        string
+		externalID  string
+		description string
+	}{
+		{-45.20, "2024-01-05", "2401050105/D/45,20//BK-7781", "ACME Market Weekly shop"},
+		{2500, "2024-01-10", "2401100110/C/2500,00/SALARY-JAN/", "Salary January"},
+		// Without any reference there is nothing that identifies the booking
+		{-45.20, "2024-01-15", "", ""},
+		// A reversal of a credit takes money out
+		{-12, "2024-01-20", "2401200120/RC/12,00//BK-9001", ""},
+	}
+	if len(stmt.Entries) != len(tests) {
+		t.Fatalf("got %d entries, want %d", len(stmt.Entries), len(tests))
+	}
+	total := stmt.OpeningBalance.Amount
+	for i, tt := range tests {
+		e := stmt.Entries[i]
+		total += e.Amount
+		if !e.Valid() || e.Amount != tt.amount || e.Date.Format("2006-01-02") != tt.date ||
+			e.ExternalID != tt.externalID || e.Description != tt.description {
+			t.Errorf("entry %d = %+v, want %+v", i, e, tt)
+		}
+	}
+	if math.Abs(total-stmt.ClosingBalance.Amount) > 0.005 {
+		t.Errorf("opening + entries = %.2f, closing = %.2f", total, stmt.ClosingBalance.Amount)
+	}
+}
+
+// The same customer reference on two bookings must not make them look like
+// one, or the second would be dropped as a duplicate on import
+func TestParseMT940ReusedReference(t *testing.T) {
+	input := ":25:123456789\n:60F:C240101EUR0,00\n" +
+		":61:240102D10,00NTRFINV-1\n" +
+		":61:240103D25,00NTRFINV-1\n" +
+		":62F:D240103EUR35,00\n"
+	stmt, err := ParseMT940(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseMT940: %v", err)
+	}
+	if len(stmt.Entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(stmt.Entries))
+	}
+	first, second := stmt.ExternalKey(stmt.Entries[0]), stmt.ExternalKey(stmt.Entries[1])
+	if first == "" || first == second {
+		t.Errorf("external keys %q and %q must be set and distinct", first, second)
+	}
+	if stmt.ClosingBalance.Amount != -35 {
+		t.Errorf("closing balance = %v, want -35", stmt.ClosingBalance.Amount)
+	}
+}
+
+func TestParseMT940EntryDateAroundNewYear(t *testing.T) {
+	e := parseMT940Entry(1, "2312311231D1,00NMSCREF")
+	if e.Date.Format("2006-01-02") != "2023-12-31" {
+		t.Errorf("date = %s", e.Date.Format("2006-01-02"))
+	}
+	e = parseMT940Entry(1, "2312310102D1,00NMSCREF")
+	if e.Date.Format("2006-01-02") != "2024-01-02" {
+		t.Errorf("booking in January after a December value date = %s, want 2024-01-02", e.Date.Format("2006-01-02"))
+	}
+	if e := parseMT940Entry(1, "garbage"); e.Valid() {
+		t.Errorf("garbage line parsed: %+v", e)
+	}
+}
+
+func TestParseMT940Invalid(t *testing.T) {
+	for _, input := range []string{"", ":20:X\n", ":60F:X1000\n"} {
+		if _, err := ParseMT940(strings.NewReader(input)); err == nil {
+			t.Errorf("ParseMT940(%q): expected an error", input)
+		}
+	}
+}
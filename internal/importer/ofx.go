@@ -0,0 +1,187 @@
+package importer
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ParseOFX reads OFX 1.x (SGML), OFX 2.x (XML) and QFX statements. SGML
+// leaves are not closed, so the document is scanned as a flat stream of tags
+// instead of being parsed as a tree; the same scan handles the XML variant.
+func ParseOFX(r io.Reader) (*Statement, error) {
+	data, err := io.ReadAll(bufio.NewReader(r))
+	if err != nil {
+		return nil, err
+	}
+	doc := string(data)
+
+	start := strings.Index(strings.ToUpper(doc), "<OFX>")
+	if start < 0 {
+		return nil, errors.New("not an OFX document: <OFX> element missing")
+	}
+	doc = doc[start:]
+
+	stmt := &Statement{}
+	var (
+		current  *ofxTransaction
+		ledger   bool
+		position int
+		row      int
+	)
+	for position < len(doc) {
+		open := strings.IndexByte(doc[position:], '<')
+		if open < 0 {
+			break
+		}
+		open += position
+		end := strings.IndexByte(doc[open:], '>')
+		if end < 0 {
+			break
+		}
+		end += open
+
+		name := strings.ToUpper(strings.TrimSpace(doc[open+1 : end]))
+		position = end + 1
+
+		next := strings.IndexByte(doc[position:], '<')
+		if next < 0 {
+			next = len(doc) - position
+		}
+		value := strings.TrimSpace(unescapeOFX(doc[position : position+next]))
+
+		switch {
+		case name == "" || name[0] == '?' || name[0] == '!':
+			continue
+		case name == "STMTTRN":
+			row++
+			current = &ofxTransaction{row: row}
+		case name == "/STMTTRN":
+			if current != nil {
+				stmt.Entries = append(stmt.Entries, current.entry())
+				current = nil
+			}
+		case name == "LEDGERBAL":
+			ledger = true
+		case name == "/LEDGERBAL":
+			ledger = false
+		case strings.HasPrefix(name, "/"):
+			// Closing tags of leaf elements in OFX 2.x carry no information
+		case current != nil:
+			current.set(name, value)
+		case ledger:
+			switch name {
+			case "BALAMT":
+				if amount, err := parseOFXAmount(value); err == nil {
+					stmt.ClosingBalance = &Balance{Amount: amount}
+				}
+			case "DTASOF":
+				if stmt.ClosingBalance != nil {
+					if t, err := parseOFXDate(value); err == nil {
+						stmt.ClosingBalance.Date = t
+					}
+				}
+			}
+		case name == "ACCTID" && stmt.AccountID == "":
+			stmt.AccountID = value
+		case name == "CURDEF" && stmt.Currency == "":
+			stmt.Currency = value
+		}
+	}
+
+	return stmt, nil
+}
+
+// ofxTransaction accumulates the fields of a STMTTRN aggregate
+type ofxTransaction struct {
+	row                          int
+	posted, amount, fitID        string
+	name, memo, trnType, checkNo string
+}
+
+func (t *ofxTransaction) set(name, value string) {
+	switch name {
+	case "DTPOSTED":
+		t.posted = value
+	case "TRNAMT":
+		t.amount = value
+	case "FITID":
+		t.fitID = value
+	case "NAME", "PAYEE":
+		if t.name == "" {
+			t.name = value
+		}
+	case "MEMO":
+		t.memo = value
+	case "TRNTYPE":
+		t.trnType = value
+	case "CHECKNUM":
+		t.checkNo = value
+	}
+}
+
+func (t *ofxTransaction) entry() Entry {
+	entry := Entry{Row: t.row, ExternalID: t.fitID}
+
+	if t.posted == "" {
+		entry.Errors = append(entry.Errors, "DTPOSTED is missing")
+	} else if date, err := parseOFXDate(t.posted); err != nil {
+		entry.Errors = append(entry.Errors, err.Error())
+	} else {
+		entry.Date = date
+	}
+
+	if t.amount == "" {
+		entry.Errors = append(entry.Errors, "TRNAMT is missing")
+	} else if amount, err := parseOFXAmount(t.amount); err != nil {
+		entry.Errors = append(entry.Errors, err.Error())
+	} else {
+		entry.Amount = amount
+	}
+
+	if t.fitID == "" {
+		entry.Errors = append(entry.Errors, "FITID is missing")
+	}
+
+	entry.Description = t.name
+	if t.memo != "" && !strings.EqualFold(t.memo, t.name) {
+		entry.Description = strings.TrimSpace(entry.Description + " " + t.memo)
+	}
+	if entry.Description == "" && t.checkNo != "" {
+		entry.Description = "Check " + t.checkNo
+	}
+	if entry.Description == "" {
+		entry.Description = t.trnType
+	}
+	return entry
+}
+
+// parseOFXDate reads the date part of YYYYMMDD[HHMMSS[.XXX]][[offset:TZ]]
+func parseOFXDate(raw string) (time.Time, error) {
+	if len(raw) < 8 {
+		return time.Time{}, fmt.Errorf("invalid OFX date %q", raw)
+	}
+	t, err := time.Parse("20060102", raw[:8])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid OFX date %q", raw)
+	}
+	return t, nil
+}
+
+// parseOFXAmount accepts both "." and "," as decimal separator, which some
+// European banks use despite the specification
+func parseOFXAmount(raw string) (float64, error) {
+	if strings.Contains(raw, ",") && !strings.Contains(raw, ".") {
+		return ParseAmount(raw, ",")
+	}
+	return ParseAmount(raw, ".")
+}
+
+var ofxEntities = strings.NewReplacer("&amp;", "&", "&lt;", "<", "&gt;", ">", "&quot;", `"`, "&apos;", "'", "&nbsp;", " ")
+
+func unescapeOFX(s string) string {
+	return ofxEntities.Replace(s)
+}
@@ -0,0 +1,106 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const ofxSGML = `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+
+<OFX>
+<BANKMSGSRSV1><STMTTRNRS><STMTRS>
+<CURDEF>USD
+<BANKACCTFROM><BANKID>121000358<ACCTID>987654321<ACCTTYPE>CHECKING</BANKACCTFROM>
+<BANKTRANLIST>
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20240105120000.000[-5:EST]
+<TRNAMT>-45.20
+<FITID>2024010501
+<NAME>ACME MARKET
+<MEMO>Groceries &amp; more
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>CHECK
+<DTPOSTED>20240106
+<TRNAMT>-120,00
+<FITID>2024010602
+<CHECKNUM>1042
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>CREDIT
+<DTPOSTED>2024
+<TRNAMT>2500.00
+</STMTTRN>
+</BANKTRANLIST>
+<LEDGERBAL><BALAMT>1834.80<DTASOF>20240131</LEDGERBAL>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>
+`
+
+const ofxXML = `<?xml version="1.0" encoding="UTF-8"?>
+<?OFX OFXHEADER="200" VERSION="220"?>
+<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS>
+<CURDEF>EUR</CURDEF>
+<BANKACCTFROM><ACCTID>DE89370400440532013000</ACCTID></BANKACCTFROM>
+<BANKTRANLIST>
+<STMTTRN><TRNTYPE>CREDIT</TRNTYPE><DTPOSTED>20240301</DTPOSTED><TRNAMT>19.99</TRNAMT><FITID>A1</FITID><NAME>Refund</NAME><MEMO>REFUND</MEMO></STMTTRN>
+</BANKTRANLIST>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>
+`
+
+func TestParseOFX(t *testing.T) {
+	t.Run("SGML", func(t *testing.T) {
+		stmt, err := ParseOFX(strings.NewReader(ofxSGML))
+		if err != nil {
+			t.Fatalf("ParseOFX: %v", err)
+		}
+		if stmt.AccountID != "987654321" || stmt.Currency != "USD" {
+			t.Errorf("account = %q, currency = %q", stmt.AccountID, stmt.Currency)
+		}
+		if stmt.ClosingBalance == nil || stmt.ClosingBalance.Amount != 1834.80 ||
+			!stmt.ClosingBalance.Date.Equal(time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("closing balance = %+v", stmt.ClosingBalance)
+		}
+		if len(stmt.Entries) != 3 {
+			t.Fatalf("got %d entries, want 3", len(stmt.Entries))
+		}
+
+		groceries := stmt.Entries[0]
+		if !groceries.Valid() || groceries.Amount != -45.2 || groceries.ExternalID != "2024010501" ||
+			groceries.Description != "ACME MARKET Groceries & more" ||
+			!groceries.Date.Equal(time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("groceries = %+v", groceries)
+		}
+		if check := stmt.Entries[1]; !check.Valid() || check.Amount != -120 || check.Description != "Check 1042" {
+			t.Errorf("check = %+v", check)
+		}
+		// A bad date and a missing FITID are both reported
+		if broken := stmt.Entries[2]; broken.Valid() || len(broken.Errors) != 2 || broken.Row != 3 {
+			t.Errorf("broken = %+v", broken)
+		}
+	})
+
+	t.Run("XML", func(t *testing.T) {
+		stmt, err := ParseOFX(strings.NewReader(ofxXML))
+		if err != nil {
+			t.Fatalf("ParseOFX: %v", err)
+		}
+		if stmt.AccountID != "DE89370400440532013000" || stmt.Currency != "EUR" || len(stmt.Entries) != 1 {
+			t.Fatalf("statement = %+v", stmt)
+		}
+		// A memo repeating the name is not appended
+		if e := stmt.Entries[0]; !e.Valid() || e.Amount != 19.99 || e.Description != "Refund" || e.ExternalID != "A1" {
+			t.Errorf("entry = %+v", e)
+		}
+	})
+
+	t.Run("not OFX", func(t *testing.T) {
+		if _, err := ParseOFX(strings.NewReader("Date,Amount\n")); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
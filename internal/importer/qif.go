@@ -0,0 +1,191 @@
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// QIF dates carry no self-describing order, so callers pick one
+const (
+	DateOrderMDY = "MDY"
+	DateOrderDMY = "DMY"
+	DateOrderYMD = "YMD"
+)
+
+// ParseQIF reads a Quicken Interchange Format file. Only the cash-like
+// account types (Bank, Cash, CCard, Oth A, Oth L) are imported; investment and
+// list sections are skipped. dateOrder is one of the DateOrder constants and
+// defaults to month/day/year.
+func ParseQIF(r io.Reader, dateOrder string) (*Statement, error) {
+	if dateOrder == "" {
+		dateOrder = DateOrderMDY
+	}
+	if dateOrder != DateOrderMDY && dateOrder != DateOrderDMY && dateOrder != DateOrderYMD {
+		return nil, fmt.Errorf("unsupported date order %q", dateOrder)
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	stmt := &Statement{}
+	var (
+		current  *qifRecord
+		line     int
+		inCash   = true
+		sawFirst bool
+	)
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if !sawFirst {
+			text = strings.TrimPrefix(text, "\ufeff")
+			sawFirst = true
+		}
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		if strings.HasPrefix(text, "!") {
+			inCash = isQIFCashSection(text)
+			current = nil
+			continue
+		}
+		if !inCash {
+			continue
+		}
+
+		code, value := text[0], strings.TrimSpace(text[1:])
+		if code == '^' {
+			if current != nil {
+				stmt.Entries = append(stmt.Entries, current.entry(dateOrder))
+			}
+			current = nil
+			continue
+		}
+		if current == nil {
+			current = &qifRecord{row: line}
+		}
+		switch code {
+		case 'D':
+			current.date = value
+		case 'T', 'U':
+			if current.amount == "" {
+				current.amount = value
+			}
+		case 'P':
+			current.payee = value
+		case 'M':
+			current.memo = value
+		case 'L':
+			current.category = value
+		case 'N':
+			current.number = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	// Tolerate a missing trailing "^"
+	if current != nil {
+		stmt.Entries = append(stmt.Entries, current.entry(dateOrder))
+	}
+
+	return stmt, nil
+}
+
+type qifRecord struct {
+	row                                 int
+	date, amount, payee, memo, category string
+	number                              string
+}
+
+func (q *qifRecord) entry(dateOrder string) Entry {
+	entry := Entry{Row: q.row}
+
+	if q.date == "" {
+		entry.Errors = append(entry.Errors, "date is missing")
+	} else if date, err := parseQIFDate(q.date, dateOrder); err != nil {
+		entry.Errors = append(entry.Errors, err.Error())
+	} else {
+		entry.Date = date
+	}
+
+	if q.amount == "" {
+		entry.Errors = append(entry.Errors, "amount is missing")
+	} else if amount, err := ParseAmount(q.amount, "."); err != nil {
+		entry.Errors = append(entry.Errors, err.Error())
+	} else {
+		entry.Amount = amount
+	}
+
+	entry.Description = q.payee
+	if q.memo != "" && !strings.EqualFold(q.memo, q.payee) {
+		entry.Description = strings.TrimSpace(entry.Description + " " + q.memo)
+	}
+	if entry.Description == "" && q.number != "" {
+		entry.Description = "Check " + q.number
+	}
+
+	// "[Account]" categories are transfers between accounts, not categories
+	if !strings.HasPrefix(q.category, "[") {
+		// Keep the top level of "Category:Subcategory/Class"
+		category := strings.SplitN(q.category, "/", 2)[0]
+		entry.CategoryName = strings.TrimSpace(strings.SplitN(category, ":", 2)[0])
+	}
+	return entry
+}
+
+// parseQIFDate reads dates such as "1/ 5/24", "01/05'2024" or "2024-01-05"
+// in the given field order. Two-digit years pivot at 70.
+func parseQIFDate(raw, order string) (time.Time, error) {
+	fields := strings.FieldsFunc(raw, func(r rune) bool { return !unicode.IsDigit(r) })
+	if len(fields) != 3 {
+		return time.Time{}, fmt.Errorf("invalid date %q", raw)
+	}
+
+	nums := make([]int, 3)
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid date %q", raw)
+		}
+		nums[i] = n
+	}
+
+	var year, month, day int
+	switch order {
+	case DateOrderDMY:
+		day, month, year = nums[0], nums[1], nums[2]
+	case DateOrderYMD:
+		year, month, day = nums[0], nums[1], nums[2]
+	default:
+		month, day, year = nums[0], nums[1], nums[2]
+	}
+	if year < 100 {
+		if year < 70 {
+			year += 2000
+		} else {
+			year += 1900
+		}
+	}
+
+	t := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if t.Year() != year || int(t.Month()) != month || t.Day() != day {
+		return time.Time{}, fmt.Errorf("invalid date %q", raw)
+	}
+	return t, nil
+}
+
+func isQIFCashSection(header string) bool {
+	switch strings.ToLower(strings.TrimSpace(header)) {
+	case "!type:bank", "!type:cash", "!type:ccard", "!type:oth a", "!type:oth l":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,71 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseQIF(t *testing.T) {
+	input := "!Type:Bank\n" +
+		"D1/ 5/24\nT-45.20\nPACME Market\nMWeekly shop\nLFood:Groceries/Home\n^\n" +
+		"D01/06'2024\nU1,250.00\nPEmployer\nLSalary\n^\n" +
+		"D1/7/24\nT-300.00\nL[Savings]\nN1042\n^\n" +
+		"!Type:Invst\nD1/8/24\nT-99.00\n^\n" +
+		"!Type:CCard\nD13/1/24\nT-10.00\n"
+
+	stmt, err := ParseQIF(strings.NewReader(input), "")
+	if err != nil {
+		t.Fatalf("ParseQIF: %v", err)
+	}
+	if len(stmt.Entries) != 4 {
+		t.Fatalf("got %d entries, want 4 (investment sections are skipped)", len(stmt.Entries))
+	}
+
+	groceries := stmt.Entries[0]
+	if !groceries.Valid() || groceries.Amount != -45.2 || groceries.Description != "ACME Market Weekly shop" ||
+		groceries.CategoryName != "Food" || !groceries.Date.Equal(time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("groceries = %+v", groceries)
+	}
+	if salary := stmt.Entries[1]; !salary.Valid() || salary.Amount != 1250 || salary.CategoryName != "Salary" {
+		t.Errorf("salary = %+v", salary)
+	}
+	// Transfers name an account instead of a category
+	if transfer := stmt.Entries[2]; !transfer.Valid() || transfer.CategoryName != "" || transfer.Description != "Check 1042" {
+		t.Errorf("transfer = %+v", transfer)
+	}
+	// Month 13 does not exist in month/day/year order; the record has no
+	// trailing "^" and is still read
+	if broken := stmt.Entries[3]; broken.Valid() {
+		t.Errorf("broken = %+v", broken)
+	}
+}
+
+func TestParseQIFDate(t *testing.T) {
+	tests := []struct {
+		raw, order string
+		want       string
+		wantErr    bool
+	}{
+		{"1/ 5/24", DateOrderMDY, "2024-01-05", false},
+		{"05/01/2024", DateOrderDMY, "2024-01-05", false},
+		{"2024-01-05", DateOrderYMD, "2024-01-05", false},
+		{"12/31'99", DateOrderMDY, "1999-12-31", false},
+		{"2/30/24", DateOrderMDY, "", true},
+		{"2024", DateOrderYMD, "", true},
+	}
+	for _, tt := range tests {
+		got, err := parseQIFDate(tt.raw, tt.order)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseQIFDate(%q, %s) err = %v", tt.raw, tt.order, err)
+			continue
+		}
+		if err == nil && got.Format("2006-01-02") != tt.want {
+			t.Errorf("parseQIFDate(%q, %s) = %s, want %s", tt.raw, tt.order, got.Format("2006-01-02"), tt.want)
+		}
+	}
+
+	if _, err := ParseQIF(strings.NewReader("!Type:Bank\n"), "DDMMYY"); err == nil {
+		t.Error("expected an error for an unknown date order")
+	}
+}
@@ -0,0 +1,67 @@
+// Package importer turns bank statement files into a format-neutral Statement
+// that the import service previews, de-duplicates and commits.
+package importer
+
+import (
+	"time"
+)
+
+// Supported statement formats besides CSV, which needs a column mapping
+const (
+	FormatOFX     = "ofx"
+	FormatQFX     = "qfx"
+	FormatQIF     = "qif"
+	FormatCAMT053 = "camt053"
+	FormatMT940   = "mt940"
+)
+
+// Entry is a single booked line of a statement. Amount is signed: negative
+// values are money going out, positive values money coming in.
+type Entry struct {
+	// Row is the 1-based position of the entry in the source file
+	Row          int
+	Date         time.Time
+	Amount       float64
+	Description  string
+	CategoryName string
+	// ExternalID is the bank assigned identifier, when the format has one
+	ExternalID string
+	// Errors collects validation problems found while parsing the entry
+	Errors []string
+}
+
+// Valid reports whether the entry parsed without errors
+func (e *Entry) Valid() bool {
+	return len(e.Errors) == 0
+}
+
+// Balance is an account balance reported by the bank at a given date
+type Balance struct {
+	Amount float64
+	Date   time.Time
+}
+
+// Statement is the parsed content of an imported file
+type Statement struct {
+	// AccountID identifies the bank account when the format provides it
+	AccountID string
+	Currency  string
+	Entries   []Entry
+	// OpeningBalance is the balance the bank reports at the start of the statement
+	OpeningBalance *Balance
+	// ClosingBalance is the balance the bank reports at the end of the statement
+	ClosingBalance *Balance
+}
+
+// ExternalKey returns the identifier stored on a transaction for idempotent
+// re-imports. Bank IDs are only unique per account, so the account is
+// included when known.
+func (s *Statement) ExternalKey(e Entry) string {
+	if e.ExternalID == "" {
+		return ""
+	}
+	if s.AccountID == "" {
+		return e.ExternalID
+	}
+	return s.AccountID + ":" + e.ExternalID
+}
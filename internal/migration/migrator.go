@@ -21,14 +21,81 @@ func (m *Migrator) AutoMigrate() error {
 		return fmt.Errorf("auto-migration is disabled in production")
 	}
 
-	return m.db.AutoMigrate(
+	if err := m.dropStaleAlertTypeCheck(); err != nil {
+		return err
+	}
+
+	if err := m.db.AutoMigrate(
 		&model.User{},
 		&model.Category{},
+		&model.Tag{},
+		&model.Transaction{},
 		&model.Cost{},
+		&model.Attachment{},
+		&model.ImportProfile{},
+		&model.ExportJob{},
+		&model.AccountDeletionRecord{},
 		&model.Alert{},
 		&model.Expense{},
 		&model.Budget{},
-	)
+		&model.NetWorthItem{},
+		&model.NetWorthValuation{},
+		&model.CategorizationRule{},
+		&model.CategoryTemplate{},
+		&model.Goal{},
+		&model.GoalContribution{},
+		&model.Loan{},
+		&model.LoanPayment{},
+		&model.Bill{},
+		&model.BillPayment{},
+		&model.SubscriptionDecision{},
+	); err != nil {
+		return err
+	}
+
+	// Category names used to be unique across all users, then across all of a
+	// user's categories including deleted ones; they are now unique among the
+	// user's active categories through idx_category_user_name_active
+	for _, index := range []string{"idx_user_category_name", "idx_category_user_name"} {
+		if m.db.Migrator().HasIndex(&model.Category{}, index) {
+			if err := m.db.Migrator().DropIndex(&model.Category{}, index); err != nil {
+				return err
+			}
+		}
+	}
+
+	return m.createSearchIndexes()
+}
+
+// dropStaleAlertTypeCheck drops an alert_type_check without the bill_* alert
+// types so that AutoMigrate recreates it with them.
+func (m *Migrator) dropStaleAlertTypeCheck() error {
+	var stale int64
+	err := m.db.Raw(`SELECT COUNT(*) FROM pg_constraint
+		WHERE conname = 'alert_type_check' AND pg_get_constraintdef(oid) NOT LIKE '%bill_overdue%'`).Scan(&stale).Error
+	if err != nil || stale == 0 {
+		return err
+	}
+	return m.db.Exec("ALTER TABLE alerts DROP CONSTRAINT alert_type_check").Error
+}
+
+// searchIndexes are the GIN indexes behind full-text search. The expressions
+// must match the ones queried in repository/search_repo.go, or PostgreSQL
+// will not use the indexes.
+var searchIndexes = []string{
+	`CREATE INDEX IF NOT EXISTS idx_transactions_search ON transactions USING GIN (to_tsvector('simple', coalesce(description, '')))`,
+	`CREATE INDEX IF NOT EXISTS idx_costs_search ON costs USING GIN (to_tsvector('simple', title))`,
+	`CREATE INDEX IF NOT EXISTS idx_categories_search ON categories USING GIN (to_tsvector('simple', name))`,
+	`CREATE INDEX IF NOT EXISTS idx_tags_search ON tags USING GIN (to_tsvector('simple', name))`,
+}
+
+func (m *Migrator) createSearchIndexes() error {
+	for _, statement := range searchIndexes {
+		if err := m.db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (m *Migrator) CreateMigrationsTable() error {
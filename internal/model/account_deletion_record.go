@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccountDeletionRecord is the compliance trail kept after an account has
+// been purged. It holds no personal data: the email is only kept as a
+// SHA-256 hash so a later request about the address can be answered.
+type AccountDeletionRecord struct {
+	ID     uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID uuid.UUID `gorm:"type:uuid;not null;index" json:"userId"`
+	// EmailHash is the hex SHA-256 of the lower-cased email address
+	EmailHash string `gorm:"type:varchar(64);not null;index" json:"emailHash"`
+	// Trigger is self_service for requests by the user, admin otherwise
+	Trigger      string     `gorm:"type:varchar(20);not null" json:"trigger"`
+	RequestedAt  *time.Time `json:"requestedAt,omitempty"`
+	ScheduledFor *time.Time `json:"scheduledFor,omitempty"`
+	PurgedAt     time.Time  `gorm:"not null" json:"purgedAt"`
+	// DeletedRows is a JSON object with the number of rows removed per table
+	DeletedRows string    `gorm:"type:text;not null" json:"deletedRows"`
+	CreatedAt   time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"createdAt"`
+}
@@ -6,17 +6,32 @@ import (
 	"github.com/google/uuid"
 )
 
+// Alert types
+const (
+	AlertTypeApproachingLimit = "approaching_limit"
+	AlertTypeOverLimit        = "over_limit"
+	AlertTypeGoalBehind       = "goal_behind"
+	AlertTypeBillDue          = "bill_due"
+	AlertTypeBillOverdue      = "bill_overdue"
+)
+
+// Alert warns about a budget, a savings goal or a bill; exactly one of
+// BudgetID, GoalID and BillID is set
 type Alert struct {
-	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
-	UserID      uuid.UUID `gorm:"type:uuid;not null;index" json:"userId"`
-	BudgetID    uuid.UUID `gorm:"type:uuid;not null;index" json:"budgetId"`
-	AlertType   string    `gorm:"type:varchar(20);not null;check:alert_type_check,alert_type IN ('approaching_limit','over_limit')" json:"alertType"`
-	TriggeredAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"triggeredAt"`
-	Message     string    `gorm:"type:text;not null" json:"message"`
-	CreatedAt   time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"createdAt"`
-	UpdatedAt   time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updatedAt"`
-	DeletedAt   DeletedAt `gorm:"index" json:"deletedAt,omitempty" swaggertype:"string"`
+	ID          uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"userId"`
+	BudgetID    *uuid.UUID `gorm:"type:uuid;index" json:"budgetId,omitempty"`
+	GoalID      *uuid.UUID `gorm:"type:uuid;index" json:"goalId,omitempty"`
+	BillID      *uuid.UUID `gorm:"type:uuid;index" json:"billId,omitempty"`
+	AlertType   string     `gorm:"type:varchar(20);not null;check:alert_type_check,alert_type IN ('approaching_limit','over_limit','goal_behind','bill_due','bill_overdue')" json:"alertType"`
+	TriggeredAt time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"triggeredAt"`
+	Message     string     `gorm:"type:text;not null" json:"message"`
+	CreatedAt   time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"createdAt"`
+	UpdatedAt   time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"updatedAt"`
+	DeletedAt   DeletedAt  `gorm:"index" json:"deletedAt,omitempty" swaggertype:"string"`
 
-	User   User   `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
-	Budget Budget `gorm:"foreignKey:BudgetID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	User   User    `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Budget *Budget `gorm:"foreignKey:BudgetID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Goal   *Goal   `gorm:"foreignKey:GoalID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Bill   *Bill   `gorm:"foreignKey:BillID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
 }
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Attachment is a receipt or document stored in the blob store and linked to
+// exactly one transaction or cost
+type Attachment struct {
+	ID            uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID        uuid.UUID  `gorm:"type:uuid;not null;index" json:"userId"`
+	TransactionID *uuid.UUID `gorm:"type:uuid;index;check:attachment_owner_check,(transaction_id IS NULL) <> (cost_id IS NULL)" json:"transactionId,omitempty"`
+	CostID        *uuid.UUID `gorm:"type:uuid;index" json:"costId,omitempty"`
+	FileName      string     `gorm:"type:varchar(255);not null" json:"fileName"`
+	ContentType   string     `gorm:"type:varchar(100);not null" json:"contentType"`
+	Size          int64      `gorm:"not null" json:"size"`
+	StorageKey    string     `gorm:"type:varchar(512);not null;uniqueIndex" json:"-"`
+	CreatedAt     time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"createdAt"`
+	UpdatedAt     time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"updatedAt"`
+	DeletedAt     DeletedAt  `gorm:"index" json:"deletedAt,omitempty" swaggertype:"string"`
+
+	User        *User        `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Transaction *Transaction `gorm:"foreignKey:TransactionID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Cost        *Cost        `gorm:"foreignKey:CostID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BillRecurrence string
+
+const (
+	BillRecurrenceOnce      BillRecurrence = "ONCE"
+	BillRecurrenceMonthly   BillRecurrence = "MONTHLY"
+	BillRecurrenceQuarterly BillRecurrence = "QUARTERLY"
+	BillRecurrenceYearly    BillRecurrence = "YEARLY"
+)
+
+// Bill is a payment that falls due on DueDay of every month, quarter or year
+// counted from the month of StartDate, or just once. A due day past the end
+// of a month falls on its last day.
+type Bill struct {
+	ID             uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID         uuid.UUID      `gorm:"type:uuid;not null;index" json:"userId"`
+	Payee          string         `gorm:"type:varchar(100);not null" json:"payee"`
+	ExpectedAmount float64        `gorm:"type:numeric(15,2);not null" json:"expectedAmount"`
+	DueDay         int            `gorm:"not null;check:bill_due_day_check,due_day BETWEEN 1 AND 31" json:"dueDay"`
+	Recurrence     BillRecurrence `gorm:"type:varchar(10);not null;check:bill_recurrence_check,recurrence IN ('ONCE', 'MONTHLY', 'QUARTERLY', 'YEARLY')" json:"recurrence"`
+	// StartDate is the first day the bill can fall due
+	StartDate time.Time `gorm:"type:date;not null" json:"startDate"`
+	// EndDate is the last day the bill can fall due, if it ends
+	EndDate *time.Time `gorm:"type:date" json:"endDate,omitempty"`
+	// RemindDaysBefore is how many days ahead of the due date an unpaid bill
+	// raises an alert
+	RemindDaysBefore int `gorm:"not null;default:3" json:"remindDaysBefore"`
+	// CategoryID is the category of the transactions booked when the bill is
+	// paid
+	CategoryID *uuid.UUID `gorm:"type:uuid;index" json:"categoryId,omitempty"`
+	Note       *string    `gorm:"type:text" json:"note,omitempty"`
+	CreatedAt  time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"createdAt"`
+	UpdatedAt  time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"updatedAt"`
+	DeletedAt  DeletedAt  `gorm:"index" json:"deletedAt,omitempty" swaggertype:"string"`
+
+	User     *User         `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Category *Category     `gorm:"foreignKey:CategoryID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL" json:"-"`
+	Payments []BillPayment `gorm:"foreignKey:BillID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"payments,omitempty"`
+}
+
+// BillPayment marks the occurrence of a bill due on DueDate as paid by a
+// transaction
+type BillPayment struct {
+	ID            uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID        uuid.UUID `gorm:"type:uuid;not null;index" json:"userId"`
+	BillID        uuid.UUID `gorm:"type:uuid;not null;index:idx_bill_payment_due_date,unique" json:"billId"`
+	DueDate       time.Time `gorm:"type:date;not null;index:idx_bill_payment_due_date,unique" json:"dueDate"`
+	TransactionID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"transactionId"`
+	CreatedAt     time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"createdAt"`
+
+	User        *User        `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Transaction *Transaction `gorm:"foreignKey:TransactionID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"transaction,omitempty"`
+}
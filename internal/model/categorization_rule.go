@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CategorizationRule assigns a category, tags or a new description to the
+// transactions it matches. All conditions that are set must hold; a rule
+// without conditions matches every transaction. Rules run in ascending
+// priority order.
+type CategorizationRule struct {
+	ID       uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID   uuid.UUID `gorm:"type:uuid;not null;index" json:"userId"`
+	Name     string    `gorm:"type:varchar(100);not null" json:"name"`
+	Priority int       `gorm:"not null;default:0" json:"priority"`
+	Enabled  bool      `gorm:"not null;default:true" json:"enabled"`
+
+	// Conditions
+	DescriptionContains *string          `gorm:"type:varchar(255)" json:"descriptionContains,omitempty"`
+	DescriptionPattern  *string          `gorm:"type:varchar(500)" json:"descriptionPattern,omitempty"`
+	MinAmount           *float64         `gorm:"type:numeric(15,2)" json:"minAmount,omitempty"`
+	MaxAmount           *float64         `gorm:"type:numeric(15,2)" json:"maxAmount,omitempty"`
+	AccountID           *string          `gorm:"type:varchar(100)" json:"accountId,omitempty"`
+	Type                *TransactionType `gorm:"type:varchar(10)" json:"type,omitempty"`
+
+	// Actions
+	SetCategoryID  *uuid.UUID `gorm:"type:uuid;index" json:"setCategoryId,omitempty"`
+	SetDescription *string    `gorm:"type:text" json:"setDescription,omitempty"`
+	// StopProcessing keeps rules of lower priority from running after a match
+	StopProcessing bool `gorm:"not null;default:false" json:"stopProcessing"`
+
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updatedAt"`
+	DeletedAt DeletedAt `gorm:"index" json:"deletedAt,omitempty" swaggertype:"string"`
+
+	User        *User     `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	SetCategory *Category `gorm:"foreignKey:SetCategoryID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+	AddTags     []Tag     `gorm:"many2many:categorization_rule_tags;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"addTags,omitempty"`
+}
@@ -4,16 +4,28 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type Category struct {
 	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
-	UserID      uuid.UUID `gorm:"type:uuid;not null;index" json:"userId"`
-	Name        string    `gorm:"type:varchar(50);not null;index:idx_user_category_name,unique" json:"name"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null;index;index:idx_category_user_name_active,unique,where:deleted_at IS NULL" json:"userId"`
+	Name        string    `gorm:"type:varchar(50);not null;index:idx_category_user_name_active,unique,where:deleted_at IS NULL" json:"name"`
 	Description *string   `gorm:"type:text" json:"description,omitempty"`
-	CreatedAt   time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"createdAt"`
-	UpdatedAt   time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updatedAt"`
-	DeletedAt   DeletedAt `gorm:"index" json:"deletedAt,omitempty" swaggertype:"string"`
+	// Kind restricts the category to INCOME or EXPENSE transactions; a
+	// category without a kind takes both
+	Kind      *TransactionType `gorm:"type:varchar(10);check:kind IN ('INCOME', 'EXPENSE')" json:"kind,omitempty"`
+	Icon      *string          `gorm:"type:varchar(50)" json:"icon,omitempty"`
+	Color     *string          `gorm:"type:varchar(7)" json:"color,omitempty"`
+	SortOrder int              `gorm:"not null;default:0" json:"sortOrder"`
+	// ParentID nests the category under another of the user's categories
+	ParentID *uuid.UUID `gorm:"type:uuid;index" json:"parentId,omitempty"`
+	// ArchivedAt hides the category from pickers while keeping its history
+	ArchivedAt *time.Time     `gorm:"index" json:"archivedAt,omitempty"`
+	CreatedAt  time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"createdAt"`
+	UpdatedAt  time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"updatedAt"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"deletedAt,omitempty" swaggertype:"string"`
 
-	User *User `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	User   *User     `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Parent *Category `gorm:"foreignKey:ParentID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL" json:"-"`
 }
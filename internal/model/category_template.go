@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CategoryTemplate is one of the categories new users of a locale start with
+type CategoryTemplate struct {
+	ID uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	// Locale is a lower-case language tag such as en or pt-br
+	Locale    string          `gorm:"type:varchar(16);not null;index:idx_category_template_locale_name,unique" json:"locale"`
+	Name      string          `gorm:"type:varchar(50);not null;index:idx_category_template_locale_name,unique" json:"name"`
+	Kind      TransactionType `gorm:"type:varchar(10);not null;check:kind IN ('INCOME', 'EXPENSE')" json:"kind"`
+	Icon      *string         `gorm:"type:varchar(50)" json:"icon,omitempty"`
+	Color     *string         `gorm:"type:varchar(7)" json:"color,omitempty"`
+	SortOrder int             `gorm:"not null;default:0" json:"sortOrder"`
+	CreatedAt time.Time       `gorm:"default:CURRENT_TIMESTAMP" json:"createdAt"`
+	UpdatedAt time.Time       `gorm:"default:CURRENT_TIMESTAMP" json:"updatedAt"`
+}
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ExportJobStatus string
+
+const (
+	ExportJobPending   ExportJobStatus = "PENDING"
+	ExportJobRunning   ExportJobStatus = "RUNNING"
+	ExportJobCompleted ExportJobStatus = "COMPLETED"
+	ExportJobFailed    ExportJobStatus = "FAILED"
+)
+
+// ExportJob tracks an export that is too large to stream in the request and
+// is generated in the background instead. The finished file is kept in the
+// blob store until ExpiresAt.
+type ExportJob struct {
+	ID          uuid.UUID       `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID      uuid.UUID       `gorm:"type:uuid;not null;index" json:"userId"`
+	Format      string          `gorm:"type:varchar(10);not null" json:"format"`
+	Status      ExportJobStatus `gorm:"type:varchar(20);not null;default:'PENDING';check:status IN ('PENDING','RUNNING','COMPLETED','FAILED')" json:"status"`
+	DateFrom    *time.Time      `gorm:"type:date" json:"dateFrom,omitempty"`
+	DateTo      *time.Time      `gorm:"type:date" json:"dateTo,omitempty"`
+	Tags        *string         `gorm:"type:text" json:"tags,omitempty"`
+	TagMode     *string         `gorm:"type:varchar(3)" json:"tagMode,omitempty"`
+	StorageKey  *string         `gorm:"type:varchar(255)" json:"-"`
+	FileName    string          `gorm:"type:varchar(255);not null" json:"fileName"`
+	Size        int64           `gorm:"not null;default:0" json:"size"`
+	RowCount    int64           `gorm:"not null;default:0" json:"rowCount"`
+	Error       *string         `gorm:"type:text" json:"error,omitempty"`
+	CompletedAt *time.Time      `json:"completedAt,omitempty"`
+	ExpiresAt   *time.Time      `gorm:"index" json:"expiresAt,omitempty"`
+	CreatedAt   time.Time       `gorm:"default:CURRENT_TIMESTAMP" json:"createdAt"`
+	UpdatedAt   time.Time       `gorm:"default:CURRENT_TIMESTAMP" json:"updatedAt"`
+	DeletedAt   DeletedAt       `gorm:"index" json:"deletedAt,omitempty" swaggertype:"string"`
+
+	User *User `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
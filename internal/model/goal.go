@@ -0,0 +1,48 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Goal is a savings target such as an emergency fund. Money counts towards
+// it through manual contributions, the user's transactions in the linked
+// category or with the linked tag, and the value of the linked asset.
+type Goal struct {
+	ID           uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID       uuid.UUID `gorm:"type:uuid;not null;index" json:"userId"`
+	Name         string    `gorm:"type:varchar(100);not null" json:"name"`
+	TargetAmount float64   `gorm:"type:numeric(15,2);not null" json:"targetAmount"`
+	// StartDate is when saving began; transactions count from that day and
+	// the pace is measured from it
+	StartDate      time.Time  `gorm:"type:date;not null" json:"startDate"`
+	Deadline       *time.Time `gorm:"type:date;index" json:"deadline,omitempty"`
+	CategoryID     *uuid.UUID `gorm:"type:uuid;index" json:"categoryId,omitempty"`
+	TagID          *uuid.UUID `gorm:"type:uuid;index" json:"tagId,omitempty"`
+	NetWorthItemID *uuid.UUID `gorm:"type:uuid;index" json:"netWorthItemId,omitempty"`
+	CreatedAt      time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"createdAt"`
+	UpdatedAt      time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"updatedAt"`
+	DeletedAt      DeletedAt  `gorm:"index" json:"deletedAt,omitempty" swaggertype:"string"`
+
+	User          *User              `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Category      *Category          `gorm:"foreignKey:CategoryID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL" json:"-"`
+	Tag           *Tag               `gorm:"foreignKey:TagID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL" json:"-"`
+	NetWorthItem  *NetWorthItem      `gorm:"foreignKey:NetWorthItemID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL" json:"-"`
+	Contributions []GoalContribution `gorm:"foreignKey:GoalID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"contributions,omitempty"`
+}
+
+// GoalContribution is money put towards a goal by hand. Withdrawals are
+// recorded with a negative amount.
+type GoalContribution struct {
+	ID            uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID        uuid.UUID `gorm:"type:uuid;not null;index" json:"userId"`
+	GoalID        uuid.UUID `gorm:"type:uuid;not null;index" json:"goalId"`
+	Amount        float64   `gorm:"type:numeric(15,2);not null" json:"amount"`
+	ContributedOn time.Time `gorm:"type:date;not null" json:"contributedOn"`
+	Note          *string   `gorm:"type:text" json:"note,omitempty"`
+	CreatedAt     time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"createdAt"`
+	UpdatedAt     time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updatedAt"`
+
+	User *User `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
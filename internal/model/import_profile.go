@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ImportProfile is a saved CSV layout that can be reused for later imports of
+// the same bank export. Column references are header names or 1-based indexes.
+type ImportProfile struct {
+	ID                uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID            uuid.UUID `gorm:"type:uuid;not null;index:idx_user_import_profile_name,unique" json:"userId"`
+	Name              string    `gorm:"type:varchar(100);not null;index:idx_user_import_profile_name,unique" json:"name"`
+	Delimiter         string    `gorm:"type:varchar(4);not null;default:','" json:"delimiter"`
+	DateFormat        string    `gorm:"type:varchar(50);not null" json:"dateFormat"`
+	DecimalSeparator  string    `gorm:"type:varchar(1);not null;default:'.'" json:"decimalSeparator"`
+	HasHeader         bool      `gorm:"not null;default:true" json:"hasHeader"`
+	DateColumn        string    `gorm:"type:varchar(100);not null" json:"dateColumn"`
+	AmountColumn      string    `gorm:"type:varchar(100)" json:"amountColumn,omitempty"`
+	DebitColumn       string    `gorm:"type:varchar(100)" json:"debitColumn,omitempty"`
+	CreditColumn      string    `gorm:"type:varchar(100)" json:"creditColumn,omitempty"`
+	DescriptionColumn string    `gorm:"type:varchar(100)" json:"descriptionColumn,omitempty"`
+	TypeColumn        string    `gorm:"type:varchar(100)" json:"typeColumn,omitempty"`
+	CategoryColumn    string    `gorm:"type:varchar(100)" json:"categoryColumn,omitempty"`
+	CreatedAt         time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"createdAt"`
+	UpdatedAt         time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updatedAt"`
+	DeletedAt         DeletedAt `gorm:"index" json:"deletedAt,omitempty" swaggertype:"string"`
+
+	User *User `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
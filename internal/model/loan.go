@@ -0,0 +1,63 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LoanDirection string
+
+const (
+	// LoanDirectionBorrowed is money the user owes, such as a mortgage
+	LoanDirectionBorrowed LoanDirection = "BORROWED"
+	// LoanDirectionLent is money owed to the user, such as a loan to a friend
+	LoanDirectionLent LoanDirection = "LENT"
+)
+
+type LoanCompounding string
+
+const (
+	LoanCompoundingDaily    LoanCompounding = "DAILY"
+	LoanCompoundingMonthly  LoanCompounding = "MONTHLY"
+	LoanCompoundingAnnually LoanCompounding = "ANNUALLY"
+)
+
+// Loan is money borrowed or lent, repaid in monthly instalments over
+// TermMonths starting one month after StartDate
+type Loan struct {
+	ID           uuid.UUID     `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID       uuid.UUID     `gorm:"type:uuid;not null;index" json:"userId"`
+	Name         string        `gorm:"type:varchar(100);not null" json:"name"`
+	Direction    LoanDirection `gorm:"type:varchar(10);not null;check:loan_direction_check,direction IN ('BORROWED', 'LENT')" json:"direction"`
+	Counterparty *string       `gorm:"type:varchar(100)" json:"counterparty,omitempty"`
+	Principal    float64       `gorm:"type:numeric(15,2);not null" json:"principal"`
+	// InterestRate is the nominal annual rate in percent
+	InterestRate float64         `gorm:"type:numeric(7,4);not null;default:0" json:"interestRate"`
+	TermMonths   int             `gorm:"not null" json:"termMonths"`
+	Compounding  LoanCompounding `gorm:"type:varchar(10);not null;default:'MONTHLY';check:loan_compounding_check,compounding IN ('DAILY', 'MONTHLY', 'ANNUALLY')" json:"compounding"`
+	StartDate    time.Time       `gorm:"type:date;not null" json:"startDate"`
+	// CategoryID is the default category of the payment transactions
+	CategoryID *uuid.UUID `gorm:"type:uuid;index" json:"categoryId,omitempty"`
+	CreatedAt  time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"createdAt"`
+	UpdatedAt  time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"updatedAt"`
+	DeletedAt  DeletedAt  `gorm:"index" json:"deletedAt,omitempty" swaggertype:"string"`
+
+	User     *User         `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Category *Category     `gorm:"foreignKey:CategoryID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL" json:"-"`
+	Payments []LoanPayment `gorm:"foreignKey:LoanID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"payments,omitempty"`
+}
+
+// LoanPayment links a repayment transaction to its loan. The amount and date
+// are those of the transaction; the split into principal and interest is
+// worked out from all payments in date order.
+type LoanPayment struct {
+	ID            uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID        uuid.UUID `gorm:"type:uuid;not null;index" json:"userId"`
+	LoanID        uuid.UUID `gorm:"type:uuid;not null;index" json:"loanId"`
+	TransactionID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"transactionId"`
+	CreatedAt     time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"createdAt"`
+
+	User        *User        `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Transaction *Transaction `gorm:"foreignKey:TransactionID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"transaction,omitempty"`
+}
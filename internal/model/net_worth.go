@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NetWorthKind string
+
+const (
+	NetWorthKindAsset     NetWorthKind = "ASSET"
+	NetWorthKindLiability NetWorthKind = "LIABILITY"
+)
+
+// NetWorthItem is a manually valued asset, such as a house, car or
+// investment, or a liability, such as a mortgage or loan
+type NetWorthItem struct {
+	ID          uuid.UUID    `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID      uuid.UUID    `gorm:"type:uuid;not null;index" json:"userId"`
+	Name        string       `gorm:"type:varchar(100);not null" json:"name"`
+	Kind        NetWorthKind `gorm:"type:varchar(10);not null;check:net_worth_kind_check,kind IN ('ASSET', 'LIABILITY')" json:"kind"`
+	Description *string      `gorm:"type:text" json:"description,omitempty"`
+	CreatedAt   time.Time    `gorm:"default:CURRENT_TIMESTAMP" json:"createdAt"`
+	UpdatedAt   time.Time    `gorm:"default:CURRENT_TIMESTAMP" json:"updatedAt"`
+	DeletedAt   DeletedAt    `gorm:"index" json:"deletedAt,omitempty" swaggertype:"string"`
+
+	User       *User               `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Valuations []NetWorthValuation `gorm:"foreignKey:ItemID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"valuations,omitempty"`
+}
+
+// NetWorthValuation is the value of an item from a given date until the next
+// valuation. Liabilities are valued with the positive amount owed.
+type NetWorthValuation struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index" json:"userId"`
+	ItemID    uuid.UUID `gorm:"type:uuid;not null;index:idx_net_worth_item_valued_at,unique" json:"itemId"`
+	Value     float64   `gorm:"type:numeric(15,2);not null" json:"value"`
+	ValuedAt  time.Time `gorm:"type:date;not null;index:idx_net_worth_item_valued_at,unique" json:"valuedAt"`
+	Note      *string   `gorm:"type:text" json:"note,omitempty"`
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updatedAt"`
+
+	User *User `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SubscriptionStatus string
+
+const (
+	SubscriptionStatusConfirmed SubscriptionStatus = "CONFIRMED"
+	SubscriptionStatusDismissed SubscriptionStatus = "DISMISSED"
+)
+
+// SubscriptionDecision records what the user made of a subscription detected
+// in their transactions. ID is the ID of the detection, which is derived from
+// the transactions it groups, so the decision sticks as new charges arrive.
+type SubscriptionDecision struct {
+	ID     uuid.UUID          `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID uuid.UUID          `gorm:"type:uuid;not null;index" json:"userId"`
+	Status SubscriptionStatus `gorm:"type:varchar(10);not null;check:subscription_decision_status_check,status IN ('CONFIRMED', 'DISMISSED')" json:"status"`
+	// BillID is the bill a confirmed subscription was turned into. Deleting
+	// the bill drops the decision, so the subscription shows up again.
+	BillID    *uuid.UUID `gorm:"type:uuid;index" json:"billId,omitempty"`
+	CreatedAt time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"createdAt"`
+
+	User *User `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Bill *Bill `gorm:"foreignKey:BillID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type TransactionType string
@@ -15,16 +16,20 @@ const (
 
 type Transaction struct {
 	ID              uuid.UUID       `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
-	UserID          uuid.UUID       `gorm:"type:uuid;not null;index" json:"userId"`
+	UserID          uuid.UUID       `gorm:"type:uuid;not null;index;index:idx_user_external_id,unique,where:external_id IS NOT NULL" json:"userId"`
 	CategoryID      uuid.UUID       `gorm:"type:uuid;not null;index" json:"categoryId"`
 	Amount          float64         `gorm:"type:numeric(15,2);not null" json:"amount"`
 	Type            TransactionType `gorm:"type:varchar(10);not null;check:type IN ('INCOME', 'EXPENSE')" json:"type"`
 	Description     *string         `gorm:"type:text" json:"description,omitempty"`
 	TransactionDate time.Time       `gorm:"type:date;not null;index:idx_user_transaction_date" json:"transactionDate"`
-	CreatedAt       time.Time       `gorm:"default:CURRENT_TIMESTAMP" json:"createdAt"`
-	UpdatedAt       time.Time       `gorm:"default:CURRENT_TIMESTAMP" json:"updatedAt"`
-	DeletedAt       DeletedAt       `gorm:"index" json:"deletedAt,omitempty" swaggertype:"string"`
+	ExternalID      *string         `gorm:"type:varchar(255);index:idx_user_external_id,unique,where:external_id IS NOT NULL" json:"externalId,omitempty"`
+	// AccountID is the bank account reported by the statement it was imported from
+	AccountID *string        `gorm:"type:varchar(100)" json:"accountId,omitempty"`
+	CreatedAt time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"createdAt"`
+	UpdatedAt time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deletedAt,omitempty" swaggertype:"string"`
 
 	User     *User     `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
 	Category *Category `gorm:"foreignKey:CategoryID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT"`
+	Tags     []Tag     `gorm:"many2many:transaction_tags;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"tags,omitempty"`
 }
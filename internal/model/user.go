@@ -9,14 +9,18 @@ import (
 )
 
 type User struct {
-	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
-	Username  string    `gorm:"type:varchar(50);uniqueIndex;not null" json:"username" validate:"required,min=3,max=50"`
-	Email     string    `gorm:"type:varchar(100);uniqueIndex;not null" json:"email" validate:"required,email"`
-	Password  string    `gorm:"type:varchar(255);not null" json:"-"`
-	Role      string    `gorm:"type:varchar(20);default:'user';not null" json:"role"`
-	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"createdAt,omitempty"`
-	UpdatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updatedAt,omitempty"`
-	DeletedAt DeletedAt `gorm:"index" json:"deletedAt,omitempty" swaggertype:"string"`
+	ID       uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	Username string    `gorm:"type:varchar(50);uniqueIndex;not null" json:"username" validate:"required,min=3,max=50"`
+	Email    string    `gorm:"type:varchar(100);uniqueIndex;not null" json:"email" validate:"required,email"`
+	Password string    `gorm:"type:varchar(255);not null" json:"-"`
+	Role     string    `gorm:"type:varchar(20);default:'user';not null" json:"role"`
+	// DeletionScheduledAt is set while a requested account deletion waits
+	// out its grace period; the account is purged once it has passed
+	DeletionRequestedAt *time.Time `json:"deletionRequestedAt,omitempty"`
+	DeletionScheduledAt *time.Time `gorm:"index" json:"deletionScheduledAt,omitempty"`
+	CreatedAt           time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"createdAt,omitempty"`
+	UpdatedAt           time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"updatedAt,omitempty"`
+	DeletedAt           DeletedAt  `gorm:"index" json:"deletedAt,omitempty" swaggertype:"string"`
 }
 
 // Validate validates the User struct
@@ -0,0 +1,40 @@
+// Package notify delivers notifications to users outside the API.
+package notify
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Notification is a short message for one user
+type Notification struct {
+	UserID  uuid.UUID
+	Title   string
+	Message string
+}
+
+// Notifier delivers notifications, e.g. by email or push
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// LogNotifier writes notifications to the log. It is used until a delivery
+// channel is configured; users still see the alerts through the API.
+type LogNotifier struct {
+	log *zap.Logger
+}
+
+func NewLogNotifier(log *zap.Logger) *LogNotifier {
+	return &LogNotifier{log: log}
+}
+
+func (n *LogNotifier) Notify(_ context.Context, notification Notification) error {
+	n.log.Info("notification",
+		zap.String("user_id", notification.UserID.String()),
+		zap.String("title", notification.Title),
+		zap.String("message", notification.Message),
+	)
+	return nil
+}
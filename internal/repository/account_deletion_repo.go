@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/constant"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// accountPurgeSteps removes everything an account owns, children first so
+// foreign keys never block a step. Tables holding user data must be listed
+// here, otherwise a purge leaves them behind.
+var accountPurgeSteps = []struct {
+	table string
+	query string
+}{
+	{"transaction_tags", "DELETE FROM transaction_tags WHERE transaction_id IN (SELECT id FROM transactions WHERE user_id = ?)"},
+	{"cost_tags", "DELETE FROM cost_tags WHERE cost_id IN (SELECT id FROM costs WHERE user_id = ?)"},
+	{"attachments", "DELETE FROM attachments WHERE user_id = ?"},
+	{"alerts", "DELETE FROM alerts WHERE user_id = ?"},
+	{"goal_contributions", "DELETE FROM goal_contributions WHERE user_id = ?"},
+	{"goals", "DELETE FROM goals WHERE user_id = ?"},
+	{"loan_payments", "DELETE FROM loan_payments WHERE user_id = ?"},
+	{"loans", "DELETE FROM loans WHERE user_id = ?"},
+	{"subscription_decisions", "DELETE FROM subscription_decisions WHERE user_id = ?"},
+	{"bill_payments", "DELETE FROM bill_payments WHERE user_id = ?"},
+	{"bills", "DELETE FROM bills WHERE user_id = ?"},
+	{"budgets", "DELETE FROM budgets WHERE user_id = ?"},
+	{"expenses", "DELETE FROM expenses WHERE user_id = ?"},
+	{"transactions", "DELETE FROM transactions WHERE user_id = ?"},
+	{"costs", "DELETE FROM costs WHERE user_id = ?"},
+	{"categorization_rule_tags", "DELETE FROM categorization_rule_tags WHERE categorization_rule_id IN (SELECT id FROM categorization_rules WHERE user_id = ?)"},
+	{"categorization_rules", "DELETE FROM categorization_rules WHERE user_id = ?"},
+	{"tags", "DELETE FROM tags WHERE user_id = ?"},
+	{"import_profiles", "DELETE FROM import_profiles WHERE user_id = ?"},
+	{"export_jobs", "DELETE FROM export_jobs WHERE user_id = ?"},
+	{"net_worth_valuations", "DELETE FROM net_worth_valuations WHERE user_id = ?"},
+	{"net_worth_items", "DELETE FROM net_worth_items WHERE user_id = ?"},
+	{"categories", "DELETE FROM categories WHERE user_id = ?"},
+	{"users", "DELETE FROM users WHERE id = ?"},
+}
+
+type AccountDeletionRepo interface {
+	// ListDue returns accounts whose deletion grace period ended before now
+	ListDue(ctx context.Context, now time.Time, limit int) ([]model.User, error)
+	// Purge hard-deletes the account and all of its data and stores record,
+	// all in one database transaction. With dueBefore set, the account is only
+	// purged if its deletion is still scheduled before that time, which guards
+	// against a cancellation racing the purge. It returns the blob storage
+	// keys the account referenced.
+	Purge(ctx context.Context, userID uuid.UUID, dueBefore *time.Time, record *model.AccountDeletionRecord) ([]string, error)
+}
+
+type accountDeletionRepo struct {
+	db *gorm.DB
+}
+
+func NewAccountDeletionRepo(db *gorm.DB) AccountDeletionRepo {
+	return &accountDeletionRepo{db: db}
+}
+
+func (r *accountDeletionRepo) ListDue(ctx context.Context, now time.Time, limit int) ([]model.User, error) {
+	var users []model.User
+	err := r.db.WithContext(ctx).
+		Where("deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= ?", now).
+		Order("deletion_scheduled_at ASC").
+		Limit(limit).
+		Find(&users).Error
+	return users, err
+}
+
+func (r *accountDeletionRepo) Purge(ctx context.Context, userID uuid.UUID, dueBefore *time.Time, record *model.AccountDeletionRecord) ([]string, error) {
+	var keys []string
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", userID)
+		if dueBefore != nil {
+			query = query.Where("deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= ?", *dueBefore)
+		}
+		var user model.User
+		if err := query.First(&user).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return constant.ErrNotFound
+			}
+			return err
+		}
+
+		var attachmentKeys, exportKeys []string
+		if err := tx.Model(&model.Attachment{}).Where("user_id = ?", userID).Pluck("storage_key", &attachmentKeys).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.ExportJob{}).Where("user_id = ? AND storage_key IS NOT NULL", userID).Pluck("storage_key", &exportKeys).Error; err != nil {
+			return err
+		}
+		keys = append(attachmentKeys, exportKeys...)
+
+		counts := make(map[string]int64, len(accountPurgeSteps))
+		for _, step := range accountPurgeSteps {
+			result := tx.Exec(step.query, userID)
+			if result.Error != nil {
+				return result.Error
+			}
+			counts[step.table] = result.RowsAffected
+		}
+
+		deleted, err := json.Marshal(counts)
+		if err != nil {
+			return err
+		}
+		record.DeletedRows = string(deleted)
+		return tx.Create(record).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
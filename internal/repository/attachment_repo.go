@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/constant"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"gorm.io/gorm"
+)
+
+type AttachmentRepo interface {
+	BaseRepo[model.Attachment]
+	ListByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]model.Attachment, error)
+	ListByCostID(ctx context.Context, costID uuid.UUID) ([]model.Attachment, error)
+	FindByStorageKey(ctx context.Context, key string) (*model.Attachment, error)
+}
+
+type attachmentRepo struct {
+	*GormBaseRepo[model.Attachment, uuid.UUID]
+}
+
+func NewAttachmentRepo(db *gorm.DB) AttachmentRepo {
+	return &attachmentRepo{
+		GormBaseRepo: NewGormBaseRepo[model.Attachment, uuid.UUID](db),
+	}
+}
+
+// ListByTransactionID returns the attachments of a transaction, newest first
+func (r *attachmentRepo) ListByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]model.Attachment, error) {
+	var attachments []model.Attachment
+	err := r.db.WithContext(ctx).
+		Where("transaction_id = ?", transactionID).
+		Order("created_at DESC").
+		Find(&attachments).Error
+	return attachments, err
+}
+
+// ListByCostID returns the attachments of a cost, newest first
+func (r *attachmentRepo) ListByCostID(ctx context.Context, costID uuid.UUID) ([]model.Attachment, error) {
+	var attachments []model.Attachment
+	err := r.db.WithContext(ctx).
+		Where("cost_id = ?", costID).
+		Order("created_at DESC").
+		Find(&attachments).Error
+	return attachments, err
+}
+
+// FindByStorageKey finds the attachment stored under key
+func (r *attachmentRepo) FindByStorageKey(ctx context.Context, key string) (*model.Attachment, error) {
+	var attachment model.Attachment
+	err := r.db.WithContext(ctx).Where("storage_key = ?", key).First(&attachment).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, constant.ErrNotFound
+		}
+		return nil, err
+	}
+	return &attachment, nil
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RestoreSet holds the records of a backup restore. IDs and references are
+// already remapped to the target account.
+type RestoreSet struct {
+	Categories          []model.Category
+	Tags                []model.Tag
+	Budgets             []model.Budget
+	Alerts              []model.Alert
+	Transactions        []model.Transaction
+	Costs               []model.Cost
+	Expenses            []model.Expense
+	ImportProfiles      []model.ImportProfile
+	NetWorthItems       []model.NetWorthItem
+	CategorizationRules []model.CategorizationRule
+	Goals               []model.Goal
+	Loans               []model.Loan
+	Bills               []model.Bill
+}
+
+type BackupRepo interface {
+	ListAlerts(ctx context.Context, userID uuid.UUID) ([]model.Alert, error)
+	ListExpenses(ctx context.Context, userID uuid.UUID) ([]model.Expense, error)
+	// ListNetWorthItems returns the user's assets and liabilities with their
+	// full valuation history
+	ListNetWorthItems(ctx context.Context, userID uuid.UUID) ([]model.NetWorthItem, error)
+	// ListCategorizationRules returns the user's rules in the order they run,
+	// with the tags they add
+	ListCategorizationRules(ctx context.Context, userID uuid.UUID) ([]model.CategorizationRule, error)
+	// ListGoals returns the user's goals with their manual contributions
+	ListGoals(ctx context.Context, userID uuid.UUID) ([]model.Goal, error)
+	// ListLoans returns the user's loans with the payments whose transaction
+	// is not in the trash
+	ListLoans(ctx context.Context, userID uuid.UUID) ([]model.Loan, error)
+	// ListBills returns the user's bills with the payments whose transaction
+	// is not in the trash
+	ListBills(ctx context.Context, userID uuid.UUID) ([]model.Bill, error)
+	// Restore inserts the whole set in a single database transaction
+	Restore(ctx context.Context, set *RestoreSet) error
+}
+
+type backupRepo struct {
+	db *gorm.DB
+}
+
+func NewBackupRepo(db *gorm.DB) BackupRepo {
+	return &backupRepo{db: db}
+}
+
+// ListAlerts returns all of the user's alerts
+func (r *backupRepo) ListAlerts(ctx context.Context, userID uuid.UUID) ([]model.Alert, error) {
+	var alerts []model.Alert
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("triggered_at ASC").
+		Find(&alerts).Error
+	return alerts, err
+}
+
+// ListExpenses returns all of the user's expenses
+func (r *backupRepo) ListExpenses(ctx context.Context, userID uuid.UUID) ([]model.Expense, error) {
+	var expenses []model.Expense
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("expense_date ASC").
+		Find(&expenses).Error
+	return expenses, err
+}
+
+func (r *backupRepo) ListNetWorthItems(ctx context.Context, userID uuid.UUID) ([]model.NetWorthItem, error) {
+	var items []model.NetWorthItem
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Preload("Valuations", func(db *gorm.DB) *gorm.DB {
+			return db.Order("valued_at ASC")
+		}).
+		Order("created_at ASC").
+		Find(&items).Error
+	return items, err
+}
+
+func (r *backupRepo) ListCategorizationRules(ctx context.Context, userID uuid.UUID) ([]model.CategorizationRule, error) {
+	var rules []model.CategorizationRule
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Preload("AddTags").
+		Order("priority ASC, created_at ASC").
+		Find(&rules).Error
+	return rules, err
+}
+
+func (r *backupRepo) ListGoals(ctx context.Context, userID uuid.UUID) ([]model.Goal, error) {
+	var goals []model.Goal
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Preload("Contributions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("contributed_on ASC, created_at ASC")
+		}).
+		Order("created_at ASC").
+		Find(&goals).Error
+	return goals, err
+}
+
+func (r *backupRepo) ListLoans(ctx context.Context, userID uuid.UUID) ([]model.Loan, error) {
+	var loans []model.Loan
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Preload("Payments", func(db *gorm.DB) *gorm.DB {
+			return db.
+				Joins("JOIN transactions ON transactions.id = loan_payments.transaction_id AND transactions.deleted_at IS NULL").
+				Order("transactions.transaction_date ASC, transactions.created_at ASC")
+		}).
+		Order("created_at ASC").
+		Find(&loans).Error
+	return loans, err
+}
+
+func (r *backupRepo) ListBills(ctx context.Context, userID uuid.UUID) ([]model.Bill, error) {
+	var bills []model.Bill
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Preload("Payments", func(db *gorm.DB) *gorm.DB {
+			return db.
+				Joins("JOIN transactions ON transactions.id = bill_payments.transaction_id AND transactions.deleted_at IS NULL").
+				Order("bill_payments.due_date ASC")
+		}).
+		Order("created_at ASC").
+		Find(&bills).Error
+	return bills, err
+}
+
+func (r *backupRepo) Restore(ctx context.Context, set *RestoreSet) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Referenced records come first so foreign keys resolve
+		if err := createAll(tx.Omit(clause.Associations), set.Categories); err != nil {
+			return err
+		}
+		if err := createAll(tx.Omit(clause.Associations), set.Tags); err != nil {
+			return err
+		}
+		if err := createAll(tx.Omit(clause.Associations), set.Budgets); err != nil {
+			return err
+		}
+		// Tags already exist, only the link rows are inserted
+		if err := createAll(tx.Omit("User", "Category", "Tags.*"), set.Transactions); err != nil {
+			return err
+		}
+		if err := createAll(tx.Omit("User", "Category", "Tags.*"), set.Costs); err != nil {
+			return err
+		}
+		if err := createAll(tx.Omit(clause.Associations), set.Expenses); err != nil {
+			return err
+		}
+		if err := createAll(tx.Omit(clause.Associations), set.ImportProfiles); err != nil {
+			return err
+		}
+		// Valuations are inserted along with their item
+		if err := createAll(tx.Omit("User", "Valuations.User"), set.NetWorthItems); err != nil {
+			return err
+		}
+		if err := createAll(tx.Omit("User", "SetCategory", "AddTags.*"), set.CategorizationRules); err != nil {
+			return err
+		}
+		// Contributions are inserted along with their goal
+		if err := createAll(tx.Omit("User", "Category", "Tag", "NetWorthItem", "Contributions.User"), set.Goals); err != nil {
+			return err
+		}
+		// Payments are inserted along with their loan
+		if err := createAll(tx.Omit("User", "Category", "Payments.User", "Payments.Transaction"), set.Loans); err != nil {
+			return err
+		}
+		// Payments are inserted along with their bill
+		if err := createAll(tx.Omit("User", "Category", "Payments.User", "Payments.Transaction"), set.Bills); err != nil {
+			return err
+		}
+		return createAll(tx.Omit(clause.Associations), set.Alerts)
+	})
+}
+
+func createAll[T any](tx *gorm.DB, records []T) error {
+	if len(records) == 0 {
+		return nil
+	}
+	return tx.CreateInBatches(&records, 500).Error
+}
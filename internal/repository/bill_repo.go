@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/constant"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BillRepo interface {
+	BaseRepo[model.Bill]
+	// ListByUserID returns the user's bills by payee
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]model.Bill, error)
+	// ListActive returns the bills of all users that have not ended before
+	// the given day, grouped by user
+	ListActive(ctx context.Context, since time.Time) ([]model.Bill, error)
+	// CreatePayment marks an occurrence paid. A given transaction is created
+	// along with the payment; without one the payment links the existing
+	// transaction set on it.
+	CreatePayment(ctx context.Context, payment *model.BillPayment, transaction *model.Transaction) error
+	// ListPayments returns the payments of the given bills whose transaction
+	// is not in the trash, by due date
+	ListPayments(ctx context.Context, billIDs []uuid.UUID) ([]model.BillPayment, error)
+	GetPayment(ctx context.Context, billID, id uuid.UUID) (*model.BillPayment, error)
+	// TransactionLinked reports whether a transaction already pays a bill
+	TransactionLinked(ctx context.Context, transactionID uuid.UUID) (bool, error)
+	// DeletePayment removes a payment; its transaction is kept
+	DeletePayment(ctx context.Context, id uuid.UUID) error
+	// LastAlertAt returns when the bill last raised an alert of the type, or
+	// nil if it never did
+	LastAlertAt(ctx context.Context, billID uuid.UUID, alertType string) (*time.Time, error)
+	CreateAlert(ctx context.Context, alert *model.Alert) error
+	// ListAlerts returns the alerts of a bill, most recent first
+	ListAlerts(ctx context.Context, billID uuid.UUID) ([]model.Alert, error)
+}
+
+type billRepo struct {
+	*GormBaseRepo[model.Bill, uuid.UUID]
+}
+
+func NewBillRepo(db *gorm.DB) BillRepo {
+	return &billRepo{
+		GormBaseRepo: NewGormBaseRepo[model.Bill, uuid.UUID](db),
+	}
+}
+
+func (r *billRepo) ListByUserID(ctx context.Context, userID uuid.UUID) ([]model.Bill, error) {
+	var bills []model.Bill
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("payee ASC, due_day ASC").
+		Find(&bills).Error
+	return bills, err
+}
+
+func (r *billRepo) ListActive(ctx context.Context, since time.Time) ([]model.Bill, error) {
+	var bills []model.Bill
+	err := r.db.WithContext(ctx).
+		Where("end_date IS NULL OR end_date >= ?", since.Format("2006-01-02")).
+		Order("user_id ASC, payee ASC").
+		Find(&bills).Error
+	return bills, err
+}
+
+func (r *billRepo) CreatePayment(ctx context.Context, payment *model.BillPayment, transaction *model.Transaction) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if transaction != nil {
+			if err := tx.Omit(clause.Associations).Create(transaction).Error; err != nil {
+				return err
+			}
+			payment.TransactionID = transaction.ID
+		}
+		// A payment whose transaction went to the trash no longer counts,
+		// so it gives way to the new one
+		err := tx.Exec(`DELETE FROM bill_payments WHERE bill_id = ? AND due_date = ?
+			AND transaction_id IN (SELECT id FROM transactions WHERE deleted_at IS NOT NULL)`,
+			payment.BillID, payment.DueDate.Format("2006-01-02")).Error
+		if err != nil {
+			return err
+		}
+		return tx.Omit(clause.Associations).Create(payment).Error
+	})
+}
+
+func (r *billRepo) ListPayments(ctx context.Context, billIDs []uuid.UUID) ([]model.BillPayment, error) {
+	if len(billIDs) == 0 {
+		return nil, nil
+	}
+	var payments []model.BillPayment
+	err := r.db.WithContext(ctx).
+		Joins("JOIN transactions ON transactions.id = bill_payments.transaction_id AND transactions.deleted_at IS NULL").
+		Where("bill_payments.bill_id IN ?", billIDs).
+		Preload("Transaction").
+		Order("bill_payments.due_date ASC").
+		Find(&payments).Error
+	return payments, err
+}
+
+func (r *billRepo) GetPayment(ctx context.Context, billID, id uuid.UUID) (*model.BillPayment, error) {
+	var payment model.BillPayment
+	err := r.db.WithContext(ctx).
+		Where("bill_id = ? AND id = ?", billID, id).
+		First(&payment).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, constant.ErrNotFound
+		}
+		return nil, err
+	}
+	return &payment, nil
+}
+
+func (r *billRepo) TransactionLinked(ctx context.Context, transactionID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&model.BillPayment{}).
+		Where("transaction_id = ?", transactionID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *billRepo) DeletePayment(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&model.BillPayment{}, "id = ?", id).Error
+}
+
+func (r *billRepo) LastAlertAt(ctx context.Context, billID uuid.UUID, alertType string) (*time.Time, error) {
+	var alerts []model.Alert
+	err := r.db.WithContext(ctx).
+		Where("bill_id = ? AND alert_type = ?", billID, alertType).
+		Order("triggered_at DESC").
+		Limit(1).
+		Find(&alerts).Error
+	if err != nil || len(alerts) == 0 {
+		return nil, err
+	}
+	return &alerts[0].TriggeredAt, nil
+}
+
+func (r *billRepo) CreateAlert(ctx context.Context, alert *model.Alert) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(alert).Error
+}
+
+func (r *billRepo) ListAlerts(ctx context.Context, billID uuid.UUID) ([]model.Alert, error) {
+	var alerts []model.Alert
+	err := r.db.WithContext(ctx).
+		Where("bill_id = ?", billID).
+		Order("triggered_at DESC").
+		Find(&alerts).Error
+	return alerts, err
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"gorm.io/gorm"
+)
+
+// RuleChange is the outcome of running categorization rules over a stored
+// transaction. Nil fields are left as they are.
+type RuleChange struct {
+	TransactionID uuid.UUID
+	CategoryID    *uuid.UUID
+	Description   *string
+	AddTagIDs     []uuid.UUID
+}
+
+type CategorizationRuleRepo interface {
+	BaseRepo[model.CategorizationRule]
+	// ListByUserID returns the user's rules in the order they run, with their
+	// category and tags loaded
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]model.CategorizationRule, error)
+	ReplaceTags(ctx context.Context, rule *model.CategorizationRule, tags []model.Tag) error
+	// ListTransactions returns the user's transactions with their category and
+	// tags, optionally limited to a date range and a current category
+	ListTransactions(ctx context.Context, userID uuid.UUID, from, to *time.Time, categoryID *uuid.UUID) ([]model.Transaction, error)
+	// ApplyChanges writes all changes in a single database transaction
+	ApplyChanges(ctx context.Context, changes []RuleChange) error
+}
+
+type categorizationRuleRepo struct {
+	*GormBaseRepo[model.CategorizationRule, uuid.UUID]
+}
+
+func NewCategorizationRuleRepo(db *gorm.DB) CategorizationRuleRepo {
+	return &categorizationRuleRepo{
+		GormBaseRepo: NewGormBaseRepo[model.CategorizationRule, uuid.UUID](db),
+	}
+}
+
+// GetByID loads a rule with its category and tags
+func (r *categorizationRuleRepo) GetByID(ctx context.Context, id uuid.UUID) (*model.CategorizationRule, error) {
+	var rule model.CategorizationRule
+	err := r.db.WithContext(ctx).
+		Preload("SetCategory").
+		Preload("AddTags").
+		First(&rule, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *categorizationRuleRepo) ListByUserID(ctx context.Context, userID uuid.UUID) ([]model.CategorizationRule, error) {
+	var rules []model.CategorizationRule
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Preload("SetCategory").
+		Preload("AddTags").
+		Order("priority ASC, created_at ASC").
+		Find(&rules).Error
+	return rules, err
+}
+
+func (r *categorizationRuleRepo) ReplaceTags(ctx context.Context, rule *model.CategorizationRule, tags []model.Tag) error {
+	return r.db.WithContext(ctx).Model(rule).Association("AddTags").Replace(tags)
+}
+
+func (r *categorizationRuleRepo) ListTransactions(ctx context.Context, userID uuid.UUID, from, to *time.Time, categoryID *uuid.UUID) ([]model.Transaction, error) {
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	if from != nil {
+		query = query.Where("transaction_date >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where("transaction_date <= ?", *to)
+	}
+	if categoryID != nil {
+		query = query.Where("category_id = ?", *categoryID)
+	}
+
+	var transactions []model.Transaction
+	err := query.
+		Preload("Category").
+		Preload("Tags").
+		Order("transaction_date ASC, created_at ASC").
+		Find(&transactions).Error
+	return transactions, err
+}
+
+func (r *categorizationRuleRepo) ApplyChanges(ctx context.Context, changes []RuleChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, c := range changes {
+			updates := make(map[string]interface{}, 3)
+			if c.CategoryID != nil {
+				updates["category_id"] = *c.CategoryID
+			}
+			if c.Description != nil {
+				updates["description"] = *c.Description
+			}
+			if len(updates) > 0 {
+				updates["updated_at"] = time.Now()
+				if err := tx.Model(&model.Transaction{}).Where("id = ?", c.TransactionID).Updates(updates).Error; err != nil {
+					return err
+				}
+			}
+			for _, tagID := range c.AddTagIDs {
+				err := tx.Exec(
+					"INSERT INTO transaction_tags (transaction_id, tag_id) VALUES (?, ?) ON CONFLICT DO NOTHING",
+					c.TransactionID, tagID,
+				).Error
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
@@ -1,13 +1,57 @@
 package repository
 
 import (
+	"context"
+	"errors"
+	"time"
+
 	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/constant"
 	"github.com/tyha2404/nexo-app-api/internal/model"
 	"gorm.io/gorm"
 )
 
+// CategoryMerge describes moving everything filed under one category onto
+// another before the source is deleted
+type CategoryMerge struct {
+	SourceID uuid.UUID
+	TargetID uuid.UUID
+	// ReparentTarget moves the target to TargetParentID first, for a target
+	// nested below the source
+	ReparentTarget bool
+	TargetParentID *uuid.UUID
+}
+
+// CategoryMergeResult counts the records moved by a merge, trashed ones
+// included
+type CategoryMergeResult struct {
+	Transactions  int64
+	Costs         int64
+	Budgets       int64
+	Expenses      int64
+	Rules         int64
+	Goals         int64
+	Loans         int64
+	Bills         int64
+	Subcategories int64
+}
+
 type CategoryRepo interface {
 	BaseRepo[model.Category]
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]model.Category, error)
+	// ListPage returns a page of the user's categories ordered by sort order
+	// and name and their total number, archived ones only when asked for
+	ListPage(ctx context.Context, userID uuid.UUID, includeArchived bool, limit, offset int) ([]model.Category, int64, error)
+	// SetArchived archives the categories at archivedAt, or unarchives them
+	// when it is nil
+	SetArchived(ctx context.Context, ids []uuid.UUID, archivedAt *time.Time) error
+	// CountTransactions counts the category's transactions of a type, trashed
+	// ones included
+	CountTransactions(ctx context.Context, id uuid.UUID, txType model.TransactionType) (int64, error)
+	// Merge moves the transactions, costs, budgets, expenses, rules, goals,
+	// loans, bills and subcategories of the source onto the target and
+	// soft-deletes the source, all in one transaction
+	Merge(ctx context.Context, merge CategoryMerge) (*CategoryMergeResult, error)
 }
 
 type categoryRepo struct {
@@ -19,3 +63,111 @@ func NewCategoryRepo(db *gorm.DB) CategoryRepo {
 		GormBaseRepo: NewGormBaseRepo[model.Category, uuid.UUID](db),
 	}
 }
+
+// ListByUserID returns all categories owned by the user ordered by sort
+// order and name
+func (r *categoryRepo) ListByUserID(ctx context.Context, userID uuid.UUID) ([]model.Category, error) {
+	var categories []model.Category
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("sort_order ASC, name ASC").
+		Find(&categories).Error
+	return categories, err
+}
+
+// Delete soft-deletes the category. Its subcategories move up to its parent
+// so they stay in the tree.
+func (r *categoryRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var category model.Category
+		if err := tx.Select("id", "parent_id").First(&category, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return constant.ErrNotFound
+			}
+			return err
+		}
+		if err := tx.Model(&model.Category{}).
+			Where("parent_id = ?", id).
+			Update("parent_id", category.ParentID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&category).Error
+	})
+}
+
+func (r *categoryRepo) ListPage(ctx context.Context, userID uuid.UUID, includeArchived bool, limit, offset int) ([]model.Category, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.Category{}).Where("user_id = ?", userID)
+	if !includeArchived {
+		query = query.Where("archived_at IS NULL")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var categories []model.Category
+	err := query.Order("sort_order ASC, name ASC").Limit(limit).Offset(offset).Find(&categories).Error
+	return categories, total, err
+}
+
+func (r *categoryRepo) SetArchived(ctx context.Context, ids []uuid.UUID, archivedAt *time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Model(&model.Category{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{"archived_at": archivedAt, "updated_at": time.Now()}).Error
+}
+
+func (r *categoryRepo) CountTransactions(ctx context.Context, id uuid.UUID, txType model.TransactionType) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Unscoped().
+		Model(&model.Transaction{}).
+		Where("category_id = ? AND type = ?", id, txType).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *categoryRepo) Merge(ctx context.Context, merge CategoryMerge) (*CategoryMergeResult, error) {
+	result := &CategoryMergeResult{}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if merge.ReparentTarget {
+			if err := tx.Exec("UPDATE categories SET parent_id = ? WHERE id = ?", merge.TargetParentID, merge.TargetID).Error; err != nil {
+				return err
+			}
+		}
+
+		// Trashed records move as well, so they can still be restored once
+		// the source is purged
+		steps := []struct {
+			query string
+			count *int64
+		}{
+			{"UPDATE categories SET parent_id = @target WHERE parent_id = @source AND id <> @target", &result.Subcategories},
+			{"UPDATE transactions SET category_id = @target WHERE category_id = @source", &result.Transactions},
+			{"UPDATE costs SET category_id = @target WHERE category_id = @source", &result.Costs},
+			{"UPDATE budgets SET category_id = @target WHERE category_id = @source", &result.Budgets},
+			{"UPDATE expenses SET category_id = @target WHERE category_id = @source", &result.Expenses},
+			{"UPDATE categorization_rules SET set_category_id = @target WHERE set_category_id = @source", &result.Rules},
+			{"UPDATE goals SET category_id = @target WHERE category_id = @source", &result.Goals},
+			{"UPDATE loans SET category_id = @target WHERE category_id = @source", &result.Loans},
+			{"UPDATE bills SET category_id = @target WHERE category_id = @source", &result.Bills},
+		}
+		args := map[string]interface{}{"source": merge.SourceID, "target": merge.TargetID}
+		for _, step := range steps {
+			res := tx.Exec(step.query, args)
+			if res.Error != nil {
+				return res.Error
+			}
+			*step.count = res.RowsAffected
+		}
+
+		return tx.Delete(&model.Category{}, "id = ?", merge.SourceID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
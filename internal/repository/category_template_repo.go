@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/constant"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"gorm.io/gorm"
+)
+
+type CategoryTemplateRepo interface {
+	BaseRepo[model.CategoryTemplate]
+	// ListByLocale returns the templates of a locale in sort order, or all
+	// templates grouped by locale when locale is empty
+	ListByLocale(ctx context.Context, locale string) ([]model.CategoryTemplate, error)
+	FindByName(ctx context.Context, locale, name string) (*model.CategoryTemplate, error)
+}
+
+type categoryTemplateRepo struct {
+	*GormBaseRepo[model.CategoryTemplate, uuid.UUID]
+}
+
+func NewCategoryTemplateRepo(db *gorm.DB) CategoryTemplateRepo {
+	return &categoryTemplateRepo{
+		GormBaseRepo: NewGormBaseRepo[model.CategoryTemplate, uuid.UUID](db),
+	}
+}
+
+func (r *categoryTemplateRepo) ListByLocale(ctx context.Context, locale string) ([]model.CategoryTemplate, error) {
+	var templates []model.CategoryTemplate
+	query := r.db.WithContext(ctx)
+	if locale != "" {
+		query = query.Where("locale = ?", locale)
+	}
+	err := query.Order("locale ASC, sort_order ASC, name ASC").Find(&templates).Error
+	return templates, err
+}
+
+func (r *categoryTemplateRepo) FindByName(ctx context.Context, locale, name string) (*model.CategoryTemplate, error) {
+	var template model.CategoryTemplate
+	err := r.db.WithContext(ctx).Where("locale = ? AND name = ?", locale, name).First(&template).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, constant.ErrNotFound
+		}
+		return nil, err
+	}
+	return &template, nil
+}
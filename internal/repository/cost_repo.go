@@ -12,6 +12,7 @@ import (
 type CostRepo interface {
 	BaseRepo[model.Cost]
 	ListWithCategory(ctx context.Context, userID uuid.UUID, limit, offset int, filters map[string]interface{}) ([]model.Cost, error)
+	ReplaceTags(ctx context.Context, cost *model.Cost, tags []model.Tag) error
 }
 
 type costRepo struct {
@@ -46,9 +47,12 @@ func (r *costRepo) ListWithCategory(
 		}
 	}
 
+	query = applyTagFilter(query, "cost_tags", "cost_id", filters)
+
 	var costs []model.Cost
 	err := query.
 		Preload("Category").
+		Preload("Tags").
 		Where("user_id = ?", userID).
 		Order("incurred_at DESC").
 		Limit(limit).
@@ -57,3 +61,21 @@ func (r *costRepo) ListWithCategory(
 
 	return costs, err
 }
+
+// ReplaceTags replaces the full set of tags linked to the cost
+func (r *costRepo) ReplaceTags(ctx context.Context, cost *model.Cost, tags []model.Tag) error {
+	return r.db.WithContext(ctx).Model(cost).Association("Tags").Replace(tags)
+}
+
+// GetByID retrieves a cost together with its category and tags
+func (r *costRepo) GetByID(ctx context.Context, id uuid.UUID) (*model.Cost, error) {
+	var cost model.Cost
+	err := r.db.WithContext(ctx).
+		Preload("Category").
+		Preload("Tags").
+		First(&cost, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &cost, nil
+}
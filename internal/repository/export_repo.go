@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"gorm.io/gorm"
+)
+
+// ExportFilter selects the records included in an export. The date range and
+// the list filters (tags, tagMode) apply to transactions and costs; categories
+// and budgets are always exported in full so the file stays self-contained.
+type ExportFilter struct {
+	// From is inclusive, To is exclusive; either may be nil
+	From    *time.Time
+	To      *time.Time
+	Filters map[string]interface{}
+}
+
+type ExportRepo interface {
+	BaseRepo[model.ExportJob]
+	ListJobsByUserID(ctx context.Context, userID uuid.UUID) ([]model.ExportJob, error)
+	ListExpiredJobs(ctx context.Context, userID uuid.UUID, now time.Time) ([]model.ExportJob, error)
+	CountRecords(ctx context.Context, userID uuid.UUID, filter ExportFilter) (int64, error)
+	EachTransactionBatch(ctx context.Context, userID uuid.UUID, filter ExportFilter, fn func([]model.Transaction) error) error
+	EachCostBatch(ctx context.Context, userID uuid.UUID, filter ExportFilter, fn func([]model.Cost) error) error
+	ListBudgets(ctx context.Context, userID uuid.UUID) ([]model.Budget, error)
+}
+
+// exportBatchSize bounds how many records an export holds in memory at once
+const exportBatchSize = 500
+
+type exportRepo struct {
+	*GormBaseRepo[model.ExportJob, uuid.UUID]
+}
+
+func NewExportRepo(db *gorm.DB) ExportRepo {
+	return &exportRepo{
+		GormBaseRepo: NewGormBaseRepo[model.ExportJob, uuid.UUID](db),
+	}
+}
+
+// ListJobsByUserID returns the user's export jobs, newest first
+func (r *exportRepo) ListJobsByUserID(ctx context.Context, userID uuid.UUID) ([]model.ExportJob, error) {
+	var jobs []model.ExportJob
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&jobs).Error
+	return jobs, err
+}
+
+// ListExpiredJobs returns the user's jobs whose file is past its expiry
+func (r *exportRepo) ListExpiredJobs(ctx context.Context, userID uuid.UUID, now time.Time) ([]model.ExportJob, error) {
+	var jobs []model.ExportJob
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND expires_at < ?", userID, now).
+		Find(&jobs).Error
+	return jobs, err
+}
+
+// CountRecords returns how many transactions and costs the export will hold
+func (r *exportRepo) CountRecords(ctx context.Context, userID uuid.UUID, filter ExportFilter) (int64, error) {
+	var transactions, costs int64
+	if err := r.transactionQuery(ctx, userID, filter).Count(&transactions).Error; err != nil {
+		return 0, err
+	}
+	if err := r.costQuery(ctx, userID, filter).Count(&costs).Error; err != nil {
+		return 0, err
+	}
+	return transactions + costs, nil
+}
+
+// EachTransactionBatch calls fn with consecutive batches of the user's
+// transactions, including their category and tags
+func (r *exportRepo) EachTransactionBatch(ctx context.Context, userID uuid.UUID, filter ExportFilter, fn func([]model.Transaction) error) error {
+	var batch []model.Transaction
+	return r.transactionQuery(ctx, userID, filter).
+		Preload("Category").
+		Preload("Tags").
+		FindInBatches(&batch, exportBatchSize, func(tx *gorm.DB, _ int) error {
+			return fn(batch)
+		}).Error
+}
+
+// EachCostBatch calls fn with consecutive batches of the user's costs,
+// including their category and tags
+func (r *exportRepo) EachCostBatch(ctx context.Context, userID uuid.UUID, filter ExportFilter, fn func([]model.Cost) error) error {
+	var batch []model.Cost
+	return r.costQuery(ctx, userID, filter).
+		Preload("Category").
+		Preload("Tags").
+		FindInBatches(&batch, exportBatchSize, func(tx *gorm.DB, _ int) error {
+			return fn(batch)
+		}).Error
+}
+
+// ListBudgets returns all of the user's budgets
+func (r *exportRepo) ListBudgets(ctx context.Context, userID uuid.UUID) ([]model.Budget, error) {
+	var budgets []model.Budget
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("period_start ASC").
+		Find(&budgets).Error
+	return budgets, err
+}
+
+func (r *exportRepo) transactionQuery(ctx context.Context, userID uuid.UUID, filter ExportFilter) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&model.Transaction{}).Where("user_id = ?", userID)
+	if filter.From != nil {
+		query = query.Where("transaction_date >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("transaction_date < ?", *filter.To)
+	}
+	return applyTagFilter(query, "transaction_tags", "transaction_id", filter.Filters)
+}
+
+func (r *exportRepo) costQuery(ctx context.Context, userID uuid.UUID, filter ExportFilter) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&model.Cost{}).Where("user_id = ?", userID)
+	if filter.From != nil {
+		query = query.Where("incurred_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("incurred_at < ?", *filter.To)
+	}
+	return applyTagFilter(query, "cost_tags", "cost_id", filter.Filters)
+}
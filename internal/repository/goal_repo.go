@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/constant"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"gorm.io/gorm"
+)
+
+// GoalTransactionFilter selects the transactions that count towards a goal:
+// those dated on or after Since that are in one of CategoryIDs or carry TagID
+type GoalTransactionFilter struct {
+	Since       time.Time
+	CategoryIDs []uuid.UUID
+	TagID       *uuid.UUID
+}
+
+func (f GoalTransactionFilter) empty() bool {
+	return len(f.CategoryIDs) == 0 && f.TagID == nil
+}
+
+type GoalRepo interface {
+	BaseRepo[model.Goal]
+	// ListByUserID returns the user's goals, soonest deadline first
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]model.Goal, error)
+	// ListWithDeadline returns the goals of all users that have a deadline
+	ListWithDeadline(ctx context.Context) ([]model.Goal, error)
+	// ContributionTotals sums the manual contributions of each goal
+	ContributionTotals(ctx context.Context, goalIDs []uuid.UUID) (map[uuid.UUID]float64, error)
+	// TransactionTotal nets the user's active transactions matching filter,
+	// counting each transaction once. Expenses are money put aside for the
+	// goal and count positive, income is taken out of it and counts negative.
+	TransactionTotal(ctx context.Context, userID uuid.UUID, filter GoalTransactionFilter) (float64, error)
+	// ListTransactions returns the user's active transactions matching
+	// filter, most recent first
+	ListTransactions(ctx context.Context, userID uuid.UUID, filter GoalTransactionFilter) ([]model.Transaction, error)
+	CreateContribution(ctx context.Context, contribution *model.GoalContribution) error
+	// ListContributions returns the manual contributions of a goal, most
+	// recent first
+	ListContributions(ctx context.Context, goalID uuid.UUID) ([]model.GoalContribution, error)
+	GetContribution(ctx context.Context, goalID, id uuid.UUID) (*model.GoalContribution, error)
+	DeleteContribution(ctx context.Context, id uuid.UUID) error
+	// LastAlertAt returns when the goal last raised an alert of the type, or
+	// nil if it never did
+	LastAlertAt(ctx context.Context, goalID uuid.UUID, alertType string) (*time.Time, error)
+	CreateAlert(ctx context.Context, alert *model.Alert) error
+	// ListAlerts returns the alerts of a goal, most recent first
+	ListAlerts(ctx context.Context, goalID uuid.UUID) ([]model.Alert, error)
+}
+
+type goalRepo struct {
+	*GormBaseRepo[model.Goal, uuid.UUID]
+}
+
+func NewGoalRepo(db *gorm.DB) GoalRepo {
+	return &goalRepo{
+		GormBaseRepo: NewGormBaseRepo[model.Goal, uuid.UUID](db),
+	}
+}
+
+func (r *goalRepo) ListByUserID(ctx context.Context, userID uuid.UUID) ([]model.Goal, error) {
+	var goals []model.Goal
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("deadline ASC NULLS LAST, name ASC").
+		Find(&goals).Error
+	return goals, err
+}
+
+func (r *goalRepo) ListWithDeadline(ctx context.Context) ([]model.Goal, error) {
+	var goals []model.Goal
+	err := r.db.WithContext(ctx).
+		Where("deadline IS NOT NULL").
+		Order("user_id ASC, deadline ASC").
+		Find(&goals).Error
+	return goals, err
+}
+
+func (r *goalRepo) ContributionTotals(ctx context.Context, goalIDs []uuid.UUID) (map[uuid.UUID]float64, error) {
+	totals := make(map[uuid.UUID]float64, len(goalIDs))
+	if len(goalIDs) == 0 {
+		return totals, nil
+	}
+
+	var rows []struct {
+		GoalID uuid.UUID
+		Total  float64
+	}
+	err := r.db.WithContext(ctx).
+		Model(&model.GoalContribution{}).
+		Select("goal_id, SUM(amount) AS total").
+		Where("goal_id IN ?", goalIDs).
+		Group("goal_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		totals[row.GoalID] = row.Total
+	}
+	return totals, nil
+}
+
+func (r *goalRepo) TransactionTotal(ctx context.Context, userID uuid.UUID, filter GoalTransactionFilter) (float64, error) {
+	if filter.empty() {
+		return 0, nil
+	}
+	var total float64
+	err := r.goalTransactions(ctx, userID, filter).
+		Select("COALESCE(SUM(CASE WHEN type = ? THEN -amount ELSE amount END), 0)", model.TransactionTypeIncome).
+		Scan(&total).Error
+	return total, err
+}
+
+func (r *goalRepo) ListTransactions(ctx context.Context, userID uuid.UUID, filter GoalTransactionFilter) ([]model.Transaction, error) {
+	if filter.empty() {
+		return nil, nil
+	}
+	var transactions []model.Transaction
+	err := r.goalTransactions(ctx, userID, filter).
+		Order("transaction_date DESC, created_at DESC").
+		Find(&transactions).Error
+	return transactions, err
+}
+
+func (r *goalRepo) goalTransactions(ctx context.Context, userID uuid.UUID, filter GoalTransactionFilter) *gorm.DB {
+	var conds []string
+	var args []interface{}
+	if len(filter.CategoryIDs) > 0 {
+		conds = append(conds, "category_id IN ?")
+		args = append(args, filter.CategoryIDs)
+	}
+	if filter.TagID != nil {
+		conds = append(conds, "EXISTS (SELECT 1 FROM transaction_tags tt WHERE tt.transaction_id = transactions.id AND tt.tag_id = ?)")
+		args = append(args, *filter.TagID)
+	}
+	return r.db.WithContext(ctx).
+		Model(&model.Transaction{}).
+		Where("user_id = ? AND transaction_date >= ?", userID, filter.Since.Format("2006-01-02")).
+		Where("("+strings.Join(conds, " OR ")+")", args...)
+}
+
+func (r *goalRepo) CreateContribution(ctx context.Context, contribution *model.GoalContribution) error {
+	return r.db.WithContext(ctx).Create(contribution).Error
+}
+
+func (r *goalRepo) ListContributions(ctx context.Context, goalID uuid.UUID) ([]model.GoalContribution, error) {
+	var contributions []model.GoalContribution
+	err := r.db.WithContext(ctx).
+		Where("goal_id = ?", goalID).
+		Order("contributed_on DESC, created_at DESC").
+		Find(&contributions).Error
+	return contributions, err
+}
+
+func (r *goalRepo) GetContribution(ctx context.Context, goalID, id uuid.UUID) (*model.GoalContribution, error) {
+	var contribution model.GoalContribution
+	err := r.db.WithContext(ctx).
+		Where("goal_id = ? AND id = ?", goalID, id).
+		First(&contribution).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, constant.ErrNotFound
+		}
+		return nil, err
+	}
+	return &contribution, nil
+}
+
+func (r *goalRepo) DeleteContribution(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&model.GoalContribution{}, "id = ?", id).Error
+}
+
+func (r *goalRepo) LastAlertAt(ctx context.Context, goalID uuid.UUID, alertType string) (*time.Time, error) {
+	var alerts []model.Alert
+	err := r.db.WithContext(ctx).
+		Where("goal_id = ? AND alert_type = ?", goalID, alertType).
+		Order("triggered_at DESC").
+		Limit(1).
+		Find(&alerts).Error
+	if err != nil || len(alerts) == 0 {
+		return nil, err
+	}
+	return &alerts[0].TriggeredAt, nil
+}
+
+func (r *goalRepo) CreateAlert(ctx context.Context, alert *model.Alert) error {
+	return r.db.WithContext(ctx).Omit("User", "Budget", "Goal").Create(alert).Error
+}
+
+func (r *goalRepo) ListAlerts(ctx context.Context, goalID uuid.UUID) ([]model.Alert, error) {
+	var alerts []model.Alert
+	err := r.db.WithContext(ctx).
+		Where("goal_id = ?", goalID).
+		Order("triggered_at DESC").
+		Find(&alerts).Error
+	return alerts, err
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/constant"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"gorm.io/gorm"
+)
+
+type ImportProfileRepo interface {
+	BaseRepo[model.ImportProfile]
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]model.ImportProfile, error)
+	FindByName(ctx context.Context, userID uuid.UUID, name string) (*model.ImportProfile, error)
+}
+
+type importProfileRepo struct {
+	*GormBaseRepo[model.ImportProfile, uuid.UUID]
+}
+
+func NewImportProfileRepo(db *gorm.DB) ImportProfileRepo {
+	return &importProfileRepo{
+		GormBaseRepo: NewGormBaseRepo[model.ImportProfile, uuid.UUID](db),
+	}
+}
+
+// ListByUserID returns the user's saved import profiles ordered by name
+func (r *importProfileRepo) ListByUserID(ctx context.Context, userID uuid.UUID) ([]model.ImportProfile, error) {
+	var profiles []model.ImportProfile
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("name ASC").
+		Find(&profiles).Error
+	return profiles, err
+}
+
+// FindByName finds a user's import profile by name
+func (r *importProfileRepo) FindByName(ctx context.Context, userID uuid.UUID, name string) (*model.ImportProfile, error) {
+	var profile model.ImportProfile
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND name = ?", userID, name).
+		First(&profile).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, constant.ErrNotFound
+		}
+		return nil, err
+	}
+	return &profile, nil
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/constant"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LoanRepo interface {
+	BaseRepo[model.Loan]
+	// ListByUserID returns the user's loans, oldest first
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]model.Loan, error)
+	// CreatePayment creates the payment transaction and links it to the loan,
+	// or neither of them
+	CreatePayment(ctx context.Context, payment *model.LoanPayment, transaction *model.Transaction) error
+	// ListPayments returns the payments of the given loans whose transaction
+	// is not in the trash, with the transaction, in date order
+	ListPayments(ctx context.Context, loanIDs []uuid.UUID) ([]model.LoanPayment, error)
+	GetPayment(ctx context.Context, loanID, id uuid.UUID) (*model.LoanPayment, error)
+	// DeletePayment removes the payment and moves its transaction to the trash
+	DeletePayment(ctx context.Context, payment *model.LoanPayment) error
+}
+
+type loanRepo struct {
+	*GormBaseRepo[model.Loan, uuid.UUID]
+}
+
+func NewLoanRepo(db *gorm.DB) LoanRepo {
+	return &loanRepo{
+		GormBaseRepo: NewGormBaseRepo[model.Loan, uuid.UUID](db),
+	}
+}
+
+func (r *loanRepo) ListByUserID(ctx context.Context, userID uuid.UUID) ([]model.Loan, error) {
+	var loans []model.Loan
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("start_date ASC, name ASC").
+		Find(&loans).Error
+	return loans, err
+}
+
+func (r *loanRepo) CreatePayment(ctx context.Context, payment *model.LoanPayment, transaction *model.Transaction) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(transaction).Error; err != nil {
+			return err
+		}
+		payment.TransactionID = transaction.ID
+		return tx.Omit(clause.Associations).Create(payment).Error
+	})
+}
+
+func (r *loanRepo) ListPayments(ctx context.Context, loanIDs []uuid.UUID) ([]model.LoanPayment, error) {
+	if len(loanIDs) == 0 {
+		return nil, nil
+	}
+	var payments []model.LoanPayment
+	err := r.db.WithContext(ctx).
+		Joins("JOIN transactions ON transactions.id = loan_payments.transaction_id AND transactions.deleted_at IS NULL").
+		Where("loan_payments.loan_id IN ?", loanIDs).
+		Preload("Transaction").
+		Order("transactions.transaction_date ASC, transactions.created_at ASC").
+		Find(&payments).Error
+	return payments, err
+}
+
+func (r *loanRepo) GetPayment(ctx context.Context, loanID, id uuid.UUID) (*model.LoanPayment, error) {
+	var payment model.LoanPayment
+	err := r.db.WithContext(ctx).
+		Where("loan_id = ? AND id = ?", loanID, id).
+		First(&payment).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, constant.ErrNotFound
+		}
+		return nil, err
+	}
+	return &payment, nil
+}
+
+func (r *loanRepo) DeletePayment(ctx context.Context, payment *model.LoanPayment) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&model.LoanPayment{}, "id = ?", payment.ID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.Transaction{}, "id = ?", payment.TransactionID).Error
+	})
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/constant"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NetWorthRepo interface {
+	BaseRepo[model.NetWorthItem]
+	// ListByUserID returns the user's items ordered by kind and name, each
+	// with its most recent valuation only
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]model.NetWorthItem, error)
+	// ListValuations returns the valuations of an item, most recent first
+	ListValuations(ctx context.Context, itemID uuid.UUID) ([]model.NetWorthValuation, error)
+	// ListUserValuations returns all valuations of the user's items dated on
+	// or before the given day, oldest first
+	ListUserValuations(ctx context.Context, userID uuid.UUID, until time.Time) ([]model.NetWorthValuation, error)
+	// SaveValuation stores a valuation, replacing one of the same item and date
+	SaveValuation(ctx context.Context, valuation *model.NetWorthValuation) error
+	GetValuation(ctx context.Context, itemID, id uuid.UUID) (*model.NetWorthValuation, error)
+	DeleteValuation(ctx context.Context, id uuid.UUID) error
+}
+
+type netWorthRepo struct {
+	*GormBaseRepo[model.NetWorthItem, uuid.UUID]
+}
+
+func NewNetWorthRepo(db *gorm.DB) NetWorthRepo {
+	return &netWorthRepo{
+		GormBaseRepo: NewGormBaseRepo[model.NetWorthItem, uuid.UUID](db),
+	}
+}
+
+func (r *netWorthRepo) ListByUserID(ctx context.Context, userID uuid.UUID) ([]model.NetWorthItem, error) {
+	var items []model.NetWorthItem
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Preload("Valuations", func(db *gorm.DB) *gorm.DB {
+			return db.Where(`NOT EXISTS (
+				SELECT 1 FROM net_worth_valuations later
+				WHERE later.item_id = net_worth_valuations.item_id AND later.valued_at > net_worth_valuations.valued_at)`)
+		}).
+		Order("kind ASC, name ASC").
+		Find(&items).Error
+	return items, err
+}
+
+func (r *netWorthRepo) ListValuations(ctx context.Context, itemID uuid.UUID) ([]model.NetWorthValuation, error) {
+	var valuations []model.NetWorthValuation
+	err := r.db.WithContext(ctx).
+		Where("item_id = ?", itemID).
+		Order("valued_at DESC").
+		Find(&valuations).Error
+	return valuations, err
+}
+
+func (r *netWorthRepo) ListUserValuations(ctx context.Context, userID uuid.UUID, until time.Time) ([]model.NetWorthValuation, error) {
+	var valuations []model.NetWorthValuation
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND valued_at <= ?", userID, until.Format("2006-01-02")).
+		Order("valued_at ASC").
+		Find(&valuations).Error
+	return valuations, err
+}
+
+func (r *netWorthRepo) SaveValuation(ctx context.Context, valuation *model.NetWorthValuation) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "item_id"}, {Name: "valued_at"}},
+			DoUpdates: clause.AssignmentColumns([]string{"value", "note", "updated_at"}),
+		}).
+		Create(valuation).Error
+}
+
+func (r *netWorthRepo) GetValuation(ctx context.Context, itemID, id uuid.UUID) (*model.NetWorthValuation, error) {
+	var valuation model.NetWorthValuation
+	err := r.db.WithContext(ctx).Where("id = ? AND item_id = ?", id, itemID).First(&valuation).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, constant.ErrNotFound
+		}
+		return nil, err
+	}
+	return &valuation, nil
+}
+
+func (r *netWorthRepo) DeleteValuation(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&model.NetWorthValuation{}, "id = ?", id).Error
+}
@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"gorm.io/gorm"
+)
+
+// TagTotal is the aggregated activity linked to a single tag
+type TagTotal struct {
+	TagID            uuid.UUID
+	Name             string
+	Income           float64
+	Expense          float64
+	TransactionCount int64
+	CostTotal        float64
+	CostCount        int64
+}
+
+// PeriodTotal is the transaction activity of one period of a summary report
+type PeriodTotal struct {
+	PeriodStart      time.Time
+	Income           float64
+	Expense          float64
+	TransactionCount int64
+}
+
+// CategoryTotal is the activity booked on a single category
+type CategoryTotal struct {
+	CategoryID       uuid.UUID
+	Name             string
+	TransactionTotal float64
+	TransactionCount int64
+	CostTotal        float64
+	CostCount        int64
+}
+
+// CategoryTotalsFilter selects the activity summed by CategoryTotals
+type CategoryTotalsFilter struct {
+	// Type is the transaction type, INCOME or EXPENSE
+	Type string
+	// IncludeCosts adds costs to the totals
+	IncludeCosts bool
+	// From and To are the inclusive range of transaction dates as YYYY-MM-DD
+	From, To string
+	// CostsFrom and CostsTo bound the cost timestamps, To exclusive
+	CostsFrom, CostsTo time.Time
+}
+
+type ReportRepo interface {
+	TagTotals(ctx context.Context, userID uuid.UUID, from, to *time.Time) ([]TagTotal, error)
+	// PeriodTotals sums the user's transactions per period, where unit is a
+	// date_trunc unit such as month or week. from and to are inclusive dates
+	// in YYYY-MM-DD form; periods without transactions are left out.
+	PeriodTotals(ctx context.Context, userID uuid.UUID, unit, from, to string) ([]PeriodTotal, error)
+	// CategoryTotals sums the user's transactions and optionally costs per
+	// category. Categories without activity are left out.
+	CategoryTotals(ctx context.Context, userID uuid.UUID, filter CategoryTotalsFilter) ([]CategoryTotal, error)
+	// Balance is the user's income minus expenses up to and including the
+	// given YYYY-MM-DD date
+	Balance(ctx context.Context, userID uuid.UUID, asOf string) (float64, error)
+	// ListBudgets returns the user's budgets on active categories
+	ListBudgets(ctx context.Context, userID uuid.UUID) ([]model.Budget, error)
+}
+
+type reportRepo struct {
+	db *gorm.DB
+}
+
+func NewReportRepo(db *gorm.DB) ReportRepo {
+	return &reportRepo{db: db}
+}
+
+// TagTotals sums the user's transactions and costs per tag. from is inclusive,
+// to is exclusive; either bound may be nil.
+func (r *reportRepo) TagTotals(ctx context.Context, userID uuid.UUID, from, to *time.Time) ([]TagTotal, error) {
+	args := map[string]interface{}{"user": userID}
+	txConds := []string{"t.user_id = @user", "t.deleted_at IS NULL"}
+	costConds := []string{"c.user_id = @user", "c.deleted_at IS NULL"}
+	if from != nil {
+		args["from"] = *from
+		txConds = append(txConds, "t.transaction_date >= @from")
+		costConds = append(costConds, "c.incurred_at >= @from")
+	}
+	if to != nil {
+		args["to"] = *to
+		txConds = append(txConds, "t.transaction_date < @to")
+		costConds = append(costConds, "c.incurred_at < @to")
+	}
+
+	query := `
+		SELECT tg.id AS tag_id, tg.name,
+			COALESCE(tx.income, 0) AS income,
+			COALESCE(tx.expense, 0) AS expense,
+			COALESCE(tx.cnt, 0) AS transaction_count,
+			COALESCE(co.total, 0) AS cost_total,
+			COALESCE(co.cnt, 0) AS cost_count
+		FROM tags tg
+		LEFT JOIN (
+			SELECT tt.tag_id,
+				SUM(CASE WHEN t.type = 'INCOME' THEN t.amount ELSE 0 END) AS income,
+				SUM(CASE WHEN t.type = 'EXPENSE' THEN t.amount ELSE 0 END) AS expense,
+				COUNT(*) AS cnt
+			FROM transaction_tags tt
+			JOIN transactions t ON t.id = tt.transaction_id
+			WHERE ` + strings.Join(txConds, " AND ") + `
+			GROUP BY tt.tag_id
+		) tx ON tx.tag_id = tg.id
+		LEFT JOIN (
+			SELECT ct.tag_id, SUM(c.amount) AS total, COUNT(*) AS cnt
+			FROM cost_tags ct
+			JOIN costs c ON c.id = ct.cost_id
+			WHERE ` + strings.Join(costConds, " AND ") + `
+			GROUP BY ct.tag_id
+		) co ON co.tag_id = tg.id
+		WHERE tg.user_id = @user AND tg.deleted_at IS NULL
+		ORDER BY tg.name ASC`
+
+	var totals []TagTotal
+	err := r.db.WithContext(ctx).Raw(query, args).Scan(&totals).Error
+	return totals, err
+}
+
+func (r *reportRepo) PeriodTotals(ctx context.Context, userID uuid.UUID, unit, from, to string) ([]PeriodTotal, error) {
+	// transaction_date is a calendar date, so it is truncated as a plain
+	// timestamp to keep the session time zone out of the bucketing
+	query := `
+		SELECT date_trunc(@unit, t.transaction_date::timestamp)::date AS period_start,
+			SUM(CASE WHEN t.type = 'INCOME' THEN t.amount ELSE 0 END) AS income,
+			SUM(CASE WHEN t.type = 'EXPENSE' THEN t.amount ELSE 0 END) AS expense,
+			COUNT(*) AS transaction_count
+		FROM transactions t
+		WHERE t.user_id = @user AND t.deleted_at IS NULL
+			AND t.transaction_date >= CAST(@from AS date) AND t.transaction_date <= CAST(@to AS date)
+		GROUP BY 1
+		ORDER BY 1`
+
+	var totals []PeriodTotal
+	err := r.db.WithContext(ctx).
+		Raw(query, map[string]interface{}{"user": userID, "unit": unit, "from": from, "to": to}).
+		Scan(&totals).Error
+	return totals, err
+}
+
+func (r *reportRepo) CategoryTotals(ctx context.Context, userID uuid.UUID, filter CategoryTotalsFilter) ([]CategoryTotal, error) {
+	activity := `
+			SELECT t.category_id,
+				SUM(t.amount) AS transaction_total, COUNT(*) AS transaction_count,
+				0 AS cost_total, 0 AS cost_count
+			FROM transactions t
+			WHERE t.user_id = @user AND t.deleted_at IS NULL AND t.type = @type
+				AND t.transaction_date >= CAST(@from AS date) AND t.transaction_date <= CAST(@to AS date)
+			GROUP BY t.category_id`
+	if filter.IncludeCosts {
+		activity += `
+			UNION ALL
+			SELECT co.category_id, 0, 0, SUM(co.amount), COUNT(*)
+			FROM costs co
+			WHERE co.user_id = @user AND co.deleted_at IS NULL
+				AND co.incurred_at >= @costsFrom AND co.incurred_at < @costsTo
+			GROUP BY co.category_id`
+	}
+
+	// Deleted categories are still named so their history stays readable
+	query := `
+		SELECT a.category_id, COALESCE(c.name, '') AS name,
+			SUM(a.transaction_total) AS transaction_total,
+			SUM(a.transaction_count) AS transaction_count,
+			SUM(a.cost_total) AS cost_total,
+			SUM(a.cost_count) AS cost_count
+		FROM (` + activity + `
+		) a
+		LEFT JOIN categories c ON c.id = a.category_id
+		GROUP BY a.category_id, c.name`
+
+	var totals []CategoryTotal
+	err := r.db.WithContext(ctx).
+		Raw(query, map[string]interface{}{
+			"user":      userID,
+			"type":      filter.Type,
+			"from":      filter.From,
+			"to":        filter.To,
+			"costsFrom": filter.CostsFrom,
+			"costsTo":   filter.CostsTo,
+		}).
+		Scan(&totals).Error
+	return totals, err
+}
+
+func (r *reportRepo) Balance(ctx context.Context, userID uuid.UUID, asOf string) (float64, error) {
+	var balance float64
+	err := r.db.WithContext(ctx).
+		Raw(`SELECT COALESCE(SUM(CASE WHEN t.type = 'INCOME' THEN t.amount ELSE -t.amount END), 0)
+			FROM transactions t
+			WHERE t.user_id = @user AND t.deleted_at IS NULL AND t.transaction_date <= CAST(@asOf AS date)`,
+			map[string]interface{}{"user": userID, "asOf": asOf}).
+		Scan(&balance).Error
+	return balance, err
+}
+
+func (r *reportRepo) ListBudgets(ctx context.Context, userID uuid.UUID) ([]model.Budget, error) {
+	var budgets []model.Budget
+	err := r.db.WithContext(ctx).
+		Joins("JOIN categories c ON c.id = budgets.category_id AND c.deleted_at IS NULL").
+		Where("budgets.user_id = ?", userID).
+		Order("budgets.period_start ASC").
+		Find(&budgets).Error
+	return budgets, err
+}
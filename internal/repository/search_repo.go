@@ -0,0 +1,334 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"gorm.io/gorm"
+)
+
+const (
+	SearchKindTransaction = "transaction"
+	SearchKindCost        = "cost"
+)
+
+// searchHeadlineOptions marks matched words in the snippets returned by Search
+const searchHeadlineOptions = "StartSel=<mark>, StopSel=</mark>, MinWords=5, MaxWords=25, ShortWord=1"
+
+// AmountCondition compares an amount with a value, e.g. amount > 50
+type AmountCondition struct {
+	// Op is one of =, <, <=, > and >=
+	Op    string
+	Value float64
+}
+
+// SearchFilter selects the transactions and costs returned by Search
+type SearchFilter struct {
+	// Query is a to_tsquery expression matched against descriptions, cost
+	// titles, category names and tag names. Empty matches everything.
+	Query string
+	// Kind restricts the results to transactions or costs; empty returns both
+	Kind string
+	// Type is INCOME or EXPENSE; costs count as expenses
+	Type    string
+	Amounts []AmountCondition
+	// Categories are case-insensitive prefixes of category names, any of
+	// which must match
+	Categories []string
+	// Tags are case-insensitive prefixes of tag names, all of which must match
+	Tags []string
+	// From and To are inclusive dates
+	From, To *time.Time
+	Limit    int
+	Offset   int
+}
+
+// SearchHit is a transaction or cost matching a search
+type SearchHit struct {
+	Kind         string
+	ID           uuid.UUID
+	Date         time.Time
+	Amount       float64
+	Currency     *string
+	Type         *string
+	Text         *string
+	CategoryID   uuid.UUID
+	CategoryName string
+	Rank         float64
+	// Highlight is the description or title with the matched words marked
+	Highlight       string
+	MatchedText     bool
+	MatchedCategory bool
+	MatchedTag      bool
+	Tags            []model.Tag `gorm:"-"`
+}
+
+type SearchRepo interface {
+	// Search returns a page of the user's transactions and costs matching the
+	// filter, best ranked first, and the total number of matches
+	Search(ctx context.Context, userID uuid.UUID, filter SearchFilter) ([]SearchHit, int64, error)
+}
+
+type searchRepo struct {
+	db *gorm.DB
+}
+
+func NewSearchRepo(db *gorm.DB) SearchRepo {
+	return &searchRepo{db: db}
+}
+
+func (r *searchRepo) Search(ctx context.Context, userID uuid.UUID, filter SearchFilter) ([]SearchHit, int64, error) {
+	args := map[string]interface{}{
+		"user":     userID,
+		"query":    filter.Query,
+		"headline": searchHeadlineOptions,
+	}
+
+	var parts []string
+	if filter.Kind != SearchKindCost {
+		part, err := r.transactionSearch(filter, args)
+		if err != nil {
+			return nil, 0, err
+		}
+		parts = append(parts, part)
+	}
+	if filter.Kind != SearchKindTransaction && filter.Type != string(model.TransactionTypeIncome) {
+		part, err := r.costSearch(filter, args)
+		if err != nil {
+			return nil, 0, err
+		}
+		parts = append(parts, part)
+	}
+	if len(parts) == 0 {
+		return nil, 0, nil
+	}
+
+	query := `
+		WITH q AS (SELECT CASE WHEN @query = '' THEN NULL ELSE to_tsquery('simple', @query) END AS query)
+		SELECT * FROM (` + strings.Join(parts, " UNION ALL ") + `) hits`
+
+	var total int64
+	if err := r.db.WithContext(ctx).
+		Raw("SELECT COUNT(*) FROM ("+query+") counted", args).
+		Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return nil, 0, nil
+	}
+
+	args["limit"] = filter.Limit
+	args["offset"] = filter.Offset
+	var hits []SearchHit
+	if err := r.db.WithContext(ctx).
+		Raw(query+` ORDER BY rank DESC, date DESC, id ASC LIMIT @limit OFFSET @offset`, args).
+		Scan(&hits).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := r.loadTags(ctx, hits); err != nil {
+		return nil, 0, err
+	}
+	return hits, total, nil
+}
+
+// transactionSearch builds the query part selecting matching transactions
+func (r *searchRepo) transactionSearch(filter SearchFilter, args map[string]interface{}) (string, error) {
+	conds := []string{"t.user_id = @user", "t.deleted_at IS NULL"}
+	if filter.Type != "" {
+		args["type"] = filter.Type
+		conds = append(conds, "t.type = @type")
+	}
+	if filter.From != nil {
+		args["from"] = filter.From.Format("2006-01-02")
+		conds = append(conds, "t.transaction_date >= @from")
+	}
+	if filter.To != nil {
+		args["to"] = filter.To.Format("2006-01-02")
+		conds = append(conds, "t.transaction_date <= @to")
+	}
+	amountConds, err := amountConditions("t.amount", filter.Amounts, args)
+	if err != nil {
+		return "", err
+	}
+	conds = append(conds, amountConds...)
+	conds = append(conds, nameConditions("c.name", "transaction_tags tt", "tt.transaction_id = t.id", filter, args)...)
+
+	textMatch := "to_tsvector('simple', coalesce(t.description, '')) @@ q.query"
+	categoryMatch := "to_tsvector('simple', c.name) @@ q.query"
+	tagMatch := `EXISTS (
+				SELECT 1 FROM transaction_tags tt
+				JOIN tags tg ON tg.id = tt.tag_id AND tg.deleted_at IS NULL
+				WHERE tt.transaction_id = t.id AND to_tsvector('simple', tg.name) @@ q.query)`
+
+	return `
+		SELECT 'transaction' AS kind, t.id, t.transaction_date::timestamp AT TIME ZONE 'UTC' AS date, t.amount,
+			NULL AS currency, t.type, t.description AS text,
+			c.id AS category_id, c.name AS category_name,
+			COALESCE(ts_rank(to_tsvector('simple', coalesce(t.description, '')), q.query) * 2
+				+ ts_rank(to_tsvector('simple', c.name), q.query), 0) AS rank,
+			CASE WHEN q.query IS NULL OR t.description IS NULL THEN coalesce(t.description, '')
+				ELSE ts_headline('simple', t.description, q.query, @headline) END AS highlight,
+			COALESCE(` + textMatch + `, false) AS matched_text,
+			COALESCE(` + categoryMatch + `, false) AS matched_category,
+			COALESCE(` + tagMatch + `, false) AS matched_tag
+		FROM transactions t
+		JOIN categories c ON c.id = t.category_id
+		CROSS JOIN q
+		WHERE ` + strings.Join(conds, " AND ") + `
+			AND (q.query IS NULL OR ` + textMatch + ` OR ` + categoryMatch + ` OR ` + tagMatch + `)`, nil
+}
+
+// costSearch builds the query part selecting matching costs
+func (r *searchRepo) costSearch(filter SearchFilter, args map[string]interface{}) (string, error) {
+	conds := []string{"co.user_id = @user", "co.deleted_at IS NULL"}
+	if filter.From != nil {
+		args["cost_from"] = *filter.From
+		conds = append(conds, "co.incurred_at >= @cost_from")
+	}
+	if filter.To != nil {
+		args["cost_to"] = filter.To.AddDate(0, 0, 1)
+		conds = append(conds, "co.incurred_at < @cost_to")
+	}
+	amountConds, err := amountConditions("co.amount", filter.Amounts, args)
+	if err != nil {
+		return "", err
+	}
+	conds = append(conds, amountConds...)
+	conds = append(conds, nameConditions("c.name", "cost_tags ct", "ct.cost_id = co.id", filter, args)...)
+
+	textMatch := "to_tsvector('simple', co.title) @@ q.query"
+	categoryMatch := "to_tsvector('simple', c.name) @@ q.query"
+	tagMatch := `EXISTS (
+				SELECT 1 FROM cost_tags ct
+				JOIN tags tg ON tg.id = ct.tag_id AND tg.deleted_at IS NULL
+				WHERE ct.cost_id = co.id AND to_tsvector('simple', tg.name) @@ q.query)`
+
+	return `
+		SELECT 'cost' AS kind, co.id, co.incurred_at AS date, co.amount,
+			co.currency, NULL AS type, co.title AS text,
+			c.id AS category_id, c.name AS category_name,
+			COALESCE(ts_rank(to_tsvector('simple', co.title), q.query) * 2
+				+ ts_rank(to_tsvector('simple', c.name), q.query), 0) AS rank,
+			CASE WHEN q.query IS NULL THEN co.title
+				ELSE ts_headline('simple', co.title, q.query, @headline) END AS highlight,
+			COALESCE(` + textMatch + `, false) AS matched_text,
+			COALESCE(` + categoryMatch + `, false) AS matched_category,
+			COALESCE(` + tagMatch + `, false) AS matched_tag
+		FROM costs co
+		JOIN categories c ON c.id = co.category_id
+		CROSS JOIN q
+		WHERE ` + strings.Join(conds, " AND ") + `
+			AND (q.query IS NULL OR ` + textMatch + ` OR ` + categoryMatch + ` OR ` + tagMatch + `)`, nil
+}
+
+// amountConditions turns the amount filters into SQL conditions on column.
+// The operator is checked against a fixed list as it ends up in the SQL.
+func amountConditions(column string, amounts []AmountCondition, args map[string]interface{}) ([]string, error) {
+	var conds []string
+	for i, a := range amounts {
+		switch a.Op {
+		case "=", "<", "<=", ">", ">=":
+		default:
+			return nil, fmt.Errorf("unsupported amount operator %q", a.Op)
+		}
+		name := fmt.Sprintf("amount%d", i)
+		args[name] = a.Value
+		conds = append(conds, fmt.Sprintf("%s %s @%s", column, a.Op, name))
+	}
+	return conds, nil
+}
+
+// nameConditions restricts the results to the filter's category and tag name
+// prefixes. links is the tag join table with its alias, linkCond joins it to
+// the searched row.
+func nameConditions(categoryColumn, links, linkCond string, filter SearchFilter, args map[string]interface{}) []string {
+	var conds []string
+	if len(filter.Categories) > 0 {
+		var anyOf []string
+		for i, name := range filter.Categories {
+			arg := fmt.Sprintf("category%d", i)
+			args[arg] = escapeLike(strings.ToLower(name)) + "%"
+			anyOf = append(anyOf, fmt.Sprintf("lower(%s) LIKE @%s", categoryColumn, arg))
+		}
+		conds = append(conds, "("+strings.Join(anyOf, " OR ")+")")
+	}
+	for i, name := range filter.Tags {
+		arg := fmt.Sprintf("tag%d", i)
+		args[arg] = escapeLike(strings.ToLower(name)) + "%"
+		conds = append(conds, fmt.Sprintf(`EXISTS (
+				SELECT 1 FROM %s
+				JOIN tags tg ON tg.id = %s.tag_id AND tg.deleted_at IS NULL
+				WHERE %s AND lower(tg.name) LIKE @%s)`, links, strings.Fields(links)[1], linkCond, arg))
+	}
+	return conds
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// loadTags fills in the tags of the hits
+func (r *searchRepo) loadTags(ctx context.Context, hits []SearchHit) error {
+	var transactionIDs, costIDs []uuid.UUID
+	for _, h := range hits {
+		if h.Kind == SearchKindTransaction {
+			transactionIDs = append(transactionIDs, h.ID)
+		} else {
+			costIDs = append(costIDs, h.ID)
+		}
+	}
+
+	type link struct {
+		OwnerID   uuid.UUID
+		ID        uuid.UUID
+		UserID    uuid.UUID
+		Name      string
+		Color     *string
+		CreatedAt time.Time
+		UpdatedAt time.Time
+	}
+	var links []link
+	for _, source := range []struct {
+		table, column string
+		ids           []uuid.UUID
+	}{
+		{"transaction_tags", "transaction_id", transactionIDs},
+		{"cost_tags", "cost_id", costIDs},
+	} {
+		if len(source.ids) == 0 {
+			continue
+		}
+		var found []link
+		if err := r.db.WithContext(ctx).
+			Table(source.table+" l").
+			Select("l."+source.column+" AS owner_id, tags.id, tags.user_id, tags.name, tags.color, tags.created_at, tags.updated_at").
+			Joins("JOIN tags ON tags.id = l.tag_id AND tags.deleted_at IS NULL").
+			Where("l."+source.column+" IN ?", source.ids).
+			Order("tags.name ASC").
+			Scan(&found).Error; err != nil {
+			return err
+		}
+		links = append(links, found...)
+	}
+
+	byOwner := make(map[uuid.UUID][]model.Tag)
+	for _, l := range links {
+		byOwner[l.OwnerID] = append(byOwner[l.OwnerID], model.Tag{
+			ID:        l.ID,
+			UserID:    l.UserID,
+			Name:      l.Name,
+			Color:     l.Color,
+			CreatedAt: l.CreatedAt,
+			UpdatedAt: l.UpdatedAt,
+		})
+	}
+	for i := range hits {
+		hits[i].Tags = byOwner[hits[i].ID]
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SubscriptionRepo interface {
+	ListDecisions(ctx context.Context, userID uuid.UUID) ([]model.SubscriptionDecision, error)
+	// Confirm creates the bill and records the confirmation, replacing any
+	// earlier decision, in one database transaction
+	Confirm(ctx context.Context, decision *model.SubscriptionDecision, bill *model.Bill) error
+	// Dismiss records the dismissal, replacing any earlier decision
+	Dismiss(ctx context.Context, decision *model.SubscriptionDecision) error
+	// DeleteDecision forgets the user's decision on a detection
+	DeleteDecision(ctx context.Context, userID, id uuid.UUID) error
+}
+
+type subscriptionRepo struct {
+	db *gorm.DB
+}
+
+func NewSubscriptionRepo(db *gorm.DB) SubscriptionRepo {
+	return &subscriptionRepo{db: db}
+}
+
+func (r *subscriptionRepo) ListDecisions(ctx context.Context, userID uuid.UUID) ([]model.SubscriptionDecision, error) {
+	var decisions []model.SubscriptionDecision
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Find(&decisions).Error
+	return decisions, err
+}
+
+func (r *subscriptionRepo) Confirm(ctx context.Context, decision *model.SubscriptionDecision, bill *model.Bill) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(bill).Error; err != nil {
+			return err
+		}
+		decision.BillID = &bill.ID
+		return saveDecision(tx, decision)
+	})
+}
+
+func (r *subscriptionRepo) Dismiss(ctx context.Context, decision *model.SubscriptionDecision) error {
+	return saveDecision(r.db.WithContext(ctx), decision)
+}
+
+func (r *subscriptionRepo) DeleteDecision(ctx context.Context, userID, id uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Where("user_id = ? AND id = ?", userID, id).
+		Delete(&model.SubscriptionDecision{}).Error
+}
+
+func saveDecision(db *gorm.DB, decision *model.SubscriptionDecision) error {
+	return db.Omit(clause.Associations).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"status", "bill_id", "created_at"}),
+		}).
+		Create(decision).Error
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/constant"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"gorm.io/gorm"
+)
+
+// tagLinkTables lists the many-to-many join tables that reference tags,
+// keyed by table name with the owning column as value
+var tagLinkTables = map[string]string{
+	"transaction_tags": "transaction_id",
+	"cost_tags":        "cost_id",
+	// Rules adding the tag follow a merge as well
+	"categorization_rule_tags": "categorization_rule_id",
+}
+
+type TagRepo interface {
+	BaseRepo[model.Tag]
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]model.Tag, error)
+	FindByIDs(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) ([]model.Tag, error)
+	FindByName(ctx context.Context, userID uuid.UUID, name string) (*model.Tag, error)
+	Merge(ctx context.Context, sourceID, targetID uuid.UUID) error
+}
+
+type tagRepo struct {
+	*GormBaseRepo[model.Tag, uuid.UUID]
+}
+
+func NewTagRepo(db *gorm.DB) TagRepo {
+	return &tagRepo{
+		GormBaseRepo: NewGormBaseRepo[model.Tag, uuid.UUID](db),
+	}
+}
+
+// ListByUserID returns all tags owned by the user ordered by name
+func (r *tagRepo) ListByUserID(ctx context.Context, userID uuid.UUID) ([]model.Tag, error) {
+	var tags []model.Tag
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("name ASC").
+		Find(&tags).Error
+	return tags, err
+}
+
+// FindByIDs returns the tags among ids that belong to the user
+func (r *tagRepo) FindByIDs(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) ([]model.Tag, error) {
+	var tags []model.Tag
+	if len(ids) == 0 {
+		return tags, nil
+	}
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND id IN ?", userID, ids).
+		Find(&tags).Error
+	return tags, err
+}
+
+// FindByName finds a user's tag by its case-insensitive name
+func (r *tagRepo) FindByName(ctx context.Context, userID uuid.UUID, name string) (*model.Tag, error) {
+	var tag model.Tag
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND LOWER(name) = LOWER(?)", userID, name).
+		First(&tag).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, constant.ErrNotFound
+		}
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// Merge re-points every link of the source tag to the target tag and removes
+// the source tag, all inside a single database transaction
+func (r *tagRepo) Merge(ctx context.Context, sourceID, targetID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for table, column := range tagLinkTables {
+			insert := fmt.Sprintf(
+				"INSERT INTO %s (%s, tag_id) SELECT %s, ? FROM %s WHERE tag_id = ? ON CONFLICT DO NOTHING",
+				table, column, column, table,
+			)
+			if err := tx.Exec(insert, targetID, sourceID).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE tag_id = ?", table), sourceID).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Delete(&model.Tag{}, "id = ?", sourceID).Error
+	})
+}
+
+// applyTagFilter restricts query to rows linked to the comma-separated tag IDs
+// in filters["tags"]. filters["tagMode"] selects whether rows must carry any
+// (default) or all of the requested tags.
+func applyTagFilter(query *gorm.DB, joinTable, ownerColumn string, filters map[string]interface{}) *gorm.DB {
+	raw, ok := filters["tags"].(string)
+	if !ok || raw == "" {
+		return query
+	}
+
+	var tagIDs []uuid.UUID
+	for _, part := range strings.Split(raw, ",") {
+		if id, err := uuid.Parse(strings.TrimSpace(part)); err == nil {
+			tagIDs = append(tagIDs, id)
+		}
+	}
+	if len(tagIDs) == 0 {
+		return query
+	}
+
+	sub := query.Session(&gorm.Session{NewDB: true}).
+		Table(joinTable).
+		Select(ownerColumn).
+		Where("tag_id IN ?", tagIDs)
+	if mode, _ := filters["tagMode"].(string); mode == "all" {
+		sub = sub.Group(ownerColumn).Having("COUNT(DISTINCT tag_id) = ?", len(tagIDs))
+	}
+
+	return query.Where("id IN (?)", sub)
+}
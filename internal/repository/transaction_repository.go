@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/tyha2404/nexo-app-api/internal/model"
@@ -11,9 +12,20 @@ import (
 type TransactionRepository interface {
 	Create(ctx context.Context, transaction *model.Transaction) error
 	GetByID(ctx context.Context, id uuid.UUID) (*model.Transaction, error)
-	ListByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]model.Transaction, int64, error)
+	ListByUserID(ctx context.Context, userID uuid.UUID, limit, offset int, filters map[string]interface{}) ([]model.Transaction, int64, error)
+	ListByDateRange(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]model.Transaction, error)
+	ListRecent(ctx context.Context, userID uuid.UUID, limit int) ([]model.Transaction, error)
+	ListWithTagsByDateRange(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]model.Transaction, error)
+	CreateBatch(ctx context.Context, transactions []model.Transaction) error
+	FindExistingExternalIDs(ctx context.Context, userID uuid.UUID, externalIDs []string) ([]string, error)
+	// AccountBalance returns the income minus expenses of the user's
+	// transactions imported for a bank account and dated before the given
+	// day, together with their number
+	AccountBalance(ctx context.Context, userID uuid.UUID, accountID string, before time.Time) (float64, int64, error)
 	Update(ctx context.Context, transaction *model.Transaction) error
+	ReplaceTags(ctx context.Context, transaction *model.Transaction, tags []model.Tag) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	Merge(ctx context.Context, keep *model.Transaction, duplicateIDs []uuid.UUID) error
 }
 
 type transactionRepository struct {
@@ -32,6 +44,7 @@ func (r *transactionRepository) GetByID(ctx context.Context, id uuid.UUID) (*mod
 	var transaction model.Transaction
 	err := r.db.WithContext(ctx).
 		Preload("Category").
+		Preload("Tags").
 		First(&transaction, "id = ?", id).Error
 	if err != nil {
 		return nil, err
@@ -39,11 +52,12 @@ func (r *transactionRepository) GetByID(ctx context.Context, id uuid.UUID) (*mod
 	return &transaction, nil
 }
 
-func (r *transactionRepository) ListByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]model.Transaction, int64, error) {
+func (r *transactionRepository) ListByUserID(ctx context.Context, userID uuid.UUID, limit, offset int, filters map[string]interface{}) ([]model.Transaction, int64, error) {
 	var transactions []model.Transaction
 	var total int64
 
 	query := r.db.WithContext(ctx).Model(&model.Transaction{}).Where("user_id = ?", userID)
+	query = applyTagFilter(query, "transaction_tags", "transaction_id", filters)
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -51,6 +65,7 @@ func (r *transactionRepository) ListByUserID(ctx context.Context, userID uuid.UU
 
 	err := query.
 		Preload("Category").
+		Preload("Tags").
 		Order("transaction_date desc, created_at desc").
 		Limit(limit).
 		Offset(offset).
@@ -59,10 +74,131 @@ func (r *transactionRepository) ListByUserID(ctx context.Context, userID uuid.UU
 	return transactions, total, err
 }
 
+// ListByDateRange returns the user's transactions dated within [from, to]
+func (r *transactionRepository) ListByDateRange(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]model.Transaction, error) {
+	var transactions []model.Transaction
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND transaction_date BETWEEN ? AND ?", userID, from, to).
+		Find(&transactions).Error
+	return transactions, err
+}
+
+// ListWithTagsByDateRange returns the user's transactions dated within
+// [from, to] with their category and tags
+func (r *transactionRepository) ListWithTagsByDateRange(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]model.Transaction, error) {
+	var transactions []model.Transaction
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND transaction_date BETWEEN ? AND ?", userID, from, to).
+		Preload("Category").
+		Preload("Tags").
+		Order("transaction_date asc, created_at asc").
+		Find(&transactions).Error
+	return transactions, err
+}
+
+// ListRecent returns up to limit of the user's most recent transactions
+// without associations, newest first
+func (r *transactionRepository) ListRecent(ctx context.Context, userID uuid.UUID, limit int) ([]model.Transaction, error) {
+	var transactions []model.Transaction
+	err := r.db.WithContext(ctx).
+		Select("id", "category_id", "amount", "type", "description", "transaction_date").
+		Where("user_id = ?", userID).
+		Order("transaction_date desc, created_at desc").
+		Limit(limit).
+		Find(&transactions).Error
+	return transactions, err
+}
+
+func (r *transactionRepository) AccountBalance(ctx context.Context, userID uuid.UUID, accountID string, before time.Time) (float64, int64, error) {
+	var result struct {
+		Balance float64
+		Count   int64
+	}
+	err := r.db.WithContext(ctx).
+		Model(&model.Transaction{}).
+		Select("COALESCE(SUM(CASE WHEN type = 'INCOME' THEN amount ELSE -amount END), 0) AS balance, COUNT(*) AS count").
+		Where("user_id = ? AND account_id = ? AND transaction_date < ?", userID, accountID, before).
+		Scan(&result).Error
+	return result.Balance, result.Count, err
+}
+
+// FindExistingExternalIDs returns the subset of externalIDs already stored
+// on the user's transactions. Transactions in the trash count as well, so a
+// re-import does not bring back what the user deleted.
+func (r *transactionRepository) FindExistingExternalIDs(ctx context.Context, userID uuid.UUID, externalIDs []string) ([]string, error) {
+	var existing []string
+	if len(externalIDs) == 0 {
+		return existing, nil
+	}
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Model(&model.Transaction{}).
+		Where("user_id = ? AND external_id IN ?", userID, externalIDs).
+		Pluck("external_id", &existing).Error
+	return existing, err
+}
+
+// CreateBatch inserts all transactions in a single database transaction so
+// that either every row is stored or none is
+func (r *transactionRepository) CreateBatch(ctx context.Context, transactions []model.Transaction) error {
+	if len(transactions) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.CreateInBatches(&transactions, 500).Error
+	})
+}
+
 func (r *transactionRepository) Update(ctx context.Context, transaction *model.Transaction) error {
 	return r.db.WithContext(ctx).Save(transaction).Error
 }
 
+// ReplaceTags replaces the full set of tags linked to the transaction
+func (r *transactionRepository) ReplaceTags(ctx context.Context, transaction *model.Transaction, tags []model.Tag) error {
+	return r.db.WithContext(ctx).Model(transaction).Association("Tags").Replace(tags)
+}
+
 func (r *transactionRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return r.db.WithContext(ctx).Delete(&model.Transaction{}, id).Error
 }
+
+// Merge folds the duplicates into keep in a single database transaction:
+// their tags are added to keep, their attachments move over and they are
+// moved to the trash. The description and external ID of keep are saved as
+// well, so that the caller can carry them over; a carried external ID is
+// cleared on the duplicate first to keep it unique.
+func (r *transactionRepository) Merge(ctx context.Context, keep *model.Transaction, duplicateIDs []uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`INSERT INTO transaction_tags (transaction_id, tag_id)
+			SELECT ?, tag_id FROM transaction_tags WHERE transaction_id IN ?
+			ON CONFLICT DO NOTHING`, keep.ID, duplicateIDs).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&model.Attachment{}).
+			Where("transaction_id IN ?", duplicateIDs).
+			Update("transaction_id", keep.ID).Error
+		if err != nil {
+			return err
+		}
+		if keep.ExternalID != nil {
+			err = tx.Model(&model.Transaction{}).
+				Where("id IN ? AND external_id = ?", duplicateIDs, *keep.ExternalID).
+				Update("external_id", nil).Error
+			if err != nil {
+				return err
+			}
+		}
+		err = tx.Model(&model.Transaction{}).
+			Where("id = ?", keep.ID).
+			Updates(map[string]interface{}{
+				"description": keep.Description,
+				"external_id": keep.ExternalID,
+				"updated_at":  time.Now(),
+			}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Delete(&model.Transaction{}, "id IN ?", duplicateIDs).Error
+	})
+}
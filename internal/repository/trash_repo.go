@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/constant"
+	"gorm.io/gorm"
+)
+
+// Trash types, named after the tables they live in
+const (
+	TrashTypeTransactions = "transactions"
+	TrashTypeCosts        = "costs"
+	TrashTypeCategories   = "categories"
+	TrashTypeBudgets      = "budgets"
+)
+
+// TrashTypes lists every kind of record that can be restored from the trash
+var TrashTypes = []string{TrashTypeTransactions, TrashTypeCosts, TrashTypeCategories, TrashTypeBudgets}
+
+// trashQueries select a user's soft-deleted records of each type in the
+// shape of TrashItem so they can be combined with UNION ALL
+var trashQueries = map[string]string{
+	TrashTypeTransactions: `SELECT 'transactions' AS type, t.id, COALESCE(t.description, '') AS label, t.amount, t.category_id, t.deleted_at
+		FROM transactions t WHERE t.user_id = @user AND t.deleted_at IS NOT NULL`,
+	TrashTypeCosts: `SELECT 'costs' AS type, c.id, c.title AS label, c.amount, c.category_id, c.deleted_at
+		FROM costs c WHERE c.user_id = @user AND c.deleted_at IS NOT NULL`,
+	TrashTypeCategories: `SELECT 'categories' AS type, c.id, c.name AS label, NULL::numeric AS amount, NULL::uuid AS category_id, c.deleted_at
+		FROM categories c WHERE c.user_id = @user AND c.deleted_at IS NOT NULL`,
+	TrashTypeBudgets: `SELECT 'budgets' AS type, b.id, COALESCE(c.name, '') AS label, b.amount, b.category_id, b.deleted_at
+		FROM budgets b LEFT JOIN categories c ON c.id = b.category_id
+		WHERE b.user_id = @user AND b.deleted_at IS NOT NULL`,
+}
+
+// trashPurgeSteps permanently remove records that have been in the trash
+// since before @before, children first so foreign keys never block a step.
+// Categories are only removed once nothing references them any more, which
+// leaves them for a later run while a trashed transaction still points at
+// them. Tables referencing categories must be checked here as well.
+var trashPurgeSteps = []struct {
+	table string
+	query string
+}{
+	{"transaction_tags", "DELETE FROM transaction_tags WHERE transaction_id IN (SELECT id FROM transactions WHERE deleted_at < @before)"},
+	{"cost_tags", "DELETE FROM cost_tags WHERE cost_id IN (SELECT id FROM costs WHERE deleted_at < @before)"},
+	{"attachments", `DELETE FROM attachments
+		WHERE transaction_id IN (SELECT id FROM transactions WHERE deleted_at < @before)
+		OR cost_id IN (SELECT id FROM costs WHERE deleted_at < @before)`},
+	{"alerts", "DELETE FROM alerts WHERE budget_id IN (SELECT id FROM budgets WHERE deleted_at < @before)"},
+	{"transactions", "DELETE FROM transactions WHERE deleted_at < @before"},
+	{"costs", "DELETE FROM costs WHERE deleted_at < @before"},
+	{"budgets", "DELETE FROM budgets WHERE deleted_at < @before"},
+	{"categories", `DELETE FROM categories c WHERE c.deleted_at < @before
+		AND NOT EXISTS (SELECT 1 FROM transactions t WHERE t.category_id = c.id)
+		AND NOT EXISTS (SELECT 1 FROM costs co WHERE co.category_id = c.id)
+		AND NOT EXISTS (SELECT 1 FROM budgets b WHERE b.category_id = c.id)
+		AND NOT EXISTS (SELECT 1 FROM expenses e WHERE e.category_id = c.id)
+		AND NOT EXISTS (SELECT 1 FROM categorization_rules r WHERE r.set_category_id = c.id)
+		AND NOT EXISTS (SELECT 1 FROM goals g WHERE g.category_id = c.id)
+		AND NOT EXISTS (SELECT 1 FROM loans l WHERE l.category_id = c.id)
+		AND NOT EXISTS (SELECT 1 FROM bills bi WHERE bi.category_id = c.id)`},
+}
+
+// TrashItem is a soft-deleted record of any of the trash types
+type TrashItem struct {
+	Type       string
+	ID         uuid.UUID
+	Label      string
+	Amount     *float64
+	CategoryID *uuid.UUID
+	DeletedAt  time.Time
+}
+
+type TrashRepo interface {
+	// List returns the user's trashed records of the given types, most
+	// recently deleted first, and the total number of them
+	List(ctx context.Context, userID uuid.UUID, types []string, limit, offset int) ([]TrashItem, int64, error)
+	// FindDeleted loads a soft-deleted record into dest, which must point to
+	// one of the trashable models
+	FindDeleted(ctx context.Context, id uuid.UUID, dest interface{}) error
+	// Restore clears the deletion of the record loaded into dest and applies
+	// updates in the same statement
+	Restore(ctx context.Context, dest interface{}, updates map[string]interface{}) error
+	// Purge permanently deletes records trashed before the given time. It
+	// returns the number of rows removed per table and the blob storage keys
+	// of the removed attachments.
+	Purge(ctx context.Context, before time.Time) (map[string]int64, []string, error)
+}
+
+type trashRepo struct {
+	db *gorm.DB
+}
+
+func NewTrashRepo(db *gorm.DB) TrashRepo {
+	return &trashRepo{db: db}
+}
+
+func (r *trashRepo) List(ctx context.Context, userID uuid.UUID, types []string, limit, offset int) ([]TrashItem, int64, error) {
+	parts := make([]string, 0, len(types))
+	for _, t := range types {
+		parts = append(parts, trashQueries[t])
+	}
+	union := strings.Join(parts, "\nUNION ALL\n")
+	params := map[string]interface{}{"user": userID, "limit": limit, "offset": offset}
+
+	var total int64
+	if err := r.db.WithContext(ctx).Raw("SELECT COUNT(*) FROM ("+union+") trash", params).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var items []TrashItem
+	err := r.db.WithContext(ctx).
+		Raw(union+"\nORDER BY deleted_at DESC, id LIMIT @limit OFFSET @offset", params).
+		Scan(&items).Error
+	return items, total, err
+}
+
+func (r *trashRepo) FindDeleted(ctx context.Context, id uuid.UUID, dest interface{}) error {
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		First(dest).Error
+	if err == gorm.ErrRecordNotFound {
+		return constant.ErrNotFound
+	}
+	return err
+}
+
+func (r *trashRepo) Restore(ctx context.Context, dest interface{}, updates map[string]interface{}) error {
+	fields := map[string]interface{}{"deleted_at": nil}
+	for k, v := range updates {
+		fields[k] = v
+	}
+	result := r.db.WithContext(ctx).
+		Unscoped().
+		Model(dest).
+		Where("deleted_at IS NOT NULL").
+		Updates(fields)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return constant.ErrNotFound
+	}
+	return nil
+}
+
+func (r *trashRepo) Purge(ctx context.Context, before time.Time) (map[string]int64, []string, error) {
+	counts := make(map[string]int64, len(trashPurgeSteps))
+	var keys []string
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		params := map[string]interface{}{"before": before}
+		err := tx.Raw(`SELECT storage_key FROM attachments
+			WHERE transaction_id IN (SELECT id FROM transactions WHERE deleted_at < @before)
+			OR cost_id IN (SELECT id FROM costs WHERE deleted_at < @before)`, params).
+			Scan(&keys).Error
+		if err != nil {
+			return err
+		}
+
+		for _, step := range trashPurgeSteps {
+			result := tx.Exec(step.query, params)
+			if result.Error != nil {
+				return result.Error
+			}
+			counts[step.table] = result.RowsAffected
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return counts, keys, nil
+}
@@ -13,6 +13,9 @@ type UserRepo interface {
 	BaseRepo[model.User]
 	FindByEmail(ctx context.Context, email string) (*model.User, error)
 	FindByUsername(ctx context.Context, username string) (*model.User, error)
+	// CreateWithCategories creates a user together with its first categories,
+	// or neither of them
+	CreateWithCategories(ctx context.Context, user *model.User, categories []model.Category) error
 }
 
 type userRepo struct {
@@ -50,6 +53,22 @@ func (r *userRepo) Create(ctx context.Context, user *model.User) error {
 	return r.db.WithContext(ctx).Create(user).Error
 }
 
+// CreateWithCategories creates a user and its categories in one transaction
+func (r *userRepo) CreateWithCategories(ctx context.Context, user *model.User, categories []model.Category) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		if len(categories) == 0 {
+			return nil
+		}
+		for i := range categories {
+			categories[i].UserID = user.ID
+		}
+		return tx.Create(&categories).Error
+	})
+}
+
 func NewUserRepo(db *gorm.DB) UserRepo {
 	return &userRepo{
 		GormBaseRepo: NewGormBaseRepo[model.User, uuid.UUID](db),
@@ -0,0 +1,31 @@
+package router
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/tyha2404/nexo-app-api/internal/handler"
+	"github.com/tyha2404/nexo-app-api/internal/middleware"
+	"go.uber.org/zap"
+)
+
+type AccountRouter struct {
+	handler *handler.AccountHandler
+	logger  *zap.Logger
+}
+
+// NewAccountRouter creates a new instance of AccountRouter
+func NewAccountRouter(handler *handler.AccountHandler, logger *zap.Logger) *AccountRouter {
+	return &AccountRouter{
+		handler: handler,
+		logger:  logger,
+	}
+}
+
+// RegisterRoutes registers all routes of the current user's account
+func (r *AccountRouter) RegisterRoutes(router chi.Router) {
+	router.Route("/account", func(accountRoute chi.Router) {
+		accountRoute.Use(middleware.AuthMiddleware)
+		accountRoute.Post("/deletion", r.handler.RequestDeletion)
+		accountRoute.Get("/deletion", r.handler.GetDeletion)
+		accountRoute.Delete("/deletion", r.handler.CancelDeletion)
+	})
+}
@@ -0,0 +1,35 @@
+package router
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/tyha2404/nexo-app-api/internal/handler"
+	"github.com/tyha2404/nexo-app-api/internal/middleware"
+	"go.uber.org/zap"
+)
+
+type AttachmentRouter struct {
+	handler *handler.AttachmentHandler
+	logger  *zap.Logger
+}
+
+// NewAttachmentRouter creates a new instance of AttachmentRouter
+func NewAttachmentRouter(handler *handler.AttachmentHandler, logger *zap.Logger) *AttachmentRouter {
+	return &AttachmentRouter{
+		handler: handler,
+		logger:  logger,
+	}
+}
+
+// RegisterRoutes registers all attachment-related routes to the router
+func (r *AttachmentRouter) RegisterRoutes(router chi.Router) {
+	router.Route("/attachments", func(attachmentsRoute chi.Router) {
+		attachmentsRoute.Use(middleware.AuthMiddleware)
+		attachmentsRoute.Post("/", r.handler.Upload)
+		attachmentsRoute.Get("/", r.handler.List)
+		attachmentsRoute.Get("/{id}", r.handler.Get)
+		attachmentsRoute.Delete("/{id}", r.handler.Delete)
+	})
+
+	// Signed download URLs carry their own authorization
+	router.Get("/files/*", r.handler.Download)
+}
@@ -0,0 +1,30 @@
+package router
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/tyha2404/nexo-app-api/internal/handler"
+	"github.com/tyha2404/nexo-app-api/internal/middleware"
+	"go.uber.org/zap"
+)
+
+type BackupRouter struct {
+	handler *handler.BackupHandler
+	logger  *zap.Logger
+}
+
+// NewBackupRouter creates a new instance of BackupRouter
+func NewBackupRouter(handler *handler.BackupHandler, logger *zap.Logger) *BackupRouter {
+	return &BackupRouter{
+		handler: handler,
+		logger:  logger,
+	}
+}
+
+// RegisterRoutes registers all backup-related routes to the router
+func (r *BackupRouter) RegisterRoutes(router chi.Router) {
+	router.Route("/backup", func(backupRoute chi.Router) {
+		backupRoute.Use(middleware.AuthMiddleware)
+		backupRoute.Get("/", r.handler.Backup)
+		backupRoute.Post("/restore", r.handler.Restore)
+	})
+}
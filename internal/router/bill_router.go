@@ -0,0 +1,38 @@
+package router
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/tyha2404/nexo-app-api/internal/handler"
+	"github.com/tyha2404/nexo-app-api/internal/middleware"
+	"go.uber.org/zap"
+)
+
+type BillRouter struct {
+	handler *handler.BillHandler
+	logger  *zap.Logger
+}
+
+// NewBillRouter creates a new instance of BillRouter
+func NewBillRouter(handler *handler.BillHandler, logger *zap.Logger) *BillRouter {
+	return &BillRouter{
+		handler: handler,
+		logger:  logger,
+	}
+}
+
+// RegisterRoutes registers all bill routes to the router
+func (r *BillRouter) RegisterRoutes(router chi.Router) {
+	router.Route("/bills", func(billsRoute chi.Router) {
+		billsRoute.Use(middleware.AuthMiddleware)
+		billsRoute.Post("/", r.handler.Create)
+		billsRoute.Get("/", r.handler.List)
+		billsRoute.Get("/upcoming", r.handler.Upcoming)
+		billsRoute.Get("/{id}", r.handler.Get)
+		billsRoute.Put("/{id}", r.handler.Update)
+		billsRoute.Delete("/{id}", r.handler.Delete)
+		billsRoute.Post("/{id}/payments", r.handler.Pay)
+		billsRoute.Get("/{id}/payments", r.handler.ListPayments)
+		billsRoute.Delete("/{id}/payments/{paymentId}", r.handler.DeletePayment)
+		billsRoute.Get("/{id}/alerts", r.handler.ListAlerts)
+	})
+}
@@ -0,0 +1,34 @@
+package router
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/tyha2404/nexo-app-api/internal/handler"
+	"github.com/tyha2404/nexo-app-api/internal/middleware"
+	"go.uber.org/zap"
+)
+
+type CategorizationRuleRouter struct {
+	handler *handler.CategorizationRuleHandler
+	logger  *zap.Logger
+}
+
+// NewCategorizationRuleRouter creates a new instance of CategorizationRuleRouter
+func NewCategorizationRuleRouter(handler *handler.CategorizationRuleHandler, logger *zap.Logger) *CategorizationRuleRouter {
+	return &CategorizationRuleRouter{
+		handler: handler,
+		logger:  logger,
+	}
+}
+
+// RegisterRoutes registers all categorization rule routes to the router
+func (r *CategorizationRuleRouter) RegisterRoutes(router chi.Router) {
+	router.Route("/categorization-rules", func(rulesRoute chi.Router) {
+		rulesRoute.Use(middleware.AuthMiddleware)
+		rulesRoute.Post("/", r.handler.Create)
+		rulesRoute.Get("/", r.handler.List)
+		rulesRoute.Post("/apply", r.handler.Apply)
+		rulesRoute.Get("/{id}", r.handler.Get)
+		rulesRoute.Put("/{id}", r.handler.Update)
+		rulesRoute.Delete("/{id}", r.handler.Delete)
+	})
+}
@@ -27,8 +27,12 @@ func (r *CategoryRouter) RegisterRoutes(router chi.Router) {
 		categoriesRoute.Use(middleware.AuthMiddleware)
 		categoriesRoute.Post("/", r.handler.Create)
 		categoriesRoute.Get("/", r.handler.List)
+		categoriesRoute.Get("/tree", r.handler.Tree)
 		categoriesRoute.Get("/{id}", r.handler.Get)
 		categoriesRoute.Put("/{id}", r.handler.Update)
 		categoriesRoute.Delete("/{id}", r.handler.Delete)
+		categoriesRoute.Post("/{id}/merge-into/{target}", r.handler.MergeInto)
+		categoriesRoute.Post("/{id}/archive", r.handler.Archive)
+		categoriesRoute.Post("/{id}/unarchive", r.handler.Unarchive)
 	})
 }
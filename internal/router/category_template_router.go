@@ -0,0 +1,33 @@
+package router
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/tyha2404/nexo-app-api/internal/handler"
+	"github.com/tyha2404/nexo-app-api/internal/middleware"
+	"go.uber.org/zap"
+)
+
+type CategoryTemplateRouter struct {
+	handler *handler.CategoryTemplateHandler
+	logger  *zap.Logger
+}
+
+// NewCategoryTemplateRouter creates a new instance of CategoryTemplateRouter
+func NewCategoryTemplateRouter(handler *handler.CategoryTemplateHandler, logger *zap.Logger) *CategoryTemplateRouter {
+	return &CategoryTemplateRouter{
+		handler: handler,
+		logger:  logger,
+	}
+}
+
+// RegisterRoutes registers the admin routes of category templates
+func (r *CategoryTemplateRouter) RegisterRoutes(router chi.Router) {
+	router.Route("/admin/category-templates", func(templatesRoute chi.Router) {
+		templatesRoute.Use(middleware.AuthMiddleware)
+		templatesRoute.Use(middleware.AdminOnly)
+		templatesRoute.Post("/", r.handler.Create)
+		templatesRoute.Get("/", r.handler.List)
+		templatesRoute.Put("/{id}", r.handler.Update)
+		templatesRoute.Delete("/{id}", r.handler.Delete)
+	})
+}
@@ -0,0 +1,33 @@
+package router
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/tyha2404/nexo-app-api/internal/handler"
+	"github.com/tyha2404/nexo-app-api/internal/middleware"
+	"go.uber.org/zap"
+)
+
+type ExportRouter struct {
+	handler *handler.ExportHandler
+	logger  *zap.Logger
+}
+
+// NewExportRouter creates a new instance of ExportRouter
+func NewExportRouter(handler *handler.ExportHandler, logger *zap.Logger) *ExportRouter {
+	return &ExportRouter{
+		handler: handler,
+		logger:  logger,
+	}
+}
+
+// RegisterRoutes registers all export-related routes to the router
+func (r *ExportRouter) RegisterRoutes(router chi.Router) {
+	router.Route("/exports", func(exportsRoute chi.Router) {
+		exportsRoute.Use(middleware.AuthMiddleware)
+		exportsRoute.Get("/", r.handler.Export)
+		exportsRoute.Get("/jobs", r.handler.ListJobs)
+		exportsRoute.Get("/jobs/{id}", r.handler.GetJob)
+		exportsRoute.Get("/jobs/{id}/download", r.handler.DownloadJob)
+		exportsRoute.Delete("/jobs/{id}", r.handler.DeleteJob)
+	})
+}
@@ -0,0 +1,37 @@
+package router
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/tyha2404/nexo-app-api/internal/handler"
+	"github.com/tyha2404/nexo-app-api/internal/middleware"
+	"go.uber.org/zap"
+)
+
+type GoalRouter struct {
+	handler *handler.GoalHandler
+	logger  *zap.Logger
+}
+
+// NewGoalRouter creates a new instance of GoalRouter
+func NewGoalRouter(handler *handler.GoalHandler, logger *zap.Logger) *GoalRouter {
+	return &GoalRouter{
+		handler: handler,
+		logger:  logger,
+	}
+}
+
+// RegisterRoutes registers all savings goal routes to the router
+func (r *GoalRouter) RegisterRoutes(router chi.Router) {
+	router.Route("/goals", func(goalsRoute chi.Router) {
+		goalsRoute.Use(middleware.AuthMiddleware)
+		goalsRoute.Post("/", r.handler.Create)
+		goalsRoute.Get("/", r.handler.List)
+		goalsRoute.Get("/{id}", r.handler.Get)
+		goalsRoute.Put("/{id}", r.handler.Update)
+		goalsRoute.Delete("/{id}", r.handler.Delete)
+		goalsRoute.Post("/{id}/contributions", r.handler.AddContribution)
+		goalsRoute.Get("/{id}/contributions", r.handler.ListContributions)
+		goalsRoute.Delete("/{id}/contributions/{contributionId}", r.handler.DeleteContribution)
+		goalsRoute.Get("/{id}/alerts", r.handler.ListAlerts)
+	})
+}
@@ -0,0 +1,32 @@
+package router
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/tyha2404/nexo-app-api/internal/handler"
+	"github.com/tyha2404/nexo-app-api/internal/middleware"
+	"go.uber.org/zap"
+)
+
+type ImportRouter struct {
+	handler *handler.ImportHandler
+	logger  *zap.Logger
+}
+
+// NewImportRouter creates a new instance of ImportRouter
+func NewImportRouter(handler *handler.ImportHandler, logger *zap.Logger) *ImportRouter {
+	return &ImportRouter{
+		handler: handler,
+		logger:  logger,
+	}
+}
+
+// RegisterRoutes registers all import-related routes to the router
+func (r *ImportRouter) RegisterRoutes(router chi.Router) {
+	router.Route("/imports", func(importsRoute chi.Router) {
+		importsRoute.Use(middleware.AuthMiddleware)
+		importsRoute.Post("/csv", r.handler.ImportCSV)
+		importsRoute.Get("/profiles", r.handler.ListProfiles)
+		importsRoute.Delete("/profiles/{id}", r.handler.DeleteProfile)
+		importsRoute.Post("/{format}", r.handler.ImportStatement)
+	})
+}
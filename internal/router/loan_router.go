@@ -0,0 +1,37 @@
+package router
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/tyha2404/nexo-app-api/internal/handler"
+	"github.com/tyha2404/nexo-app-api/internal/middleware"
+	"go.uber.org/zap"
+)
+
+type LoanRouter struct {
+	handler *handler.LoanHandler
+	logger  *zap.Logger
+}
+
+// NewLoanRouter creates a new instance of LoanRouter
+func NewLoanRouter(handler *handler.LoanHandler, logger *zap.Logger) *LoanRouter {
+	return &LoanRouter{
+		handler: handler,
+		logger:  logger,
+	}
+}
+
+// RegisterRoutes registers all loan routes to the router
+func (r *LoanRouter) RegisterRoutes(router chi.Router) {
+	router.Route("/loans", func(loansRoute chi.Router) {
+		loansRoute.Use(middleware.AuthMiddleware)
+		loansRoute.Post("/", r.handler.Create)
+		loansRoute.Get("/", r.handler.List)
+		loansRoute.Get("/{id}", r.handler.Get)
+		loansRoute.Put("/{id}", r.handler.Update)
+		loansRoute.Delete("/{id}", r.handler.Delete)
+		loansRoute.Get("/{id}/schedule", r.handler.Schedule)
+		loansRoute.Post("/{id}/payments", r.handler.AddPayment)
+		loansRoute.Get("/{id}/payments", r.handler.ListPayments)
+		loansRoute.Delete("/{id}/payments/{paymentId}", r.handler.DeletePayment)
+	})
+}
@@ -0,0 +1,36 @@
+package router
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/tyha2404/nexo-app-api/internal/handler"
+	"github.com/tyha2404/nexo-app-api/internal/middleware"
+	"go.uber.org/zap"
+)
+
+type NetWorthRouter struct {
+	handler *handler.NetWorthHandler
+	logger  *zap.Logger
+}
+
+// NewNetWorthRouter creates a new instance of NetWorthRouter
+func NewNetWorthRouter(handler *handler.NetWorthHandler, logger *zap.Logger) *NetWorthRouter {
+	return &NetWorthRouter{
+		handler: handler,
+		logger:  logger,
+	}
+}
+
+// RegisterRoutes registers all asset and liability routes to the router
+func (r *NetWorthRouter) RegisterRoutes(router chi.Router) {
+	router.Route("/net-worth/items", func(itemsRoute chi.Router) {
+		itemsRoute.Use(middleware.AuthMiddleware)
+		itemsRoute.Post("/", r.handler.CreateItem)
+		itemsRoute.Get("/", r.handler.ListItems)
+		itemsRoute.Get("/{id}", r.handler.GetItem)
+		itemsRoute.Put("/{id}", r.handler.UpdateItem)
+		itemsRoute.Delete("/{id}", r.handler.DeleteItem)
+		itemsRoute.Post("/{id}/valuations", r.handler.AddValuation)
+		itemsRoute.Get("/{id}/valuations", r.handler.ListValuations)
+		itemsRoute.Delete("/{id}/valuations/{valuationId}", r.handler.DeleteValuation)
+	})
+}
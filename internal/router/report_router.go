@@ -0,0 +1,34 @@
+package router
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/tyha2404/nexo-app-api/internal/handler"
+	"github.com/tyha2404/nexo-app-api/internal/middleware"
+	"go.uber.org/zap"
+)
+
+type ReportRouter struct {
+	handler *handler.ReportHandler
+	logger  *zap.Logger
+}
+
+// NewReportRouter creates a new instance of ReportRouter
+func NewReportRouter(handler *handler.ReportHandler, logger *zap.Logger) *ReportRouter {
+	return &ReportRouter{
+		handler: handler,
+		logger:  logger,
+	}
+}
+
+// RegisterRoutes registers all report-related routes to the router
+func (r *ReportRouter) RegisterRoutes(router chi.Router) {
+	router.Route("/reports", func(reportsRoute chi.Router) {
+		reportsRoute.Use(middleware.AuthMiddleware)
+		reportsRoute.Get("/tags", r.handler.TagTotals)
+		reportsRoute.Get("/summary", r.handler.Summary)
+		reportsRoute.Get("/categories", r.handler.Categories)
+		reportsRoute.Get("/forecast", r.handler.Forecast)
+		reportsRoute.Get("/net-worth", r.handler.NetWorth)
+		reportsRoute.Get("/budgets", r.handler.Budgets)
+	})
+}
@@ -2,16 +2,19 @@ package router
 
 import (
 	"github.com/go-chi/chi/v5"
+	"github.com/tyha2404/nexo-app-api/internal/config"
 	"github.com/tyha2404/nexo-app-api/internal/handler"
 	"github.com/tyha2404/nexo-app-api/internal/middleware"
+	"github.com/tyha2404/nexo-app-api/internal/notify"
 	"github.com/tyha2404/nexo-app-api/internal/repository"
 	"github.com/tyha2404/nexo-app-api/internal/service"
+	"github.com/tyha2404/nexo-app-api/internal/storage"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
 // New creates a new router with all routes configured
-func New(db *gorm.DB, logger *zap.Logger) *chi.Mux {
+func New(cfg *config.Config, db *gorm.DB, store storage.BlobStore, logger *zap.Logger) *chi.Mux {
 	r := chi.NewRouter()
 
 	// Add logging middleware to log query strings
@@ -22,21 +25,71 @@ func New(db *gorm.DB, logger *zap.Logger) *chi.Mux {
 	categoryRepo := repository.NewCategoryRepo(db)
 	costRepo := repository.NewCostRepo(db)
 	transactionRepo := repository.NewTransactionRepository(db)
+	tagRepo := repository.NewTagRepo(db)
+	reportRepo := repository.NewReportRepo(db)
+	attachmentRepo := repository.NewAttachmentRepo(db)
+	importProfileRepo := repository.NewImportProfileRepo(db)
+	exportRepo := repository.NewExportRepo(db)
+	backupRepo := repository.NewBackupRepo(db)
+	accountDeletionRepo := repository.NewAccountDeletionRepo(db)
+	trashRepo := repository.NewTrashRepo(db)
+	netWorthRepo := repository.NewNetWorthRepo(db)
+	ruleRepo := repository.NewCategorizationRuleRepo(db)
+	searchRepo := repository.NewSearchRepo(db)
+	templateRepo := repository.NewCategoryTemplateRepo(db)
+	goalRepo := repository.NewGoalRepo(db)
+	loanRepo := repository.NewLoanRepo(db)
+	billRepo := repository.NewBillRepo(db)
+	subscriptionRepo := repository.NewSubscriptionRepo(db)
 
 	// Initialize services
-	authService := service.NewAuthService(userRepo)
+	categorySuggester := service.NewCategorySuggester(transactionRepo)
+	authService := service.NewAuthService(userRepo, templateRepo)
 	userService := service.NewUserService(userRepo)
-	categoryService := service.NewCategoryService(categoryRepo)
-	costService := service.NewCostService(costRepo)
-	transactionService := service.NewTransactionService(transactionRepo, categoryRepo)
+	accountDeletionService := service.NewAccountDeletionService(userRepo, accountDeletionRepo, store, cfg.AccountDeletionGracePeriod())
+	categoryService := service.NewCategoryService(categoryRepo, categorySuggester)
+	costService := service.NewCostService(costRepo, tagRepo)
+	transactionService := service.NewTransactionService(transactionRepo, categoryRepo, tagRepo, ruleRepo, categorySuggester)
+	tagService := service.NewTagService(tagRepo)
+	reportService := service.NewReportService(reportRepo, transactionRepo, categoryRepo, netWorthRepo)
+	attachmentService := service.NewAttachmentService(attachmentRepo, transactionRepo, costRepo, store, cfg.AttachmentMaxBytes)
+	importService := service.NewImportService(transactionRepo, categoryRepo, importProfileRepo, ruleRepo, categorySuggester)
+	exportService := service.NewExportService(exportRepo, categoryRepo, store, cfg.ExportAsyncThreshold)
+	backupService := service.NewBackupService(userRepo, categoryRepo, tagRepo, transactionRepo, importProfileRepo, exportRepo, backupRepo)
+	trashService := service.NewTrashService(trashRepo, categoryRepo, categorySuggester, store, cfg.TrashRetention())
+	netWorthService := service.NewNetWorthService(netWorthRepo)
+	ruleService := service.NewCategorizationRuleService(ruleRepo, categoryRepo, tagRepo, categorySuggester)
+	searchService := service.NewSearchService(searchRepo)
+	templateService := service.NewCategoryTemplateService(templateRepo)
+	goalService := service.NewGoalService(goalRepo, categoryRepo, tagRepo, netWorthRepo)
+	loanService := service.NewLoanService(loanRepo, categoryRepo)
+	billService := service.NewBillService(billRepo, categoryRepo, transactionRepo, notify.NewLogNotifier(logger), logger)
+	subscriptionService := service.NewSubscriptionService(subscriptionRepo, transactionRepo, categoryRepo)
 
 	// Initialize handlers
 	healthHandler := handler.NewHealthHandler(db, logger)
 	authHandler := handler.NewAuthHandler(authService, logger)
-	userHandler := handler.NewUserHandler(userService, logger)
+	userHandler := handler.NewUserHandler(userService, accountDeletionService, logger)
 	categoryHandler := handler.NewCategoryHandler(categoryService, logger)
 	costHandler := handler.NewCostHandler(costService, logger)
 	transactionHandler := handler.NewTransactionHandler(transactionService, logger)
+	tagHandler := handler.NewTagHandler(tagService, logger)
+	reportHandler := handler.NewReportHandler(reportService, logger)
+	urlVerifier, _ := store.(storage.URLVerifier)
+	attachmentHandler := handler.NewAttachmentHandler(attachmentService, urlVerifier, cfg.AttachmentMaxBytes, logger)
+	importHandler := handler.NewImportHandler(importService, logger)
+	exportHandler := handler.NewExportHandler(exportService, logger)
+	backupHandler := handler.NewBackupHandler(backupService, logger)
+	accountHandler := handler.NewAccountHandler(accountDeletionService, logger)
+	trashHandler := handler.NewTrashHandler(trashService, logger)
+	netWorthHandler := handler.NewNetWorthHandler(netWorthService, logger)
+	ruleHandler := handler.NewCategorizationRuleHandler(ruleService, logger)
+	searchHandler := handler.NewSearchHandler(searchService, logger)
+	templateHandler := handler.NewCategoryTemplateHandler(templateService, logger)
+	goalHandler := handler.NewGoalHandler(goalService, logger)
+	loanHandler := handler.NewLoanHandler(loanService, logger)
+	billHandler := handler.NewBillHandler(billService, logger)
+	subscriptionHandler := handler.NewSubscriptionHandler(subscriptionService, logger)
 
 	// Initialize routers
 	healthRouter := NewHealthRouter(healthHandler)
@@ -45,6 +98,22 @@ func New(db *gorm.DB, logger *zap.Logger) *chi.Mux {
 	categoryRouter := NewCategoryRouter(categoryHandler, logger)
 	costRouter := NewCostRouter(costHandler, logger)
 	transactionRouter := NewTransactionRouter(transactionHandler, middleware.AuthMiddleware)
+	tagRouter := NewTagRouter(tagHandler, logger)
+	reportRouter := NewReportRouter(reportHandler, logger)
+	attachmentRouter := NewAttachmentRouter(attachmentHandler, logger)
+	importRouter := NewImportRouter(importHandler, logger)
+	exportRouter := NewExportRouter(exportHandler, logger)
+	backupRouter := NewBackupRouter(backupHandler, logger)
+	accountRouter := NewAccountRouter(accountHandler, logger)
+	trashRouter := NewTrashRouter(trashHandler, logger)
+	netWorthRouter := NewNetWorthRouter(netWorthHandler, logger)
+	ruleRouter := NewCategorizationRuleRouter(ruleHandler, logger)
+	searchRouter := NewSearchRouter(searchHandler, logger)
+	templateRouter := NewCategoryTemplateRouter(templateHandler, logger)
+	goalRouter := NewGoalRouter(goalHandler, logger)
+	loanRouter := NewLoanRouter(loanHandler, logger)
+	billRouter := NewBillRouter(billHandler, logger)
+	subscriptionRouter := NewSubscriptionRouter(subscriptionHandler, logger)
 
 	// Register health check routes (outside API versioning)
 
@@ -56,6 +125,22 @@ func New(db *gorm.DB, logger *zap.Logger) *chi.Mux {
 		categoryRouter.RegisterRoutes(apiRouter)
 		costRouter.RegisterRoutes(apiRouter)
 		transactionRouter.RegisterRoutes(apiRouter)
+		tagRouter.RegisterRoutes(apiRouter)
+		reportRouter.RegisterRoutes(apiRouter)
+		attachmentRouter.RegisterRoutes(apiRouter)
+		importRouter.RegisterRoutes(apiRouter)
+		exportRouter.RegisterRoutes(apiRouter)
+		backupRouter.RegisterRoutes(apiRouter)
+		accountRouter.RegisterRoutes(apiRouter)
+		trashRouter.RegisterRoutes(apiRouter)
+		netWorthRouter.RegisterRoutes(apiRouter)
+		ruleRouter.RegisterRoutes(apiRouter)
+		searchRouter.RegisterRoutes(apiRouter)
+		templateRouter.RegisterRoutes(apiRouter)
+		goalRouter.RegisterRoutes(apiRouter)
+		loanRouter.RegisterRoutes(apiRouter)
+		billRouter.RegisterRoutes(apiRouter)
+		subscriptionRouter.RegisterRoutes(apiRouter)
 	})
 
 	// Register Swagger UI route
@@ -0,0 +1,29 @@
+package router
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/tyha2404/nexo-app-api/internal/handler"
+	"github.com/tyha2404/nexo-app-api/internal/middleware"
+	"go.uber.org/zap"
+)
+
+type SearchRouter struct {
+	handler *handler.SearchHandler
+	logger  *zap.Logger
+}
+
+// NewSearchRouter creates a new instance of SearchRouter
+func NewSearchRouter(handler *handler.SearchHandler, logger *zap.Logger) *SearchRouter {
+	return &SearchRouter{
+		handler: handler,
+		logger:  logger,
+	}
+}
+
+// RegisterRoutes registers the search route to the router
+func (r *SearchRouter) RegisterRoutes(router chi.Router) {
+	router.Route("/search", func(searchRoute chi.Router) {
+		searchRoute.Use(middleware.AuthMiddleware)
+		searchRoute.Get("/", r.handler.Search)
+	})
+}
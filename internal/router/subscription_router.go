@@ -0,0 +1,32 @@
+package router
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/tyha2404/nexo-app-api/internal/handler"
+	"github.com/tyha2404/nexo-app-api/internal/middleware"
+	"go.uber.org/zap"
+)
+
+type SubscriptionRouter struct {
+	handler *handler.SubscriptionHandler
+	logger  *zap.Logger
+}
+
+// NewSubscriptionRouter creates a new instance of SubscriptionRouter
+func NewSubscriptionRouter(handler *handler.SubscriptionHandler, logger *zap.Logger) *SubscriptionRouter {
+	return &SubscriptionRouter{
+		handler: handler,
+		logger:  logger,
+	}
+}
+
+// RegisterRoutes registers all subscription insight routes to the router
+func (r *SubscriptionRouter) RegisterRoutes(router chi.Router) {
+	router.Route("/insights/subscriptions", func(subscriptionsRoute chi.Router) {
+		subscriptionsRoute.Use(middleware.AuthMiddleware)
+		subscriptionsRoute.Get("/", r.handler.List)
+		subscriptionsRoute.Post("/{id}/confirm", r.handler.Confirm)
+		subscriptionsRoute.Post("/{id}/dismiss", r.handler.Dismiss)
+		subscriptionsRoute.Delete("/{id}/dismiss", r.handler.Undismiss)
+	})
+}
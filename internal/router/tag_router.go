@@ -0,0 +1,34 @@
+package router
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/tyha2404/nexo-app-api/internal/handler"
+	"github.com/tyha2404/nexo-app-api/internal/middleware"
+	"go.uber.org/zap"
+)
+
+type TagRouter struct {
+	handler *handler.TagHandler
+	logger  *zap.Logger
+}
+
+// NewTagRouter creates a new instance of TagRouter
+func NewTagRouter(handler *handler.TagHandler, logger *zap.Logger) *TagRouter {
+	return &TagRouter{
+		handler: handler,
+		logger:  logger,
+	}
+}
+
+// RegisterRoutes registers all tag-related routes to the router
+func (r *TagRouter) RegisterRoutes(router chi.Router) {
+	router.Route("/tags", func(tagsRoute chi.Router) {
+		tagsRoute.Use(middleware.AuthMiddleware)
+		tagsRoute.Post("/", r.handler.Create)
+		tagsRoute.Get("/", r.handler.List)
+		tagsRoute.Get("/{id}", r.handler.Get)
+		tagsRoute.Put("/{id}", r.handler.Update)
+		tagsRoute.Post("/{id}/merge", r.handler.Merge)
+		tagsRoute.Delete("/{id}", r.handler.Delete)
+	})
+}
@@ -24,8 +24,11 @@ func (r *TransactionRouter) RegisterRoutes(router chi.Router) {
 		router.Use(r.authMiddleware)
 		router.Post("/", r.handler.CreateTransaction)
 		router.Get("/", r.handler.ListTransactions)
+		router.Post("/suggest-category", r.handler.SuggestCategory)
+		router.Get("/duplicates", r.handler.FindDuplicates)
 		router.Get("/{id}", r.handler.GetTransaction)
 		router.Put("/{id}", r.handler.UpdateTransaction)
 		router.Delete("/{id}", r.handler.DeleteTransaction)
+		router.Post("/{id}/merge", r.handler.MergeTransactions)
 	})
 }
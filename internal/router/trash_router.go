@@ -0,0 +1,30 @@
+package router
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/tyha2404/nexo-app-api/internal/handler"
+	"github.com/tyha2404/nexo-app-api/internal/middleware"
+	"go.uber.org/zap"
+)
+
+type TrashRouter struct {
+	handler *handler.TrashHandler
+	logger  *zap.Logger
+}
+
+// NewTrashRouter creates a new instance of TrashRouter
+func NewTrashRouter(handler *handler.TrashHandler, logger *zap.Logger) *TrashRouter {
+	return &TrashRouter{
+		handler: handler,
+		logger:  logger,
+	}
+}
+
+// RegisterRoutes registers all trash routes
+func (r *TrashRouter) RegisterRoutes(router chi.Router) {
+	router.Route("/trash", func(trashRoute chi.Router) {
+		trashRoute.Use(middleware.AuthMiddleware)
+		trashRoute.Get("/", r.handler.List)
+		trashRoute.Post("/{type}/{id}/restore", r.handler.Restore)
+	})
+}
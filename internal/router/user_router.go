@@ -3,6 +3,7 @@ package router
 import (
 	"github.com/go-chi/chi/v5"
 	"github.com/tyha2404/nexo-app-api/internal/handler"
+	"github.com/tyha2404/nexo-app-api/internal/middleware"
 	"go.uber.org/zap"
 )
 
@@ -27,6 +28,11 @@ func (r *UserRouter) RegisterRoutes(router chi.Router) {
 		usersRoute.Get("/", r.handler.List)
 		usersRoute.Get("/{id}", r.handler.Get)
 		usersRoute.Put("/{id}", r.handler.Update)
-		usersRoute.Delete("/{id}", r.handler.Delete)
+		// Deleting purges the account for good, so only admins may do it
+		usersRoute.Group(func(adminRoute chi.Router) {
+			adminRoute.Use(middleware.AuthMiddleware)
+			adminRoute.Use(middleware.AdminOnly)
+			adminRoute.Delete("/{id}", r.handler.Delete)
+		})
 	})
 }
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/constant"
+	"github.com/tyha2404/nexo-app-api/internal/dto"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"github.com/tyha2404/nexo-app-api/internal/repository"
+	"github.com/tyha2404/nexo-app-api/internal/storage"
+	"gorm.io/gorm"
+)
+
+const (
+	deletionTriggerSelfService = "self_service"
+	deletionTriggerAdmin       = "admin"
+	// accountPurgeBatchSize bounds how many accounts one purge run handles
+	accountPurgeBatchSize = 50
+)
+
+type AccountDeletionService interface {
+	// RequestDeletion schedules the account for deletion once the grace
+	// period has passed. Requesting again keeps the original schedule.
+	RequestDeletion(ctx context.Context, userID uuid.UUID, req dto.AccountDeletionRequest) (*dto.AccountDeletionStatusResponse, error)
+	GetStatus(ctx context.Context, userID uuid.UUID) (*dto.AccountDeletionStatusResponse, error)
+	CancelDeletion(ctx context.Context, userID uuid.UUID) error
+	// PurgeNow deletes the account and all of its data immediately
+	PurgeNow(ctx context.Context, userID uuid.UUID) error
+	// PurgeDue deletes every account whose grace period has ended and returns
+	// how many were purged
+	PurgeDue(ctx context.Context) (int, error)
+}
+
+type accountDeletionService struct {
+	userRepo     repository.UserRepo
+	deletionRepo repository.AccountDeletionRepo
+	store        storage.BlobStore
+	gracePeriod  time.Duration
+}
+
+func NewAccountDeletionService(
+	userRepo repository.UserRepo,
+	deletionRepo repository.AccountDeletionRepo,
+	store storage.BlobStore,
+	gracePeriod time.Duration,
+) AccountDeletionService {
+	return &accountDeletionService{
+		userRepo:     userRepo,
+		deletionRepo: deletionRepo,
+		store:        store,
+		gracePeriod:  gracePeriod,
+	}
+}
+
+func (s *accountDeletionService) RequestDeletion(ctx context.Context, userID uuid.UUID, req dto.AccountDeletionRequest) (*dto.AccountDeletionStatusResponse, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := user.CheckPassword(req.Password); err != nil {
+		return nil, constant.ErrInvalidCredentials
+	}
+
+	if user.DeletionScheduledAt == nil {
+		now := time.Now()
+		scheduled := now.Add(s.gracePeriod)
+		err := s.userRepo.UpdateFields(ctx, userID, map[string]interface{}{
+			"deletion_requested_at": now,
+			"deletion_scheduled_at": scheduled,
+		})
+		if err != nil {
+			return nil, err
+		}
+		user.DeletionRequestedAt = &now
+		user.DeletionScheduledAt = &scheduled
+	}
+
+	return toAccountDeletionStatus(user), nil
+}
+
+func (s *accountDeletionService) GetStatus(ctx context.Context, userID uuid.UUID) (*dto.AccountDeletionStatusResponse, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toAccountDeletionStatus(user), nil
+}
+
+func (s *accountDeletionService) CancelDeletion(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.DeletionScheduledAt == nil {
+		return constant.ErrNotFound
+	}
+	return s.userRepo.UpdateFields(ctx, userID, map[string]interface{}{
+		"deletion_requested_at": nil,
+		"deletion_scheduled_at": nil,
+	})
+}
+
+func (s *accountDeletionService) PurgeNow(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	// The account is gone once the rows are; a blob that failed to delete is
+	// unreachable and does not make the request fail
+	_, err = s.purge(ctx, user, nil, deletionTriggerAdmin)
+	return err
+}
+
+func (s *accountDeletionService) PurgeDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	users, err := s.deletionRepo.ListDue(ctx, now, accountPurgeBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	var errs []error
+	for i := range users {
+		blobErr, err := s.purge(ctx, &users[i], &now, deletionTriggerSelfService)
+		switch {
+		case err == nil:
+			purged++
+			if blobErr != nil {
+				errs = append(errs, fmt.Errorf("purge account %s: %w", users[i].ID, blobErr))
+			}
+		case errors.Is(err, constant.ErrNotFound):
+			// Cancelled after it was listed
+		default:
+			errs = append(errs, fmt.Errorf("purge account %s: %w", users[i].ID, err))
+		}
+	}
+	return purged, errors.Join(errs...)
+}
+
+// purge removes the account's rows, then its blobs. Blobs cannot take part in
+// the database transaction, so failures to delete them are returned
+// separately as blobErr and do not undo the purge.
+func (s *accountDeletionService) purge(ctx context.Context, user *model.User, dueBefore *time.Time, trigger string) (blobErr error, err error) {
+	record := &model.AccountDeletionRecord{
+		UserID:       user.ID,
+		EmailHash:    hashEmail(user.Email),
+		Trigger:      trigger,
+		RequestedAt:  user.DeletionRequestedAt,
+		ScheduledFor: user.DeletionScheduledAt,
+		PurgedAt:     time.Now(),
+	}
+	keys, err := s.deletionRepo.Purge(ctx, user.ID, dueBefore, record)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for _, key := range keys {
+		if err := s.store.Delete(ctx, key); err != nil {
+			errs = append(errs, fmt.Errorf("delete blob %s: %w", key, err))
+		}
+	}
+	return errors.Join(errs...), nil
+}
+
+func (s *accountDeletionService) getUser(ctx context.Context, userID uuid.UUID) (*model.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, constant.ErrNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+func toAccountDeletionStatus(user *model.User) *dto.AccountDeletionStatusResponse {
+	resp := &dto.AccountDeletionStatusResponse{Scheduled: user.DeletionScheduledAt != nil}
+	if user.DeletionRequestedAt != nil {
+		requested := user.DeletionRequestedAt.UTC().Format(time.RFC3339)
+		resp.RequestedAt = &requested
+	}
+	if user.DeletionScheduledAt != nil {
+		scheduled := user.DeletionScheduledAt.UTC().Format(time.RFC3339)
+		resp.ScheduledFor = &scheduled
+	}
+	return resp
+}
+
+func hashEmail(email string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"math"
+	"time"
+
+	"github.com/tyha2404/nexo-app-api/internal/dto"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+)
+
+// Loan balance statuses
+const (
+	loanStatusActive  = "active"
+	loanStatusPaidOff = "paid_off"
+)
+
+func compoundingsPerYear(c model.LoanCompounding) float64 {
+	switch c {
+	case model.LoanCompoundingDaily:
+		return 365
+	case model.LoanCompoundingAnnually:
+		return 1
+	default:
+		return 12
+	}
+}
+
+// loanMonthlyRate is the effective interest rate of one month
+func loanMonthlyRate(loan *model.Loan) float64 {
+	n := compoundingsPerYear(loan.Compounding)
+	return math.Pow(1+loan.InterestRate/100/n, n/12) - 1
+}
+
+// loanInterest is the interest accrued on balance from one date to another.
+// Whole calendar months are counted as a twelfth of a year and the days left
+// over as 1/365 each, so a payment made on its due date matches the schedule.
+func loanInterest(loan *model.Loan, balance float64, from, to time.Time) float64 {
+	if balance <= 0 || loan.InterestRate == 0 || !to.After(from) {
+		return 0
+	}
+	months := 0
+	for !addMonthsClamped(from, months+1).After(to) {
+		months++
+	}
+	days := to.Sub(addMonthsClamped(from, months)).Hours() / 24
+	years := float64(months)/12 + days/365
+
+	n := compoundingsPerYear(loan.Compounding)
+	return balance * (math.Pow(1+loan.InterestRate/100/n, n*years) - 1)
+}
+
+// loanMonthlyPayment is the fixed instalment that repays the loan over its
+// term
+func loanMonthlyPayment(loan *model.Loan) float64 {
+	rate := loanMonthlyRate(loan)
+	if rate == 0 {
+		return roundCents(loan.Principal / float64(loan.TermMonths))
+	}
+	return roundCents(loan.Principal * rate / (1 - math.Pow(1+rate, -float64(loan.TermMonths))))
+}
+
+// amortizationSchedule splits the instalments of a loan into principal and
+// interest. The last instalment settles whatever rounding left over.
+func amortizationSchedule(loan *model.Loan) dto.LoanScheduleResponse {
+	rate := loanMonthlyRate(loan)
+	payment := loanMonthlyPayment(loan)
+	schedule := dto.LoanScheduleResponse{
+		MonthlyPayment: payment,
+		Instalments:    make([]dto.LoanInstalmentResponse, 0, loan.TermMonths),
+	}
+
+	balance := loan.Principal
+	for number := 1; number <= loan.TermMonths && balance > 0; number++ {
+		interest := roundCents(balance * rate)
+		principal := roundCents(payment - interest)
+		if number == loan.TermMonths || principal > balance {
+			principal = balance
+		}
+		balance = roundCents(balance - principal)
+
+		instalment := dto.LoanInstalmentResponse{
+			Number:    number,
+			DueDate:   addMonthsClamped(loan.StartDate, number).Format("2006-01-02"),
+			Payment:   roundCents(principal + interest),
+			Principal: principal,
+			Interest:  interest,
+			Balance:   balance,
+		}
+		schedule.Instalments = append(schedule.Instalments, instalment)
+		schedule.TotalPayment += instalment.Payment
+		schedule.TotalInterest += instalment.Interest
+	}
+	schedule.TotalPayment = roundCents(schedule.TotalPayment)
+	schedule.TotalInterest = roundCents(schedule.TotalInterest)
+	return schedule
+}
+
+// replayLoan applies the payments of a loan in date order. Each payment first
+// covers the interest accrued since the previous one, then the principal;
+// whatever exceeds the remaining principal is ignored. It returns the split
+// of each payment and the balance as of today.
+func replayLoan(loan *model.Loan, payments []model.LoanPayment, today time.Time) ([]dto.LoanPaymentResponse, dto.LoanBalanceResponse) {
+	principal := loan.Principal
+	unpaidInterest := 0.0
+	last := loan.StartDate
+
+	var balance dto.LoanBalanceResponse
+	responses := make([]dto.LoanPaymentResponse, 0, len(payments))
+	for _, p := range payments {
+		t := p.Transaction
+		if t.TransactionDate.After(last) {
+			unpaidInterest = roundCents(unpaidInterest + loanInterest(loan, principal, last, t.TransactionDate))
+			last = t.TransactionDate
+		}
+
+		interest := roundCents(math.Min(t.Amount, unpaidInterest))
+		unpaidInterest = roundCents(unpaidInterest - interest)
+		principalPart := roundCents(math.Min(t.Amount-interest, principal))
+		principal = roundCents(principal - principalPart)
+
+		balance.PaymentsMade++
+		balance.InterestPaid += interest
+		balance.PrincipalPaid += principalPart
+		responses = append(responses, dto.LoanPaymentResponse{
+			ID:            p.ID.String(),
+			TransactionID: t.ID.String(),
+			Amount:        t.Amount,
+			PaidOn:        t.TransactionDate.Format("2006-01-02"),
+			Description:   t.Description,
+			Principal:     principalPart,
+			Interest:      interest,
+			Balance:       principal,
+		})
+	}
+
+	accrued := unpaidInterest
+	if today.After(last) {
+		accrued += loanInterest(loan, principal, last, today)
+	}
+	balance.PrincipalPaid = roundCents(balance.PrincipalPaid)
+	balance.InterestPaid = roundCents(balance.InterestPaid)
+	balance.RemainingPrincipal = principal
+	balance.AccruedInterest = roundCents(accrued)
+	balance.RemainingBalance = roundCents(principal + balance.AccruedInterest)
+	balance.Status = loanStatusActive
+	if principal == 0 && unpaidInterest == 0 {
+		balance.Status = loanStatusPaidOff
+	}
+	return responses, balance
+}
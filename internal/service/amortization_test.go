@@ -0,0 +1,176 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+)
+
+func testLoan(t *testing.T, principal, rate float64, months int, compounding model.LoanCompounding) *model.Loan {
+	t.Helper()
+	return &model.Loan{
+		Principal:    principal,
+		InterestRate: rate,
+		TermMonths:   months,
+		Compounding:  compounding,
+		StartDate:    mustDate(t, "2024-01-15"),
+	}
+}
+
+func testLoanPayment(t *testing.T, amount float64, date string) model.LoanPayment {
+	t.Helper()
+	tx := testTransaction(t, model.TransactionTypeExpense, amount, date, "")
+	return model.LoanPayment{ID: uuid.New(), TransactionID: tx.ID, Transaction: &tx}
+}
+
+func TestLoanMonthlyPayment(t *testing.T) {
+	tests := []struct {
+		name        string
+		principal   float64
+		rate        float64
+		months      int
+		compounding model.LoanCompounding
+		want        float64
+	}{
+		{"monthly compounding", 12000, 6.5, 48, model.LoanCompoundingMonthly, 284.58},
+		{"interest free", 1200, 0, 12, model.LoanCompoundingMonthly, 100},
+		{"interest free uneven", 1000, 0, 3, model.LoanCompoundingMonthly, 333.33},
+		{"annual compounding", 12000, 12, 12, model.LoanCompoundingAnnually, 1062.74},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loan := testLoan(t, tt.principal, tt.rate, tt.months, tt.compounding)
+			if got := loanMonthlyPayment(loan); got != tt.want {
+				t.Errorf("loanMonthlyPayment = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoanMonthlyRate(t *testing.T) {
+	tests := []struct {
+		compounding model.LoanCompounding
+		want        float64
+	}{
+		{model.LoanCompoundingMonthly, 0.01},
+		{model.LoanCompoundingAnnually, 0.009489},
+		{model.LoanCompoundingDaily, 0.010049},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.compounding), func(t *testing.T) {
+			loan := testLoan(t, 1000, 12, 12, tt.compounding)
+			if got := loanMonthlyRate(loan); got < tt.want-0.000001 || got > tt.want+0.000001 {
+				t.Errorf("loanMonthlyRate = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAmortizationSchedule(t *testing.T) {
+	loan := testLoan(t, 12000, 6.5, 48, model.LoanCompoundingMonthly)
+	schedule := amortizationSchedule(loan)
+
+	if len(schedule.Instalments) != 48 {
+		t.Fatalf("got %d instalments, want 48", len(schedule.Instalments))
+	}
+	first := schedule.Instalments[0]
+	if first.DueDate != "2024-02-15" || first.Payment != 284.58 || first.Principal != 219.58 || first.Interest != 65 || first.Balance != 11780.42 {
+		t.Errorf("first instalment = %+v", first)
+	}
+	last := schedule.Instalments[47]
+	if last.DueDate != "2028-01-15" || last.Balance != 0 {
+		t.Errorf("last instalment = %+v, want due 2028-01-15 with nothing left", last)
+	}
+	if schedule.MonthlyPayment != 284.58 || schedule.TotalPayment != 13659.81 || schedule.TotalInterest != 1659.81 {
+		t.Errorf("totals = %v/%v/%v, want 284.58/13659.81/1659.81", schedule.MonthlyPayment, schedule.TotalPayment, schedule.TotalInterest)
+	}
+
+	var principal float64
+	for _, i := range schedule.Instalments {
+		principal += i.Principal
+	}
+	if roundCents(principal) != loan.Principal {
+		t.Errorf("instalments repay %v, want %v", roundCents(principal), loan.Principal)
+	}
+
+	t.Run("due dates clamp to the month end", func(t *testing.T) {
+		loan := testLoan(t, 300, 0, 3, model.LoanCompoundingMonthly)
+		loan.StartDate = mustDate(t, "2024-01-31")
+		var got []string
+		for _, i := range amortizationSchedule(loan).Instalments {
+			got = append(got, i.DueDate)
+		}
+		want := []string{"2024-02-29", "2024-03-31", "2024-04-30"}
+		for i := range want {
+			if i >= len(got) || got[i] != want[i] {
+				t.Fatalf("due dates = %v, want %v", got, want)
+			}
+		}
+	})
+}
+
+func TestLoanInterest(t *testing.T) {
+	loan := testLoan(t, 12000, 6.5, 48, model.LoanCompoundingMonthly)
+	tests := []struct {
+		name     string
+		balance  float64
+		from, to string
+		want     float64
+	}{
+		{"one month", 12000, "2024-01-15", "2024-02-15", 65},
+		{"month end clamp", 12000, "2024-01-31", "2024-02-29", 65},
+		{"no time passed", 12000, "2024-01-15", "2024-01-15", 0},
+		{"nothing owed", 0, "2024-01-15", "2024-02-15", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := roundCents(loanInterest(loan, tt.balance, mustDate(t, tt.from), mustDate(t, tt.to)))
+			if got != tt.want {
+				t.Errorf("loanInterest = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReplayLoan(t *testing.T) {
+	loan := testLoan(t, 12000, 6.5, 48, model.LoanCompoundingMonthly)
+
+	t.Run("payment on the due date matches the schedule", func(t *testing.T) {
+		payments, balance := replayLoan(loan, []model.LoanPayment{testLoanPayment(t, 284.58, "2024-02-15")}, mustDate(t, "2024-02-15"))
+		p := payments[0]
+		if p.Interest != 65 || p.Principal != 219.58 || p.Balance != 11780.42 {
+			t.Errorf("payment split = %+v", p)
+		}
+		if balance.RemainingPrincipal != 11780.42 || balance.AccruedInterest != 0 || balance.Status != loanStatusActive {
+			t.Errorf("balance = %+v", balance)
+		}
+	})
+
+	t.Run("interest accrues until today", func(t *testing.T) {
+		_, balance := replayLoan(loan, nil, mustDate(t, "2024-02-15"))
+		if balance.AccruedInterest != 65 || balance.RemainingBalance != 12065 || balance.PaymentsMade != 0 {
+			t.Errorf("balance = %+v", balance)
+		}
+	})
+
+	t.Run("small payment covers interest first", func(t *testing.T) {
+		payments, balance := replayLoan(loan, []model.LoanPayment{testLoanPayment(t, 50, "2024-02-15")}, mustDate(t, "2024-02-15"))
+		if payments[0].Interest != 50 || payments[0].Principal != 0 {
+			t.Errorf("payment split = %+v", payments[0])
+		}
+		if balance.AccruedInterest != 15 || balance.RemainingPrincipal != 12000 {
+			t.Errorf("balance = %+v", balance)
+		}
+	})
+
+	t.Run("overpayment pays the loan off", func(t *testing.T) {
+		payments, balance := replayLoan(loan, []model.LoanPayment{testLoanPayment(t, 20000, "2024-02-15")}, mustDate(t, "2024-06-01"))
+		if payments[0].Principal != 12000 || payments[0].Interest != 65 {
+			t.Errorf("payment split = %+v", payments[0])
+		}
+		if balance.Status != loanStatusPaidOff || balance.RemainingBalance != 0 {
+			t.Errorf("balance = %+v", balance)
+		}
+	})
+}
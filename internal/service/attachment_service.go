@@ -0,0 +1,283 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/constant"
+	"github.com/tyha2404/nexo-app-api/internal/dto"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"github.com/tyha2404/nexo-app-api/internal/repository"
+	"github.com/tyha2404/nexo-app-api/internal/storage"
+	"gorm.io/gorm"
+)
+
+// attachmentURLTTL is how long a generated download URL stays valid
+const attachmentURLTTL = 15 * time.Minute
+
+// sniffLen is the number of leading bytes http.DetectContentType inspects
+const sniffLen = 512
+
+// allowedAttachmentTypes lists the sniffed content types accepted for upload
+var allowedAttachmentTypes = map[string]struct{}{
+	"image/jpeg":      {},
+	"image/png":       {},
+	"image/gif":       {},
+	"image/webp":      {},
+	"application/pdf": {},
+}
+
+// AttachmentTarget identifies the record an attachment belongs to. Exactly
+// one of the IDs must be set.
+type AttachmentTarget struct {
+	TransactionID *uuid.UUID
+	CostID        *uuid.UUID
+}
+
+type AttachmentService interface {
+	Upload(ctx context.Context, userID uuid.UUID, target AttachmentTarget, fileName string, size int64, body io.Reader) (*dto.AttachmentResponse, error)
+	List(ctx context.Context, userID uuid.UUID, target AttachmentTarget) ([]dto.AttachmentResponse, error)
+	GetAttachment(ctx context.Context, userID, id uuid.UUID) (*dto.AttachmentResponse, error)
+	DeleteAttachment(ctx context.Context, userID, id uuid.UUID) error
+	OpenByStorageKey(ctx context.Context, key string) (*model.Attachment, io.ReadCloser, error)
+}
+
+type attachmentService struct {
+	attachmentRepo  repository.AttachmentRepo
+	transactionRepo repository.TransactionRepository
+	costRepo        repository.CostRepo
+	store           storage.BlobStore
+	maxBytes        int64
+}
+
+func NewAttachmentService(
+	attachmentRepo repository.AttachmentRepo,
+	transactionRepo repository.TransactionRepository,
+	costRepo repository.CostRepo,
+	store storage.BlobStore,
+	maxBytes int64,
+) AttachmentService {
+	return &attachmentService{
+		attachmentRepo:  attachmentRepo,
+		transactionRepo: transactionRepo,
+		costRepo:        costRepo,
+		store:           store,
+		maxBytes:        maxBytes,
+	}
+}
+
+// Upload sniffs the content type of body, stores it in the blob store and
+// records the attachment
+func (s *attachmentService) Upload(ctx context.Context, userID uuid.UUID, target AttachmentTarget, fileName string, size int64, body io.Reader) (*dto.AttachmentResponse, error) {
+	if err := s.checkTarget(ctx, userID, target); err != nil {
+		return nil, err
+	}
+	if size <= 0 {
+		return nil, fmt.Errorf("%w: empty file", constant.ErrInvalidInput)
+	}
+	if size > s.maxBytes {
+		return nil, constant.ErrFileTooLarge
+	}
+
+	// Trust the bytes, not the client supplied Content-Type
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(body, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	head = head[:n]
+	contentType := http.DetectContentType(head)
+	if _, ok := allowedAttachmentTypes[contentType]; !ok {
+		return nil, fmt.Errorf("%w: %s", constant.ErrUnsupportedMedia, contentType)
+	}
+
+	id := uuid.New()
+	attachment := &model.Attachment{
+		ID:            id,
+		UserID:        userID,
+		TransactionID: target.TransactionID,
+		CostID:        target.CostID,
+		FileName:      sanitizeFileName(fileName),
+		ContentType:   contentType,
+		Size:          size,
+		StorageKey:    fmt.Sprintf("attachments/%s/%s", userID, id),
+	}
+
+	content := io.MultiReader(bytes.NewReader(head), body)
+	if err := s.store.Put(ctx, attachment.StorageKey, content, size, contentType); err != nil {
+		return nil, err
+	}
+
+	if err := s.attachmentRepo.Create(ctx, attachment); err != nil {
+		// Do not leave an orphaned blob behind
+		_ = s.store.Delete(ctx, attachment.StorageKey)
+		return nil, err
+	}
+
+	return s.toResponse(ctx, attachment, true)
+}
+
+func (s *attachmentService) List(ctx context.Context, userID uuid.UUID, target AttachmentTarget) ([]dto.AttachmentResponse, error) {
+	if err := s.checkTarget(ctx, userID, target); err != nil {
+		return nil, err
+	}
+
+	var (
+		attachments []model.Attachment
+		err         error
+	)
+	if target.TransactionID != nil {
+		attachments, err = s.attachmentRepo.ListByTransactionID(ctx, *target.TransactionID)
+	} else {
+		attachments, err = s.attachmentRepo.ListByCostID(ctx, *target.CostID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.AttachmentResponse, 0, len(attachments))
+	for i := range attachments {
+		resp, err := s.toResponse(ctx, &attachments[i], false)
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, *resp)
+	}
+	return responses, nil
+}
+
+// GetAttachment returns the attachment metadata with a fresh signed download URL
+func (s *attachmentService) GetAttachment(ctx context.Context, userID, id uuid.UUID) (*dto.AttachmentResponse, error) {
+	attachment, err := s.getOwnedAttachment(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponse(ctx, attachment, true)
+}
+
+func (s *attachmentService) DeleteAttachment(ctx context.Context, userID, id uuid.UUID) error {
+	attachment, err := s.getOwnedAttachment(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.store.Delete(ctx, attachment.StorageKey); err != nil {
+		return err
+	}
+	return s.attachmentRepo.Delete(ctx, id)
+}
+
+// OpenByStorageKey is used to serve signed URLs of stores that rely on this
+// application for downloads. The caller must verify the signature first.
+func (s *attachmentService) OpenByStorageKey(ctx context.Context, key string) (*model.Attachment, io.ReadCloser, error) {
+	attachment, err := s.attachmentRepo.FindByStorageKey(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	content, err := s.store.Get(ctx, key)
+	if err != nil {
+		if err == storage.ErrObjectNotFound {
+			return nil, nil, constant.ErrNotFound
+		}
+		return nil, nil, err
+	}
+	return attachment, content, nil
+}
+
+// checkTarget ensures exactly one owner is set and that it belongs to the user
+func (s *attachmentService) checkTarget(ctx context.Context, userID uuid.UUID, target AttachmentTarget) error {
+	switch {
+	case target.TransactionID != nil && target.CostID == nil:
+		transaction, err := s.transactionRepo.GetByID(ctx, *target.TransactionID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return constant.ErrNotFound
+			}
+			return err
+		}
+		if transaction.UserID != userID {
+			return constant.ErrNotFound
+		}
+	case target.CostID != nil && target.TransactionID == nil:
+		cost, err := s.costRepo.GetByID(ctx, *target.CostID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return constant.ErrNotFound
+			}
+			return err
+		}
+		if cost.UserID != userID {
+			return constant.ErrNotFound
+		}
+	default:
+		return fmt.Errorf("%w: exactly one of transactionId or costId is required", constant.ErrInvalidInput)
+	}
+	return nil
+}
+
+func (s *attachmentService) getOwnedAttachment(ctx context.Context, userID, id uuid.UUID) (*model.Attachment, error) {
+	attachment, err := s.attachmentRepo.GetByID(ctx, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, constant.ErrNotFound
+		}
+		return nil, err
+	}
+	if attachment.UserID != userID {
+		return nil, constant.ErrNotFound
+	}
+	return attachment, nil
+}
+
+func (s *attachmentService) toResponse(ctx context.Context, a *model.Attachment, withURL bool) (*dto.AttachmentResponse, error) {
+	resp := &dto.AttachmentResponse{
+		ID:          a.ID.String(),
+		UserID:      a.UserID.String(),
+		FileName:    a.FileName,
+		ContentType: a.ContentType,
+		Size:        a.Size,
+		CreatedAt:   a.CreatedAt.Format(time.RFC3339),
+	}
+	if a.TransactionID != nil {
+		id := a.TransactionID.String()
+		resp.TransactionID = &id
+	}
+	if a.CostID != nil {
+		id := a.CostID.String()
+		resp.CostID = &id
+	}
+
+	if withURL {
+		url, err := s.store.SignedURL(ctx, a.StorageKey, attachmentURLTTL)
+		if err != nil {
+			return nil, err
+		}
+		resp.DownloadURL = url
+		resp.ExpiresAt = time.Now().Add(attachmentURLTTL).UTC().Format(time.RFC3339)
+	}
+	return resp, nil
+}
+
+// sanitizeFileName keeps only the base name of a client supplied file name
+func sanitizeFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+	if len(name) > 255 {
+		name = name[len(name)-255:]
+	}
+	return name
+}
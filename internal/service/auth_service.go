@@ -10,16 +10,19 @@ import (
 
 type AuthService interface {
 	Login(ctx context.Context, email string, password string) (*model.User, error)
-	Register(ctx context.Context, user *model.User) (*model.User, error)
+	// Register creates the user along with the default categories of locale
+	Register(ctx context.Context, user *model.User, locale string) (*model.User, error)
 }
 
 type authService struct {
-	repo repository.UserRepo
+	repo         repository.UserRepo
+	templateRepo repository.CategoryTemplateRepo
 }
 
-func NewAuthService(repo repository.UserRepo) AuthService {
+func NewAuthService(repo repository.UserRepo, templateRepo repository.CategoryTemplateRepo) AuthService {
 	return &authService{
-		repo: repo,
+		repo:         repo,
+		templateRepo: templateRepo,
 	}
 }
 
@@ -48,7 +51,7 @@ func (s *authService) Login(ctx context.Context, email string, password string)
 	return user, nil
 }
 
-func (s *authService) Register(ctx context.Context, user *model.User) (*model.User, error) {
+func (s *authService) Register(ctx context.Context, user *model.User, locale string) (*model.User, error) {
 	// Validate user data
 	if err := user.Validate(); err != nil {
 		return nil, err
@@ -77,8 +80,14 @@ func (s *authService) Register(ctx context.Context, user *model.User) (*model.Us
 		return nil, err
 	}
 
-	// Save user to database
-	if err := s.repo.Create(ctx, user); err != nil {
+	// Start the user off with the categories of their locale
+	templates, err := resolveCategoryTemplates(ctx, s.templateRepo, locale)
+	if err != nil {
+		return nil, err
+	}
+
+	// Save user and categories to database
+	if err := s.repo.CreateWithCategories(ctx, user, categoriesFromTemplates(templates)); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,1404 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/constant"
+	"github.com/tyha2404/nexo-app-api/internal/dto"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"github.com/tyha2404/nexo-app-api/internal/repository"
+)
+
+const (
+	backupFormat = "nexo-backup"
+	// backupVersion is the archive layout written by Backup. Restore accepts
+	// every version up to this one.
+	backupVersion = 1
+	// maxRestoreWarnings caps the warnings returned by a restore
+	maxRestoreWarnings = 100
+)
+
+type BackupService interface {
+	// Backup streams the user's complete archive as JSON to w
+	Backup(ctx context.Context, userID uuid.UUID, w io.Writer) error
+	// Restore reads an archive and recreates its records in the user's
+	// account with new IDs
+	Restore(ctx context.Context, userID uuid.UUID, r io.Reader, req dto.RestoreRequest) (*dto.RestoreResultResponse, error)
+}
+
+type backupService struct {
+	userRepo        repository.UserRepo
+	categoryRepo    repository.CategoryRepo
+	tagRepo         repository.TagRepo
+	transactionRepo repository.TransactionRepository
+	profileRepo     repository.ImportProfileRepo
+	exportRepo      repository.ExportRepo
+	backupRepo      repository.BackupRepo
+}
+
+func NewBackupService(
+	userRepo repository.UserRepo,
+	categoryRepo repository.CategoryRepo,
+	tagRepo repository.TagRepo,
+	transactionRepo repository.TransactionRepository,
+	profileRepo repository.ImportProfileRepo,
+	exportRepo repository.ExportRepo,
+	backupRepo repository.BackupRepo,
+) BackupService {
+	return &backupService{
+		userRepo:        userRepo,
+		categoryRepo:    categoryRepo,
+		tagRepo:         tagRepo,
+		transactionRepo: transactionRepo,
+		profileRepo:     profileRepo,
+		exportRepo:      exportRepo,
+		backupRepo:      backupRepo,
+	}
+}
+
+func (s *backupService) Backup(ctx context.Context, userID uuid.UUID, w io.Writer) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	out := &jsonStreamWriter{w: bufio.NewWriter(w)}
+	out.raw("{")
+	out.field("format", backupFormat)
+	out.raw(",")
+	out.field("version", backupVersion)
+	out.raw(",")
+	out.field("createdAt", time.Now().UTC().Format(time.RFC3339))
+	out.raw(",")
+	out.field("profile", dto.BackupProfile{
+		ID:        user.ID,
+		Username:  user.Username,
+		Email:     user.Email,
+		CreatedAt: formatTimestamp(user.CreatedAt),
+	})
+
+	categories, err := s.categoryRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	out.beginArray("categories")
+	for _, c := range categories {
+		category := dto.BackupCategory{
+			ID:          c.ID,
+			Name:        c.Name,
+			Description: c.Description,
+			Kind:        categoryKindString(c.Kind),
+			Icon:        c.Icon,
+			Color:       c.Color,
+			SortOrder:   c.SortOrder,
+			ParentID:    c.ParentID,
+			CreatedAt:   formatTimestamp(c.CreatedAt),
+		}
+		if c.ArchivedAt != nil {
+			archivedAt := formatTimestamp(*c.ArchivedAt)
+			category.ArchivedAt = &archivedAt
+		}
+		out.item(category)
+	}
+	out.endArray()
+
+	tags, err := s.tagRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	out.beginArray("tags")
+	for _, t := range tags {
+		out.item(dto.BackupTag{ID: t.ID, Name: t.Name, Color: t.Color, CreatedAt: formatTimestamp(t.CreatedAt)})
+	}
+	out.endArray()
+
+	budgets, err := s.exportRepo.ListBudgets(ctx, userID)
+	if err != nil {
+		return err
+	}
+	out.beginArray("budgets")
+	for _, b := range budgets {
+		out.item(dto.BackupBudget{
+			ID:          b.ID,
+			CategoryID:  b.CategoryID,
+			Amount:      b.Amount,
+			PeriodType:  b.PeriodType,
+			PeriodStart: b.PeriodStart.Format("2006-01-02"),
+			CreatedAt:   formatTimestamp(b.CreatedAt),
+		})
+	}
+	out.endArray()
+
+	alerts, err := s.backupRepo.ListAlerts(ctx, userID)
+	if err != nil {
+		return err
+	}
+	out.beginArray("alerts")
+	for _, a := range alerts {
+		out.item(dto.BackupAlert{
+			ID:          a.ID,
+			BudgetID:    a.BudgetID,
+			GoalID:      a.GoalID,
+			BillID:      a.BillID,
+			AlertType:   a.AlertType,
+			Message:     a.Message,
+			TriggeredAt: formatTimestamp(a.TriggeredAt),
+		})
+	}
+	out.endArray()
+
+	out.beginArray("transactions")
+	err = s.exportRepo.EachTransactionBatch(ctx, userID, repository.ExportFilter{}, func(batch []model.Transaction) error {
+		for _, t := range batch {
+			out.item(dto.BackupTransaction{
+				ID:              t.ID,
+				CategoryID:      t.CategoryID,
+				Amount:          t.Amount,
+				Type:            string(t.Type),
+				Description:     t.Description,
+				TransactionDate: t.TransactionDate.Format("2006-01-02"),
+				ExternalID:      t.ExternalID,
+				AccountID:       t.AccountID,
+				TagIDs:          tagIDs(t.Tags),
+				CreatedAt:       formatTimestamp(t.CreatedAt),
+			})
+		}
+		return out.err
+	})
+	if err != nil {
+		return err
+	}
+	out.endArray()
+
+	out.beginArray("costs")
+	err = s.exportRepo.EachCostBatch(ctx, userID, repository.ExportFilter{}, func(batch []model.Cost) error {
+		for _, c := range batch {
+			out.item(dto.BackupCost{
+				ID:         c.ID,
+				CategoryID: c.CategoryID,
+				Title:      c.Title,
+				Amount:     c.Amount,
+				Currency:   c.Currency,
+				IncurredAt: formatTimestamp(c.IncurredAt),
+				TagIDs:     tagIDs(c.Tags),
+				CreatedAt:  formatTimestamp(c.CreatedAt),
+			})
+		}
+		return out.err
+	})
+	if err != nil {
+		return err
+	}
+	out.endArray()
+
+	expenses, err := s.backupRepo.ListExpenses(ctx, userID)
+	if err != nil {
+		return err
+	}
+	out.beginArray("expenses")
+	for _, e := range expenses {
+		out.item(dto.BackupExpense{
+			ID:          e.ID,
+			CategoryID:  e.CategoryID,
+			Amount:      e.Amount,
+			Description: e.Description,
+			ExpenseDate: e.ExpenseDate.Format("2006-01-02"),
+			CreatedAt:   formatTimestamp(e.CreatedAt),
+		})
+	}
+	out.endArray()
+
+	profiles, err := s.profileRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	out.beginArray("importProfiles")
+	for _, p := range profiles {
+		out.item(dto.BackupImportProfile{
+			Name:              p.Name,
+			Delimiter:         p.Delimiter,
+			DateFormat:        p.DateFormat,
+			DecimalSeparator:  p.DecimalSeparator,
+			HasHeader:         p.HasHeader,
+			DateColumn:        p.DateColumn,
+			AmountColumn:      p.AmountColumn,
+			DebitColumn:       p.DebitColumn,
+			CreditColumn:      p.CreditColumn,
+			DescriptionColumn: p.DescriptionColumn,
+			TypeColumn:        p.TypeColumn,
+			CategoryColumn:    p.CategoryColumn,
+		})
+	}
+	out.endArray()
+
+	items, err := s.backupRepo.ListNetWorthItems(ctx, userID)
+	if err != nil {
+		return err
+	}
+	out.beginArray("netWorthItems")
+	for _, item := range items {
+		valuations := make([]dto.BackupValuation, 0, len(item.Valuations))
+		for _, v := range item.Valuations {
+			valuations = append(valuations, dto.BackupValuation{
+				Value:    v.Value,
+				ValuedAt: v.ValuedAt.Format("2006-01-02"),
+				Note:     v.Note,
+			})
+		}
+		out.item(dto.BackupNetWorthItem{
+			ID:          item.ID,
+			Name:        item.Name,
+			Kind:        string(item.Kind),
+			Description: item.Description,
+			Valuations:  valuations,
+			CreatedAt:   formatTimestamp(item.CreatedAt),
+		})
+	}
+	out.endArray()
+
+	rules, err := s.backupRepo.ListCategorizationRules(ctx, userID)
+	if err != nil {
+		return err
+	}
+	out.beginArray("categorizationRules")
+	for _, r := range rules {
+		rule := dto.BackupCategorizationRule{
+			ID:                  r.ID,
+			Name:                r.Name,
+			Priority:            r.Priority,
+			Enabled:             r.Enabled,
+			DescriptionContains: r.DescriptionContains,
+			DescriptionPattern:  r.DescriptionPattern,
+			MinAmount:           r.MinAmount,
+			MaxAmount:           r.MaxAmount,
+			AccountID:           r.AccountID,
+			SetCategoryID:       r.SetCategoryID,
+			SetDescription:      r.SetDescription,
+			AddTagIDs:           tagIDs(r.AddTags),
+			StopProcessing:      r.StopProcessing,
+			CreatedAt:           formatTimestamp(r.CreatedAt),
+		}
+		if r.Type != nil {
+			txType := string(*r.Type)
+			rule.Type = &txType
+		}
+		out.item(rule)
+	}
+	out.endArray()
+
+	goals, err := s.backupRepo.ListGoals(ctx, userID)
+	if err != nil {
+		return err
+	}
+	out.beginArray("goals")
+	for _, g := range goals {
+		goal := dto.BackupGoal{
+			ID:             g.ID,
+			Name:           g.Name,
+			TargetAmount:   g.TargetAmount,
+			StartDate:      g.StartDate.Format("2006-01-02"),
+			CategoryID:     g.CategoryID,
+			TagID:          g.TagID,
+			NetWorthItemID: g.NetWorthItemID,
+			Contributions:  make([]dto.BackupGoalContribution, 0, len(g.Contributions)),
+			CreatedAt:      formatTimestamp(g.CreatedAt),
+		}
+		if g.Deadline != nil {
+			deadline := g.Deadline.Format("2006-01-02")
+			goal.Deadline = &deadline
+		}
+		for _, c := range g.Contributions {
+			goal.Contributions = append(goal.Contributions, dto.BackupGoalContribution{
+				Amount:        c.Amount,
+				ContributedOn: c.ContributedOn.Format("2006-01-02"),
+				Note:          c.Note,
+			})
+		}
+		out.item(goal)
+	}
+	out.endArray()
+
+	loans, err := s.backupRepo.ListLoans(ctx, userID)
+	if err != nil {
+		return err
+	}
+	out.beginArray("loans")
+	for _, l := range loans {
+		loan := dto.BackupLoan{
+			ID:           l.ID,
+			Name:         l.Name,
+			Direction:    string(l.Direction),
+			Counterparty: l.Counterparty,
+			Principal:    l.Principal,
+			InterestRate: l.InterestRate,
+			TermMonths:   l.TermMonths,
+			Compounding:  string(l.Compounding),
+			StartDate:    l.StartDate.Format("2006-01-02"),
+			CategoryID:   l.CategoryID,
+			CreatedAt:    formatTimestamp(l.CreatedAt),
+		}
+		for _, p := range l.Payments {
+			loan.PaymentTransactionIDs = append(loan.PaymentTransactionIDs, p.TransactionID)
+		}
+		out.item(loan)
+	}
+	out.endArray()
+
+	bills, err := s.backupRepo.ListBills(ctx, userID)
+	if err != nil {
+		return err
+	}
+	out.beginArray("bills")
+	for _, b := range bills {
+		bill := dto.BackupBill{
+			ID:               b.ID,
+			Payee:            b.Payee,
+			ExpectedAmount:   b.ExpectedAmount,
+			DueDay:           b.DueDay,
+			Recurrence:       string(b.Recurrence),
+			StartDate:        b.StartDate.Format("2006-01-02"),
+			RemindDaysBefore: b.RemindDaysBefore,
+			CategoryID:       b.CategoryID,
+			Note:             b.Note,
+			CreatedAt:        formatTimestamp(b.CreatedAt),
+		}
+		if b.EndDate != nil {
+			endDate := b.EndDate.Format("2006-01-02")
+			bill.EndDate = &endDate
+		}
+		for _, p := range b.Payments {
+			bill.Payments = append(bill.Payments, dto.BackupBillPayment{
+				DueDate:       p.DueDate.Format("2006-01-02"),
+				TransactionID: p.TransactionID,
+			})
+		}
+		out.item(bill)
+	}
+	out.endArray()
+
+	out.raw("}")
+	return out.flush()
+}
+
+func (s *backupService) Restore(ctx context.Context, userID uuid.UUID, r io.Reader, req dto.RestoreRequest) (*dto.RestoreResultResponse, error) {
+	var archive dto.BackupArchive
+	if err := json.NewDecoder(r).Decode(&archive); err != nil {
+		return nil, fmt.Errorf("%w: invalid backup file: %v", constant.ErrInvalidInput, err)
+	}
+	if archive.Format != backupFormat {
+		return nil, fmt.Errorf("%w: not a %s archive", constant.ErrInvalidInput, backupFormat)
+	}
+	if archive.Version < 1 || archive.Version > backupVersion {
+		return nil, fmt.Errorf("%w: unsupported backup version %d", constant.ErrInvalidInput, archive.Version)
+	}
+
+	p := &restorePlan{
+		userID:       userID,
+		rename:       req.OnConflict == "rename",
+		categories:   make(map[uuid.UUID]uuid.UUID),
+		tags:         make(map[uuid.UUID]uuid.UUID),
+		budgets:      make(map[uuid.UUID]uuid.UUID),
+		items:        make(map[uuid.UUID]uuid.UUID),
+		goals:        make(map[uuid.UUID]uuid.UUID),
+		transactions: make(map[uuid.UUID]uuid.UUID),
+		bills:        make(map[uuid.UUID]uuid.UUID),
+		result: &dto.RestoreResultResponse{
+			DryRun:        req.DryRun == nil || *req.DryRun,
+			SourceVersion: archive.Version,
+			Entities:      make(map[string]dto.RestoreEntityResult),
+		},
+	}
+
+	if err := s.planCategories(ctx, p, archive.Categories); err != nil {
+		return nil, err
+	}
+	if err := s.planTags(ctx, p, archive.Tags); err != nil {
+		return nil, err
+	}
+	p.planBudgets(archive.Budgets)
+	skipDuplicates := req.SkipDuplicates == nil || *req.SkipDuplicates
+	if err := s.planTransactions(ctx, p, archive.Transactions, skipDuplicates); err != nil {
+		return nil, err
+	}
+	p.planCosts(archive.Costs)
+	p.planExpenses(archive.Expenses)
+	if err := s.planImportProfiles(ctx, p, archive.ImportProfiles); err != nil {
+		return nil, err
+	}
+	if err := s.planNetWorthItems(ctx, p, archive.NetWorthItems); err != nil {
+		return nil, err
+	}
+	if err := s.planCategorizationRules(ctx, p, archive.CategorizationRules); err != nil {
+		return nil, err
+	}
+	if err := s.planGoals(ctx, p, archive.Goals); err != nil {
+		return nil, err
+	}
+	if err := s.planLoans(ctx, p, archive.Loans); err != nil {
+		return nil, err
+	}
+	if err := s.planBills(ctx, p, archive.Bills); err != nil {
+		return nil, err
+	}
+	p.planAlerts(archive.Alerts)
+
+	if p.dropped > 0 {
+		p.result.Warnings = append(p.result.Warnings, fmt.Sprintf("%d more warnings omitted", p.dropped))
+	}
+	if p.result.DryRun {
+		return p.result, nil
+	}
+
+	if err := s.backupRepo.Restore(ctx, &p.set); err != nil {
+		return nil, err
+	}
+	return p.result, nil
+}
+
+// restorePlan collects the remapped records of a restore together with the
+// mapping from archive IDs to new IDs
+type restorePlan struct {
+	userID       uuid.UUID
+	rename       bool
+	categories   map[uuid.UUID]uuid.UUID
+	tags         map[uuid.UUID]uuid.UUID
+	budgets      map[uuid.UUID]uuid.UUID
+	items        map[uuid.UUID]uuid.UUID
+	goals        map[uuid.UUID]uuid.UUID
+	transactions map[uuid.UUID]uuid.UUID
+	bills        map[uuid.UUID]uuid.UUID
+	set          repository.RestoreSet
+	result       *dto.RestoreResultResponse
+	dropped      int
+}
+
+func (p *restorePlan) count(entity string, update func(*dto.RestoreEntityResult)) {
+	r := p.result.Entities[entity]
+	update(&r)
+	p.result.Entities[entity] = r
+}
+
+func (p *restorePlan) created(entity string) {
+	p.count(entity, func(r *dto.RestoreEntityResult) { r.Created++ })
+}
+
+func (p *restorePlan) merged(entity string) {
+	p.count(entity, func(r *dto.RestoreEntityResult) { r.Merged++ })
+}
+
+func (p *restorePlan) skip(entity string, format string, args ...any) {
+	p.count(entity, func(r *dto.RestoreEntityResult) { r.Skipped++ })
+	if len(p.result.Warnings) >= maxRestoreWarnings {
+		p.dropped++
+		return
+	}
+	p.result.Warnings = append(p.result.Warnings, entity+": "+fmt.Sprintf(format, args...))
+}
+
+// resolveName decides how a named record is restored. It returns the
+// existing ID to merge into, or the name to create the record under.
+func (p *restorePlan) resolveName(name string, taken map[string]uuid.UUID) (uuid.UUID, string) {
+	key := strings.ToLower(name)
+	existing, ok := taken[key]
+	if !ok {
+		return uuid.Nil, name
+	}
+	if !p.rename {
+		return existing, ""
+	}
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s (%d)", name, n)
+		if _, ok := taken[strings.ToLower(candidate)]; !ok {
+			return uuid.Nil, candidate
+		}
+	}
+}
+
+func (s *backupService) planCategories(ctx context.Context, p *restorePlan, categories []dto.BackupCategory) error {
+	existing, err := s.categoryRepo.ListByUserID(ctx, p.userID)
+	if err != nil {
+		return err
+	}
+	taken := make(map[string]uuid.UUID, len(existing))
+	for _, c := range existing {
+		taken[strings.ToLower(c.Name)] = c.ID
+	}
+
+	for _, c := range categories {
+		name := strings.TrimSpace(c.Name)
+		if name == "" {
+			p.skip("categories", "category %s has no name", c.ID)
+			continue
+		}
+		existingID, name := p.resolveName(name, taken)
+		if existingID != uuid.Nil {
+			p.categories[c.ID] = existingID
+			p.merged("categories")
+			continue
+		}
+		if len(name) > 50 {
+			p.skip("categories", "category name %q is too long", name)
+			continue
+		}
+
+		category := model.Category{
+			ID:          uuid.New(),
+			UserID:      p.userID,
+			Name:        name,
+			Description: c.Description,
+			SortOrder:   c.SortOrder,
+			CreatedAt:   parseTimestamp(c.CreatedAt),
+		}
+		if c.Kind != nil {
+			kind := model.TransactionType(*c.Kind)
+			if kind == model.TransactionTypeIncome || kind == model.TransactionTypeExpense {
+				category.Kind = &kind
+			}
+		}
+		if c.Icon != nil && len(*c.Icon) <= 50 {
+			category.Icon = c.Icon
+		}
+		if c.Color != nil && len(*c.Color) <= 7 {
+			category.Color = c.Color
+		}
+		if c.ArchivedAt != nil {
+			if archivedAt := parseTimestamp(*c.ArchivedAt); !archivedAt.IsZero() {
+				category.ArchivedAt = &archivedAt
+			}
+		}
+		taken[strings.ToLower(name)] = category.ID
+		p.categories[c.ID] = category.ID
+		p.set.Categories = append(p.set.Categories, category)
+		p.created("categories")
+	}
+
+	p.nestCategories(categories)
+	return nil
+}
+
+// nestCategories restores the parents of the created categories and orders
+// them parents first. Merged categories keep their place in the tree, so a
+// created category can only end up below an existing one, never above it.
+func (p *restorePlan) nestCategories(categories []dto.BackupCategory) {
+	created := make(map[uuid.UUID]*model.Category, len(p.set.Categories))
+	for i := range p.set.Categories {
+		created[p.set.Categories[i].ID] = &p.set.Categories[i]
+	}
+	for _, c := range categories {
+		category, ok := created[p.categories[c.ID]]
+		if !ok || c.ParentID == nil {
+			continue
+		}
+		parentID, ok := p.categories[*c.ParentID]
+		if !ok || parentID == category.ID {
+			continue
+		}
+		category.ParentID = &parentID
+	}
+
+	// Cycles can only come from a tampered file; they are cut where found
+	var ordered []model.Category
+	placed := make(map[uuid.UUID]bool, len(created))
+	var place func(c *model.Category, visiting map[uuid.UUID]bool)
+	place = func(c *model.Category, visiting map[uuid.UUID]bool) {
+		if placed[c.ID] {
+			return
+		}
+		visiting[c.ID] = true
+		if c.ParentID != nil {
+			if parent, ok := created[*c.ParentID]; ok {
+				if visiting[parent.ID] {
+					c.ParentID = nil
+				} else {
+					place(parent, visiting)
+				}
+			}
+		}
+		placed[c.ID] = true
+		ordered = append(ordered, *c)
+	}
+	for i := range p.set.Categories {
+		place(&p.set.Categories[i], make(map[uuid.UUID]bool))
+	}
+	p.set.Categories = ordered
+}
+
+func (s *backupService) planTags(ctx context.Context, p *restorePlan, tags []dto.BackupTag) error {
+	existing, err := s.tagRepo.ListByUserID(ctx, p.userID)
+	if err != nil {
+		return err
+	}
+	taken := make(map[string]uuid.UUID, len(existing))
+	for _, t := range existing {
+		taken[strings.ToLower(t.Name)] = t.ID
+	}
+
+	for _, t := range tags {
+		name := strings.TrimSpace(t.Name)
+		if name == "" {
+			p.skip("tags", "tag %s has no name", t.ID)
+			continue
+		}
+		existingID, name := p.resolveName(name, taken)
+		if existingID != uuid.Nil {
+			p.tags[t.ID] = existingID
+			p.merged("tags")
+			continue
+		}
+		if len(name) > 50 {
+			p.skip("tags", "tag name %q is too long", name)
+			continue
+		}
+
+		color := t.Color
+		if color != nil && len(*color) > 7 {
+			color = nil
+		}
+		tag := model.Tag{
+			ID:        uuid.New(),
+			UserID:    p.userID,
+			Name:      name,
+			Color:     color,
+			CreatedAt: parseTimestamp(t.CreatedAt),
+		}
+		taken[strings.ToLower(name)] = tag.ID
+		p.tags[t.ID] = tag.ID
+		p.set.Tags = append(p.set.Tags, tag)
+		p.created("tags")
+	}
+	return nil
+}
+
+func (p *restorePlan) planBudgets(budgets []dto.BackupBudget) {
+	for _, b := range budgets {
+		categoryID, ok := p.categories[b.CategoryID]
+		if !ok {
+			p.skip("budgets", "budget %s references unknown category %s", b.ID, b.CategoryID)
+			continue
+		}
+		if b.PeriodType != "monthly" && b.PeriodType != "yearly" {
+			p.skip("budgets", "budget %s has invalid period type %q", b.ID, b.PeriodType)
+			continue
+		}
+		start, err := time.Parse("2006-01-02", b.PeriodStart)
+		if err != nil {
+			p.skip("budgets", "budget %s has invalid period start %q", b.ID, b.PeriodStart)
+			continue
+		}
+
+		budget := model.Budget{
+			ID:          uuid.New(),
+			UserID:      p.userID,
+			CategoryID:  categoryID,
+			Amount:      b.Amount,
+			PeriodType:  b.PeriodType,
+			PeriodStart: start,
+			CreatedAt:   parseTimestamp(b.CreatedAt),
+		}
+		p.budgets[b.ID] = budget.ID
+		p.set.Budgets = append(p.set.Budgets, budget)
+		p.created("budgets")
+	}
+}
+
+func (p *restorePlan) planAlerts(alerts []dto.BackupAlert) {
+	for _, a := range alerts {
+		alert := model.Alert{
+			ID:          uuid.New(),
+			UserID:      p.userID,
+			AlertType:   a.AlertType,
+			Message:     a.Message,
+			TriggeredAt: parseTimestamp(a.TriggeredAt),
+		}
+		switch a.AlertType {
+		case model.AlertTypeApproachingLimit, model.AlertTypeOverLimit:
+			if a.BudgetID == nil {
+				p.skip("alerts", "alert %s has no budget", a.ID)
+				continue
+			}
+			budgetID, ok := p.budgets[*a.BudgetID]
+			if !ok {
+				p.skip("alerts", "alert %s references unknown budget %s", a.ID, *a.BudgetID)
+				continue
+			}
+			alert.BudgetID = &budgetID
+		case model.AlertTypeGoalBehind:
+			if a.GoalID == nil {
+				p.skip("alerts", "alert %s has no goal", a.ID)
+				continue
+			}
+			goalID, ok := p.goals[*a.GoalID]
+			if !ok {
+				p.skip("alerts", "alert %s references unknown goal %s", a.ID, *a.GoalID)
+				continue
+			}
+			alert.GoalID = &goalID
+		case model.AlertTypeBillDue, model.AlertTypeBillOverdue:
+			if a.BillID == nil {
+				p.skip("alerts", "alert %s has no bill", a.ID)
+				continue
+			}
+			billID, ok := p.bills[*a.BillID]
+			if !ok {
+				p.skip("alerts", "alert %s references unknown bill %s", a.ID, *a.BillID)
+				continue
+			}
+			alert.BillID = &billID
+		default:
+			p.skip("alerts", "alert %s has invalid type %q", a.ID, a.AlertType)
+			continue
+		}
+
+		p.set.Alerts = append(p.set.Alerts, alert)
+		p.created("alerts")
+	}
+}
+
+func (s *backupService) planTransactions(ctx context.Context, p *restorePlan, transactions []dto.BackupTransaction, skipDuplicates bool) error {
+	var externalIDs []string
+	var from, to time.Time
+	for _, t := range transactions {
+		if t.ExternalID != nil {
+			externalIDs = append(externalIDs, *t.ExternalID)
+		}
+		if d, err := time.Parse("2006-01-02", t.TransactionDate); err == nil {
+			if from.IsZero() || d.Before(from) {
+				from = d
+			}
+			if to.IsZero() || d.After(to) {
+				to = d
+			}
+		}
+	}
+	if !skipDuplicates {
+		from = time.Time{}
+	}
+	// Restored records keep their dates, so only same-day bookings match
+	detector, err := loadDuplicateDetector(ctx, s.transactionRepo, p.userID, externalIDs, from, to, 0)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range transactions {
+		categoryID, ok := p.categories[t.CategoryID]
+		if !ok {
+			p.skip("transactions", "transaction %s references unknown category %s", t.ID, t.CategoryID)
+			continue
+		}
+		kind := model.TransactionType(t.Type)
+		if kind != model.TransactionTypeIncome && kind != model.TransactionTypeExpense {
+			p.skip("transactions", "transaction %s has invalid type %q", t.ID, t.Type)
+			continue
+		}
+		if t.Amount <= 0 {
+			p.skip("transactions", "transaction %s has a non-positive amount", t.ID)
+			continue
+		}
+		date, err := time.Parse("2006-01-02", t.TransactionDate)
+		if err != nil {
+			p.skip("transactions", "transaction %s has invalid date %q", t.ID, t.TransactionDate)
+			continue
+		}
+
+		transaction := model.Transaction{
+			ID:              uuid.New(),
+			UserID:          p.userID,
+			CategoryID:      categoryID,
+			Amount:          t.Amount,
+			Type:            kind,
+			Description:     t.Description,
+			TransactionDate: date,
+			ExternalID:      t.ExternalID,
+			AccountID:       t.AccountID,
+			Tags:            p.mapTags("transactions", t.ID, t.TagIDs),
+			CreatedAt:       parseTimestamp(t.CreatedAt),
+		}
+
+		// A bank ID identifies the same booking, so it is never restored twice
+		if t.ExternalID != nil {
+			if _, exists := detector.externalIDs[*t.ExternalID]; exists {
+				p.skip("transactions", "transaction %s already exists with bank ID %q", t.ID, *t.ExternalID)
+				continue
+			}
+		}
+		if skipDuplicates && detector.isDuplicate(&transaction) {
+			p.skip("transactions", "transaction %s duplicates an existing transaction", t.ID)
+			continue
+		}
+
+		p.transactions[t.ID] = transaction.ID
+		p.set.Transactions = append(p.set.Transactions, transaction)
+		p.created("transactions")
+	}
+	return nil
+}
+
+func (p *restorePlan) planCosts(costs []dto.BackupCost) {
+	for _, c := range costs {
+		categoryID, ok := p.categories[c.CategoryID]
+		if !ok {
+			p.skip("costs", "cost %s references unknown category %s", c.ID, c.CategoryID)
+			continue
+		}
+		if strings.TrimSpace(c.Title) == "" || c.Amount <= 0 || len(c.Currency) != 3 {
+			p.skip("costs", "cost %s needs a title, a positive amount and a 3-letter currency", c.ID)
+			continue
+		}
+		incurredAt := parseTimestamp(c.IncurredAt)
+		if incurredAt.IsZero() {
+			p.skip("costs", "cost %s has invalid date %q", c.ID, c.IncurredAt)
+			continue
+		}
+
+		p.set.Costs = append(p.set.Costs, model.Cost{
+			ID:         uuid.New(),
+			UserID:     p.userID,
+			CategoryID: categoryID,
+			Title:      c.Title,
+			Amount:     c.Amount,
+			Currency:   strings.ToUpper(c.Currency),
+			IncurredAt: incurredAt,
+			Tags:       p.mapTags("costs", c.ID, c.TagIDs),
+			CreatedAt:  parseTimestamp(c.CreatedAt),
+		})
+		p.created("costs")
+	}
+}
+
+func (p *restorePlan) planExpenses(expenses []dto.BackupExpense) {
+	for _, e := range expenses {
+		categoryID, ok := p.categories[e.CategoryID]
+		if !ok {
+			p.skip("expenses", "expense %s references unknown category %s", e.ID, e.CategoryID)
+			continue
+		}
+		date, err := time.Parse("2006-01-02", e.ExpenseDate)
+		if err != nil {
+			p.skip("expenses", "expense %s has invalid date %q", e.ID, e.ExpenseDate)
+			continue
+		}
+
+		p.set.Expenses = append(p.set.Expenses, model.Expense{
+			ID:          uuid.New(),
+			UserID:      p.userID,
+			CategoryID:  categoryID,
+			Amount:      e.Amount,
+			Description: e.Description,
+			ExpenseDate: date,
+			CreatedAt:   parseTimestamp(e.CreatedAt),
+		})
+		p.created("expenses")
+	}
+}
+
+func (s *backupService) planImportProfiles(ctx context.Context, p *restorePlan, profiles []dto.BackupImportProfile) error {
+	existing, err := s.profileRepo.ListByUserID(ctx, p.userID)
+	if err != nil {
+		return err
+	}
+	taken := make(map[string]uuid.UUID, len(existing))
+	for _, ip := range existing {
+		taken[strings.ToLower(ip.Name)] = ip.ID
+	}
+
+	for _, ip := range profiles {
+		name := strings.TrimSpace(ip.Name)
+		if name == "" || ip.DateFormat == "" || ip.DateColumn == "" {
+			p.skip("importProfiles", "import profile %q is incomplete", ip.Name)
+			continue
+		}
+		// Merging keeps the existing mapping rather than overwriting it
+		existingID, name := p.resolveName(name, taken)
+		if existingID != uuid.Nil {
+			p.merged("importProfiles")
+			continue
+		}
+
+		profile := model.ImportProfile{
+			ID:                uuid.New(),
+			UserID:            p.userID,
+			Name:              name,
+			Delimiter:         ip.Delimiter,
+			DateFormat:        ip.DateFormat,
+			DecimalSeparator:  ip.DecimalSeparator,
+			HasHeader:         ip.HasHeader,
+			DateColumn:        ip.DateColumn,
+			AmountColumn:      ip.AmountColumn,
+			DebitColumn:       ip.DebitColumn,
+			CreditColumn:      ip.CreditColumn,
+			DescriptionColumn: ip.DescriptionColumn,
+			TypeColumn:        ip.TypeColumn,
+			CategoryColumn:    ip.CategoryColumn,
+		}
+		if profile.Delimiter == "" {
+			profile.Delimiter = ","
+		}
+		if profile.DecimalSeparator == "" {
+			profile.DecimalSeparator = "."
+		}
+		taken[strings.ToLower(name)] = profile.ID
+		p.set.ImportProfiles = append(p.set.ImportProfiles, profile)
+		p.created("importProfiles")
+	}
+	return nil
+}
+
+func (s *backupService) planNetWorthItems(ctx context.Context, p *restorePlan, items []dto.BackupNetWorthItem) error {
+	existing, err := s.backupRepo.ListNetWorthItems(ctx, p.userID)
+	if err != nil {
+		return err
+	}
+	taken := make(map[string]uuid.UUID, len(existing))
+	for _, item := range existing {
+		taken[strings.ToLower(item.Name)] = item.ID
+	}
+
+	for _, item := range items {
+		name := strings.TrimSpace(item.Name)
+		kind := model.NetWorthKind(item.Kind)
+		if name == "" || (kind != model.NetWorthKindAsset && kind != model.NetWorthKindLiability) {
+			p.skip("netWorthItems", "net worth item %q is incomplete", item.Name)
+			continue
+		}
+		// Merging keeps the existing valuation history as it is
+		existingID, name := p.resolveName(name, taken)
+		if existingID != uuid.Nil {
+			if item.ID != uuid.Nil {
+				p.items[item.ID] = existingID
+			}
+			p.merged("netWorthItems")
+			continue
+		}
+
+		restored := model.NetWorthItem{
+			ID:          uuid.New(),
+			UserID:      p.userID,
+			Name:        name,
+			Kind:        kind,
+			Description: item.Description,
+			CreatedAt:   parseTimestamp(item.CreatedAt),
+		}
+		seen := make(map[string]bool, len(item.Valuations))
+		for _, v := range item.Valuations {
+			date, err := time.Parse("2006-01-02", v.ValuedAt)
+			if err != nil || seen[v.ValuedAt] || v.Value < 0 {
+				p.skip("netWorthValuations", "valuation of %q on %q is invalid or repeated", name, v.ValuedAt)
+				continue
+			}
+			seen[v.ValuedAt] = true
+			restored.Valuations = append(restored.Valuations, model.NetWorthValuation{
+				ID:       uuid.New(),
+				UserID:   p.userID,
+				ItemID:   restored.ID,
+				Value:    v.Value,
+				ValuedAt: date,
+				Note:     v.Note,
+			})
+			p.created("netWorthValuations")
+		}
+		taken[strings.ToLower(name)] = restored.ID
+		if item.ID != uuid.Nil {
+			p.items[item.ID] = restored.ID
+		}
+		p.set.NetWorthItems = append(p.set.NetWorthItems, restored)
+		p.created("netWorthItems")
+	}
+	return nil
+}
+
+func (s *backupService) planCategorizationRules(ctx context.Context, p *restorePlan, rules []dto.BackupCategorizationRule) error {
+	existing, err := s.backupRepo.ListCategorizationRules(ctx, p.userID)
+	if err != nil {
+		return err
+	}
+	taken := make(map[string]uuid.UUID, len(existing))
+	for _, r := range existing {
+		taken[strings.ToLower(r.Name)] = r.ID
+	}
+
+	for _, r := range rules {
+		name := strings.TrimSpace(r.Name)
+		if name == "" {
+			p.skip("categorizationRules", "categorization rule %s has no name", r.ID)
+			continue
+		}
+		if r.DescriptionPattern != nil {
+			if _, err := compileRulePattern(*r.DescriptionPattern); err != nil {
+				p.skip("categorizationRules", "categorization rule %q has an invalid pattern", name)
+				continue
+			}
+		}
+		var txType *model.TransactionType
+		if r.Type != nil {
+			kind := model.TransactionType(*r.Type)
+			if kind != model.TransactionTypeIncome && kind != model.TransactionTypeExpense {
+				p.skip("categorizationRules", "categorization rule %q has invalid type %q", name, *r.Type)
+				continue
+			}
+			txType = &kind
+		}
+		// Merging keeps the existing rule as it is
+		existingID, name := p.resolveName(name, taken)
+		if existingID != uuid.Nil {
+			p.merged("categorizationRules")
+			continue
+		}
+
+		rule := model.CategorizationRule{
+			ID:                  uuid.New(),
+			UserID:              p.userID,
+			Name:                name,
+			Priority:            r.Priority,
+			Enabled:             r.Enabled,
+			DescriptionContains: r.DescriptionContains,
+			DescriptionPattern:  r.DescriptionPattern,
+			MinAmount:           r.MinAmount,
+			MaxAmount:           r.MaxAmount,
+			AccountID:           r.AccountID,
+			Type:                txType,
+			SetDescription:      r.SetDescription,
+			AddTags:             p.mapTags("categorizationRules", r.ID, r.AddTagIDs),
+			StopProcessing:      r.StopProcessing,
+			CreatedAt:           parseTimestamp(r.CreatedAt),
+		}
+		if r.SetCategoryID != nil {
+			categoryID, ok := p.categories[*r.SetCategoryID]
+			if !ok {
+				p.skip("categorizationRules", "categorization rule %q references unknown category %s", name, *r.SetCategoryID)
+				continue
+			}
+			rule.SetCategoryID = &categoryID
+		}
+		if rule.SetCategoryID == nil && rule.SetDescription == nil && len(rule.AddTags) == 0 {
+			p.skip("categorizationRules", "categorization rule %q has no action", name)
+			continue
+		}
+		taken[strings.ToLower(name)] = rule.ID
+		p.set.CategorizationRules = append(p.set.CategorizationRules, rule)
+		p.created("categorizationRules")
+	}
+	return nil
+}
+
+// planGoals merges goals into existing ones of the same name and creates
+// the others together with their contributions
+func (s *backupService) planGoals(ctx context.Context, p *restorePlan, goals []dto.BackupGoal) error {
+	existing, err := s.backupRepo.ListGoals(ctx, p.userID)
+	if err != nil {
+		return err
+	}
+	taken := make(map[string]uuid.UUID, len(existing))
+	for _, g := range existing {
+		taken[strings.ToLower(g.Name)] = g.ID
+	}
+
+	for _, g := range goals {
+		name := strings.TrimSpace(g.Name)
+		startDate, err := time.Parse("2006-01-02", g.StartDate)
+		if name == "" || len(name) > 100 || g.TargetAmount <= 0 || err != nil {
+			p.skip("goals", "goal %q is incomplete", g.Name)
+			continue
+		}
+		// Merging keeps the existing contributions as they are
+		existingID, name := p.resolveName(name, taken)
+		if existingID != uuid.Nil {
+			p.goals[g.ID] = existingID
+			p.merged("goals")
+			continue
+		}
+
+		goal := model.Goal{
+			ID:           uuid.New(),
+			UserID:       p.userID,
+			Name:         name,
+			TargetAmount: g.TargetAmount,
+			StartDate:    startDate,
+			CreatedAt:    parseTimestamp(g.CreatedAt),
+		}
+		if g.Deadline != nil {
+			if deadline, err := time.Parse("2006-01-02", *g.Deadline); err == nil && !deadline.Before(startDate) {
+				goal.Deadline = &deadline
+			}
+		}
+		// Links to records that are not restored are dropped
+		if g.CategoryID != nil {
+			if id, ok := p.categories[*g.CategoryID]; ok {
+				goal.CategoryID = &id
+			}
+		}
+		if g.TagID != nil {
+			if id, ok := p.tags[*g.TagID]; ok {
+				goal.TagID = &id
+			}
+		}
+		if g.NetWorthItemID != nil {
+			if id, ok := p.items[*g.NetWorthItemID]; ok {
+				goal.NetWorthItemID = &id
+			}
+		}
+		for _, c := range g.Contributions {
+			date, err := time.Parse("2006-01-02", c.ContributedOn)
+			if err != nil || c.Amount == 0 {
+				p.skip("goalContributions", "contribution to %q on %q is invalid", name, c.ContributedOn)
+				continue
+			}
+			goal.Contributions = append(goal.Contributions, model.GoalContribution{
+				ID:            uuid.New(),
+				UserID:        p.userID,
+				GoalID:        goal.ID,
+				Amount:        c.Amount,
+				ContributedOn: date,
+				Note:          c.Note,
+			})
+			p.created("goalContributions")
+		}
+		taken[strings.ToLower(name)] = goal.ID
+		p.goals[g.ID] = goal.ID
+		p.set.Goals = append(p.set.Goals, goal)
+		p.created("goals")
+	}
+	return nil
+}
+
+func (s *backupService) planLoans(ctx context.Context, p *restorePlan, loans []dto.BackupLoan) error {
+	existing, err := s.backupRepo.ListLoans(ctx, p.userID)
+	if err != nil {
+		return err
+	}
+	taken := make(map[string]uuid.UUID, len(existing))
+	for _, l := range existing {
+		taken[strings.ToLower(l.Name)] = l.ID
+	}
+
+	for _, l := range loans {
+		name := strings.TrimSpace(l.Name)
+		direction := model.LoanDirection(l.Direction)
+		compounding := model.LoanCompounding(l.Compounding)
+		startDate, err := time.Parse("2006-01-02", l.StartDate)
+		if name == "" || len(name) > 100 || l.Principal <= 0 || l.InterestRate < 0 || l.TermMonths < 1 || err != nil ||
+			(direction != model.LoanDirectionBorrowed && direction != model.LoanDirectionLent) {
+			p.skip("loans", "loan %q is incomplete", l.Name)
+			continue
+		}
+		if compounding != model.LoanCompoundingDaily && compounding != model.LoanCompoundingAnnually {
+			compounding = model.LoanCompoundingMonthly
+		}
+		// Merging keeps the existing payments as they are
+		existingID, name := p.resolveName(name, taken)
+		if existingID != uuid.Nil {
+			p.merged("loans")
+			continue
+		}
+
+		loan := model.Loan{
+			ID:           uuid.New(),
+			UserID:       p.userID,
+			Name:         name,
+			Direction:    direction,
+			Counterparty: l.Counterparty,
+			Principal:    l.Principal,
+			InterestRate: l.InterestRate,
+			TermMonths:   l.TermMonths,
+			Compounding:  compounding,
+			StartDate:    startDate,
+			CreatedAt:    parseTimestamp(l.CreatedAt),
+		}
+		if l.CategoryID != nil {
+			if id, ok := p.categories[*l.CategoryID]; ok {
+				loan.CategoryID = &id
+			}
+		}
+		// Payments of transactions skipped above, e.g. as duplicates, are
+		// dropped
+		for _, transactionID := range l.PaymentTransactionIDs {
+			id, ok := p.transactions[transactionID]
+			if !ok {
+				p.skip("loanPayments", "payment of %q references transaction %s that is not restored", name, transactionID)
+				continue
+			}
+			loan.Payments = append(loan.Payments, model.LoanPayment{
+				ID:            uuid.New(),
+				UserID:        p.userID,
+				LoanID:        loan.ID,
+				TransactionID: id,
+			})
+			p.created("loanPayments")
+		}
+		taken[strings.ToLower(name)] = loan.ID
+		p.set.Loans = append(p.set.Loans, loan)
+		p.created("loans")
+	}
+	return nil
+}
+
+// planBills restores every bill as a new one, since bills of the same payee
+// are common
+func (s *backupService) planBills(ctx context.Context, p *restorePlan, bills []dto.BackupBill) error {
+	for _, b := range bills {
+		payee := strings.TrimSpace(b.Payee)
+		recurrence := model.BillRecurrence(b.Recurrence)
+		startDate, err := time.Parse("2006-01-02", b.StartDate)
+		if payee == "" || len(payee) > 100 || b.ExpectedAmount <= 0 || b.DueDay < 1 || b.DueDay > 31 || err != nil ||
+			(recurrence != model.BillRecurrenceOnce && billRecurrenceMonths(recurrence) == 0) {
+			p.skip("bills", "bill %q is incomplete", b.Payee)
+			continue
+		}
+
+		bill := model.Bill{
+			ID:               uuid.New(),
+			UserID:           p.userID,
+			Payee:            payee,
+			ExpectedAmount:   b.ExpectedAmount,
+			DueDay:           b.DueDay,
+			Recurrence:       recurrence,
+			StartDate:        startDate,
+			RemindDaysBefore: max(b.RemindDaysBefore, 0),
+			Note:             b.Note,
+			CreatedAt:        parseTimestamp(b.CreatedAt),
+		}
+		if b.EndDate != nil {
+			if endDate, err := time.Parse("2006-01-02", *b.EndDate); err == nil && !endDate.Before(startDate) {
+				bill.EndDate = &endDate
+			}
+		}
+		if b.CategoryID != nil {
+			if id, ok := p.categories[*b.CategoryID]; ok {
+				bill.CategoryID = &id
+			}
+		}
+		for _, payment := range b.Payments {
+			dueDate, err := time.Parse("2006-01-02", payment.DueDate)
+			if err != nil {
+				p.skip("billPayments", "payment of %q has invalid due date %q", payee, payment.DueDate)
+				continue
+			}
+			transactionID, ok := p.transactions[payment.TransactionID]
+			if !ok {
+				p.skip("billPayments", "payment of %q references transaction %s that is not restored", payee, payment.TransactionID)
+				continue
+			}
+			bill.Payments = append(bill.Payments, model.BillPayment{
+				ID:            uuid.New(),
+				UserID:        p.userID,
+				BillID:        bill.ID,
+				DueDate:       dueDate,
+				TransactionID: transactionID,
+			})
+			p.created("billPayments")
+		}
+		p.bills[b.ID] = bill.ID
+		p.set.Bills = append(p.set.Bills, bill)
+		p.created("bills")
+	}
+	return nil
+}
+
+// mapTags translates archive tag IDs; links to unknown tags are dropped
+func (p *restorePlan) mapTags(entity string, ownerID uuid.UUID, ids []uuid.UUID) []model.Tag {
+	var tags []model.Tag
+	for _, id := range ids {
+		newID, ok := p.tags[id]
+		if !ok {
+			if len(p.result.Warnings) < maxRestoreWarnings {
+				p.result.Warnings = append(p.result.Warnings, fmt.Sprintf("%s: %s references unknown tag %s", entity, ownerID, id))
+			} else {
+				p.dropped++
+			}
+			continue
+		}
+		tags = append(tags, model.Tag{ID: newID})
+	}
+	return tags
+}
+
+func tagIDs(tags []model.Tag) []uuid.UUID {
+	if len(tags) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, len(tags))
+	for i, t := range tags {
+		ids[i] = t.ID
+	}
+	return ids
+}
+
+func formatTimestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// parseTimestamp accepts RFC 3339 timestamps and plain dates. It returns the
+// zero time for anything else, which lets the database default apply.
+func parseTimestamp(value string) time.Time {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t
+	}
+	return time.Time{}
+}
+
+// jsonStreamWriter writes a JSON document piece by piece. The first error is
+// kept and reported by flush.
+type jsonStreamWriter struct {
+	w     *bufio.Writer
+	err   error
+	first bool
+}
+
+func (j *jsonStreamWriter) raw(s string) {
+	if j.err == nil {
+		_, j.err = j.w.WriteString(s)
+	}
+}
+
+func (j *jsonStreamWriter) value(v any) {
+	if j.err != nil {
+		return
+	}
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		j.err = err
+		return
+	}
+	_, j.err = j.w.Write(encoded)
+}
+
+func (j *jsonStreamWriter) field(name string, v any) {
+	j.value(name)
+	j.raw(":")
+	j.value(v)
+}
+
+func (j *jsonStreamWriter) beginArray(name string) {
+	j.raw(",")
+	j.value(name)
+	j.raw(":[")
+	j.first = true
+}
+
+func (j *jsonStreamWriter) item(v any) {
+	if !j.first {
+		j.raw(",")
+	}
+	j.first = false
+	j.value(v)
+}
+
+func (j *jsonStreamWriter) endArray() {
+	j.raw("]")
+}
+
+func (j *jsonStreamWriter) flush() error {
+	if j.err != nil {
+		return j.err
+	}
+	return j.w.Flush()
+}
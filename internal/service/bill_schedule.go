@@ -0,0 +1,93 @@
+package service
+
+import (
+	"time"
+
+	"github.com/tyha2404/nexo-app-api/internal/model"
+)
+
+// Bill occurrence statuses
+const (
+	billStatusPaid    = "paid"
+	billStatusDue     = "due"
+	billStatusOverdue = "overdue"
+)
+
+// billRecurrenceMonths is the number of months between occurrences, or 0 for
+// a one-off bill
+func billRecurrenceMonths(r model.BillRecurrence) int {
+	switch r {
+	case model.BillRecurrenceMonthly:
+		return 1
+	case model.BillRecurrenceQuarterly:
+		return 3
+	case model.BillRecurrenceYearly:
+		return 12
+	default:
+		return 0
+	}
+}
+
+// billDueDate is the due day of the bill in the month offset months after
+// the month of its start date
+func billDueDate(bill *model.Bill, offset int) time.Time {
+	first := time.Date(bill.StartDate.Year(), bill.StartDate.Month()+time.Month(offset), 1, 0, 0, 0, 0, time.UTC)
+	lastDay := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(bill.DueDay, lastDay)-1)
+}
+
+// billOccurrences returns the due dates of a bill from one day to another,
+// both included
+func billOccurrences(bill *model.Bill, from, to time.Time) []time.Time {
+	step := billRecurrenceMonths(bill.Recurrence)
+	var dates []time.Time
+	for offset := 0; ; {
+		date := billDueDate(bill, offset)
+		if date.After(to) || (bill.EndDate != nil && date.After(*bill.EndDate)) {
+			break
+		}
+		if !date.Before(bill.StartDate) {
+			if !date.Before(from) {
+				dates = append(dates, date)
+			}
+			if step == 0 {
+				break
+			}
+		}
+		// A one-off bill whose due day passed in the start month falls due
+		// in the following month
+		if step == 0 {
+			offset++
+		} else {
+			offset += step
+		}
+	}
+	return dates
+}
+
+// billPreviousDue is the last occurrence due before today, if any
+func billPreviousDue(bill *model.Bill, today time.Time) *time.Time {
+	dates := billOccurrences(bill, bill.StartDate, today.AddDate(0, 0, -1))
+	if len(dates) == 0 {
+		return nil
+	}
+	return &dates[len(dates)-1]
+}
+
+// billNextUnpaid is the first unpaid occurrence due today or later, if any.
+// paid maps due dates in YYYY-MM-DD form to their payments.
+func billNextUnpaid(bill *model.Bill, paid map[string]*model.BillPayment, today time.Time) *time.Time {
+	base := today
+	if bill.StartDate.After(base) {
+		base = bill.StartDate
+	}
+	// Occurrences may be paid ahead, so the search reaches one occurrence
+	// further than there are payments
+	months := (len(paid)+1)*max(billRecurrenceMonths(bill.Recurrence), 1) + 1
+	for _, date := range billOccurrences(bill, today, addMonthsClamped(base, months)) {
+		if paid[date.Format("2006-01-02")] == nil {
+			return &date
+		}
+	}
+	return nil
+}
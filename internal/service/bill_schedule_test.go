@@ -0,0 +1,120 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tyha2404/nexo-app-api/internal/model"
+)
+
+func testBill(t *testing.T, recurrence model.BillRecurrence, dueDay int, start string) *model.Bill {
+	t.Helper()
+	return &model.Bill{Recurrence: recurrence, DueDay: dueDay, StartDate: mustDate(t, start)}
+}
+
+func formatDates(dates []time.Time) []string {
+	formatted := make([]string, 0, len(dates))
+	for _, d := range dates {
+		formatted = append(formatted, d.Format("2006-01-02"))
+	}
+	return formatted
+}
+
+func formatDate(d *time.Time) string {
+	if d == nil {
+		return "<nil>"
+	}
+	return d.Format("2006-01-02")
+}
+
+func TestBillOccurrences(t *testing.T) {
+	withEnd := testBill(t, model.BillRecurrenceMonthly, 1, "2024-01-01")
+	end := mustDate(t, "2024-03-15")
+	withEnd.EndDate = &end
+
+	tests := []struct {
+		name     string
+		bill     *model.Bill
+		from, to string
+		want     []string
+	}{
+		{"monthly clamps to the month end", testBill(t, model.BillRecurrenceMonthly, 31, "2024-01-01"), "2024-01-01", "2024-04-30",
+			[]string{"2024-01-31", "2024-02-29", "2024-03-31", "2024-04-30"}},
+		{"monthly from a later day", testBill(t, model.BillRecurrenceMonthly, 5, "2024-01-01"), "2024-03-01", "2024-04-30",
+			[]string{"2024-03-05", "2024-04-05"}},
+		{"quarterly", testBill(t, model.BillRecurrenceQuarterly, 15, "2024-01-01"), "2024-01-01", "2024-12-31",
+			[]string{"2024-01-15", "2024-04-15", "2024-07-15", "2024-10-15"}},
+		{"quarterly started after the due day", testBill(t, model.BillRecurrenceQuarterly, 15, "2024-01-20"), "2024-01-01", "2024-12-31",
+			[]string{"2024-04-15", "2024-07-15", "2024-10-15"}},
+		{"yearly on a leap day", testBill(t, model.BillRecurrenceYearly, 29, "2024-02-01"), "2024-01-01", "2026-12-31",
+			[]string{"2024-02-29", "2025-02-28", "2026-02-28"}},
+		{"one-off", testBill(t, model.BillRecurrenceOnce, 10, "2024-03-05"), "2024-01-01", "2024-12-31",
+			[]string{"2024-03-10"}},
+		{"one-off after its due day", testBill(t, model.BillRecurrenceOnce, 10, "2024-03-20"), "2024-01-01", "2024-12-31",
+			[]string{"2024-04-10"}},
+		{"ends", withEnd, "2024-01-01", "2024-12-31",
+			[]string{"2024-01-01", "2024-02-01", "2024-03-01"}},
+		{"nothing in range", testBill(t, model.BillRecurrenceMonthly, 20, "2024-01-01"), "2024-03-21", "2024-04-19",
+			[]string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatDates(billOccurrences(tt.bill, mustDate(t, tt.from), mustDate(t, tt.to)))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("billOccurrences = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBillPreviousDue(t *testing.T) {
+	bill := testBill(t, model.BillRecurrenceMonthly, 15, "2024-01-01")
+	tests := []struct {
+		today string
+		want  string
+	}{
+		{"2024-01-10", "<nil>"},
+		{"2024-03-15", "2024-02-15"},
+		{"2024-03-16", "2024-03-15"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.today, func(t *testing.T) {
+			if got := formatDate(billPreviousDue(bill, mustDate(t, tt.today))); got != tt.want {
+				t.Errorf("billPreviousDue = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBillNextUnpaid(t *testing.T) {
+	paid := func(dates ...string) map[string]*model.BillPayment {
+		payments := make(map[string]*model.BillPayment)
+		for _, d := range dates {
+			payments[d] = &model.BillPayment{DueDate: mustDate(t, d)}
+		}
+		return payments
+	}
+	tests := []struct {
+		name  string
+		bill  *model.Bill
+		paid  map[string]*model.BillPayment
+		today string
+		want  string
+	}{
+		{"next due", testBill(t, model.BillRecurrenceMonthly, 15, "2024-01-01"), nil, "2024-03-10", "2024-03-15"},
+		{"due today", testBill(t, model.BillRecurrenceMonthly, 15, "2024-01-01"), nil, "2024-03-15", "2024-03-15"},
+		{"paid ahead", testBill(t, model.BillRecurrenceMonthly, 15, "2024-01-01"), paid("2024-03-15", "2024-04-15"), "2024-03-10", "2024-05-15"},
+		{"quarterly paid ahead", testBill(t, model.BillRecurrenceQuarterly, 15, "2024-01-01"), paid("2024-04-15"), "2024-02-01", "2024-07-15"},
+		{"not started yet", testBill(t, model.BillRecurrenceMonthly, 15, "2024-06-01"), nil, "2024-03-10", "2024-06-15"},
+		{"one-off paid", testBill(t, model.BillRecurrenceOnce, 15, "2024-03-01"), paid("2024-03-15"), "2024-03-10", "<nil>"},
+		{"one-off overdue", testBill(t, model.BillRecurrenceOnce, 15, "2024-03-01"), nil, "2024-03-20", "<nil>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatDate(billNextUnpaid(tt.bill, tt.paid, mustDate(t, tt.today))); got != tt.want {
+				t.Errorf("billNextUnpaid = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,630 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/constant"
+	"github.com/tyha2404/nexo-app-api/internal/dto"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"github.com/tyha2404/nexo-app-api/internal/notify"
+	"github.com/tyha2404/nexo-app-api/internal/repository"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultBillRemindDays = 3
+	maxUpcomingBillDays   = 366
+)
+
+type BillService interface {
+	CreateBill(ctx context.Context, userID uuid.UUID, req dto.CreateBillRequest) (*dto.BillResponse, error)
+	GetBill(ctx context.Context, userID, id uuid.UUID) (*dto.BillResponse, error)
+	ListBills(ctx context.Context, userID uuid.UUID) ([]dto.BillResponse, error)
+	UpdateBill(ctx context.Context, userID, id uuid.UUID, req dto.UpdateBillRequest) (*dto.BillResponse, error)
+	// DeleteBill deletes a bill; the transactions that paid it are kept
+	DeleteBill(ctx context.Context, userID, id uuid.UUID) error
+	// Upcoming lists the occurrences due in the next days, today included,
+	// together with the last occurrence of each bill if it is overdue
+	Upcoming(ctx context.Context, userID uuid.UUID, days int) ([]dto.BillOccurrenceResponse, error)
+	// Pay marks an occurrence paid by an existing or a new expense
+	Pay(ctx context.Context, userID, billID uuid.UUID, req dto.PayBillRequest) (*dto.BillPaymentResponse, error)
+	// ListPayments returns the payments of a bill, most recent first
+	ListPayments(ctx context.Context, userID, billID uuid.UUID) ([]dto.BillPaymentResponse, error)
+	// DeletePayment marks the occurrence unpaid again; the transaction is kept
+	DeletePayment(ctx context.Context, userID, billID, id uuid.UUID) error
+	ListAlerts(ctx context.Context, userID, billID uuid.UUID) ([]dto.BillAlertResponse, error)
+	// CheckDue raises an alert and sends a notification for every unpaid
+	// bill that is due within its reminder period or overdue, once per
+	// occurrence. It returns the number of alerts raised.
+	CheckDue(ctx context.Context, now time.Time) (int, error)
+}
+
+type billService struct {
+	billRepo        repository.BillRepo
+	categoryRepo    repository.CategoryRepo
+	transactionRepo repository.TransactionRepository
+	notifier        notify.Notifier
+	log             *zap.Logger
+}
+
+func NewBillService(billRepo repository.BillRepo, categoryRepo repository.CategoryRepo, transactionRepo repository.TransactionRepository, notifier notify.Notifier, log *zap.Logger) BillService {
+	return &billService{
+		billRepo:        billRepo,
+		categoryRepo:    categoryRepo,
+		transactionRepo: transactionRepo,
+		notifier:        notifier,
+		log:             log,
+	}
+}
+
+func (s *billService) CreateBill(ctx context.Context, userID uuid.UUID, req dto.CreateBillRequest) (*dto.BillResponse, error) {
+	payee := strings.TrimSpace(req.Payee)
+	if payee == "" {
+		return nil, fmt.Errorf("%w: payee is required", constant.ErrInvalidInput)
+	}
+
+	bill := &model.Bill{
+		UserID:           userID,
+		Payee:            payee,
+		ExpectedAmount:   req.ExpectedAmount,
+		DueDay:           req.DueDay,
+		Recurrence:       model.BillRecurrence(req.Recurrence),
+		StartDate:        goalToday(time.Now()),
+		RemindDaysBefore: defaultBillRemindDays,
+		Note:             req.Note,
+	}
+	if req.StartDate != nil {
+		date, err := time.Parse("2006-01-02", *req.StartDate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: startDate must be YYYY-MM-DD", constant.ErrInvalidInput)
+		}
+		bill.StartDate = date
+	}
+	if req.EndDate != nil {
+		date, err := time.Parse("2006-01-02", *req.EndDate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: endDate must be YYYY-MM-DD", constant.ErrInvalidInput)
+		}
+		bill.EndDate = &date
+	}
+	if err := checkBillDates(bill); err != nil {
+		return nil, err
+	}
+	if req.RemindDaysBefore != nil {
+		bill.RemindDaysBefore = *req.RemindDaysBefore
+	}
+	if req.CategoryID != nil {
+		if _, err := s.expenseCategory(ctx, userID, *req.CategoryID); err != nil {
+			return nil, err
+		}
+		bill.CategoryID = req.CategoryID
+	}
+
+	if err := s.billRepo.Create(ctx, bill); err != nil {
+		return nil, err
+	}
+	return s.GetBill(ctx, userID, bill.ID)
+}
+
+func (s *billService) GetBill(ctx context.Context, userID, id uuid.UUID) (*dto.BillResponse, error) {
+	bill, err := s.getOwnedBill(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	responses, err := s.toResponses(ctx, []model.Bill{*bill}, goalToday(time.Now()))
+	if err != nil {
+		return nil, err
+	}
+	return &responses[0], nil
+}
+
+func (s *billService) ListBills(ctx context.Context, userID uuid.UUID) ([]dto.BillResponse, error) {
+	bills, err := s.billRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponses(ctx, bills, goalToday(time.Now()))
+}
+
+func (s *billService) UpdateBill(ctx context.Context, userID, id uuid.UUID, req dto.UpdateBillRequest) (*dto.BillResponse, error) {
+	bill, err := s.getOwnedBill(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Payee != nil {
+		payee := strings.TrimSpace(*req.Payee)
+		if payee == "" {
+			return nil, fmt.Errorf("%w: payee is required", constant.ErrInvalidInput)
+		}
+		bill.Payee = payee
+	}
+	if req.ExpectedAmount != nil {
+		bill.ExpectedAmount = *req.ExpectedAmount
+	}
+	if req.DueDay != nil {
+		bill.DueDay = *req.DueDay
+	}
+	if req.Recurrence != nil {
+		bill.Recurrence = model.BillRecurrence(*req.Recurrence)
+	}
+	if req.StartDate != nil {
+		date, err := time.Parse("2006-01-02", *req.StartDate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: startDate must be YYYY-MM-DD", constant.ErrInvalidInput)
+		}
+		bill.StartDate = date
+	}
+	if req.EndDate != nil {
+		if *req.EndDate == "" {
+			bill.EndDate = nil
+		} else {
+			date, err := time.Parse("2006-01-02", *req.EndDate)
+			if err != nil {
+				return nil, fmt.Errorf("%w: endDate must be YYYY-MM-DD", constant.ErrInvalidInput)
+			}
+			bill.EndDate = &date
+		}
+	}
+	if err := checkBillDates(bill); err != nil {
+		return nil, err
+	}
+	if req.RemindDaysBefore != nil {
+		bill.RemindDaysBefore = *req.RemindDaysBefore
+	}
+	if req.CategoryID.Set {
+		if req.CategoryID.Value != nil {
+			if _, err := s.expenseCategory(ctx, userID, *req.CategoryID.Value); err != nil {
+				return nil, err
+			}
+		}
+		bill.CategoryID = req.CategoryID.Value
+	}
+	if req.Note != nil {
+		bill.Note = nil
+		if *req.Note != "" {
+			bill.Note = req.Note
+		}
+	}
+
+	if err := s.billRepo.Update(ctx, bill); err != nil {
+		return nil, err
+	}
+	return s.GetBill(ctx, userID, id)
+}
+
+func (s *billService) DeleteBill(ctx context.Context, userID, id uuid.UUID) error {
+	if _, err := s.getOwnedBill(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.billRepo.Delete(ctx, id)
+}
+
+func (s *billService) Upcoming(ctx context.Context, userID uuid.UUID, days int) ([]dto.BillOccurrenceResponse, error) {
+	if days < 1 || days > maxUpcomingBillDays {
+		return nil, fmt.Errorf("%w: days must be between 1 and %d", constant.ErrInvalidInput, maxUpcomingBillDays)
+	}
+	bills, err := s.billRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	paid, err := s.paidByBill(ctx, bills)
+	if err != nil {
+		return nil, err
+	}
+
+	today := goalToday(time.Now())
+	until := today.AddDate(0, 0, days-1)
+	var occurrences []dto.BillOccurrenceResponse
+	for i := range bills {
+		bill := &bills[i]
+		if previous := billPreviousDue(bill, today); previous != nil && paid[bill.ID][previous.Format("2006-01-02")] == nil {
+			occurrences = append(occurrences, toBillOccurrence(bill, *previous, nil, today))
+		}
+		for _, date := range billOccurrences(bill, today, until) {
+			occurrences = append(occurrences, toBillOccurrence(bill, date, paid[bill.ID][date.Format("2006-01-02")], today))
+		}
+	}
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		return occurrences[i].DueDate < occurrences[j].DueDate
+	})
+	return occurrences, nil
+}
+
+func (s *billService) Pay(ctx context.Context, userID, billID uuid.UUID, req dto.PayBillRequest) (*dto.BillPaymentResponse, error) {
+	bill, err := s.getOwnedBill(ctx, userID, billID)
+	if err != nil {
+		return nil, err
+	}
+	paid, err := s.paidByBill(ctx, []model.Bill{*bill})
+	if err != nil {
+		return nil, err
+	}
+	today := goalToday(time.Now())
+
+	var dueDate time.Time
+	if req.DueDate != nil {
+		dueDate, err = time.Parse("2006-01-02", *req.DueDate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: dueDate must be YYYY-MM-DD", constant.ErrInvalidInput)
+		}
+		if len(billOccurrences(bill, dueDate, dueDate)) == 0 {
+			return nil, fmt.Errorf("%w: the bill is not due on %s", constant.ErrInvalidInput, *req.DueDate)
+		}
+	} else {
+		next := billPreviousDue(bill, today)
+		if next == nil || paid[bill.ID][next.Format("2006-01-02")] != nil {
+			next = billNextUnpaid(bill, paid[bill.ID], today)
+		}
+		if next == nil {
+			return nil, fmt.Errorf("%w: the bill has no unpaid occurrence left", constant.ErrInvalidInput)
+		}
+		dueDate = *next
+	}
+	if paid[bill.ID][dueDate.Format("2006-01-02")] != nil {
+		return nil, fmt.Errorf("%w: the bill due on %s is paid already", constant.ErrInvalidInput, dueDate.Format("2006-01-02"))
+	}
+
+	payment := &model.BillPayment{
+		UserID:  userID,
+		BillID:  billID,
+		DueDate: dueDate,
+	}
+	var transaction *model.Transaction
+	if req.TransactionID != nil {
+		existing, err := s.linkableTransaction(ctx, userID, *req.TransactionID)
+		if err != nil {
+			return nil, err
+		}
+		payment.TransactionID = existing.ID
+		payment.Transaction = existing
+	} else {
+		transaction, err = s.newPaymentTransaction(ctx, userID, bill, req, today)
+		if err != nil {
+			return nil, err
+		}
+		payment.Transaction = transaction
+	}
+
+	if err := s.billRepo.CreatePayment(ctx, payment, transaction); err != nil {
+		return nil, err
+	}
+	return toBillPaymentResponse(payment), nil
+}
+
+func (s *billService) ListPayments(ctx context.Context, userID, billID uuid.UUID) ([]dto.BillPaymentResponse, error) {
+	if _, err := s.getOwnedBill(ctx, userID, billID); err != nil {
+		return nil, err
+	}
+	payments, err := s.billRepo.ListPayments(ctx, []uuid.UUID{billID})
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.BillPaymentResponse, 0, len(payments))
+	for i := len(payments) - 1; i >= 0; i-- {
+		responses = append(responses, *toBillPaymentResponse(&payments[i]))
+	}
+	return responses, nil
+}
+
+func (s *billService) DeletePayment(ctx context.Context, userID, billID, id uuid.UUID) error {
+	if _, err := s.getOwnedBill(ctx, userID, billID); err != nil {
+		return err
+	}
+	if _, err := s.billRepo.GetPayment(ctx, billID, id); err != nil {
+		return err
+	}
+	return s.billRepo.DeletePayment(ctx, id)
+}
+
+func (s *billService) ListAlerts(ctx context.Context, userID, billID uuid.UUID) ([]dto.BillAlertResponse, error) {
+	if _, err := s.getOwnedBill(ctx, userID, billID); err != nil {
+		return nil, err
+	}
+	alerts, err := s.billRepo.ListAlerts(ctx, billID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.BillAlertResponse, 0, len(alerts))
+	for _, a := range alerts {
+		responses = append(responses, dto.BillAlertResponse{
+			ID:          a.ID.String(),
+			AlertType:   a.AlertType,
+			Message:     a.Message,
+			TriggeredAt: a.TriggeredAt.Format(time.RFC3339),
+		})
+	}
+	return responses, nil
+}
+
+func (s *billService) CheckDue(ctx context.Context, now time.Time) (int, error) {
+	today := goalToday(now)
+	bills, err := s.billRepo.ListActive(ctx, today.AddDate(0, -1, 0))
+	if err != nil {
+		return 0, err
+	}
+	paid, err := s.paidByBill(ctx, bills)
+	if err != nil {
+		return 0, err
+	}
+
+	raised := 0
+	for i := range bills {
+		bill := &bills[i]
+
+		// The last occurrence is overdue once, unless it fell due before the
+		// bill was added
+		if previous := billPreviousDue(bill, today); previous != nil && paid[bill.ID][previous.Format("2006-01-02")] == nil &&
+			!previous.Before(goalToday(bill.CreatedAt)) {
+			ok, err := s.raise(ctx, bill, model.AlertTypeBillOverdue, *previous, previous.AddDate(0, 0, 1), now)
+			if err != nil {
+				return raised, err
+			}
+			if ok {
+				raised++
+			}
+		}
+
+		// The next occurrence reminds once its reminder period started
+		dates := billOccurrences(bill, today, today.AddDate(0, 0, bill.RemindDaysBefore))
+		if len(dates) > 0 && paid[bill.ID][dates[0].Format("2006-01-02")] == nil {
+			ok, err := s.raise(ctx, bill, model.AlertTypeBillDue, dates[0], dates[0].AddDate(0, 0, -bill.RemindDaysBefore), now)
+			if err != nil {
+				return raised, err
+			}
+			if ok {
+				raised++
+			}
+		}
+	}
+	return raised, nil
+}
+
+// raise creates an alert about the occurrence due on dueDate and notifies the
+// user, unless the bill raised one of the type since the given day
+func (s *billService) raise(ctx context.Context, bill *model.Bill, alertType string, dueDate, since, now time.Time) (bool, error) {
+	last, err := s.billRepo.LastAlertAt(ctx, bill.ID, alertType)
+	if err != nil {
+		return false, err
+	}
+	if last != nil && !last.UTC().Before(since) {
+		return false, nil
+	}
+
+	alert := &model.Alert{
+		UserID:      bill.UserID,
+		BillID:      &bill.ID,
+		AlertType:   alertType,
+		TriggeredAt: now,
+		Message:     billAlertMessage(bill, alertType, dueDate),
+	}
+	if err := s.billRepo.CreateAlert(ctx, alert); err != nil {
+		return false, err
+	}
+
+	title := "Bill due"
+	if alertType == model.AlertTypeBillOverdue {
+		title = "Bill overdue"
+	}
+	// The alert is kept even if the notification fails to go out
+	if err := s.notifier.Notify(ctx, notify.Notification{UserID: bill.UserID, Title: title, Message: alert.Message}); err != nil {
+		s.log.Error("failed to send bill notification",
+			zap.String("billId", bill.ID.String()), zap.String("alertType", alertType), zap.Error(err))
+	}
+	return true, nil
+}
+
+// paidByBill maps each bill to its payments by due date
+func (s *billService) paidByBill(ctx context.Context, bills []model.Bill) (map[uuid.UUID]map[string]*model.BillPayment, error) {
+	ids := make([]uuid.UUID, 0, len(bills))
+	for _, b := range bills {
+		ids = append(ids, b.ID)
+	}
+	payments, err := s.billRepo.ListPayments(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	paid := make(map[uuid.UUID]map[string]*model.BillPayment, len(bills))
+	for i := range payments {
+		p := &payments[i]
+		if paid[p.BillID] == nil {
+			paid[p.BillID] = make(map[string]*model.BillPayment)
+		}
+		paid[p.BillID][p.DueDate.Format("2006-01-02")] = p
+	}
+	return paid, nil
+}
+
+func (s *billService) toResponses(ctx context.Context, bills []model.Bill, today time.Time) ([]dto.BillResponse, error) {
+	paid, err := s.paidByBill(ctx, bills)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.BillResponse, 0, len(bills))
+	for i := range bills {
+		bill := &bills[i]
+		resp := toBillResponse(bill)
+		if next := billNextUnpaid(bill, paid[bill.ID], today); next != nil {
+			date := next.Format("2006-01-02")
+			resp.NextDueDate = &date
+		}
+		if previous := billPreviousDue(bill, today); previous != nil && paid[bill.ID][previous.Format("2006-01-02")] == nil {
+			date := previous.Format("2006-01-02")
+			resp.OverdueDate = &date
+		}
+		responses = append(responses, resp)
+	}
+	return responses, nil
+}
+
+// linkableTransaction returns the user's expense if no bill is paid with it
+// yet
+func (s *billService) linkableTransaction(ctx context.Context, userID, id uuid.UUID) (*model.Transaction, error) {
+	transaction, err := s.transactionRepo.GetByID(ctx, id)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if transaction == nil || transaction.UserID != userID {
+		return nil, fmt.Errorf("%w: transaction %s not found", constant.ErrInvalidInput, id)
+	}
+	if transaction.Type != model.TransactionTypeExpense {
+		return nil, fmt.Errorf("%w: a bill can only be paid by an expense", constant.ErrInvalidInput)
+	}
+	linked, err := s.billRepo.TransactionLinked(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if linked {
+		return nil, fmt.Errorf("%w: transaction %s already pays a bill", constant.ErrInvalidInput, id)
+	}
+	return transaction, nil
+}
+
+// newPaymentTransaction builds the expense booked for a payment
+func (s *billService) newPaymentTransaction(ctx context.Context, userID uuid.UUID, bill *model.Bill, req dto.PayBillRequest, today time.Time) (*model.Transaction, error) {
+	categoryID := bill.CategoryID
+	if req.CategoryID != nil {
+		categoryID = req.CategoryID
+	}
+	if categoryID == nil {
+		return nil, fmt.Errorf("%w: categoryId is required when the bill has no category", constant.ErrInvalidInput)
+	}
+	category, err := s.expenseCategory(ctx, userID, *categoryID)
+	if err != nil {
+		return nil, err
+	}
+	if category.ArchivedAt != nil {
+		return nil, fmt.Errorf("%w: category %q is archived", constant.ErrInvalidInput, category.Name)
+	}
+
+	transaction := &model.Transaction{
+		UserID:          userID,
+		CategoryID:      category.ID,
+		Amount:          bill.ExpectedAmount,
+		Type:            model.TransactionTypeExpense,
+		Description:     req.Description,
+		TransactionDate: today,
+	}
+	if req.Amount != nil {
+		transaction.Amount = *req.Amount
+	}
+	if req.PaidOn != nil {
+		transaction.TransactionDate, err = time.Parse("2006-01-02", *req.PaidOn)
+		if err != nil {
+			return nil, fmt.Errorf("%w: paidOn must be YYYY-MM-DD", constant.ErrInvalidInput)
+		}
+	}
+	if transaction.Description == nil {
+		payee := bill.Payee
+		transaction.Description = &payee
+	}
+	return transaction, nil
+}
+
+// expenseCategory returns the user's category if expenses can be booked in it
+func (s *billService) expenseCategory(ctx context.Context, userID, categoryID uuid.UUID) (*model.Category, error) {
+	category, err := s.categoryRepo.GetByID(ctx, categoryID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if category == nil || category.UserID != userID {
+		return nil, fmt.Errorf("%w: category %s not found", constant.ErrInvalidInput, categoryID)
+	}
+	if err := checkCategoryKind(category, model.TransactionTypeExpense); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+func (s *billService) getOwnedBill(ctx context.Context, userID, id uuid.UUID) (*model.Bill, error) {
+	bill, err := s.billRepo.GetByID(ctx, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, constant.ErrNotFound
+		}
+		return nil, err
+	}
+	if bill.UserID != userID {
+		return nil, constant.ErrNotFound
+	}
+	return bill, nil
+}
+
+func checkBillDates(bill *model.Bill) error {
+	if bill.EndDate != nil && bill.EndDate.Before(bill.StartDate) {
+		return fmt.Errorf("%w: endDate must not be before startDate", constant.ErrInvalidInput)
+	}
+	return nil
+}
+
+func billAlertMessage(bill *model.Bill, alertType string, dueDate time.Time) string {
+	if alertType == model.AlertTypeBillOverdue {
+		return fmt.Sprintf("%s (%.2f) was due on %s and is not marked paid.", bill.Payee, bill.ExpectedAmount, dueDate.Format("2006-01-02"))
+	}
+	return fmt.Sprintf("%s (%.2f) is due on %s.", bill.Payee, bill.ExpectedAmount, dueDate.Format("2006-01-02"))
+}
+
+func toBillOccurrence(bill *model.Bill, dueDate time.Time, payment *model.BillPayment, today time.Time) dto.BillOccurrenceResponse {
+	occurrence := dto.BillOccurrenceResponse{
+		BillID:         bill.ID.String(),
+		Payee:          bill.Payee,
+		ExpectedAmount: bill.ExpectedAmount,
+		DueDate:        dueDate.Format("2006-01-02"),
+		DaysUntilDue:   int(dueDate.Sub(today).Hours() / 24),
+		Status:         billStatusDue,
+	}
+	switch {
+	case payment != nil:
+		occurrence.Status = billStatusPaid
+		occurrence.Payment = toBillPaymentResponse(payment)
+	case dueDate.Before(today):
+		occurrence.Status = billStatusOverdue
+	}
+	return occurrence
+}
+
+func toBillResponse(b *model.Bill) dto.BillResponse {
+	resp := dto.BillResponse{
+		ID:               b.ID.String(),
+		Payee:            b.Payee,
+		ExpectedAmount:   b.ExpectedAmount,
+		DueDay:           b.DueDay,
+		Recurrence:       string(b.Recurrence),
+		StartDate:        b.StartDate.Format("2006-01-02"),
+		RemindDaysBefore: b.RemindDaysBefore,
+		Note:             b.Note,
+		CreatedAt:        b.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:        b.UpdatedAt.Format(time.RFC3339),
+	}
+	if b.EndDate != nil {
+		endDate := b.EndDate.Format("2006-01-02")
+		resp.EndDate = &endDate
+	}
+	if b.CategoryID != nil {
+		id := b.CategoryID.String()
+		resp.CategoryID = &id
+	}
+	return resp
+}
+
+func toBillPaymentResponse(p *model.BillPayment) *dto.BillPaymentResponse {
+	return &dto.BillPaymentResponse{
+		ID:            p.ID.String(),
+		DueDate:       p.DueDate.Format("2006-01-02"),
+		TransactionID: p.TransactionID.String(),
+		Amount:        p.Transaction.Amount,
+		PaidOn:        p.Transaction.TransactionDate.Format("2006-01-02"),
+		Description:   p.Transaction.Description,
+	}
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"github.com/tyha2404/nexo-app-api/internal/repository"
+)
+
+// ruleEngine runs categorization rules over transactions in priority order.
+// The first matching rule that sets the category or the description wins;
+// tags are collected from every matching rule. A rule whose category is
+// archived or meant for the other type does not match.
+type ruleEngine struct {
+	rules []compiledRule
+}
+
+type compiledRule struct {
+	*model.CategorizationRule
+	contains string
+	pattern  *regexp.Regexp
+}
+
+// ruleOutcome is what the matching rules assign to a transaction. Category is
+// nil when no rule sets one or its category is in the trash.
+type ruleOutcome struct {
+	Category    *model.Category
+	Description *string
+	Tags        []model.Tag
+	RuleIDs     []uuid.UUID
+}
+
+// loadRuleEngine prepares the user's enabled rules
+func loadRuleEngine(ctx context.Context, ruleRepo repository.CategorizationRuleRepo, userID uuid.UUID) (*ruleEngine, error) {
+	rules, err := ruleRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	enabled := rules[:0]
+	for _, r := range rules {
+		if r.Enabled {
+			enabled = append(enabled, r)
+		}
+	}
+	return newRuleEngine(enabled), nil
+}
+
+// newRuleEngine compiles rules that are already in priority order. Rules
+// with a pattern that does not compile are left out; they are rejected when
+// saved, so this only guards against rows changed behind the API's back.
+func newRuleEngine(rules []model.CategorizationRule) *ruleEngine {
+	engine := &ruleEngine{rules: make([]compiledRule, 0, len(rules))}
+	for i := range rules {
+		c := compiledRule{CategorizationRule: &rules[i]}
+		if rules[i].DescriptionContains != nil {
+			c.contains = strings.ToLower(*rules[i].DescriptionContains)
+		}
+		if rules[i].DescriptionPattern != nil {
+			pattern, err := compileRulePattern(*rules[i].DescriptionPattern)
+			if err != nil {
+				continue
+			}
+			c.pattern = pattern
+		}
+		engine.rules = append(engine.rules, c)
+	}
+	return engine
+}
+
+// compileRulePattern compiles a description pattern, which ignores case
+func compileRulePattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("(?i)" + pattern)
+}
+
+// evaluate runs the rules against t without changing it
+func (e *ruleEngine) evaluate(t *model.Transaction) ruleOutcome {
+	var outcome ruleOutcome
+	seenTags := make(map[uuid.UUID]bool)
+	for _, r := range e.rules {
+		if !r.matches(t) {
+			continue
+		}
+		outcome.RuleIDs = append(outcome.RuleIDs, r.ID)
+		if outcome.Category == nil && r.SetCategory != nil {
+			outcome.Category = r.SetCategory
+		}
+		if outcome.Description == nil && r.SetDescription != nil {
+			outcome.Description = r.SetDescription
+		}
+		for _, tag := range r.AddTags {
+			if !seenTags[tag.ID] {
+				seenTags[tag.ID] = true
+				outcome.Tags = append(outcome.Tags, tag)
+			}
+		}
+		if r.StopProcessing {
+			break
+		}
+	}
+	return outcome
+}
+
+func (r *compiledRule) matches(t *model.Transaction) bool {
+	if r.Type != nil && *r.Type != t.Type {
+		return false
+	}
+	if r.SetCategory != nil && checkNewCategory(r.SetCategory, t.Type) != nil {
+		return false
+	}
+	if r.MinAmount != nil && t.Amount < *r.MinAmount {
+		return false
+	}
+	if r.MaxAmount != nil && t.Amount > *r.MaxAmount {
+		return false
+	}
+	if r.AccountID != nil && (t.AccountID == nil || !strings.EqualFold(*t.AccountID, *r.AccountID)) {
+		return false
+	}
+	if r.contains != "" || r.pattern != nil {
+		if t.Description == nil {
+			return false
+		}
+		if r.contains != "" && !strings.Contains(strings.ToLower(*t.Description), r.contains) {
+			return false
+		}
+		if r.pattern != nil && !r.pattern.MatchString(*t.Description) {
+			return false
+		}
+	}
+	return true
+}
+
+// applyTo copies the outcome onto a new transaction. The category is only
+// replaced when setCategory is true, so a category chosen explicitly stays.
+// Only CategoryID is set; the Category association is left to the caller so
+// that creating the transaction does not try to save the category again.
+func (o *ruleOutcome) applyTo(t *model.Transaction, setCategory bool) {
+	if setCategory && o.Category != nil {
+		t.CategoryID = o.Category.ID
+	}
+	if o.Description != nil {
+		description := *o.Description
+		t.Description = &description
+	}
+	for _, tag := range o.Tags {
+		if !hasTag(t.Tags, tag.ID) {
+			t.Tags = append(t.Tags, tag)
+		}
+	}
+}
+
+func hasTag(tags []model.Tag, id uuid.UUID) bool {
+	for _, tag := range tags {
+		if tag.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,349 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/constant"
+	"github.com/tyha2404/nexo-app-api/internal/dto"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"github.com/tyha2404/nexo-app-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+// maxRuleChangesListed caps the changes returned by an apply run; the counts
+// always cover every affected transaction
+const maxRuleChangesListed = 500
+
+type CategorizationRuleService interface {
+	CreateRule(ctx context.Context, userID uuid.UUID, req dto.CategorizationRuleRequest) (*dto.CategorizationRuleResponse, error)
+	GetRule(ctx context.Context, userID, id uuid.UUID) (*dto.CategorizationRuleResponse, error)
+	ListRules(ctx context.Context, userID uuid.UUID) ([]dto.CategorizationRuleResponse, error)
+	UpdateRule(ctx context.Context, userID, id uuid.UUID, req dto.CategorizationRuleRequest) (*dto.CategorizationRuleResponse, error)
+	DeleteRule(ctx context.Context, userID, id uuid.UUID) error
+	// ApplyRules runs rules over existing transactions, previewing the
+	// changes or writing them
+	ApplyRules(ctx context.Context, userID uuid.UUID, req dto.ApplyRulesRequest) (*dto.ApplyRulesResponse, error)
+}
+
+type categorizationRuleService struct {
+	ruleRepo     repository.CategorizationRuleRepo
+	categoryRepo repository.CategoryRepo
+	tagRepo      repository.TagRepo
+	suggester    CategorySuggester
+}
+
+func NewCategorizationRuleService(ruleRepo repository.CategorizationRuleRepo, categoryRepo repository.CategoryRepo, tagRepo repository.TagRepo, suggester CategorySuggester) CategorizationRuleService {
+	return &categorizationRuleService{
+		ruleRepo:     ruleRepo,
+		categoryRepo: categoryRepo,
+		tagRepo:      tagRepo,
+		suggester:    suggester,
+	}
+}
+
+func (s *categorizationRuleService) CreateRule(ctx context.Context, userID uuid.UUID, req dto.CategorizationRuleRequest) (*dto.CategorizationRuleResponse, error) {
+	rule := &model.CategorizationRule{UserID: userID}
+	tags, err := s.fillRule(ctx, rule, req)
+	if err != nil {
+		return nil, err
+	}
+	rule.AddTags = tags
+
+	if err := s.ruleRepo.Create(ctx, rule); err != nil {
+		return nil, err
+	}
+	return s.GetRule(ctx, userID, rule.ID)
+}
+
+func (s *categorizationRuleService) GetRule(ctx context.Context, userID, id uuid.UUID) (*dto.CategorizationRuleResponse, error) {
+	rule, err := s.getOwnedRule(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return toCategorizationRuleResponse(rule), nil
+}
+
+func (s *categorizationRuleService) ListRules(ctx context.Context, userID uuid.UUID) ([]dto.CategorizationRuleResponse, error) {
+	rules, err := s.ruleRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.CategorizationRuleResponse, 0, len(rules))
+	for i := range rules {
+		responses = append(responses, *toCategorizationRuleResponse(&rules[i]))
+	}
+	return responses, nil
+}
+
+func (s *categorizationRuleService) UpdateRule(ctx context.Context, userID, id uuid.UUID, req dto.CategorizationRuleRequest) (*dto.CategorizationRuleResponse, error) {
+	rule, err := s.getOwnedRule(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := s.fillRule(ctx, rule, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Associations are written separately below
+	rule.SetCategory = nil
+	rule.AddTags = nil
+	if err := s.ruleRepo.Update(ctx, rule); err != nil {
+		return nil, err
+	}
+	if err := s.ruleRepo.ReplaceTags(ctx, rule, tags); err != nil {
+		return nil, err
+	}
+	return s.GetRule(ctx, userID, id)
+}
+
+func (s *categorizationRuleService) DeleteRule(ctx context.Context, userID, id uuid.UUID) error {
+	if _, err := s.getOwnedRule(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.ruleRepo.Delete(ctx, id)
+}
+
+func (s *categorizationRuleService) ApplyRules(ctx context.Context, userID uuid.UUID, req dto.ApplyRulesRequest) (*dto.ApplyRulesResponse, error) {
+	from, err := parseOptionalDate(req.From, "from")
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseOptionalDate(req.To, "to")
+	if err != nil {
+		return nil, err
+	}
+	if from != nil && to != nil && from.After(*to) {
+		return nil, fmt.Errorf("%w: from must not be after to", constant.ErrInvalidInput)
+	}
+
+	rules, err := s.ruleRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	rules, err = selectRules(rules, req.RuleIDs)
+	if err != nil {
+		return nil, err
+	}
+	engine := newRuleEngine(rules)
+
+	transactions, err := s.ruleRepo.ListTransactions(ctx, userID, from, to, req.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	dryRun := true
+	if req.DryRun != nil {
+		dryRun = *req.DryRun
+	}
+	result := &dto.ApplyRulesResponse{
+		DryRun:  dryRun,
+		Scanned: len(transactions),
+		Changes: []dto.RuleChangeResponse{},
+	}
+
+	var changes []repository.RuleChange
+	for i := range transactions {
+		t := &transactions[i]
+		outcome := engine.evaluate(t)
+		change, resp, ok := diffRuleOutcome(t, &outcome)
+		if !ok {
+			continue
+		}
+		changes = append(changes, change)
+		result.Affected++
+		if len(result.Changes) < maxRuleChangesListed {
+			result.Changes = append(result.Changes, resp)
+		} else {
+			result.Truncated = true
+		}
+	}
+
+	if dryRun {
+		return result, nil
+	}
+	if err := s.ruleRepo.ApplyChanges(ctx, changes); err != nil {
+		return nil, err
+	}
+	// Recategorized transactions change what suggestions learn from
+	s.suggester.Invalidate(userID)
+	return result, nil
+}
+
+// fillRule validates req and copies it onto rule. It returns the tags the
+// rule adds, which the caller stores.
+func (s *categorizationRuleService) fillRule(ctx context.Context, rule *model.CategorizationRule, req dto.CategorizationRuleRequest) ([]model.Tag, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", constant.ErrInvalidInput)
+	}
+	if req.SetCategoryID == nil && req.SetDescription == nil && len(req.AddTagIDs) == 0 {
+		return nil, fmt.Errorf("%w: a rule needs setCategoryId, setDescription or addTagIds", constant.ErrInvalidInput)
+	}
+	if req.DescriptionPattern != nil {
+		if _, err := compileRulePattern(*req.DescriptionPattern); err != nil {
+			return nil, fmt.Errorf("%w: descriptionPattern is not a valid regular expression: %v", constant.ErrInvalidInput, err)
+		}
+	}
+	if req.MinAmount != nil && req.MaxAmount != nil && *req.MinAmount > *req.MaxAmount {
+		return nil, fmt.Errorf("%w: minAmount must not be greater than maxAmount", constant.ErrInvalidInput)
+	}
+
+	if req.SetCategoryID != nil {
+		category, err := s.categoryRepo.GetByID(ctx, *req.SetCategoryID)
+		if err != nil || category.UserID != rule.UserID {
+			return nil, fmt.Errorf("%w: category not found", constant.ErrInvalidInput)
+		}
+	}
+	tags, err := resolveTags(ctx, s.tagRepo, rule.UserID, req.AddTagIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	rule.Name = name
+	rule.Priority = req.Priority
+	rule.Enabled = req.Enabled == nil || *req.Enabled
+	rule.DescriptionContains = req.DescriptionContains
+	rule.DescriptionPattern = req.DescriptionPattern
+	rule.MinAmount = req.MinAmount
+	rule.MaxAmount = req.MaxAmount
+	rule.AccountID = req.AccountID
+	rule.Type = nil
+	if req.Type != nil {
+		txType := model.TransactionType(*req.Type)
+		rule.Type = &txType
+	}
+	rule.SetCategoryID = req.SetCategoryID
+	rule.SetDescription = req.SetDescription
+	rule.StopProcessing = req.StopProcessing
+	return tags, nil
+}
+
+// getOwnedRule loads a rule and hides it if it belongs to another user
+func (s *categorizationRuleService) getOwnedRule(ctx context.Context, userID, id uuid.UUID) (*model.CategorizationRule, error) {
+	rule, err := s.ruleRepo.GetByID(ctx, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, constant.ErrNotFound
+		}
+		return nil, err
+	}
+	if rule.UserID != userID {
+		return nil, constant.ErrNotFound
+	}
+	return rule, nil
+}
+
+// selectRules keeps the rules listed in ids, or the enabled rules when ids
+// is empty, in their original order
+func selectRules(rules []model.CategorizationRule, ids []uuid.UUID) ([]model.CategorizationRule, error) {
+	wanted := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+
+	selected := make([]model.CategorizationRule, 0, len(rules))
+	for _, r := range rules {
+		if (len(ids) == 0 && r.Enabled) || wanted[r.ID] {
+			selected = append(selected, r)
+			delete(wanted, r.ID)
+		}
+	}
+	if len(wanted) > 0 {
+		return nil, fmt.Errorf("%w: unknown rule", constant.ErrInvalidInput)
+	}
+	return selected, nil
+}
+
+// parseOptionalDate parses a YYYY-MM-DD request field that may be left out
+func parseOptionalDate(value *string, field string) (*time.Time, error) {
+	if value == nil {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", *value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s must be YYYY-MM-DD", constant.ErrInvalidInput, field)
+	}
+	return &date, nil
+}
+
+// diffRuleOutcome compares what the rules assign with a stored transaction.
+// It reports false when nothing would change.
+func diffRuleOutcome(t *model.Transaction, o *ruleOutcome) (repository.RuleChange, dto.RuleChangeResponse, bool) {
+	change := repository.RuleChange{TransactionID: t.ID}
+	resp := dto.RuleChangeResponse{
+		TransactionID:   t.ID.String(),
+		TransactionDate: t.TransactionDate.Format("2006-01-02"),
+		Amount:          t.Amount,
+		Type:            string(t.Type),
+		Description:     t.Description,
+		CategoryID:      t.CategoryID.String(),
+	}
+	if t.Category != nil {
+		resp.CategoryName = t.Category.Name
+	}
+
+	if o.Category != nil && o.Category.ID != t.CategoryID {
+		id := o.Category.ID.String()
+		change.CategoryID = &o.Category.ID
+		resp.NewCategoryID = &id
+		resp.NewCategoryName = &o.Category.Name
+	}
+	if o.Description != nil && (t.Description == nil || *t.Description != *o.Description) {
+		change.Description = o.Description
+		resp.NewDescription = o.Description
+	}
+	for _, tag := range o.Tags {
+		if !hasTag(t.Tags, tag.ID) {
+			change.AddTagIDs = append(change.AddTagIDs, tag.ID)
+			resp.AddedTagIDs = append(resp.AddedTagIDs, tag.ID.String())
+		}
+	}
+	if change.CategoryID == nil && change.Description == nil && len(change.AddTagIDs) == 0 {
+		return change, resp, false
+	}
+
+	resp.RuleIDs = make([]string, 0, len(o.RuleIDs))
+	for _, id := range o.RuleIDs {
+		resp.RuleIDs = append(resp.RuleIDs, id.String())
+	}
+	return change, resp, true
+}
+
+func toCategorizationRuleResponse(r *model.CategorizationRule) *dto.CategorizationRuleResponse {
+	resp := &dto.CategorizationRuleResponse{
+		ID:                  r.ID.String(),
+		Name:                r.Name,
+		Priority:            r.Priority,
+		Enabled:             r.Enabled,
+		DescriptionContains: r.DescriptionContains,
+		DescriptionPattern:  r.DescriptionPattern,
+		MinAmount:           r.MinAmount,
+		MaxAmount:           r.MaxAmount,
+		AccountID:           r.AccountID,
+		SetDescription:      r.SetDescription,
+		StopProcessing:      r.StopProcessing,
+		CreatedAt:           r.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:           r.UpdatedAt.Format(time.RFC3339),
+	}
+	if r.Type != nil {
+		txType := string(*r.Type)
+		resp.Type = &txType
+	}
+	if r.SetCategoryID != nil {
+		id := r.SetCategoryID.String()
+		resp.SetCategoryID = &id
+	}
+	if r.SetCategory != nil {
+		resp.SetCategoryName = &r.SetCategory.Name
+	}
+	for i := range r.AddTags {
+		resp.AddTags = append(resp.AddTags, *toTagResponse(&r.AddTags[i]))
+	}
+	return resp
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+)
+
+func TestRuleEngineCategory(t *testing.T) {
+	expenseKind := model.TransactionTypeExpense
+	archivedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	groceries := &model.Category{ID: uuid.New(), Name: "Groceries", Kind: &expenseKind}
+	archived := &model.Category{ID: uuid.New(), Name: "Old groceries", ArchivedAt: &archivedAt}
+	anything := &model.Category{ID: uuid.New(), Name: "Misc"}
+
+	rule := func(category *model.Category) model.CategorizationRule {
+		return model.CategorizationRule{ID: uuid.New(), Enabled: true, DescriptionContains: strPtr("rewe"), SetCategoryID: &category.ID, SetCategory: category}
+	}
+	tests := []struct {
+		name   string
+		rules  []model.CategorizationRule
+		txType model.TransactionType
+		want   *model.Category
+	}{
+		{"kind matches", []model.CategorizationRule{rule(groceries)}, model.TransactionTypeExpense, groceries},
+		{"refund skips an expense category", []model.CategorizationRule{rule(groceries), rule(anything)}, model.TransactionTypeIncome, anything},
+		{"archived category is skipped", []model.CategorizationRule{rule(archived), rule(groceries)}, model.TransactionTypeExpense, groceries},
+		{"no rule fits", []model.CategorizationRule{rule(groceries)}, model.TransactionTypeIncome, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := testTransaction(t, tt.txType, 12.5, "2024-03-10", "REWE 1234")
+			outcome := newRuleEngine(tt.rules).evaluate(&tx)
+			if outcome.Category != tt.want {
+				t.Errorf("category = %v, want %v", outcome.Category, tt.want)
+			}
+			if tt.want == nil && len(outcome.RuleIDs) != 0 {
+				t.Errorf("rules %v matched, want none", outcome.RuleIDs)
+			}
+		})
+	}
+}
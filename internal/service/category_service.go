@@ -1,20 +1,276 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/constant"
+	"github.com/tyha2404/nexo-app-api/internal/dto"
 	"github.com/tyha2404/nexo-app-api/internal/model"
 	"github.com/tyha2404/nexo-app-api/internal/repository"
+	"gorm.io/gorm"
 )
 
 type CategoryService interface {
 	BaseService[model.Category]
+	// CheckParent validates nesting the user's category id under parentID.
+	// id is uuid.Nil for a category about to be created.
+	CheckParent(ctx context.Context, userID, id, parentID uuid.UUID) error
+	// UpdateCategory applies column updates to the user's category. A kind
+	// is only accepted while the category holds no transactions of the other
+	// type.
+	UpdateCategory(ctx context.Context, userID, id uuid.UUID, updates map[string]interface{}) (*model.Category, error)
+	// DeleteCategory deletes the user's category; its subcategories move up
+	// to its parent
+	DeleteCategory(ctx context.Context, userID, id uuid.UUID) error
+	// Tree lists the user's categories nested under their parents. Archived
+	// categories are left out unless includeArchived is set.
+	Tree(ctx context.Context, userID uuid.UUID, includeArchived bool) ([]dto.CategoryTreeResponse, error)
+	// ListCategories returns a page of the user's categories and their total
+	// number. Archived categories are left out unless includeArchived is set.
+	ListCategories(ctx context.Context, userID uuid.UUID, includeArchived bool, limit, offset int) ([]model.Category, int64, error)
+	// Archive hides the category and its subcategories from pickers
+	Archive(ctx context.Context, userID, id uuid.UUID) (*model.Category, error)
+	// Unarchive brings back the category with its subcategories and any
+	// archived parents
+	Unarchive(ctx context.Context, userID, id uuid.UUID) (*model.Category, error)
+	// MergeInto moves everything filed under the category onto the target
+	// and deletes the category
+	MergeInto(ctx context.Context, userID, id, targetID uuid.UUID) (*dto.CategoryMergeResponse, error)
 }
 
 type categoryService struct {
 	*BaseServiceImpl[model.Category]
+	categoryRepo repository.CategoryRepo
+	suggester    CategorySuggester
 }
 
-func NewCategoryService(repo repository.CategoryRepo) CategoryService {
+func NewCategoryService(repo repository.CategoryRepo, suggester CategorySuggester) CategoryService {
 	return &categoryService{
 		BaseServiceImpl: NewBaseService(repo),
+		categoryRepo:    repo,
+		suggester:       suggester,
+	}
+}
+
+func (s *categoryService) CheckParent(ctx context.Context, userID, id, parentID uuid.UUID) error {
+	if id != uuid.Nil {
+		if _, err := s.getOwnedCategory(ctx, userID, id); err != nil {
+			return err
+		}
+	}
+
+	forest, err := loadCategoryForest(ctx, s.categoryRepo, userID)
+	if err != nil {
+		return err
+	}
+	return forest.checkParent(id, parentID)
+}
+
+func (s *categoryService) UpdateCategory(ctx context.Context, userID, id uuid.UUID, updates map[string]interface{}) (*model.Category, error) {
+	category, err := s.getOwnedCategory(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if kind, ok := updates["kind"].(string); ok && (category.Kind == nil || string(*category.Kind) != kind) {
+		other := model.TransactionTypeIncome
+		if kind == string(model.TransactionTypeIncome) {
+			other = model.TransactionTypeExpense
+		}
+		count, err := s.categoryRepo.CountTransactions(ctx, id, other)
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			return nil, fmt.Errorf("%w: category %q has %d %s transactions; move them before changing its kind",
+				constant.ErrInvalidInput, category.Name, count, strings.ToLower(string(other)))
+		}
+	}
+
+	if err := s.categoryRepo.UpdateFields(ctx, id, updates); err != nil {
+		return nil, err
+	}
+	return s.categoryRepo.GetByID(ctx, id)
+}
+
+func (s *categoryService) DeleteCategory(ctx context.Context, userID, id uuid.UUID) error {
+	if _, err := s.getOwnedCategory(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.categoryRepo.Delete(ctx, id)
+}
+
+func (s *categoryService) Tree(ctx context.Context, userID uuid.UUID, includeArchived bool) ([]dto.CategoryTreeResponse, error) {
+	forest, err := loadCategoryForest(ctx, s.categoryRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var build func(id uuid.UUID, depth int) dto.CategoryTreeResponse
+	build = func(id uuid.UUID, depth int) dto.CategoryTreeResponse {
+		c := forest.byID[id]
+		node := dto.CategoryTreeResponse{
+			ID:          c.ID.String(),
+			Name:        c.Name,
+			Description: c.Description,
+			Kind:        categoryKindString(c.Kind),
+			Icon:        c.Icon,
+			Color:       c.Color,
+			SortOrder:   c.SortOrder,
+			Archived:    c.ArchivedAt != nil,
+			Depth:       depth,
+			Children:    []dto.CategoryTreeResponse{},
+		}
+		if parentID := forest.parentOf(id); parentID != uuid.Nil {
+			parent := parentID.String()
+			node.ParentID = &parent
+		}
+		for _, child := range forest.children[id] {
+			if includeArchived || forest.byID[child].ArchivedAt == nil {
+				node.Children = append(node.Children, build(child, depth+1))
+			}
+		}
+		return node
+	}
+
+	tree := make([]dto.CategoryTreeResponse, 0, len(forest.roots))
+	for _, id := range forest.roots {
+		if includeArchived || forest.byID[id].ArchivedAt == nil {
+			tree = append(tree, build(id, 0))
+		}
+	}
+	return tree, nil
+}
+
+func (s *categoryService) ListCategories(ctx context.Context, userID uuid.UUID, includeArchived bool, limit, offset int) ([]model.Category, int64, error) {
+	return s.categoryRepo.ListPage(ctx, userID, includeArchived, limit, offset)
+}
+
+func (s *categoryService) Archive(ctx context.Context, userID, id uuid.UUID) (*model.Category, error) {
+	if _, err := s.getOwnedCategory(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	forest, err := loadCategoryForest(ctx, s.categoryRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uuid.UUID
+	for _, c := range forest.subtree(id) {
+		if forest.byID[c].ArchivedAt == nil {
+			ids = append(ids, c)
+		}
+	}
+	now := time.Now()
+	if err := s.categoryRepo.SetArchived(ctx, ids, &now); err != nil {
+		return nil, err
+	}
+	return s.getOwnedCategory(ctx, userID, id)
+}
+
+func (s *categoryService) Unarchive(ctx context.Context, userID, id uuid.UUID) (*model.Category, error) {
+	if _, err := s.getOwnedCategory(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	forest, err := loadCategoryForest(ctx, s.categoryRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A subcategory is only visible below a visible parent
+	candidates := forest.subtree(id)
+	for parentID := forest.parentOf(id); parentID != uuid.Nil && !forest.isAncestor(id, parentID); parentID = forest.parentOf(parentID) {
+		candidates = append(candidates, parentID)
+	}
+	var ids []uuid.UUID
+	for _, c := range candidates {
+		if forest.byID[c].ArchivedAt != nil {
+			ids = append(ids, c)
+		}
+	}
+	if err := s.categoryRepo.SetArchived(ctx, ids, nil); err != nil {
+		return nil, err
+	}
+	return s.getOwnedCategory(ctx, userID, id)
+}
+
+func (s *categoryService) MergeInto(ctx context.Context, userID, id, targetID uuid.UUID) (*dto.CategoryMergeResponse, error) {
+	if id == targetID {
+		return nil, fmt.Errorf("%w: a category cannot be merged into itself", constant.ErrInvalidInput)
+	}
+	source, err := s.getOwnedCategory(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.getOwnedCategory(ctx, userID, targetID)
+	if err != nil {
+		if errors.Is(err, constant.ErrNotFound) {
+			return nil, fmt.Errorf("%w: target category %s not found", constant.ErrInvalidInput, targetID)
+		}
+		return nil, err
+	}
+	if target.ArchivedAt != nil {
+		return nil, fmt.Errorf("%w: target category %q is archived", constant.ErrInvalidInput, target.Name)
+	}
+
+	forest, err := loadCategoryForest(ctx, s.categoryRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+	merge := repository.CategoryMerge{SourceID: source.ID, TargetID: target.ID}
+	// A target below the source takes the source's place in the tree
+	if forest.isAncestor(source.ID, target.ID) {
+		merge.ReparentTarget = true
+		merge.TargetParentID = source.ParentID
+	}
+
+	result, err := s.categoryRepo.Merge(ctx, merge)
+	if err != nil {
+		return nil, err
+	}
+	if result.Transactions > 0 {
+		s.suggester.Invalidate(userID)
+	}
+
+	return &dto.CategoryMergeResponse{
+		TargetID:      target.ID.String(),
+		Transactions:  result.Transactions,
+		Costs:         result.Costs,
+		Budgets:       result.Budgets,
+		Expenses:      result.Expenses,
+		Rules:         result.Rules,
+		Goals:         result.Goals,
+		Loans:         result.Loans,
+		Bills:         result.Bills,
+		Subcategories: result.Subcategories,
+	}, nil
+}
+
+// categoryKindString returns the kind of a category for responses
+func categoryKindString(kind *model.TransactionType) *string {
+	if kind == nil {
+		return nil
+	}
+	k := string(*kind)
+	return &k
+}
+
+// getOwnedCategory loads an active category and hides those of other users
+func (s *categoryService) getOwnedCategory(ctx context.Context, userID, id uuid.UUID) (*model.Category, error) {
+	category, err := s.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, constant.ErrNotFound
+		}
+		return nil, err
+	}
+	if category.UserID != userID {
+		return nil, constant.ErrNotFound
 	}
+	return category, nil
 }
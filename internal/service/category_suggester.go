@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"github.com/tyha2404/nexo-app-api/internal/repository"
+)
+
+const (
+	// suggestionTrainingLimit is how many of a user's most recent
+	// transactions a model is trained on
+	suggestionTrainingLimit = 20000
+	// suggestionModelMaxAge rebuilds models from the database now and then,
+	// which picks up changes made outside the incremental updates, e.g. by
+	// another server instance
+	suggestionModelMaxAge = time.Hour
+	// suggestionMaxCachedUsers bounds the memory held by models; the least
+	// recently used one is dropped first
+	suggestionMaxCachedUsers = 1000
+)
+
+// CategoryScore is the probability the model assigns to a category
+type CategoryScore struct {
+	CategoryID  uuid.UUID
+	Probability float64
+}
+
+// CategorySuggester ranks a user's categories for a transaction with a naive
+// Bayes model trained on the user's own categorized transactions. Models are
+// built on first use, kept in memory and updated as transactions change.
+type CategorySuggester interface {
+	// Suggest ranks the categories for which allowed returns true. It also
+	// returns the number of transactions the model was trained on.
+	Suggest(ctx context.Context, userID uuid.UUID, t *model.Transaction, allowed func(uuid.UUID) bool) ([]CategoryScore, int, error)
+	// Learn adds a transaction to the user's model if one is loaded
+	Learn(userID uuid.UUID, t *model.Transaction)
+	// Forget removes a transaction previously learned from the user's model
+	Forget(userID uuid.UUID, t *model.Transaction)
+	// Invalidate drops the user's model, e.g. after a bulk change, so it is
+	// rebuilt on next use
+	Invalidate(userID uuid.UUID)
+}
+
+type categorySuggester struct {
+	transactionRepo repository.TransactionRepository
+
+	mu     sync.Mutex
+	models map[uuid.UUID]*categoryModel
+}
+
+func NewCategorySuggester(transactionRepo repository.TransactionRepository) CategorySuggester {
+	return &categorySuggester{
+		transactionRepo: transactionRepo,
+		models:          make(map[uuid.UUID]*categoryModel),
+	}
+}
+
+func (s *categorySuggester) Suggest(ctx context.Context, userID uuid.UUID, t *model.Transaction, allowed func(uuid.UUID) bool) ([]CategoryScore, int, error) {
+	s.mu.Lock()
+	m, ok := s.models[userID]
+	s.mu.Unlock()
+
+	if !ok || time.Since(m.builtAt) > suggestionModelMaxAge {
+		transactions, err := s.transactionRepo.ListRecent(ctx, userID, suggestionTrainingLimit)
+		if err != nil {
+			return nil, 0, err
+		}
+		m = newCategoryModel()
+		for i := range transactions {
+			m.add(&transactions[i], 1)
+		}
+		s.store(userID, m)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m.lastUsed = time.Now()
+	return m.rank(features(t), allowed), m.documents, nil
+}
+
+func (s *categorySuggester) Learn(userID uuid.UUID, t *model.Transaction) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if m, ok := s.models[userID]; ok {
+		m.add(t, 1)
+	}
+}
+
+func (s *categorySuggester) Forget(userID uuid.UUID, t *model.Transaction) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if m, ok := s.models[userID]; ok {
+		m.add(t, -1)
+	}
+}
+
+func (s *categorySuggester) Invalidate(userID uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.models, userID)
+}
+
+// store caches a freshly built model, evicting the least recently used one
+// when the cache is full
+func (s *categorySuggester) store(userID uuid.UUID, m *categoryModel) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.models[userID]; !ok && len(s.models) >= suggestionMaxCachedUsers {
+		var oldestID uuid.UUID
+		var oldest time.Time
+		for id, cached := range s.models {
+			if oldest.IsZero() || cached.lastUsed.Before(oldest) {
+				oldestID, oldest = id, cached.lastUsed
+			}
+		}
+		delete(s.models, oldestID)
+	}
+	s.models[userID] = m
+}
+
+// categoryModel holds the counts of a multinomial naive Bayes classifier
+type categoryModel struct {
+	builtAt   time.Time
+	lastUsed  time.Time
+	documents int
+	// vocabulary counts every feature across all categories
+	vocabulary map[string]int
+	categories map[uuid.UUID]*categoryCounts
+}
+
+type categoryCounts struct {
+	documents int
+	features  int
+	counts    map[string]int
+}
+
+func newCategoryModel() *categoryModel {
+	now := time.Now()
+	return &categoryModel{
+		builtAt:    now,
+		lastUsed:   now,
+		vocabulary: make(map[string]int),
+		categories: make(map[uuid.UUID]*categoryCounts),
+	}
+}
+
+// add learns (delta 1) or unlearns (delta -1) a transaction
+func (m *categoryModel) add(t *model.Transaction, delta int) {
+	c, ok := m.categories[t.CategoryID]
+	if !ok {
+		if delta < 0 {
+			return
+		}
+		c = &categoryCounts{counts: make(map[string]int)}
+		m.categories[t.CategoryID] = c
+	}
+
+	m.documents += delta
+	c.documents += delta
+	for _, f := range features(t) {
+		c.features += delta
+		c.counts[f] += delta
+		if c.counts[f] <= 0 {
+			delete(c.counts, f)
+		}
+		m.vocabulary[f] += delta
+		if m.vocabulary[f] <= 0 {
+			delete(m.vocabulary, f)
+		}
+	}
+	if c.documents <= 0 {
+		delete(m.categories, t.CategoryID)
+	}
+}
+
+// rank scores every allowed category with Laplace smoothing and turns the
+// log scores into probabilities, highest first
+func (m *categoryModel) rank(feats []string, allowed func(uuid.UUID) bool) []CategoryScore {
+	vocabulary := float64(len(m.vocabulary) + 1)
+	var scores []CategoryScore
+	var documents int
+	for id, c := range m.categories {
+		if allowed(id) {
+			documents += c.documents
+		}
+	}
+	if documents == 0 {
+		return nil
+	}
+
+	for id, c := range m.categories {
+		if !allowed(id) {
+			continue
+		}
+		score := math.Log(float64(c.documents) / float64(documents))
+		for _, f := range feats {
+			score += math.Log((float64(c.counts[f]) + 1) / (float64(c.features) + vocabulary))
+		}
+		scores = append(scores, CategoryScore{CategoryID: id, Probability: score})
+	}
+
+	// Softmax, shifted by the best score to stay within float range
+	best := math.Inf(-1)
+	for _, s := range scores {
+		best = math.Max(best, s.Probability)
+	}
+	var sum float64
+	for i := range scores {
+		scores[i].Probability = math.Exp(scores[i].Probability - best)
+		sum += scores[i].Probability
+	}
+	for i := range scores {
+		scores[i].Probability /= sum
+	}
+
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Probability != scores[j].Probability {
+			return scores[i].Probability > scores[j].Probability
+		}
+		return scores[i].CategoryID.String() < scores[j].CategoryID.String()
+	})
+	return scores
+}
+
+// features turns a transaction into the words of its description, its type
+// and the order of magnitude of its amount
+func features(t *model.Transaction) []string {
+	var feats []string
+	if t.Description != nil {
+		feats = descriptionWords(*t.Description)
+	}
+	if t.Type != "" {
+		feats = append(feats, "type:"+string(t.Type))
+	}
+	if t.Amount > 0 {
+		feats = append(feats, fmt.Sprintf("amount:%d", int(math.Log2(t.Amount+1))))
+	}
+	return feats
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/constant"
+	"github.com/tyha2404/nexo-app-api/internal/dto"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"github.com/tyha2404/nexo-app-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+// CategoryTemplateService manages the categories new users start with. A
+// locale without stored templates falls back to the built-in ones.
+type CategoryTemplateService interface {
+	Create(ctx context.Context, req dto.CreateCategoryTemplateRequest) (*dto.CategoryTemplateResponse, error)
+	// List returns the stored templates of a locale, or of all locales when
+	// locale is empty
+	List(ctx context.Context, locale string) ([]dto.CategoryTemplateResponse, error)
+	Update(ctx context.Context, id uuid.UUID, req dto.UpdateCategoryTemplateRequest) (*dto.CategoryTemplateResponse, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type categoryTemplateService struct {
+	templateRepo repository.CategoryTemplateRepo
+}
+
+func NewCategoryTemplateService(templateRepo repository.CategoryTemplateRepo) CategoryTemplateService {
+	return &categoryTemplateService{
+		templateRepo: templateRepo,
+	}
+}
+
+func (s *categoryTemplateService) Create(ctx context.Context, req dto.CreateCategoryTemplateRequest) (*dto.CategoryTemplateResponse, error) {
+	locale, err := normalizeLocale(req.Locale)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(req.Name)
+	if err := s.ensureNameAvailable(ctx, locale, name, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	template := &model.CategoryTemplate{
+		Locale:    locale,
+		Name:      name,
+		Kind:      model.TransactionType(req.Kind),
+		Icon:      req.Icon,
+		Color:     req.Color,
+		SortOrder: req.SortOrder,
+	}
+	if err := s.templateRepo.Create(ctx, template); err != nil {
+		return nil, err
+	}
+	return toCategoryTemplateResponse(template), nil
+}
+
+func (s *categoryTemplateService) List(ctx context.Context, locale string) ([]dto.CategoryTemplateResponse, error) {
+	if locale != "" {
+		normalized, err := normalizeLocale(locale)
+		if err != nil {
+			return nil, err
+		}
+		locale = normalized
+	}
+
+	templates, err := s.templateRepo.ListByLocale(ctx, locale)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]dto.CategoryTemplateResponse, 0, len(templates))
+	for i := range templates {
+		responses = append(responses, *toCategoryTemplateResponse(&templates[i]))
+	}
+	return responses, nil
+}
+
+func (s *categoryTemplateService) Update(ctx context.Context, id uuid.UUID, req dto.UpdateCategoryTemplateRequest) (*dto.CategoryTemplateResponse, error) {
+	template, err := s.getTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if err := s.ensureNameAvailable(ctx, template.Locale, name, template.ID); err != nil {
+			return nil, err
+		}
+		template.Name = name
+	}
+	if req.Kind != nil {
+		template.Kind = model.TransactionType(*req.Kind)
+	}
+	if req.Icon != nil {
+		template.Icon = req.Icon
+	}
+	if req.Color != nil {
+		template.Color = req.Color
+	}
+	if req.SortOrder != nil {
+		template.SortOrder = *req.SortOrder
+	}
+
+	if err := s.templateRepo.Update(ctx, template); err != nil {
+		return nil, err
+	}
+	return toCategoryTemplateResponse(template), nil
+}
+
+func (s *categoryTemplateService) Delete(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.getTemplate(ctx, id); err != nil {
+		return err
+	}
+	return s.templateRepo.Delete(ctx, id)
+}
+
+func (s *categoryTemplateService) getTemplate(ctx context.Context, id uuid.UUID) (*model.CategoryTemplate, error) {
+	template, err := s.templateRepo.GetByID(ctx, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, constant.ErrNotFound
+		}
+		return nil, err
+	}
+	return template, nil
+}
+
+// ensureNameAvailable rejects names already used by another template of the
+// locale
+func (s *categoryTemplateService) ensureNameAvailable(ctx context.Context, locale, name string, selfID uuid.UUID) error {
+	if name == "" {
+		return fmt.Errorf("%w: template name is required", constant.ErrInvalidInput)
+	}
+
+	existing, err := s.templateRepo.FindByName(ctx, locale, name)
+	if err != nil && err != constant.ErrNotFound {
+		return err
+	}
+	if existing != nil && existing.ID != selfID {
+		return constant.ErrTemplateExists
+	}
+	return nil
+}
+
+func toCategoryTemplateResponse(t *model.CategoryTemplate) *dto.CategoryTemplateResponse {
+	return &dto.CategoryTemplateResponse{
+		ID:        t.ID.String(),
+		Locale:    t.Locale,
+		Name:      t.Name,
+		Kind:      string(t.Kind),
+		Icon:      t.Icon,
+		Color:     t.Color,
+		SortOrder: t.SortOrder,
+		CreatedAt: t.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: t.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tyha2404/nexo-app-api/internal/constant"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"github.com/tyha2404/nexo-app-api/internal/repository"
+)
+
+// defaultCategoryLocale is used when neither the requested locale nor its
+// language has templates
+const defaultCategoryLocale = "en"
+
+// builtinCategoryTemplates are used for a locale until an admin stores
+// templates for it
+var builtinCategoryTemplates = map[string][]model.CategoryTemplate{
+	"en": {
+		builtinTemplate("Salary", model.TransactionTypeIncome, "work", "#2E7D32"),
+		builtinTemplate("Bonus", model.TransactionTypeIncome, "card_giftcard", "#43A047"),
+		builtinTemplate("Investments", model.TransactionTypeIncome, "trending_up", "#00897B"),
+		builtinTemplate("Other income", model.TransactionTypeIncome, "savings", "#7CB342"),
+		builtinTemplate("Food & Drinks", model.TransactionTypeExpense, "restaurant", "#EF6C00"),
+		builtinTemplate("Groceries", model.TransactionTypeExpense, "shopping_cart", "#F9A825"),
+		builtinTemplate("Transport", model.TransactionTypeExpense, "directions_car", "#1E88E5"),
+		builtinTemplate("Housing", model.TransactionTypeExpense, "home", "#6D4C41"),
+		builtinTemplate("Utilities", model.TransactionTypeExpense, "bolt", "#FDD835"),
+		builtinTemplate("Health", model.TransactionTypeExpense, "medical_services", "#E53935"),
+		builtinTemplate("Shopping", model.TransactionTypeExpense, "shopping_bag", "#D81B60"),
+		builtinTemplate("Entertainment", model.TransactionTypeExpense, "movie", "#8E24AA"),
+		builtinTemplate("Education", model.TransactionTypeExpense, "school", "#3949AB"),
+		builtinTemplate("Travel", model.TransactionTypeExpense, "flight", "#039BE5"),
+		builtinTemplate("Other expenses", model.TransactionTypeExpense, "more_horiz", "#757575"),
+	},
+	"vi": {
+		builtinTemplate("Lương", model.TransactionTypeIncome, "work", "#2E7D32"),
+		builtinTemplate("Thưởng", model.TransactionTypeIncome, "card_giftcard", "#43A047"),
+		builtinTemplate("Đầu tư", model.TransactionTypeIncome, "trending_up", "#00897B"),
+		builtinTemplate("Thu nhập khác", model.TransactionTypeIncome, "savings", "#7CB342"),
+		builtinTemplate("Ăn uống", model.TransactionTypeExpense, "restaurant", "#EF6C00"),
+		builtinTemplate("Đi chợ", model.TransactionTypeExpense, "shopping_cart", "#F9A825"),
+		builtinTemplate("Di chuyển", model.TransactionTypeExpense, "directions_car", "#1E88E5"),
+		builtinTemplate("Nhà ở", model.TransactionTypeExpense, "home", "#6D4C41"),
+		builtinTemplate("Hóa đơn", model.TransactionTypeExpense, "bolt", "#FDD835"),
+		builtinTemplate("Sức khỏe", model.TransactionTypeExpense, "medical_services", "#E53935"),
+		builtinTemplate("Mua sắm", model.TransactionTypeExpense, "shopping_bag", "#D81B60"),
+		builtinTemplate("Giải trí", model.TransactionTypeExpense, "movie", "#8E24AA"),
+		builtinTemplate("Giáo dục", model.TransactionTypeExpense, "school", "#3949AB"),
+		builtinTemplate("Du lịch", model.TransactionTypeExpense, "flight", "#039BE5"),
+		builtinTemplate("Chi phí khác", model.TransactionTypeExpense, "more_horiz", "#757575"),
+	},
+}
+
+func builtinTemplate(name string, kind model.TransactionType, icon, color string) model.CategoryTemplate {
+	return model.CategoryTemplate{Name: name, Kind: kind, Icon: &icon, Color: &color}
+}
+
+// normalizeLocale turns a language tag such as pt_BR into pt-br
+func normalizeLocale(locale string) (string, error) {
+	locale = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(locale)), "_", "-")
+	if len(locale) < 2 || len(locale) > 16 {
+		return "", fmt.Errorf("%w: locale must be a language tag such as en or pt-BR", constant.ErrInvalidInput)
+	}
+	for _, r := range locale {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+			return "", fmt.Errorf("%w: locale must be a language tag such as en or pt-BR", constant.ErrInvalidInput)
+		}
+	}
+	return locale, nil
+}
+
+// resolveCategoryTemplates returns the templates for a locale. It tries the
+// locale, then its language, then the default locale, and for each of them
+// prefers the stored templates over the built-in ones.
+func resolveCategoryTemplates(ctx context.Context, templateRepo repository.CategoryTemplateRepo, locale string) ([]model.CategoryTemplate, error) {
+	candidates := []string{defaultCategoryLocale}
+	if normalized, err := normalizeLocale(locale); err == nil {
+		language, _, _ := strings.Cut(normalized, "-")
+		candidates = []string{normalized, language, defaultCategoryLocale}
+	}
+
+	for _, candidate := range candidates {
+		templates, err := templateRepo.ListByLocale(ctx, candidate)
+		if err != nil {
+			return nil, err
+		}
+		if len(templates) > 0 {
+			return templates, nil
+		}
+		if builtin, ok := builtinCategoryTemplates[candidate]; ok {
+			// Built-in templates keep the order they are listed in
+			templates = make([]model.CategoryTemplate, len(builtin))
+			for i, t := range builtin {
+				t.SortOrder = (i + 1) * 10
+				templates[i] = t
+			}
+			return templates, nil
+		}
+	}
+	return nil, nil
+}
+
+// categoriesFromTemplates builds the categories a new user starts with
+func categoriesFromTemplates(templates []model.CategoryTemplate) []model.Category {
+	categories := make([]model.Category, 0, len(templates))
+	for _, t := range templates {
+		kind := t.Kind
+		categories = append(categories, model.Category{
+			Name:      t.Name,
+			Kind:      &kind,
+			Icon:      t.Icon,
+			Color:     t.Color,
+			SortOrder: t.SortOrder,
+		})
+	}
+	return categories
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/constant"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"github.com/tyha2404/nexo-app-api/internal/repository"
+)
+
+// categoryForest is the hierarchy of a user's active categories. A category
+// whose parent is missing, e.g. in the trash, counts as a top-level one.
+type categoryForest struct {
+	byID     map[uuid.UUID]*model.Category
+	children map[uuid.UUID][]uuid.UUID
+	roots    []uuid.UUID
+}
+
+func newCategoryForest(categories []model.Category) *categoryForest {
+	f := &categoryForest{
+		byID:     make(map[uuid.UUID]*model.Category, len(categories)),
+		children: make(map[uuid.UUID][]uuid.UUID),
+	}
+	for i := range categories {
+		f.byID[categories[i].ID] = &categories[i]
+	}
+	// Sorted by sort order and name, so children and roots come out in
+	// display order
+	ids := make([]uuid.UUID, 0, len(categories))
+	for i := range categories {
+		ids = append(ids, categories[i].ID)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := f.byID[ids[i]], f.byID[ids[j]]
+		if a.SortOrder != b.SortOrder {
+			return a.SortOrder < b.SortOrder
+		}
+		return a.Name < b.Name
+	})
+	for _, id := range ids {
+		if parentID := f.parentOf(id); parentID != uuid.Nil {
+			f.children[parentID] = append(f.children[parentID], id)
+		} else {
+			f.roots = append(f.roots, id)
+		}
+	}
+	return f
+}
+
+func loadCategoryForest(ctx context.Context, categoryRepo repository.CategoryRepo, userID uuid.UUID) (*categoryForest, error) {
+	categories, err := categoryRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return newCategoryForest(categories), nil
+}
+
+// parentOf returns the active parent of a category, or uuid.Nil
+func (f *categoryForest) parentOf(id uuid.UUID) uuid.UUID {
+	c, ok := f.byID[id]
+	if !ok || c.ParentID == nil || *c.ParentID == id {
+		return uuid.Nil
+	}
+	if _, ok := f.byID[*c.ParentID]; !ok {
+		return uuid.Nil
+	}
+	return *c.ParentID
+}
+
+// rootOf returns the top-level ancestor of a category, or the category itself
+func (f *categoryForest) rootOf(id uuid.UUID) uuid.UUID {
+	seen := make(map[uuid.UUID]bool)
+	for {
+		parentID := f.parentOf(id)
+		if parentID == uuid.Nil || seen[parentID] {
+			return id
+		}
+		seen[id] = true
+		id = parentID
+	}
+}
+
+// isAncestor reports whether ancestor is id itself or one of its ancestors
+func (f *categoryForest) isAncestor(ancestor, id uuid.UUID) bool {
+	seen := make(map[uuid.UUID]bool)
+	for id != uuid.Nil && !seen[id] {
+		if id == ancestor {
+			return true
+		}
+		seen[id] = true
+		id = f.parentOf(id)
+	}
+	return false
+}
+
+// subtree returns the category and all of its descendants
+func (f *categoryForest) subtree(id uuid.UUID) []uuid.UUID {
+	ids := []uuid.UUID{id}
+	seen := map[uuid.UUID]bool{id: true}
+	for i := 0; i < len(ids); i++ {
+		for _, child := range f.children[ids[i]] {
+			if !seen[child] {
+				seen[child] = true
+				ids = append(ids, child)
+			}
+		}
+	}
+	return ids
+}
+
+// checkParent validates moving the category id under parentID. id is
+// uuid.Nil for a category that does not exist yet.
+func (f *categoryForest) checkParent(id, parentID uuid.UUID) error {
+	if _, ok := f.byID[parentID]; !ok {
+		return fmt.Errorf("%w: parent category %s not found", constant.ErrInvalidInput, parentID)
+	}
+	if id != uuid.Nil && f.isAncestor(id, parentID) {
+		return fmt.Errorf("%w: a category cannot be moved under itself or one of its subcategories", constant.ErrInvalidInput)
+	}
+	return nil
+}
@@ -11,20 +11,54 @@ import (
 type CostService interface {
 	BaseService[model.Cost]
 	ListWithCategory(ctx context.Context, userID uuid.UUID, limit, offset int, filters map[string]interface{}) ([]model.Cost, error)
+	// ReplaceTags replaces the tags of the cost with the user's tags of the
+	// given IDs
+	ReplaceTags(ctx context.Context, userID uuid.UUID, cost *model.Cost, tagIDs []uuid.UUID) error
 }
 
 type costService struct {
 	*BaseServiceImpl[model.Cost]
-	repo repository.CostRepo
+	repo    repository.CostRepo
+	tagRepo repository.TagRepo
 }
 
-func NewCostService(repo repository.CostRepo) CostService {
+func NewCostService(repo repository.CostRepo, tagRepo repository.TagRepo) CostService {
 	return &costService{
 		BaseServiceImpl: NewBaseService(repo),
 		repo:            repo,
+		tagRepo:         tagRepo,
 	}
 }
 
+// Create stores a new cost. Tags on the cost only need their IDs set; they are
+// resolved against the owner's tags before saving.
+func (s *costService) Create(ctx context.Context, cost *model.Cost) (*model.Cost, error) {
+	if len(cost.Tags) > 0 {
+		ids := make([]uuid.UUID, 0, len(cost.Tags))
+		for _, t := range cost.Tags {
+			ids = append(ids, t.ID)
+		}
+		tags, err := resolveTags(ctx, s.tagRepo, cost.UserID, ids)
+		if err != nil {
+			return nil, err
+		}
+		cost.Tags = tags
+	}
+	return s.BaseServiceImpl.Create(ctx, cost)
+}
+
+func (s *costService) ReplaceTags(ctx context.Context, userID uuid.UUID, cost *model.Cost, tagIDs []uuid.UUID) error {
+	tags, err := resolveTags(ctx, s.tagRepo, userID, tagIDs)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.ReplaceTags(ctx, cost, tags); err != nil {
+		return err
+	}
+	cost.Tags = tags
+	return nil
+}
+
 func (s *costService) ListWithCategory(ctx context.Context, userID uuid.UUID, limit, offset int, filters map[string]interface{}) ([]model.Cost, error) {
 	return s.repo.ListWithCategory(ctx, userID, limit, offset, filters)
 }
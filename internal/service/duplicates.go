@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"github.com/tyha2404/nexo-app-api/internal/repository"
+)
+
+const (
+	// duplicateWindowDays is how many days apart two bookings of the same
+	// payment may be dated, e.g. a manual entry and the bank's booking date
+	duplicateWindowDays = 3
+	// maxDuplicateWindowDays caps the window a caller may ask for
+	maxDuplicateWindowDays = 14
+	// duplicateMinSimilarity is the description similarity from which two
+	// transactions of the same amount count as duplicates
+	duplicateMinSimilarity = 0.5
+)
+
+// isLikelyDuplicate reports whether two transactions look like the same
+// payment: same type and amount, dated at most windowDays apart and with
+// similar descriptions. A missing description matches any other. Bookings
+// with different bank IDs are distinct however alike they look.
+func isLikelyDuplicate(a, b *model.Transaction, windowDays int) bool {
+	if a.Type != b.Type || amountCents(a.Amount) != amountCents(b.Amount) {
+		return false
+	}
+	if a.ExternalID != nil && b.ExternalID != nil && *a.ExternalID != *b.ExternalID {
+		return false
+	}
+	if daysApart(a.TransactionDate, b.TransactionDate) > windowDays {
+		return false
+	}
+	return descriptionSimilarity(a.Description, b.Description) >= duplicateMinSimilarity
+}
+
+// descriptionSimilarity is the share of words of the shorter description
+// that also appear in the other, ignoring case and digits. Comparing with the
+// shorter one lets a short manual entry like "Rewe" match the bank's longer
+// "REWE SAGT DANKE 1234". It is 1 when either description has no words.
+func descriptionSimilarity(a, b *string) float64 {
+	wordsA, wordsB := wordSet(a), wordSet(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return 1
+	}
+	if len(wordsA) > len(wordsB) {
+		wordsA, wordsB = wordsB, wordsA
+	}
+	var shared int
+	for w := range wordsA {
+		if wordsB[w] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(wordsA))
+}
+
+func wordSet(description *string) map[string]bool {
+	set := make(map[string]bool)
+	if description != nil {
+		for _, w := range descriptionWords(*description) {
+			set[w] = true
+		}
+	}
+	return set
+}
+
+// descriptionWords splits a description into lower-case words of at least
+// two letters. Digits are dropped, as they tend to be dates and reference
+// numbers.
+func descriptionWords(description string) []string {
+	var words []string
+	for _, w := range strings.FieldsFunc(strings.ToLower(description), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}) {
+		if len([]rune(w)) >= 2 {
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+func amountCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+func daysApart(a, b time.Time) int {
+	days := int(math.Round(a.Sub(b).Hours() / 24))
+	if days < 0 {
+		return -days
+	}
+	return days
+}
+
+// groupDuplicates clusters transactions that are likely duplicates of each
+// other, directly or through a third one. Only groups of two or more are
+// returned, as indexes into transactions.
+func groupDuplicates(transactions []model.Transaction, windowDays int) [][]int {
+	order := make([]int, len(transactions))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		a, b := &transactions[order[i]], &transactions[order[j]]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if ca, cb := amountCents(a.Amount), amountCents(b.Amount); ca != cb {
+			return ca < cb
+		}
+		return a.TransactionDate.Before(b.TransactionDate)
+	})
+
+	parent := make([]int, len(transactions))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	// Sorted by type, amount and date, candidates of a transaction follow it
+	// directly and the scan stops at the first one out of reach
+	for i, a := range order {
+		for _, b := range order[i+1:] {
+			ta, tb := &transactions[a], &transactions[b]
+			if ta.Type != tb.Type || amountCents(ta.Amount) != amountCents(tb.Amount) ||
+				daysApart(ta.TransactionDate, tb.TransactionDate) > windowDays {
+				break
+			}
+			if isLikelyDuplicate(ta, tb, windowDays) {
+				parent[find(b)] = find(a)
+			}
+		}
+	}
+
+	members := make(map[int][]int)
+	var roots []int
+	for _, i := range order {
+		root := find(i)
+		if _, ok := members[root]; !ok {
+			roots = append(roots, root)
+		}
+		members[root] = append(members[root], i)
+	}
+	var groups [][]int
+	for _, root := range roots {
+		if len(members[root]) > 1 {
+			groups = append(groups, members[root])
+		}
+	}
+	return groups
+}
+
+// duplicateDetector matches new transactions against the user's existing
+// ones. Transactions carrying a bank ID are matched on it, which makes
+// re-importing the same statement idempotent. Others are matched with
+// isLikelyDuplicate; each existing transaction can only absorb one new
+// transaction, so two identical purchases are only flagged if the user
+// already has two of them.
+type duplicateDetector struct {
+	windowDays  int
+	candidates  map[string][]*duplicateCandidate
+	externalIDs map[string]struct{}
+}
+
+type duplicateCandidate struct {
+	transaction model.Transaction
+	used        bool
+}
+
+// loadDuplicateDetector prepares a detector for new transactions carrying the
+// given external IDs and dated within [from, to]. A zero from skips loading
+// the existing transactions.
+func loadDuplicateDetector(ctx context.Context, transactionRepo repository.TransactionRepository, userID uuid.UUID, externalIDs []string, from, to time.Time, windowDays int) (*duplicateDetector, error) {
+	detector := &duplicateDetector{
+		windowDays:  windowDays,
+		candidates:  make(map[string][]*duplicateCandidate),
+		externalIDs: make(map[string]struct{}),
+	}
+
+	existingIDs, err := transactionRepo.FindExistingExternalIDs(ctx, userID, externalIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range existingIDs {
+		detector.externalIDs[id] = struct{}{}
+	}
+
+	if from.IsZero() {
+		return detector, nil
+	}
+
+	existing, err := transactionRepo.ListByDateRange(ctx, userID, from.AddDate(0, 0, -windowDays), to.AddDate(0, 0, windowDays))
+	if err != nil {
+		return nil, err
+	}
+	for i := range existing {
+		key := candidateKey(&existing[i])
+		detector.candidates[key] = append(detector.candidates[key], &duplicateCandidate{transaction: existing[i]})
+	}
+	return detector, nil
+}
+
+// match returns the existing transaction t duplicates, if any. A match on
+// the bank ID alone reports uuid.Nil, as the matching transaction is not
+// loaded.
+func (d *duplicateDetector) match(t *model.Transaction) (uuid.UUID, bool) {
+	candidate := d.closest(t)
+
+	if t.ExternalID != nil {
+		if _, seen := d.externalIDs[*t.ExternalID]; seen {
+			// The matched transaction must not absorb another entry as well
+			if candidate != nil {
+				candidate.used = true
+				return candidate.transaction.ID, true
+			}
+			return uuid.Nil, true
+		}
+		// Also catches the same bank ID appearing twice in one file
+		d.externalIDs[*t.ExternalID] = struct{}{}
+	}
+
+	if candidate != nil {
+		candidate.used = true
+		return candidate.transaction.ID, true
+	}
+	return uuid.Nil, false
+}
+
+func (d *duplicateDetector) isDuplicate(t *model.Transaction) bool {
+	_, duplicate := d.match(t)
+	return duplicate
+}
+
+// closest finds the unused likely duplicate of t with the nearest date,
+// preferring the more similar description on a tie
+func (d *duplicateDetector) closest(t *model.Transaction) *duplicateCandidate {
+	var best *duplicateCandidate
+	var bestDays int
+	var bestSimilarity float64
+	for _, c := range d.candidates[candidateKey(t)] {
+		if c.used || !isLikelyDuplicate(&c.transaction, t, d.windowDays) {
+			continue
+		}
+		days := daysApart(c.transaction.TransactionDate, t.TransactionDate)
+		similarity := descriptionSimilarity(c.transaction.Description, t.Description)
+		if best == nil || days < bestDays || (days == bestDays && similarity > bestSimilarity) {
+			best, bestDays, bestSimilarity = c, days, similarity
+		}
+	}
+	return best
+}
+
+// candidateKey buckets transactions that can be duplicates of each other
+func candidateKey(t *model.Transaction) string {
+	return string(t.Type) + "|" + strconv.FormatInt(amountCents(t.Amount), 10)
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"github.com/tyha2404/nexo-app-api/internal/repository"
+)
+
+// mustDate parses a YYYY-MM-DD calendar date
+func mustDate(t *testing.T, s string) time.Time {
+	t.Helper()
+	d, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		t.Fatalf("invalid date %q: %v", s, err)
+	}
+	return d
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func testTransaction(t *testing.T, txType model.TransactionType, amount float64, date, description string) model.Transaction {
+	t.Helper()
+	tx := model.Transaction{
+		ID:              uuid.New(),
+		Type:            txType,
+		Amount:          amount,
+		TransactionDate: mustDate(t, date),
+	}
+	if description != "" {
+		tx.Description = strPtr(description)
+	}
+	return tx
+}
+
+func TestIsLikelyDuplicate(t *testing.T) {
+	base := testTransaction(t, model.TransactionTypeExpense, 42.5, "2024-03-10", "Rewe")
+	tests := []struct {
+		name  string
+		other model.Transaction
+		want  bool
+	}{
+		{"bank wording of the same purchase", testTransaction(t, model.TransactionTypeExpense, 42.5, "2024-03-12", "REWE SAGT DANKE 1234"), true},
+		{"no description", testTransaction(t, model.TransactionTypeExpense, 42.5, "2024-03-10", ""), true},
+		{"other amount", testTransaction(t, model.TransactionTypeExpense, 42.51, "2024-03-10", "Rewe"), false},
+		{"other type", testTransaction(t, model.TransactionTypeIncome, 42.5, "2024-03-10", "Rewe"), false},
+		{"outside the window", testTransaction(t, model.TransactionTypeExpense, 42.5, "2024-03-14", "Rewe"), false},
+		{"other shop", testTransaction(t, model.TransactionTypeExpense, 42.5, "2024-03-10", "Aldi Sued"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isLikelyDuplicate(&base, &tt.other, duplicateWindowDays); got != tt.want {
+				t.Errorf("isLikelyDuplicate = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("different bank IDs", func(t *testing.T) {
+		a, b := base, base
+		a.ExternalID, b.ExternalID = strPtr("A"), strPtr("B")
+		if isLikelyDuplicate(&a, &b, duplicateWindowDays) {
+			t.Error("bookings with different bank IDs are distinct")
+		}
+	})
+}
+
+func TestDescriptionSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b *string
+		want float64
+	}{
+		{strPtr("Netflix"), strPtr("NETFLIX.COM 8837"), 1},
+		{strPtr("Coffee shop"), strPtr("Coffee bar downtown"), 0.5},
+		{strPtr("Coffee"), strPtr("coffee"), 1},
+		{strPtr("Coffee shop"), strPtr("Tea"), 0},
+		{nil, strPtr("anything"), 1},
+		{strPtr("12345"), strPtr("Rent"), 1},
+	}
+	for _, tt := range tests {
+		if got := descriptionSimilarity(tt.a, tt.b); got != tt.want {
+			t.Errorf("descriptionSimilarity(%v, %v) = %v, want %v", deref(tt.a), deref(tt.b), got, tt.want)
+		}
+	}
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return "<nil>"
+	}
+	return *s
+}
+
+func TestGroupDuplicates(t *testing.T) {
+	transactions := []model.Transaction{
+		testTransaction(t, model.TransactionTypeExpense, 9.99, "2024-03-01", "Spotify"),
+		testTransaction(t, model.TransactionTypeExpense, 42.5, "2024-03-10", "Rewe"),
+		testTransaction(t, model.TransactionTypeExpense, 9.99, "2024-03-02", "SPOTIFY AB"),
+		testTransaction(t, model.TransactionTypeExpense, 42.5, "2024-03-20", "Rewe"),
+		// Chained: 3 days from the first Spotify, 2 from the second
+		testTransaction(t, model.TransactionTypeExpense, 9.99, "2024-03-04", ""),
+		testTransaction(t, model.TransactionTypeIncome, 9.99, "2024-03-01", "Spotify refund"),
+	}
+	got := groupDuplicates(transactions, duplicateWindowDays)
+	want := [][]int{{0, 2, 4}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("groupDuplicates = %v, want %v", got, want)
+	}
+
+	if got := groupDuplicates(nil, duplicateWindowDays); got != nil {
+		t.Errorf("groupDuplicates(nil) = %v", got)
+	}
+}
+
+// duplicateRepo serves the two queries the duplicate detector makes
+type duplicateRepo struct {
+	repository.TransactionRepository
+	existing    []model.Transaction
+	externalIDs []string
+}
+
+func (r *duplicateRepo) FindExistingExternalIDs(_ context.Context, _ uuid.UUID, ids []string) ([]string, error) {
+	var found []string
+	for _, id := range ids {
+		for _, known := range r.externalIDs {
+			if id == known {
+				found = append(found, id)
+			}
+		}
+	}
+	return found, nil
+}
+
+func (r *duplicateRepo) ListByDateRange(_ context.Context, _ uuid.UUID, from, to time.Time) ([]model.Transaction, error) {
+	var found []model.Transaction
+	for _, tx := range r.existing {
+		if !tx.TransactionDate.Before(from) && !tx.TransactionDate.After(to) {
+			found = append(found, tx)
+		}
+	}
+	return found, nil
+}
+
+func TestDuplicateDetector(t *testing.T) {
+	coffee := testTransaction(t, model.TransactionTypeExpense, 3.2, "2024-03-05", "Coffee")
+	repo := &duplicateRepo{
+		existing:    []model.Transaction{coffee},
+		externalIDs: []string{"acct:FIT-1"},
+	}
+	detector, err := loadDuplicateDetector(context.Background(), repo, uuid.New(),
+		[]string{"acct:FIT-1", "acct:FIT-2"}, mustDate(t, "2024-03-01"), mustDate(t, "2024-03-31"), duplicateWindowDays)
+	if err != nil {
+		t.Fatalf("loadDuplicateDetector: %v", err)
+	}
+
+	// The existing coffee absorbs one new coffee, not two
+	first := testTransaction(t, model.TransactionTypeExpense, 3.2, "2024-03-06", "COFFEE")
+	if id, ok := detector.match(&first); !ok || id != coffee.ID {
+		t.Errorf("first coffee: match = %v, %v; want %v", id, ok, coffee.ID)
+	}
+	second := testTransaction(t, model.TransactionTypeExpense, 3.2, "2024-03-06", "COFFEE")
+	if detector.isDuplicate(&second) {
+		t.Error("second coffee matched an already used transaction")
+	}
+
+	// A known bank ID is a duplicate even without a similar transaction
+	known := testTransaction(t, model.TransactionTypeIncome, 100, "2024-03-10", "Transfer")
+	known.ExternalID = strPtr("acct:FIT-1")
+	if id, ok := detector.match(&known); !ok || id != uuid.Nil {
+		t.Errorf("known bank ID: match = %v, %v; want uuid.Nil, true", id, ok)
+	}
+
+	// A bank ID seen twice in the same file only counts once
+	fresh := testTransaction(t, model.TransactionTypeIncome, 50, "2024-03-11", "Gift")
+	fresh.ExternalID = strPtr("acct:FIT-2")
+	if detector.isDuplicate(&fresh) {
+		t.Error("new bank ID flagged as duplicate")
+	}
+	again := fresh
+	if !detector.isDuplicate(&again) {
+		t.Error("bank ID repeated within the file not flagged")
+	}
+}
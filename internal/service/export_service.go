@@ -0,0 +1,459 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/constant"
+	"github.com/tyha2404/nexo-app-api/internal/dto"
+	"github.com/tyha2404/nexo-app-api/internal/exporter"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"github.com/tyha2404/nexo-app-api/internal/repository"
+	"github.com/tyha2404/nexo-app-api/internal/storage"
+	"gorm.io/gorm"
+)
+
+const (
+	// exportJobTimeout bounds how long a background export may take, queueing
+	// included. Jobs still unfinished after that were interrupted.
+	exportJobTimeout = time.Hour
+	// exportFileTTL is how long a finished export can be downloaded
+	exportFileTTL = 7 * 24 * time.Hour
+	// exportWorkers limits how many background exports run at once
+	exportWorkers = 2
+)
+
+var (
+	categoryExportColumns    = []string{"id", "name", "description", "createdAt", "parentId", "kind", "icon", "color", "sortOrder"}
+	budgetExportColumns      = []string{"id", "categoryId", "amount", "periodType", "periodStart", "createdAt"}
+	transactionExportColumns = []string{"id", "date", "type", "amount", "categoryId", "category", "description", "tags", "externalId", "createdAt"}
+	costExportColumns        = []string{"id", "date", "title", "amount", "currency", "categoryId", "category", "tags", "createdAt"}
+)
+
+type ExportService interface {
+	// ShouldRunAsync reports whether the export is too large to be streamed
+	// in the request
+	ShouldRunAsync(ctx context.Context, userID uuid.UUID, filter repository.ExportFilter) (bool, error)
+	// Export streams the user's data in the given format to w
+	Export(ctx context.Context, userID uuid.UUID, format string, filter repository.ExportFilter, w io.Writer) error
+	// StartJob queues a background export and returns immediately
+	StartJob(ctx context.Context, userID uuid.UUID, format string, filter repository.ExportFilter) (*dto.ExportJobResponse, error)
+	ListJobs(ctx context.Context, userID uuid.UUID) ([]dto.ExportJobResponse, error)
+	GetJob(ctx context.Context, userID, id uuid.UUID) (*dto.ExportJobResponse, error)
+	// OpenJobFile opens the file of a completed export
+	OpenJobFile(ctx context.Context, userID, id uuid.UUID) (*model.ExportJob, io.ReadCloser, error)
+	DeleteJob(ctx context.Context, userID, id uuid.UUID) error
+}
+
+type exportService struct {
+	exportRepo     repository.ExportRepo
+	categoryRepo   repository.CategoryRepo
+	store          storage.BlobStore
+	asyncThreshold int64
+	workers        chan struct{}
+}
+
+func NewExportService(
+	exportRepo repository.ExportRepo,
+	categoryRepo repository.CategoryRepo,
+	store storage.BlobStore,
+	asyncThreshold int64,
+) ExportService {
+	return &exportService{
+		exportRepo:     exportRepo,
+		categoryRepo:   categoryRepo,
+		store:          store,
+		asyncThreshold: asyncThreshold,
+		workers:        make(chan struct{}, exportWorkers),
+	}
+}
+
+func (s *exportService) ShouldRunAsync(ctx context.Context, userID uuid.UUID, filter repository.ExportFilter) (bool, error) {
+	count, err := s.exportRepo.CountRecords(ctx, userID, filter)
+	if err != nil {
+		return false, err
+	}
+	return count > s.asyncThreshold, nil
+}
+
+func (s *exportService) Export(ctx context.Context, userID uuid.UUID, format string, filter repository.ExportFilter, w io.Writer) error {
+	_, err := s.write(ctx, userID, format, filter, w)
+	return err
+}
+
+// write streams every section and returns the number of records written
+func (s *exportService) write(ctx context.Context, userID uuid.UUID, format string, filter repository.ExportFilter, w io.Writer) (int64, error) {
+	out, err := exporter.NewWriter(format, w)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", constant.ErrInvalidInput, err)
+	}
+	var rows int64
+
+	categories, err := s.categoryRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return rows, err
+	}
+	if err := out.BeginSection("categories", categoryExportColumns); err != nil {
+		return rows, err
+	}
+	for _, c := range categories {
+		var parentID any
+		if c.ParentID != nil {
+			parentID = c.ParentID.String()
+		}
+		row := []any{c.ID.String(), c.Name, optionalString(c.Description), c.CreatedAt, parentID,
+			optionalString(categoryKindString(c.Kind)), optionalString(c.Icon), optionalString(c.Color), int64(c.SortOrder)}
+		if err := out.WriteRow(row); err != nil {
+			return rows, err
+		}
+		rows++
+	}
+
+	budgets, err := s.exportRepo.ListBudgets(ctx, userID)
+	if err != nil {
+		return rows, err
+	}
+	if err := out.BeginSection("budgets", budgetExportColumns); err != nil {
+		return rows, err
+	}
+	for _, b := range budgets {
+		if err := out.WriteRow([]any{b.ID.String(), b.CategoryID.String(), b.Amount, b.PeriodType, b.PeriodStart, b.CreatedAt}); err != nil {
+			return rows, err
+		}
+		rows++
+	}
+
+	if err := out.BeginSection("transactions", transactionExportColumns); err != nil {
+		return rows, err
+	}
+	err = s.exportRepo.EachTransactionBatch(ctx, userID, filter, func(batch []model.Transaction) error {
+		for _, t := range batch {
+			var category any
+			if t.Category != nil {
+				category = t.Category.Name
+			}
+			row := []any{
+				t.ID.String(), t.TransactionDate, string(t.Type), t.Amount, t.CategoryID.String(), category,
+				optionalString(t.Description), tagNames(t.Tags), optionalString(t.ExternalID), t.CreatedAt,
+			}
+			if err := out.WriteRow(row); err != nil {
+				return err
+			}
+			rows++
+		}
+		return nil
+	})
+	if err != nil {
+		return rows, err
+	}
+
+	if err := out.BeginSection("costs", costExportColumns); err != nil {
+		return rows, err
+	}
+	err = s.exportRepo.EachCostBatch(ctx, userID, filter, func(batch []model.Cost) error {
+		for _, c := range batch {
+			var category any
+			if c.Category != nil {
+				category = c.Category.Name
+			}
+			row := []any{
+				c.ID.String(), c.IncurredAt, c.Title, c.Amount, c.Currency, c.CategoryID.String(), category,
+				tagNames(c.Tags), c.CreatedAt,
+			}
+			if err := out.WriteRow(row); err != nil {
+				return err
+			}
+			rows++
+		}
+		return nil
+	})
+	if err != nil {
+		return rows, err
+	}
+
+	return rows, out.Close()
+}
+
+func (s *exportService) StartJob(ctx context.Context, userID uuid.UUID, format string, filter repository.ExportFilter) (*dto.ExportJobResponse, error) {
+	if !exporter.IsSupported(format) {
+		return nil, fmt.Errorf("%w: unsupported export format %q", constant.ErrInvalidInput, format)
+	}
+
+	jobs, err := s.exportRepo.ListJobsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range jobs {
+		if isExportJobActive(&jobs[i]) {
+			return nil, constant.ErrExportInProgress
+		}
+	}
+	s.purgeExpired(ctx, userID)
+
+	now := time.Now()
+	job := &model.ExportJob{
+		UserID:   userID,
+		Format:   format,
+		Status:   model.ExportJobPending,
+		DateFrom: filter.From,
+		FileName: "nexo-export-" + now.Format("20060102") + exporter.FileExtension(format),
+	}
+	if filter.To != nil {
+		// Stored inclusive, like the query parameter it came from
+		to := filter.To.AddDate(0, 0, -1)
+		job.DateTo = &to
+	}
+	if tags, _ := filter.Filters["tags"].(string); tags != "" {
+		job.Tags = &tags
+	}
+	if mode, _ := filter.Filters["tagMode"].(string); mode != "" {
+		job.TagMode = &mode
+	}
+	if err := s.exportRepo.Create(ctx, job); err != nil {
+		return nil, err
+	}
+
+	go s.run(*job, filter)
+
+	resp := toExportJobResponse(job)
+	return &resp, nil
+}
+
+// run generates the export file in the background. It uses its own context
+// because the request that queued the job is already finished.
+func (s *exportService) run(job model.ExportJob, filter repository.ExportFilter) {
+	ctx, cancel := context.WithDeadline(context.Background(), job.CreatedAt.Add(exportJobTimeout))
+	defer cancel()
+
+	select {
+	case s.workers <- struct{}{}:
+		defer func() { <-s.workers }()
+	case <-ctx.Done():
+		s.failJob(job.ID, ctx.Err())
+		return
+	}
+
+	if err := s.exportRepo.UpdateFields(ctx, job.ID, map[string]interface{}{"status": model.ExportJobRunning}); err != nil {
+		s.failJob(job.ID, err)
+		return
+	}
+
+	key := fmt.Sprintf("exports/%s/%s%s", job.UserID, job.ID, exporter.FileExtension(job.Format))
+	size, rows, err := s.generate(ctx, job, filter, key)
+	if err != nil {
+		s.failJob(job.ID, err)
+		return
+	}
+
+	now := time.Now()
+	err = s.exportRepo.UpdateFields(ctx, job.ID, map[string]interface{}{
+		"status":       model.ExportJobCompleted,
+		"storage_key":  key,
+		"size":         size,
+		"row_count":    rows,
+		"completed_at": now,
+		"expires_at":   now.Add(exportFileTTL),
+	})
+	if err != nil {
+		_ = s.store.Delete(context.Background(), key)
+		s.failJob(job.ID, err)
+	}
+}
+
+// generate writes the export to a temporary file first, because the blob
+// store needs the size of the object up front
+func (s *exportService) generate(ctx context.Context, job model.ExportJob, filter repository.ExportFilter, key string) (int64, int64, error) {
+	tmp, err := os.CreateTemp("", "nexo-export-*")
+	if err != nil {
+		return 0, 0, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	rows, err := s.write(ctx, job.UserID, job.Format, filter, tmp)
+	if err != nil {
+		return 0, 0, err
+	}
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, 0, err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return 0, 0, err
+	}
+	if err := s.store.Put(ctx, key, tmp, size, exporter.ContentType(job.Format)); err != nil {
+		return 0, 0, err
+	}
+	return size, rows, nil
+}
+
+func (s *exportService) failJob(id uuid.UUID, cause error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_ = s.exportRepo.UpdateFields(ctx, id, map[string]interface{}{
+		"status": model.ExportJobFailed,
+		"error":  cause.Error(),
+	})
+}
+
+// purgeExpired removes the user's expired export files. Failures are ignored
+// and retried on the next export.
+func (s *exportService) purgeExpired(ctx context.Context, userID uuid.UUID) {
+	jobs, err := s.exportRepo.ListExpiredJobs(ctx, userID, time.Now())
+	if err != nil {
+		return
+	}
+	for _, job := range jobs {
+		if job.StorageKey != nil {
+			if err := s.store.Delete(ctx, *job.StorageKey); err != nil {
+				continue
+			}
+		}
+		_ = s.exportRepo.Delete(ctx, job.ID)
+	}
+}
+
+func (s *exportService) ListJobs(ctx context.Context, userID uuid.UUID) ([]dto.ExportJobResponse, error) {
+	jobs, err := s.exportRepo.ListJobsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	resp := make([]dto.ExportJobResponse, len(jobs))
+	for i := range jobs {
+		resp[i] = toExportJobResponse(&jobs[i])
+	}
+	return resp, nil
+}
+
+func (s *exportService) GetJob(ctx context.Context, userID, id uuid.UUID) (*dto.ExportJobResponse, error) {
+	job, err := s.getOwnedJob(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	resp := toExportJobResponse(job)
+	return &resp, nil
+}
+
+func (s *exportService) OpenJobFile(ctx context.Context, userID, id uuid.UUID) (*model.ExportJob, io.ReadCloser, error) {
+	job, err := s.getOwnedJob(ctx, userID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !isExportJobDownloadable(job) {
+		return nil, nil, constant.ErrNotFound
+	}
+	content, err := s.store.Get(ctx, *job.StorageKey)
+	if err != nil {
+		if err == storage.ErrObjectNotFound {
+			return nil, nil, constant.ErrNotFound
+		}
+		return nil, nil, err
+	}
+	return job, content, nil
+}
+
+func (s *exportService) DeleteJob(ctx context.Context, userID, id uuid.UUID) error {
+	job, err := s.getOwnedJob(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if isExportJobActive(job) {
+		return constant.ErrExportInProgress
+	}
+	if job.StorageKey != nil {
+		if err := s.store.Delete(ctx, *job.StorageKey); err != nil {
+			return err
+		}
+	}
+	return s.exportRepo.Delete(ctx, id)
+}
+
+func (s *exportService) getOwnedJob(ctx context.Context, userID, id uuid.UUID) (*model.ExportJob, error) {
+	job, err := s.exportRepo.GetByID(ctx, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, constant.ErrNotFound
+		}
+		return nil, err
+	}
+	if job.UserID != userID {
+		return nil, constant.ErrNotFound
+	}
+	return job, nil
+}
+
+// isExportJobActive reports whether the job is still queued or running. Jobs
+// past their deadline were interrupted, e.g. by a restart, and are not.
+func isExportJobActive(job *model.ExportJob) bool {
+	if job.Status != model.ExportJobPending && job.Status != model.ExportJobRunning {
+		return false
+	}
+	return time.Since(job.CreatedAt) < exportJobTimeout
+}
+
+func isExportJobDownloadable(job *model.ExportJob) bool {
+	return job.Status == model.ExportJobCompleted &&
+		job.StorageKey != nil &&
+		(job.ExpiresAt == nil || time.Now().Before(*job.ExpiresAt))
+}
+
+func toExportJobResponse(job *model.ExportJob) dto.ExportJobResponse {
+	resp := dto.ExportJobResponse{
+		ID:        job.ID.String(),
+		Format:    job.Format,
+		Status:    string(job.Status),
+		FileName:  job.FileName,
+		Size:      job.Size,
+		RowCount:  job.RowCount,
+		Error:     job.Error,
+		CreatedAt: job.CreatedAt.Format(time.RFC3339),
+	}
+
+	if (job.Status == model.ExportJobPending || job.Status == model.ExportJobRunning) && !isExportJobActive(job) {
+		resp.Status = string(model.ExportJobFailed)
+		interrupted := "export was interrupted"
+		resp.Error = &interrupted
+	}
+	if job.DateFrom != nil {
+		from := job.DateFrom.Format("2006-01-02")
+		resp.DateFrom = &from
+	}
+	if job.DateTo != nil {
+		to := job.DateTo.Format("2006-01-02")
+		resp.DateTo = &to
+	}
+	if job.CompletedAt != nil {
+		completed := job.CompletedAt.Format(time.RFC3339)
+		resp.CompletedAt = &completed
+	}
+	if job.ExpiresAt != nil {
+		expires := job.ExpiresAt.Format(time.RFC3339)
+		resp.ExpiresAt = &expires
+	}
+	if isExportJobDownloadable(job) {
+		url := "/api/v1/exports/jobs/" + job.ID.String() + "/download"
+		resp.DownloadURL = &url
+	}
+	return resp
+}
+
+// optionalString turns a nil pointer into an empty export cell
+func optionalString(s *string) any {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
+
+func tagNames(tags []model.Tag) string {
+	names := make([]string, len(tags))
+	for i, t := range tags {
+		names[i] = t.Name
+	}
+	return strings.Join(names, "; ")
+}
@@ -0,0 +1,579 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/constant"
+	"github.com/tyha2404/nexo-app-api/internal/dto"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"github.com/tyha2404/nexo-app-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Goal progress statuses
+const (
+	goalStatusAchieved   = "achieved"
+	goalStatusOnTrack    = "on_track"
+	goalStatusBehind     = "behind"
+	goalStatusOverdue    = "overdue"
+	goalStatusNoDeadline = "no_deadline"
+)
+
+type GoalService interface {
+	CreateGoal(ctx context.Context, userID uuid.UUID, req dto.CreateGoalRequest) (*dto.GoalResponse, error)
+	GetGoal(ctx context.Context, userID, id uuid.UUID) (*dto.GoalResponse, error)
+	ListGoals(ctx context.Context, userID uuid.UUID) ([]dto.GoalResponse, error)
+	UpdateGoal(ctx context.Context, userID, id uuid.UUID, req dto.UpdateGoalRequest) (*dto.GoalResponse, error)
+	DeleteGoal(ctx context.Context, userID, id uuid.UUID) error
+	AddContribution(ctx context.Context, userID, goalID uuid.UUID, req dto.CreateGoalContributionRequest) (*dto.GoalContributionResponse, error)
+	// ListContributions returns the manual contributions of a goal together
+	// with the transactions counting towards it, most recent first
+	ListContributions(ctx context.Context, userID, goalID uuid.UUID) ([]dto.GoalContributionResponse, error)
+	DeleteContribution(ctx context.Context, userID, goalID, id uuid.UUID) error
+	ListAlerts(ctx context.Context, userID, goalID uuid.UUID) ([]dto.GoalAlertResponse, error)
+	// CheckPace raises an alert for every goal that fell behind pace or
+	// missed its deadline, at most one per goal and month. It returns the
+	// number of alerts raised.
+	CheckPace(ctx context.Context, now time.Time) (int, error)
+}
+
+type goalService struct {
+	goalRepo     repository.GoalRepo
+	categoryRepo repository.CategoryRepo
+	tagRepo      repository.TagRepo
+	netWorthRepo repository.NetWorthRepo
+}
+
+func NewGoalService(goalRepo repository.GoalRepo, categoryRepo repository.CategoryRepo, tagRepo repository.TagRepo, netWorthRepo repository.NetWorthRepo) GoalService {
+	return &goalService{
+		goalRepo:     goalRepo,
+		categoryRepo: categoryRepo,
+		tagRepo:      tagRepo,
+		netWorthRepo: netWorthRepo,
+	}
+}
+
+func (s *goalService) CreateGoal(ctx context.Context, userID uuid.UUID, req dto.CreateGoalRequest) (*dto.GoalResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", constant.ErrInvalidInput)
+	}
+
+	goal := &model.Goal{
+		UserID:       userID,
+		Name:         name,
+		TargetAmount: req.TargetAmount,
+		StartDate:    goalToday(time.Now()),
+	}
+	if req.StartDate != nil {
+		date, err := time.Parse("2006-01-02", *req.StartDate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: startDate must be YYYY-MM-DD", constant.ErrInvalidInput)
+		}
+		goal.StartDate = date
+	}
+	if req.Deadline != nil {
+		date, err := time.Parse("2006-01-02", *req.Deadline)
+		if err != nil {
+			return nil, fmt.Errorf("%w: deadline must be YYYY-MM-DD", constant.ErrInvalidInput)
+		}
+		goal.Deadline = &date
+	}
+	if err := checkGoalDates(goal); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkLinks(ctx, userID, req.CategoryID, req.TagID, req.NetWorthItemID); err != nil {
+		return nil, err
+	}
+	goal.CategoryID = req.CategoryID
+	goal.TagID = req.TagID
+	goal.NetWorthItemID = req.NetWorthItemID
+
+	if err := s.goalRepo.Create(ctx, goal); err != nil {
+		return nil, err
+	}
+	return s.GetGoal(ctx, userID, goal.ID)
+}
+
+func (s *goalService) GetGoal(ctx context.Context, userID, id uuid.UUID) (*dto.GoalResponse, error) {
+	goal, err := s.getOwnedGoal(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	responses, err := s.toResponses(ctx, userID, []model.Goal{*goal}, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &responses[0], nil
+}
+
+func (s *goalService) ListGoals(ctx context.Context, userID uuid.UUID) ([]dto.GoalResponse, error) {
+	goals, err := s.goalRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponses(ctx, userID, goals, time.Now())
+}
+
+func (s *goalService) UpdateGoal(ctx context.Context, userID, id uuid.UUID, req dto.UpdateGoalRequest) (*dto.GoalResponse, error) {
+	goal, err := s.getOwnedGoal(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, fmt.Errorf("%w: name is required", constant.ErrInvalidInput)
+		}
+		goal.Name = name
+	}
+	if req.TargetAmount != nil {
+		goal.TargetAmount = *req.TargetAmount
+	}
+	if req.StartDate != nil {
+		date, err := time.Parse("2006-01-02", *req.StartDate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: startDate must be YYYY-MM-DD", constant.ErrInvalidInput)
+		}
+		goal.StartDate = date
+	}
+	if req.Deadline != nil {
+		if *req.Deadline == "" {
+			goal.Deadline = nil
+		} else {
+			date, err := time.Parse("2006-01-02", *req.Deadline)
+			if err != nil {
+				return nil, fmt.Errorf("%w: deadline must be YYYY-MM-DD", constant.ErrInvalidInput)
+			}
+			goal.Deadline = &date
+		}
+	}
+	if err := checkGoalDates(goal); err != nil {
+		return nil, err
+	}
+
+	// Only newly set links are checked, so a goal stays editable while its
+	// category is in the trash
+	var categoryID, tagID, itemID *uuid.UUID
+	if req.CategoryID.Set {
+		categoryID = req.CategoryID.Value
+		goal.CategoryID = req.CategoryID.Value
+	}
+	if req.TagID.Set {
+		tagID = req.TagID.Value
+		goal.TagID = req.TagID.Value
+	}
+	if req.NetWorthItemID.Set {
+		itemID = req.NetWorthItemID.Value
+		goal.NetWorthItemID = req.NetWorthItemID.Value
+	}
+	if err := s.checkLinks(ctx, userID, categoryID, tagID, itemID); err != nil {
+		return nil, err
+	}
+
+	if err := s.goalRepo.Update(ctx, goal); err != nil {
+		return nil, err
+	}
+	return s.GetGoal(ctx, userID, id)
+}
+
+func (s *goalService) DeleteGoal(ctx context.Context, userID, id uuid.UUID) error {
+	if _, err := s.getOwnedGoal(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.goalRepo.Delete(ctx, id)
+}
+
+func (s *goalService) AddContribution(ctx context.Context, userID, goalID uuid.UUID, req dto.CreateGoalContributionRequest) (*dto.GoalContributionResponse, error) {
+	if _, err := s.getOwnedGoal(ctx, userID, goalID); err != nil {
+		return nil, err
+	}
+	if req.Amount == 0 {
+		return nil, fmt.Errorf("%w: amount must not be zero", constant.ErrInvalidInput)
+	}
+
+	contributedOn := goalToday(time.Now())
+	if req.ContributedOn != nil {
+		date, err := time.Parse("2006-01-02", *req.ContributedOn)
+		if err != nil {
+			return nil, fmt.Errorf("%w: contributedOn must be YYYY-MM-DD", constant.ErrInvalidInput)
+		}
+		contributedOn = date
+	}
+
+	contribution := &model.GoalContribution{
+		UserID:        userID,
+		GoalID:        goalID,
+		Amount:        req.Amount,
+		ContributedOn: contributedOn,
+		Note:          req.Note,
+	}
+	if err := s.goalRepo.CreateContribution(ctx, contribution); err != nil {
+		return nil, err
+	}
+	return toManualContributionResponse(contribution), nil
+}
+
+func (s *goalService) ListContributions(ctx context.Context, userID, goalID uuid.UUID) ([]dto.GoalContributionResponse, error) {
+	goal, err := s.getOwnedGoal(ctx, userID, goalID)
+	if err != nil {
+		return nil, err
+	}
+
+	contributions, err := s.goalRepo.ListContributions(ctx, goalID)
+	if err != nil {
+		return nil, err
+	}
+	forest, err := loadCategoryForest(ctx, s.categoryRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+	transactions, err := s.goalRepo.ListTransactions(ctx, userID, goalTransactionFilter(goal, forest))
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.GoalContributionResponse, 0, len(contributions)+len(transactions))
+	for i := range contributions {
+		responses = append(responses, *toManualContributionResponse(&contributions[i]))
+	}
+	for _, t := range transactions {
+		amount := t.Amount
+		if t.Type == model.TransactionTypeIncome {
+			amount = -amount
+		}
+		responses = append(responses, dto.GoalContributionResponse{
+			Source:        "transaction",
+			ID:            t.ID.String(),
+			Amount:        amount,
+			ContributedOn: t.TransactionDate.Format("2006-01-02"),
+			Note:          t.Description,
+		})
+	}
+	// Both lists are sorted already; a stable sort keeps that within a day
+	sort.SliceStable(responses, func(i, j int) bool {
+		return responses[i].ContributedOn > responses[j].ContributedOn
+	})
+	return responses, nil
+}
+
+func (s *goalService) DeleteContribution(ctx context.Context, userID, goalID, id uuid.UUID) error {
+	if _, err := s.getOwnedGoal(ctx, userID, goalID); err != nil {
+		return err
+	}
+	if _, err := s.goalRepo.GetContribution(ctx, goalID, id); err != nil {
+		return err
+	}
+	return s.goalRepo.DeleteContribution(ctx, id)
+}
+
+func (s *goalService) ListAlerts(ctx context.Context, userID, goalID uuid.UUID) ([]dto.GoalAlertResponse, error) {
+	if _, err := s.getOwnedGoal(ctx, userID, goalID); err != nil {
+		return nil, err
+	}
+	alerts, err := s.goalRepo.ListAlerts(ctx, goalID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.GoalAlertResponse, 0, len(alerts))
+	for _, a := range alerts {
+		responses = append(responses, dto.GoalAlertResponse{
+			ID:          a.ID.String(),
+			AlertType:   a.AlertType,
+			Message:     a.Message,
+			TriggeredAt: a.TriggeredAt.Format(time.RFC3339),
+		})
+	}
+	return responses, nil
+}
+
+func (s *goalService) CheckPace(ctx context.Context, now time.Time) (int, error) {
+	goals, err := s.goalRepo.ListWithDeadline(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// Goals come ordered by user, so each user's goals are evaluated together
+	raised := 0
+	for start := 0; start < len(goals); {
+		end := start
+		for end < len(goals) && goals[end].UserID == goals[start].UserID {
+			end++
+		}
+		responses, err := s.toResponses(ctx, goals[start].UserID, goals[start:end], now)
+		if err != nil {
+			return raised, err
+		}
+
+		for i, resp := range responses {
+			goal := &goals[start+i]
+			progress := resp.Progress
+			if progress.Status != goalStatusBehind && progress.Status != goalStatusOverdue {
+				continue
+			}
+			last, err := s.goalRepo.LastAlertAt(ctx, goal.ID, model.AlertTypeGoalBehind)
+			if err != nil {
+				return raised, err
+			}
+			if last != nil && sameMonth(last.UTC(), now.UTC()) {
+				continue
+			}
+
+			alert := &model.Alert{
+				UserID:      goal.UserID,
+				GoalID:      &goal.ID,
+				AlertType:   model.AlertTypeGoalBehind,
+				TriggeredAt: now,
+				Message:     goalAlertMessage(goal, progress),
+			}
+			if err := s.goalRepo.CreateAlert(ctx, alert); err != nil {
+				return raised, err
+			}
+			raised++
+		}
+		start = end
+	}
+	return raised, nil
+}
+
+// toResponses computes the progress of goals of one user as of now
+func (s *goalService) toResponses(ctx context.Context, userID uuid.UUID, goals []model.Goal, now time.Time) ([]dto.GoalResponse, error) {
+	ids := make([]uuid.UUID, 0, len(goals))
+	for _, g := range goals {
+		ids = append(ids, g.ID)
+	}
+	contributed, err := s.goalRepo.ContributionTotals(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	forest, err := loadCategoryForest(ctx, s.categoryRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	today := goalToday(now)
+	responses := make([]dto.GoalResponse, 0, len(goals))
+	for i := range goals {
+		goal := &goals[i]
+		progress := dto.GoalProgressResponse{Contributed: roundCents(contributed[goal.ID])}
+
+		fromTransactions, err := s.goalRepo.TransactionTotal(ctx, userID, goalTransactionFilter(goal, forest))
+		if err != nil {
+			return nil, err
+		}
+		progress.FromTransactions = roundCents(fromTransactions)
+		if goal.NetWorthItemID != nil {
+			progress.FromAccount, err = s.accountValue(ctx, *goal.NetWorthItemID, today)
+			if err != nil {
+				return nil, err
+			}
+		}
+		progress.Saved = roundCents(progress.Contributed + progress.FromTransactions + progress.FromAccount)
+		goalPace(&progress, goal.TargetAmount, goal.StartDate, goal.Deadline, today)
+
+		responses = append(responses, toGoalResponse(goal, progress))
+	}
+	return responses, nil
+}
+
+// accountValue returns the latest valuation of an asset up to today
+func (s *goalService) accountValue(ctx context.Context, itemID uuid.UUID, today time.Time) (float64, error) {
+	valuations, err := s.netWorthRepo.ListValuations(ctx, itemID)
+	if err != nil {
+		return 0, err
+	}
+	for _, v := range valuations {
+		if !v.ValuedAt.After(today) {
+			return v.Value, nil
+		}
+	}
+	return 0, nil
+}
+
+// checkLinks verifies that the linked category, tag and asset, where given,
+// belong to the user
+func (s *goalService) checkLinks(ctx context.Context, userID uuid.UUID, categoryID, tagID, itemID *uuid.UUID) error {
+	if categoryID != nil {
+		category, err := s.categoryRepo.GetByID(ctx, *categoryID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if category == nil || category.UserID != userID {
+			return fmt.Errorf("%w: category %s not found", constant.ErrInvalidInput, *categoryID)
+		}
+	}
+	if tagID != nil {
+		tag, err := s.tagRepo.GetByID(ctx, *tagID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if tag == nil || tag.UserID != userID {
+			return fmt.Errorf("%w: tag %s not found", constant.ErrInvalidInput, *tagID)
+		}
+	}
+	if itemID != nil {
+		item, err := s.netWorthRepo.GetByID(ctx, *itemID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if item == nil || item.UserID != userID {
+			return fmt.Errorf("%w: net worth item %s not found", constant.ErrInvalidInput, *itemID)
+		}
+		if item.Kind != model.NetWorthKindAsset {
+			return fmt.Errorf("%w: a goal can only be linked to an asset", constant.ErrInvalidInput)
+		}
+	}
+	return nil
+}
+
+func (s *goalService) getOwnedGoal(ctx context.Context, userID, id uuid.UUID) (*model.Goal, error) {
+	goal, err := s.goalRepo.GetByID(ctx, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, constant.ErrNotFound
+		}
+		return nil, err
+	}
+	if goal.UserID != userID {
+		return nil, constant.ErrNotFound
+	}
+	return goal, nil
+}
+
+func checkGoalDates(goal *model.Goal) error {
+	if goal.Deadline != nil && goal.Deadline.Before(goal.StartDate) {
+		return fmt.Errorf("%w: deadline must not be before startDate", constant.ErrInvalidInput)
+	}
+	return nil
+}
+
+// goalTransactionFilter selects the transactions of the goal's category, its
+// subcategories included, and of its tag
+func goalTransactionFilter(goal *model.Goal, forest *categoryForest) repository.GoalTransactionFilter {
+	filter := repository.GoalTransactionFilter{Since: goal.StartDate, TagID: goal.TagID}
+	if goal.CategoryID != nil {
+		if _, ok := forest.byID[*goal.CategoryID]; ok {
+			filter.CategoryIDs = forest.subtree(*goal.CategoryID)
+		} else {
+			filter.CategoryIDs = []uuid.UUID{*goal.CategoryID}
+		}
+	}
+	return filter
+}
+
+// goalPace fills in the remaining amount, the pace and the status of a goal
+// whose Saved amount is set. The pace assumes an even amount is saved in
+// every calendar month from the start date to the deadline.
+func goalPace(p *dto.GoalProgressResponse, target float64, start time.Time, deadline *time.Time, today time.Time) {
+	p.Remaining = roundCents(math.Max(target-p.Saved, 0))
+	p.Percent = math.Round(p.Saved/target*10000) / 100
+
+	if deadline == nil {
+		p.Status = goalStatusNoDeadline
+		if p.Remaining == 0 {
+			p.Status = goalStatusAchieved
+		}
+		return
+	}
+
+	// Months are counted inclusively, so a goal due this month has one left
+	totalMonths := monthIndex(*deadline) - monthIndex(start) + 1
+	elapsed := monthIndex(today) - monthIndex(start)
+	elapsed = max(0, min(elapsed, totalMonths))
+	expected := roundCents(target * float64(elapsed) / float64(totalMonths))
+	p.ExpectedByNow = &expected
+
+	monthsLeft := max(monthIndex(*deadline)-monthIndex(today)+1, 0)
+	if today.After(*deadline) {
+		monthsLeft = 0
+	}
+	p.MonthsLeft = &monthsLeft
+	required := p.Remaining
+	if monthsLeft > 0 {
+		required = roundCents(p.Remaining / float64(monthsLeft))
+	}
+	p.RequiredMonthly = &required
+
+	switch {
+	case p.Remaining == 0:
+		p.Status = goalStatusAchieved
+	case today.After(*deadline):
+		p.Status = goalStatusOverdue
+	case p.Saved < expected:
+		p.Status = goalStatusBehind
+	default:
+		p.Status = goalStatusOnTrack
+	}
+}
+
+func goalAlertMessage(goal *model.Goal, p dto.GoalProgressResponse) string {
+	deadline := goal.Deadline.Format("2006-01-02")
+	if p.Status == goalStatusOverdue {
+		return fmt.Sprintf("%s missed its deadline of %s: %.2f of %.2f saved.", goal.Name, deadline, p.Saved, goal.TargetAmount)
+	}
+	return fmt.Sprintf("%s is behind pace: %.2f saved, %.2f expected by now. Saving %.2f a month reaches %.2f by %s.",
+		goal.Name, p.Saved, *p.ExpectedByNow, *p.RequiredMonthly, goal.TargetAmount, deadline)
+}
+
+// goalToday is the current day as stored in date columns
+func goalToday(now time.Time) time.Time {
+	now = now.UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func monthIndex(t time.Time) int {
+	return t.Year()*12 + int(t.Month()) - 1
+}
+
+func sameMonth(a, b time.Time) bool {
+	return a.Year() == b.Year() && a.Month() == b.Month()
+}
+
+func toGoalResponse(g *model.Goal, progress dto.GoalProgressResponse) dto.GoalResponse {
+	resp := dto.GoalResponse{
+		ID:           g.ID.String(),
+		Name:         g.Name,
+		TargetAmount: g.TargetAmount,
+		StartDate:    g.StartDate.Format("2006-01-02"),
+		Progress:     progress,
+		CreatedAt:    g.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:    g.UpdatedAt.Format(time.RFC3339),
+	}
+	if g.Deadline != nil {
+		deadline := g.Deadline.Format("2006-01-02")
+		resp.Deadline = &deadline
+	}
+	if g.CategoryID != nil {
+		id := g.CategoryID.String()
+		resp.CategoryID = &id
+	}
+	if g.TagID != nil {
+		id := g.TagID.String()
+		resp.TagID = &id
+	}
+	if g.NetWorthItemID != nil {
+		id := g.NetWorthItemID.String()
+		resp.NetWorthItemID = &id
+	}
+	return resp
+}
+
+func toManualContributionResponse(c *model.GoalContribution) *dto.GoalContributionResponse {
+	return &dto.GoalContributionResponse{
+		Source:        "manual",
+		ID:            c.ID.String(),
+		Amount:        c.Amount,
+		ContributedOn: c.ContributedOn.Format("2006-01-02"),
+		Note:          c.Note,
+	}
+}
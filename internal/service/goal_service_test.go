@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/dto"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"github.com/tyha2404/nexo-app-api/internal/repository"
+)
+
+func TestGoalPace(t *testing.T) {
+	deadline := mustDate(t, "2024-12-31")
+	tests := []struct {
+		name          string
+		saved         float64
+		deadline      *time.Time
+		today         string
+		wantStatus    string
+		wantRemaining float64
+		wantPercent   float64
+		wantExpected  float64
+		wantMonths    int
+		wantRequired  float64
+	}{
+		{"exactly on pace", 300, &deadline, "2024-04-15", goalStatusOnTrack, 900, 25, 300, 9, 100},
+		{"a cent behind", 299.99, &deadline, "2024-04-15", goalStatusBehind, 900.01, 25, 300, 9, 100},
+		{"ahead", 600, &deadline, "2024-04-15", goalStatusOnTrack, 600, 50, 300, 9, 66.67},
+		{"achieved", 1200, &deadline, "2024-04-15", goalStatusAchieved, 0, 100, 300, 9, 0},
+		{"beyond the target", 1500, &deadline, "2024-04-15", goalStatusAchieved, 0, 125, 300, 9, 0},
+		{"withdrawn", -100, &deadline, "2024-04-15", goalStatusBehind, 1300, -8.33, 300, 9, 144.44},
+		{"not started", 0, &deadline, "2023-12-20", goalStatusOnTrack, 1200, 0, 0, 13, 92.31},
+		{"first month", 0, &deadline, "2024-01-31", goalStatusOnTrack, 1200, 0, 0, 12, 100},
+		{"deadline day", 1100, &deadline, "2024-12-31", goalStatusOnTrack, 100, 91.67, 1100, 1, 100},
+		{"overdue", 1000, &deadline, "2025-01-01", goalStatusOverdue, 200, 83.33, 1200, 0, 200},
+		{"achieved after the deadline", 1200, &deadline, "2025-01-01", goalStatusAchieved, 0, 100, 1200, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := dto.GoalProgressResponse{Saved: tt.saved}
+			goalPace(&p, 1200, mustDate(t, "2024-01-01"), tt.deadline, mustDate(t, tt.today))
+			if p.Status != tt.wantStatus || p.Remaining != tt.wantRemaining || p.Percent != tt.wantPercent {
+				t.Errorf("status %s, remaining %v, percent %v; want %s, %v, %v", p.Status, p.Remaining, p.Percent, tt.wantStatus, tt.wantRemaining, tt.wantPercent)
+			}
+			if p.ExpectedByNow == nil || p.MonthsLeft == nil || p.RequiredMonthly == nil {
+				t.Fatalf("pace not set: %+v", p)
+			}
+			if *p.ExpectedByNow != tt.wantExpected || *p.MonthsLeft != tt.wantMonths || *p.RequiredMonthly != tt.wantRequired {
+				t.Errorf("expected %v, months left %d, required %v; want %v, %d, %v",
+					*p.ExpectedByNow, *p.MonthsLeft, *p.RequiredMonthly, tt.wantExpected, tt.wantMonths, tt.wantRequired)
+			}
+		})
+	}
+
+	t.Run("no deadline", func(t *testing.T) {
+		for saved, want := range map[float64]string{500: goalStatusNoDeadline, 1200: goalStatusAchieved} {
+			p := dto.GoalProgressResponse{Saved: saved}
+			goalPace(&p, 1200, mustDate(t, "2024-01-01"), nil, mustDate(t, "2024-04-15"))
+			if p.Status != want || p.ExpectedByNow != nil || p.MonthsLeft != nil || p.RequiredMonthly != nil {
+				t.Errorf("saved %v: %+v, want status %s without a pace", saved, p, want)
+			}
+		}
+	})
+}
+
+// paceRepo serves the goal queries of CheckPace from memory
+type paceRepo struct {
+	repository.GoalRepo
+	goals       []model.Goal
+	contributed map[uuid.UUID]float64
+	lastAlerts  map[uuid.UUID]time.Time
+	alerts      []model.Alert
+}
+
+func (r *paceRepo) ListWithDeadline(context.Context) ([]model.Goal, error) {
+	return r.goals, nil
+}
+
+func (r *paceRepo) ContributionTotals(_ context.Context, ids []uuid.UUID) (map[uuid.UUID]float64, error) {
+	totals := make(map[uuid.UUID]float64)
+	for _, id := range ids {
+		totals[id] = r.contributed[id]
+	}
+	return totals, nil
+}
+
+func (r *paceRepo) TransactionTotal(context.Context, uuid.UUID, repository.GoalTransactionFilter) (float64, error) {
+	return 0, nil
+}
+
+func (r *paceRepo) LastAlertAt(_ context.Context, goalID uuid.UUID, _ string) (*time.Time, error) {
+	if last, ok := r.lastAlerts[goalID]; ok {
+		return &last, nil
+	}
+	return nil, nil
+}
+
+func (r *paceRepo) CreateAlert(_ context.Context, alert *model.Alert) error {
+	r.alerts = append(r.alerts, *alert)
+	return nil
+}
+
+type paceCategoryRepo struct {
+	repository.CategoryRepo
+}
+
+func (paceCategoryRepo) ListByUserID(context.Context, uuid.UUID) ([]model.Category, error) {
+	return nil, nil
+}
+
+func TestCheckPace(t *testing.T) {
+	now := time.Date(2024, 4, 15, 9, 0, 0, 0, time.UTC)
+	alice, bob := uuid.New(), uuid.New()
+	goal := func(userID uuid.UUID, name, deadline string) model.Goal {
+		d := mustDate(t, deadline)
+		return model.Goal{ID: uuid.New(), UserID: userID, Name: name, TargetAmount: 1200, StartDate: mustDate(t, "2024-01-01"), Deadline: &d}
+	}
+	behind := goal(alice, "Bike", "2024-12-31")
+	onTrack := goal(alice, "Laptop", "2024-12-31")
+	alerted := goal(alice, "Trip", "2024-12-31")
+	overdue := goal(bob, "Camera", "2024-03-31")
+	alertedLastMonth := goal(bob, "Sofa", "2024-12-31")
+
+	repo := &paceRepo{
+		goals: []model.Goal{behind, onTrack, alerted, overdue, alertedLastMonth},
+		contributed: map[uuid.UUID]float64{
+			behind.ID:           100,
+			onTrack.ID:          300,
+			alerted.ID:          100,
+			overdue.ID:          1000,
+			alertedLastMonth.ID: 100,
+		},
+		lastAlerts: map[uuid.UUID]time.Time{
+			alerted.ID:          time.Date(2024, 4, 1, 9, 0, 0, 0, time.UTC),
+			alertedLastMonth.ID: time.Date(2024, 3, 31, 23, 0, 0, 0, time.UTC),
+		},
+	}
+	svc := NewGoalService(repo, paceCategoryRepo{}, nil, nil)
+
+	raised, err := svc.CheckPace(context.Background(), now)
+	if err != nil {
+		t.Fatalf("CheckPace: %v", err)
+	}
+	if raised != 3 || len(repo.alerts) != 3 {
+		t.Fatalf("raised %d alerts, want 3", raised)
+	}
+
+	want := map[uuid.UUID]string{
+		behind.ID:           "Bike is behind pace: 100.00 saved, 300.00 expected by now. Saving 122.22 a month reaches 1200.00 by 2024-12-31.",
+		overdue.ID:          "Camera missed its deadline of 2024-03-31: 1000.00 of 1200.00 saved.",
+		alertedLastMonth.ID: "Sofa is behind pace",
+	}
+	for _, a := range repo.alerts {
+		if a.GoalID == nil || a.AlertType != model.AlertTypeGoalBehind || !a.TriggeredAt.Equal(now) {
+			t.Errorf("alert = %+v", a)
+			continue
+		}
+		message, ok := want[*a.GoalID]
+		if !ok {
+			t.Errorf("unexpected alert for goal %s: %s", *a.GoalID, a.Message)
+			continue
+		}
+		if !strings.HasPrefix(a.Message, message) {
+			t.Errorf("message = %q, want %q", a.Message, message)
+		}
+	}
+}
@@ -0,0 +1,494 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/constant"
+	"github.com/tyha2404/nexo-app-api/internal/dto"
+	"github.com/tyha2404/nexo-app-api/internal/importer"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"github.com/tyha2404/nexo-app-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+// ImportOptions controls how a parsed statement is turned into transactions
+type ImportOptions struct {
+	// DefaultCategoryID is used for entries without a recognized category.
+	// Entries of the type the category is not meant for are invalid.
+	DefaultCategoryID uuid.UUID
+	// DryRun only previews the import without writing anything
+	DryRun bool
+	// SkipInvalid commits the valid rows even if some rows have errors
+	SkipInvalid bool
+}
+
+type ImportService interface {
+	// Import previews or commits a parsed statement. Every file format goes
+	// through this method after parsing.
+	Import(ctx context.Context, userID uuid.UUID, stmt *importer.Statement, opts ImportOptions) (*dto.ImportResultResponse, error)
+	ImportCSV(ctx context.Context, userID uuid.UUID, file io.Reader, req dto.CSVImportRequest) (*dto.ImportResultResponse, error)
+	ImportStatement(ctx context.Context, userID uuid.UUID, format string, file io.Reader, req dto.StatementImportRequest) (*dto.ImportResultResponse, error)
+	ListProfiles(ctx context.Context, userID uuid.UUID) ([]model.ImportProfile, error)
+	DeleteProfile(ctx context.Context, userID, id uuid.UUID) error
+}
+
+type importService struct {
+	transactionRepo repository.TransactionRepository
+	categoryRepo    repository.CategoryRepo
+	profileRepo     repository.ImportProfileRepo
+	ruleRepo        repository.CategorizationRuleRepo
+	suggester       CategorySuggester
+}
+
+func NewImportService(
+	transactionRepo repository.TransactionRepository,
+	categoryRepo repository.CategoryRepo,
+	profileRepo repository.ImportProfileRepo,
+	ruleRepo repository.CategorizationRuleRepo,
+	suggester CategorySuggester,
+) ImportService {
+	return &importService{
+		transactionRepo: transactionRepo,
+		categoryRepo:    categoryRepo,
+		profileRepo:     profileRepo,
+		ruleRepo:        ruleRepo,
+		suggester:       suggester,
+	}
+}
+
+func (s *importService) ImportCSV(ctx context.Context, userID uuid.UUID, file io.Reader, req dto.CSVImportRequest) (*dto.ImportResultResponse, error) {
+	var opts importer.CSVOptions
+	var profile *model.ImportProfile
+
+	switch {
+	case req.Mapping != nil:
+		opts = csvOptionsFromMapping(*req.Mapping)
+	case req.ProfileID != nil:
+		p, err := s.getOwnedProfile(ctx, userID, *req.ProfileID)
+		if err != nil {
+			return nil, err
+		}
+		profile = p
+		opts = csvOptionsFromProfile(p)
+	default:
+		return nil, fmt.Errorf("%w: mapping or profileId is required", constant.ErrInvalidInput)
+	}
+
+	stmt, err := importer.ParseCSV(file, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", constant.ErrInvalidInput, err)
+	}
+
+	dryRun := true
+	if req.DryRun != nil {
+		dryRun = *req.DryRun
+	}
+	var profileName string
+	if req.SaveProfileAs != nil {
+		if profileName = strings.TrimSpace(*req.SaveProfileAs); profileName == "" {
+			return nil, fmt.Errorf("%w: profile name is required", constant.ErrInvalidInput)
+		}
+	}
+
+	result, err := s.Import(ctx, userID, stmt, ImportOptions{
+		DefaultCategoryID: req.DefaultCategoryID,
+		DryRun:            dryRun,
+		SkipInvalid:       req.SkipInvalid,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// A preview has no side effects, so the mapping is only saved once the
+	// import is committed
+	if profileName != "" && !dryRun {
+		if profile, err = s.saveProfile(ctx, userID, profileName, opts); err != nil {
+			return nil, err
+		}
+	}
+	if profile != nil {
+		id := profile.ID.String()
+		result.ProfileID = &id
+	}
+	return result, nil
+}
+
+// ImportStatement parses a self-describing bank statement and runs it through
+// the same preview/commit pipeline as CSV imports
+func (s *importService) ImportStatement(ctx context.Context, userID uuid.UUID, format string, file io.Reader, req dto.StatementImportRequest) (*dto.ImportResultResponse, error) {
+	var (
+		stmt *importer.Statement
+		err  error
+	)
+	switch format {
+	case importer.FormatOFX, importer.FormatQFX:
+		stmt, err = importer.ParseOFX(file)
+	case importer.FormatQIF:
+		stmt, err = importer.ParseQIF(file, req.DateOrder)
+	case importer.FormatCAMT053:
+		stmt, err = importer.ParseCAMT053(file)
+	case importer.FormatMT940:
+		stmt, err = importer.ParseMT940(file)
+	default:
+		return nil, fmt.Errorf("%w: unsupported import format %q", constant.ErrInvalidInput, format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", constant.ErrInvalidInput, err)
+	}
+
+	dryRun := true
+	if req.DryRun != nil {
+		dryRun = *req.DryRun
+	}
+
+	return s.Import(ctx, userID, stmt, ImportOptions{
+		DefaultCategoryID: req.DefaultCategoryID,
+		DryRun:            dryRun,
+		SkipInvalid:       req.SkipInvalid,
+	})
+}
+
+func (s *importService) Import(ctx context.Context, userID uuid.UUID, stmt *importer.Statement, opts ImportOptions) (*dto.ImportResultResponse, error) {
+	defaultCategory, err := s.categoryRepo.GetByID(ctx, opts.DefaultCategoryID)
+	if err != nil || defaultCategory.UserID != userID {
+		return nil, fmt.Errorf("%w: default category not found", constant.ErrInvalidInput)
+	}
+	if defaultCategory.ArchivedAt != nil {
+		return nil, fmt.Errorf("%w: default category %q is archived", constant.ErrInvalidInput, defaultCategory.Name)
+	}
+
+	categories, err := s.categoryRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	categoryByName := make(map[string]*model.Category, len(categories))
+	for i := range categories {
+		categoryByName[strings.ToLower(categories[i].Name)] = &categories[i]
+	}
+
+	rules, err := loadRuleEngine(ctx, s.ruleRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	detector, err := s.newDuplicateDetector(ctx, userID, stmt)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.ImportResultResponse{
+		DryRun:    opts.DryRun,
+		AccountID: stmt.AccountID,
+		TotalRows: len(stmt.Entries),
+		Rows:      make([]dto.ImportRowResponse, 0, len(stmt.Entries)),
+	}
+	if result.BalanceCheck = checkBalance(stmt); result.BalanceCheck != nil {
+		if err := s.checkStoredBalance(ctx, userID, stmt, result.BalanceCheck); err != nil {
+			return nil, err
+		}
+	}
+
+	var pending []model.Transaction
+	for _, entry := range stmt.Entries {
+		if entry.Valid() && entry.Amount == 0 {
+			entry.Errors = append(entry.Errors, "amount must not be zero")
+		}
+
+		row := dto.ImportRowResponse{
+			Row:        entry.Row,
+			Amount:     math.Abs(entry.Amount),
+			ExternalID: entry.ExternalID,
+			Errors:     entry.Errors,
+		}
+		if entry.Description != "" {
+			description := entry.Description
+			row.Description = &description
+		}
+
+		if !entry.Valid() {
+			result.InvalidRows++
+			result.Rows = append(result.Rows, row)
+			continue
+		}
+
+		transaction := model.Transaction{
+			UserID:          userID,
+			Amount:          math.Abs(entry.Amount),
+			Type:            entryType(entry.Amount),
+			Description:     row.Description,
+			TransactionDate: truncateToDate(entry.Date),
+		}
+		if key := stmt.ExternalKey(entry); key != "" {
+			transaction.ExternalID = &key
+		}
+		if stmt.AccountID != "" {
+			accountID := stmt.AccountID
+			transaction.AccountID = &accountID
+		}
+
+		// Rules take precedence over the bank's category, which takes
+		// precedence over the default. Archived categories and those meant
+		// for the other type are passed over.
+		outcome := rules.evaluate(&transaction)
+		outcome.applyTo(&transaction, false)
+		category := outcome.Category
+		if c, ok := categoryByName[strings.ToLower(entry.CategoryName)]; category == nil && ok && checkNewCategory(c, transaction.Type) == nil {
+			category = c
+		}
+		if category == nil {
+			if err := checkCategoryKind(defaultCategory, transaction.Type); err != nil {
+				row.Errors = append(row.Errors, fmt.Sprintf("no category for %s transactions: default category %q is for %s transactions",
+					strings.ToLower(string(transaction.Type)), defaultCategory.Name, strings.ToLower(string(*defaultCategory.Kind))))
+				result.InvalidRows++
+				result.Rows = append(result.Rows, row)
+				continue
+			}
+			category = defaultCategory
+		}
+		transaction.CategoryID = category.ID
+		for _, id := range outcome.RuleIDs {
+			row.RuleIDs = append(row.RuleIDs, id.String())
+		}
+		for _, tag := range transaction.Tags {
+			row.TagIDs = append(row.TagIDs, tag.ID.String())
+		}
+
+		row.Date = transaction.TransactionDate.Format("2006-01-02")
+		row.Type = string(transaction.Type)
+		row.Description = transaction.Description
+		row.CategoryID = transaction.CategoryID.String()
+		if duplicateOf, duplicate := detector.match(&transaction); duplicate {
+			row.Duplicate = true
+			if duplicateOf != uuid.Nil {
+				row.DuplicateOf = duplicateOf.String()
+			}
+		}
+
+		result.ValidRows++
+		if row.Duplicate {
+			result.DuplicateRows++
+		} else {
+			pending = append(pending, transaction)
+		}
+		result.Rows = append(result.Rows, row)
+	}
+
+	if opts.DryRun {
+		return result, nil
+	}
+
+	if result.InvalidRows > 0 && !opts.SkipInvalid {
+		return nil, fmt.Errorf("%w: %d rows have errors, fix them or set skipInvalid", constant.ErrInvalidInput, result.InvalidRows)
+	}
+
+	if err := s.transactionRepo.CreateBatch(ctx, pending); err != nil {
+		return nil, err
+	}
+	result.ImportedRows = len(pending)
+	s.suggester.Invalidate(userID)
+
+	return result, nil
+}
+
+func (s *importService) ListProfiles(ctx context.Context, userID uuid.UUID) ([]model.ImportProfile, error) {
+	return s.profileRepo.ListByUserID(ctx, userID)
+}
+
+func (s *importService) DeleteProfile(ctx context.Context, userID, id uuid.UUID) error {
+	if _, err := s.getOwnedProfile(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.profileRepo.Delete(ctx, id)
+}
+
+// saveProfile creates the named profile or overwrites an existing one with
+// the same name
+func (s *importService) saveProfile(ctx context.Context, userID uuid.UUID, name string, opts importer.CSVOptions) (*model.ImportProfile, error) {
+	if name == "" {
+		return nil, fmt.Errorf("%w: profile name is required", constant.ErrInvalidInput)
+	}
+
+	profile, err := s.profileRepo.FindByName(ctx, userID, name)
+	if err != nil && err != constant.ErrNotFound {
+		return nil, err
+	}
+	if profile == nil {
+		profile = &model.ImportProfile{UserID: userID, Name: name}
+	}
+
+	profile.Delimiter = opts.Delimiter
+	if profile.Delimiter == "" {
+		profile.Delimiter = ","
+	}
+	profile.DecimalSeparator = opts.DecimalSeparator
+	if profile.DecimalSeparator == "" {
+		profile.DecimalSeparator = "."
+	}
+	profile.DateFormat = opts.DateFormat
+	profile.HasHeader = opts.HasHeader
+	profile.DateColumn = opts.DateColumn
+	profile.AmountColumn = opts.AmountColumn
+	profile.DebitColumn = opts.DebitColumn
+	profile.CreditColumn = opts.CreditColumn
+	profile.DescriptionColumn = opts.DescriptionColumn
+	profile.TypeColumn = opts.TypeColumn
+	profile.CategoryColumn = opts.CategoryColumn
+
+	if profile.ID == uuid.Nil {
+		err = s.profileRepo.Create(ctx, profile)
+	} else {
+		err = s.profileRepo.Update(ctx, profile)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+func (s *importService) getOwnedProfile(ctx context.Context, userID, id uuid.UUID) (*model.ImportProfile, error) {
+	profile, err := s.profileRepo.GetByID(ctx, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, constant.ErrNotFound
+		}
+		return nil, err
+	}
+	if profile.UserID != userID {
+		return nil, constant.ErrNotFound
+	}
+	return profile, nil
+}
+
+func (s *importService) newDuplicateDetector(ctx context.Context, userID uuid.UUID, stmt *importer.Statement) (*duplicateDetector, error) {
+	var keys []string
+	for _, e := range stmt.Entries {
+		if key := stmt.ExternalKey(e); key != "" {
+			keys = append(keys, key)
+		}
+	}
+
+	var from, to time.Time
+	for _, e := range stmt.Entries {
+		if !e.Valid() {
+			continue
+		}
+		d := truncateToDate(e.Date)
+		if from.IsZero() || d.Before(from) {
+			from = d
+		}
+		if to.IsZero() || d.After(to) {
+			to = d
+		}
+	}
+
+	return loadDuplicateDetector(ctx, s.transactionRepo, userID, keys, from, to, duplicateWindowDays)
+}
+
+// checkBalance recomputes the closing balance from the opening balance and
+// the statement entries. A difference means the file is truncated, holds
+// entries that failed to parse, or was filtered by the bank. Formats that do
+// not report both balances are not checked.
+func checkBalance(stmt *importer.Statement) *dto.BalanceCheckResponse {
+	if stmt.OpeningBalance == nil || stmt.ClosingBalance == nil {
+		return nil
+	}
+
+	var cents int64
+	for _, e := range stmt.Entries {
+		cents += int64(math.Round(e.Amount * 100))
+	}
+	opening := int64(math.Round(stmt.OpeningBalance.Amount * 100))
+	closing := int64(math.Round(stmt.ClosingBalance.Amount * 100))
+	computed := opening + cents
+
+	return &dto.BalanceCheckResponse{
+		Currency:               stmt.Currency,
+		OpeningBalance:         float64(opening) / 100,
+		OpeningDate:            stmt.OpeningBalance.Date.Format("2006-01-02"),
+		ClosingBalance:         float64(closing) / 100,
+		ClosingDate:            stmt.ClosingBalance.Date.Format("2006-01-02"),
+		ComputedClosingBalance: float64(computed) / 100,
+		Difference:             float64(closing-computed) / 100,
+		Matches:                closing == computed,
+	}
+}
+
+// checkStoredBalance compares the opening balance of the statement with the
+// balance of its account computed from the stored transactions. The opening
+// balance holds at the end of its date, and the statement's own entries may
+// already be stored from an earlier import, so transactions count up to the
+// opening date but not from the first entry on.
+func (s *importService) checkStoredBalance(ctx context.Context, userID uuid.UUID, stmt *importer.Statement, check *dto.BalanceCheckResponse) error {
+	if stmt.AccountID == "" {
+		return nil
+	}
+	cutoff := truncateToDate(stmt.OpeningBalance.Date).AddDate(0, 0, 1)
+	for _, e := range stmt.Entries {
+		if d := truncateToDate(e.Date); e.Valid() && d.Before(cutoff) {
+			cutoff = d
+		}
+	}
+
+	balance, count, err := s.transactionRepo.AccountBalance(ctx, userID, stmt.AccountID, cutoff)
+	if err != nil || count == 0 {
+		return err
+	}
+	stored := int64(math.Round(balance * 100))
+	opening := int64(math.Round(stmt.OpeningBalance.Amount * 100))
+	storedBalance := float64(stored) / 100
+	difference := float64(opening-stored) / 100
+	matches := opening == stored
+	check.StoredOpeningBalance = &storedBalance
+	check.OpeningDifference = &difference
+	check.OpeningMatches = &matches
+	return nil
+}
+
+// entryType derives the transaction type from the sign of a statement amount
+func entryType(amount float64) model.TransactionType {
+	if amount < 0 {
+		return model.TransactionTypeExpense
+	}
+	return model.TransactionTypeIncome
+}
+
+func truncateToDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func csvOptionsFromMapping(m dto.CSVMapping) importer.CSVOptions {
+	return importer.CSVOptions{
+		Delimiter:         m.Delimiter,
+		DateFormat:        m.DateFormat,
+		DecimalSeparator:  m.DecimalSeparator,
+		HasHeader:         m.HasHeader,
+		DateColumn:        m.DateColumn,
+		AmountColumn:      m.AmountColumn,
+		DebitColumn:       m.DebitColumn,
+		CreditColumn:      m.CreditColumn,
+		DescriptionColumn: m.DescriptionColumn,
+		TypeColumn:        m.TypeColumn,
+		CategoryColumn:    m.CategoryColumn,
+	}
+}
+
+func csvOptionsFromProfile(p *model.ImportProfile) importer.CSVOptions {
+	return importer.CSVOptions{
+		Delimiter:         p.Delimiter,
+		DateFormat:        p.DateFormat,
+		DecimalSeparator:  p.DecimalSeparator,
+		HasHeader:         p.HasHeader,
+		DateColumn:        p.DateColumn,
+		AmountColumn:      p.AmountColumn,
+		DebitColumn:       p.DebitColumn,
+		CreditColumn:      p.CreditColumn,
+		DescriptionColumn: p.DescriptionColumn,
+		TypeColumn:        p.TypeColumn,
+		CategoryColumn:    p.CategoryColumn,
+	}
+}
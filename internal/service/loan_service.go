@@ -0,0 +1,353 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/constant"
+	"github.com/tyha2404/nexo-app-api/internal/dto"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"github.com/tyha2404/nexo-app-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+type LoanService interface {
+	CreateLoan(ctx context.Context, userID uuid.UUID, req dto.CreateLoanRequest) (*dto.LoanResponse, error)
+	GetLoan(ctx context.Context, userID, id uuid.UUID) (*dto.LoanResponse, error)
+	ListLoans(ctx context.Context, userID uuid.UUID) ([]dto.LoanResponse, error)
+	UpdateLoan(ctx context.Context, userID, id uuid.UUID, req dto.UpdateLoanRequest) (*dto.LoanResponse, error)
+	// DeleteLoan deletes a loan; its payment transactions are kept
+	DeleteLoan(ctx context.Context, userID, id uuid.UUID) error
+	GetSchedule(ctx context.Context, userID, id uuid.UUID) (*dto.LoanScheduleResponse, error)
+	// AddPayment books a repayment as a transaction linked to the loan
+	AddPayment(ctx context.Context, userID, loanID uuid.UUID, req dto.CreateLoanPaymentRequest) (*dto.LoanPaymentResponse, error)
+	// ListPayments returns the payments of a loan in date order
+	ListPayments(ctx context.Context, userID, loanID uuid.UUID) ([]dto.LoanPaymentResponse, error)
+	// DeletePayment deletes a payment and moves its transaction to the trash
+	DeletePayment(ctx context.Context, userID, loanID, id uuid.UUID) error
+}
+
+type loanService struct {
+	loanRepo     repository.LoanRepo
+	categoryRepo repository.CategoryRepo
+}
+
+func NewLoanService(loanRepo repository.LoanRepo, categoryRepo repository.CategoryRepo) LoanService {
+	return &loanService{
+		loanRepo:     loanRepo,
+		categoryRepo: categoryRepo,
+	}
+}
+
+func (s *loanService) CreateLoan(ctx context.Context, userID uuid.UUID, req dto.CreateLoanRequest) (*dto.LoanResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", constant.ErrInvalidInput)
+	}
+
+	loan := &model.Loan{
+		UserID:       userID,
+		Name:         name,
+		Direction:    model.LoanDirection(req.Direction),
+		Principal:    req.Principal,
+		InterestRate: req.InterestRate,
+		TermMonths:   req.TermMonths,
+		Compounding:  model.LoanCompoundingMonthly,
+		StartDate:    goalToday(time.Now()),
+	}
+	if req.Counterparty != nil && strings.TrimSpace(*req.Counterparty) != "" {
+		counterparty := strings.TrimSpace(*req.Counterparty)
+		loan.Counterparty = &counterparty
+	}
+	if req.Compounding != nil {
+		loan.Compounding = model.LoanCompounding(*req.Compounding)
+	}
+	if req.StartDate != nil {
+		date, err := time.Parse("2006-01-02", *req.StartDate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: startDate must be YYYY-MM-DD", constant.ErrInvalidInput)
+		}
+		loan.StartDate = date
+	}
+	if req.CategoryID != nil {
+		if _, err := s.paymentCategory(ctx, userID, loan, *req.CategoryID); err != nil {
+			return nil, err
+		}
+		loan.CategoryID = req.CategoryID
+	}
+
+	if err := s.loanRepo.Create(ctx, loan); err != nil {
+		return nil, err
+	}
+	return s.GetLoan(ctx, userID, loan.ID)
+}
+
+func (s *loanService) GetLoan(ctx context.Context, userID, id uuid.UUID) (*dto.LoanResponse, error) {
+	loan, err := s.getOwnedLoan(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	responses, err := s.toResponses(ctx, []model.Loan{*loan})
+	if err != nil {
+		return nil, err
+	}
+	return &responses[0], nil
+}
+
+func (s *loanService) ListLoans(ctx context.Context, userID uuid.UUID) ([]dto.LoanResponse, error) {
+	loans, err := s.loanRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponses(ctx, loans)
+}
+
+func (s *loanService) UpdateLoan(ctx context.Context, userID, id uuid.UUID, req dto.UpdateLoanRequest) (*dto.LoanResponse, error) {
+	loan, err := s.getOwnedLoan(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, fmt.Errorf("%w: name is required", constant.ErrInvalidInput)
+		}
+		loan.Name = name
+	}
+	if req.Counterparty != nil {
+		loan.Counterparty = nil
+		if counterparty := strings.TrimSpace(*req.Counterparty); counterparty != "" {
+			loan.Counterparty = &counterparty
+		}
+	}
+	if req.Principal != nil {
+		loan.Principal = *req.Principal
+	}
+	if req.InterestRate != nil {
+		loan.InterestRate = *req.InterestRate
+	}
+	if req.TermMonths != nil {
+		loan.TermMonths = *req.TermMonths
+	}
+	if req.Compounding != nil {
+		loan.Compounding = model.LoanCompounding(*req.Compounding)
+	}
+	if req.StartDate != nil {
+		date, err := time.Parse("2006-01-02", *req.StartDate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: startDate must be YYYY-MM-DD", constant.ErrInvalidInput)
+		}
+		loan.StartDate = date
+	}
+	if req.CategoryID.Set {
+		if req.CategoryID.Value != nil {
+			if _, err := s.paymentCategory(ctx, userID, loan, *req.CategoryID.Value); err != nil {
+				return nil, err
+			}
+		}
+		loan.CategoryID = req.CategoryID.Value
+	}
+
+	if err := s.loanRepo.Update(ctx, loan); err != nil {
+		return nil, err
+	}
+	return s.GetLoan(ctx, userID, id)
+}
+
+func (s *loanService) DeleteLoan(ctx context.Context, userID, id uuid.UUID) error {
+	if _, err := s.getOwnedLoan(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.loanRepo.Delete(ctx, id)
+}
+
+func (s *loanService) GetSchedule(ctx context.Context, userID, id uuid.UUID) (*dto.LoanScheduleResponse, error) {
+	loan, err := s.getOwnedLoan(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	schedule := amortizationSchedule(loan)
+	return &schedule, nil
+}
+
+func (s *loanService) AddPayment(ctx context.Context, userID, loanID uuid.UUID, req dto.CreateLoanPaymentRequest) (*dto.LoanPaymentResponse, error) {
+	loan, err := s.getOwnedLoan(ctx, userID, loanID)
+	if err != nil {
+		return nil, err
+	}
+
+	paidOn := goalToday(time.Now())
+	if req.PaidOn != nil {
+		paidOn, err = time.Parse("2006-01-02", *req.PaidOn)
+		if err != nil {
+			return nil, fmt.Errorf("%w: paidOn must be YYYY-MM-DD", constant.ErrInvalidInput)
+		}
+	}
+	if paidOn.Before(loan.StartDate) {
+		return nil, fmt.Errorf("%w: paidOn must not be before the start of the loan", constant.ErrInvalidInput)
+	}
+
+	categoryID := loan.CategoryID
+	if req.CategoryID != nil {
+		categoryID = req.CategoryID
+	}
+	if categoryID == nil {
+		return nil, fmt.Errorf("%w: categoryId is required when the loan has no category", constant.ErrInvalidInput)
+	}
+	category, err := s.paymentCategory(ctx, userID, loan, *categoryID)
+	if err != nil {
+		return nil, err
+	}
+	if category.ArchivedAt != nil {
+		return nil, fmt.Errorf("%w: category %q is archived", constant.ErrInvalidInput, category.Name)
+	}
+
+	description := req.Description
+	if description == nil {
+		text := loan.Name + " payment"
+		description = &text
+	}
+	transaction := &model.Transaction{
+		UserID:          userID,
+		CategoryID:      category.ID,
+		Amount:          req.Amount,
+		Type:            loanTransactionType(loan.Direction),
+		Description:     description,
+		TransactionDate: paidOn,
+	}
+	payment := &model.LoanPayment{
+		UserID: userID,
+		LoanID: loanID,
+	}
+	if err := s.loanRepo.CreatePayment(ctx, payment, transaction); err != nil {
+		return nil, err
+	}
+
+	// The split depends on the payments around it, so the loan is replayed
+	responses, err := s.ListPayments(ctx, userID, loanID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range responses {
+		if responses[i].ID == payment.ID.String() {
+			return &responses[i], nil
+		}
+	}
+	return nil, constant.ErrNotFound
+}
+
+func (s *loanService) ListPayments(ctx context.Context, userID, loanID uuid.UUID) ([]dto.LoanPaymentResponse, error) {
+	loan, err := s.getOwnedLoan(ctx, userID, loanID)
+	if err != nil {
+		return nil, err
+	}
+	payments, err := s.loanRepo.ListPayments(ctx, []uuid.UUID{loanID})
+	if err != nil {
+		return nil, err
+	}
+	responses, _ := replayLoan(loan, payments, goalToday(time.Now()))
+	return responses, nil
+}
+
+func (s *loanService) DeletePayment(ctx context.Context, userID, loanID, id uuid.UUID) error {
+	if _, err := s.getOwnedLoan(ctx, userID, loanID); err != nil {
+		return err
+	}
+	payment, err := s.loanRepo.GetPayment(ctx, loanID, id)
+	if err != nil {
+		return err
+	}
+	return s.loanRepo.DeletePayment(ctx, payment)
+}
+
+// toResponses works out the balance of loans as of today
+func (s *loanService) toResponses(ctx context.Context, loans []model.Loan) ([]dto.LoanResponse, error) {
+	ids := make([]uuid.UUID, 0, len(loans))
+	for _, l := range loans {
+		ids = append(ids, l.ID)
+	}
+	payments, err := s.loanRepo.ListPayments(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byLoan := make(map[uuid.UUID][]model.LoanPayment, len(loans))
+	for _, p := range payments {
+		byLoan[p.LoanID] = append(byLoan[p.LoanID], p)
+	}
+
+	today := goalToday(time.Now())
+	responses := make([]dto.LoanResponse, 0, len(loans))
+	for i := range loans {
+		_, balance := replayLoan(&loans[i], byLoan[loans[i].ID], today)
+		responses = append(responses, toLoanResponse(&loans[i], balance))
+	}
+	return responses, nil
+}
+
+// paymentCategory returns the category if payments of the loan can be booked
+// in it
+func (s *loanService) paymentCategory(ctx context.Context, userID uuid.UUID, loan *model.Loan, categoryID uuid.UUID) (*model.Category, error) {
+	category, err := s.categoryRepo.GetByID(ctx, categoryID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if category == nil || category.UserID != userID {
+		return nil, fmt.Errorf("%w: category %s not found", constant.ErrInvalidInput, categoryID)
+	}
+	if err := checkCategoryKind(category, loanTransactionType(loan.Direction)); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+func (s *loanService) getOwnedLoan(ctx context.Context, userID, id uuid.UUID) (*model.Loan, error) {
+	loan, err := s.loanRepo.GetByID(ctx, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, constant.ErrNotFound
+		}
+		return nil, err
+	}
+	if loan.UserID != userID {
+		return nil, constant.ErrNotFound
+	}
+	return loan, nil
+}
+
+// loanTransactionType is the type of the repayments of a loan: money going
+// out on a borrowed loan and coming in on a lent one
+func loanTransactionType(direction model.LoanDirection) model.TransactionType {
+	if direction == model.LoanDirectionLent {
+		return model.TransactionTypeIncome
+	}
+	return model.TransactionTypeExpense
+}
+
+func toLoanResponse(l *model.Loan, balance dto.LoanBalanceResponse) dto.LoanResponse {
+	resp := dto.LoanResponse{
+		ID:             l.ID.String(),
+		Name:           l.Name,
+		Direction:      string(l.Direction),
+		Counterparty:   l.Counterparty,
+		Principal:      l.Principal,
+		InterestRate:   l.InterestRate,
+		TermMonths:     l.TermMonths,
+		Compounding:    string(l.Compounding),
+		StartDate:      l.StartDate.Format("2006-01-02"),
+		EndDate:        addMonthsClamped(l.StartDate, l.TermMonths).Format("2006-01-02"),
+		MonthlyPayment: loanMonthlyPayment(l),
+		Balance:        balance,
+		CreatedAt:      l.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      l.UpdatedAt.Format(time.RFC3339),
+	}
+	if l.CategoryID != nil {
+		id := l.CategoryID.String()
+		resp.CategoryID = &id
+	}
+	return resp
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/constant"
+	"github.com/tyha2404/nexo-app-api/internal/dto"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"github.com/tyha2404/nexo-app-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+type NetWorthService interface {
+	CreateItem(ctx context.Context, userID uuid.UUID, req dto.CreateNetWorthItemRequest) (*dto.NetWorthItemResponse, error)
+	GetItem(ctx context.Context, userID, id uuid.UUID) (*dto.NetWorthItemResponse, error)
+	ListItems(ctx context.Context, userID uuid.UUID) ([]dto.NetWorthItemResponse, error)
+	UpdateItem(ctx context.Context, userID, id uuid.UUID, req dto.UpdateNetWorthItemRequest) (*dto.NetWorthItemResponse, error)
+	DeleteItem(ctx context.Context, userID, id uuid.UUID) error
+	AddValuation(ctx context.Context, userID, itemID uuid.UUID, req dto.CreateValuationRequest) (*dto.ValuationResponse, error)
+	ListValuations(ctx context.Context, userID, itemID uuid.UUID) ([]dto.ValuationResponse, error)
+	DeleteValuation(ctx context.Context, userID, itemID, id uuid.UUID) error
+}
+
+type netWorthService struct {
+	netWorthRepo repository.NetWorthRepo
+}
+
+func NewNetWorthService(netWorthRepo repository.NetWorthRepo) NetWorthService {
+	return &netWorthService{
+		netWorthRepo: netWorthRepo,
+	}
+}
+
+func (s *netWorthService) CreateItem(ctx context.Context, userID uuid.UUID, req dto.CreateNetWorthItemRequest) (*dto.NetWorthItemResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", constant.ErrInvalidInput)
+	}
+
+	item := &model.NetWorthItem{
+		UserID:      userID,
+		Name:        name,
+		Kind:        model.NetWorthKind(req.Kind),
+		Description: req.Description,
+	}
+	if req.Value != nil {
+		valuedAt := time.Now().UTC().Format("2006-01-02")
+		if req.ValuedAt != nil {
+			valuedAt = *req.ValuedAt
+		}
+		date, err := time.Parse("2006-01-02", valuedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%w: valuedAt must be YYYY-MM-DD", constant.ErrInvalidInput)
+		}
+		item.Valuations = []model.NetWorthValuation{{UserID: userID, Value: *req.Value, ValuedAt: date}}
+	}
+
+	if err := s.netWorthRepo.Create(ctx, item); err != nil {
+		return nil, err
+	}
+	return toNetWorthItemResponse(item), nil
+}
+
+func (s *netWorthService) GetItem(ctx context.Context, userID, id uuid.UUID) (*dto.NetWorthItemResponse, error) {
+	item, err := s.getOwnedItem(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	valuations, err := s.netWorthRepo.ListValuations(ctx, item.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(valuations) > 0 {
+		item.Valuations = valuations[:1]
+	}
+	return toNetWorthItemResponse(item), nil
+}
+
+func (s *netWorthService) ListItems(ctx context.Context, userID uuid.UUID) ([]dto.NetWorthItemResponse, error) {
+	items, err := s.netWorthRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.NetWorthItemResponse, 0, len(items))
+	for i := range items {
+		responses = append(responses, *toNetWorthItemResponse(&items[i]))
+	}
+	return responses, nil
+}
+
+func (s *netWorthService) UpdateItem(ctx context.Context, userID, id uuid.UUID, req dto.UpdateNetWorthItemRequest) (*dto.NetWorthItemResponse, error) {
+	item, err := s.getOwnedItem(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, fmt.Errorf("%w: name is required", constant.ErrInvalidInput)
+		}
+		item.Name = name
+	}
+	if req.Description != nil {
+		item.Description = req.Description
+	}
+
+	if err := s.netWorthRepo.Update(ctx, item); err != nil {
+		return nil, err
+	}
+	return s.GetItem(ctx, userID, id)
+}
+
+func (s *netWorthService) DeleteItem(ctx context.Context, userID, id uuid.UUID) error {
+	if _, err := s.getOwnedItem(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.netWorthRepo.Delete(ctx, id)
+}
+
+func (s *netWorthService) AddValuation(ctx context.Context, userID, itemID uuid.UUID, req dto.CreateValuationRequest) (*dto.ValuationResponse, error) {
+	if _, err := s.getOwnedItem(ctx, userID, itemID); err != nil {
+		return nil, err
+	}
+	date, err := time.Parse("2006-01-02", req.ValuedAt)
+	if err != nil {
+		return nil, fmt.Errorf("%w: valuedAt must be YYYY-MM-DD", constant.ErrInvalidInput)
+	}
+
+	valuation := &model.NetWorthValuation{
+		UserID:   userID,
+		ItemID:   itemID,
+		Value:    req.Value,
+		ValuedAt: date,
+		Note:     req.Note,
+	}
+	if err := s.netWorthRepo.SaveValuation(ctx, valuation); err != nil {
+		return nil, err
+	}
+	return toValuationResponse(valuation), nil
+}
+
+func (s *netWorthService) ListValuations(ctx context.Context, userID, itemID uuid.UUID) ([]dto.ValuationResponse, error) {
+	if _, err := s.getOwnedItem(ctx, userID, itemID); err != nil {
+		return nil, err
+	}
+	valuations, err := s.netWorthRepo.ListValuations(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.ValuationResponse, 0, len(valuations))
+	for i := range valuations {
+		responses = append(responses, *toValuationResponse(&valuations[i]))
+	}
+	return responses, nil
+}
+
+func (s *netWorthService) DeleteValuation(ctx context.Context, userID, itemID, id uuid.UUID) error {
+	if _, err := s.getOwnedItem(ctx, userID, itemID); err != nil {
+		return err
+	}
+	if _, err := s.netWorthRepo.GetValuation(ctx, itemID, id); err != nil {
+		return err
+	}
+	return s.netWorthRepo.DeleteValuation(ctx, id)
+}
+
+// getOwnedItem loads an item and hides it if it belongs to another user
+func (s *netWorthService) getOwnedItem(ctx context.Context, userID, id uuid.UUID) (*model.NetWorthItem, error) {
+	item, err := s.netWorthRepo.GetByID(ctx, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, constant.ErrNotFound
+		}
+		return nil, err
+	}
+	if item.UserID != userID {
+		return nil, constant.ErrNotFound
+	}
+	return item, nil
+}
+
+// toNetWorthItemResponse expects at most the latest valuation to be loaded
+func toNetWorthItemResponse(item *model.NetWorthItem) *dto.NetWorthItemResponse {
+	resp := &dto.NetWorthItemResponse{
+		ID:          item.ID.String(),
+		Name:        item.Name,
+		Kind:        string(item.Kind),
+		Description: item.Description,
+		CreatedAt:   item.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   item.UpdatedAt.Format(time.RFC3339),
+	}
+	if len(item.Valuations) > 0 {
+		latest := item.Valuations[0]
+		valuedAt := latest.ValuedAt.Format("2006-01-02")
+		resp.CurrentValue = &latest.Value
+		resp.ValuedAt = &valuedAt
+	}
+	return resp
+}
+
+func toValuationResponse(v *model.NetWorthValuation) *dto.ValuationResponse {
+	return &dto.ValuationResponse{
+		ID:        v.ID.String(),
+		ItemID:    v.ItemID.String(),
+		Value:     v.Value,
+		ValuedAt:  v.ValuedAt.Format("2006-01-02"),
+		Note:      v.Note,
+		CreatedAt: v.CreatedAt.Format(time.RFC3339),
+	}
+}
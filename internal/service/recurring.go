@@ -0,0 +1,241 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+)
+
+const (
+	// recurringLookbackDays is how much history recurring detection looks
+	// at; enough for two yearly bookings, see cadenceYearly
+	recurringLookbackDays = 800
+	// recurringMaxDeviation is how far, relative to the median, an amount
+	// may stray before a series no longer counts as recurring
+	recurringMaxDeviation = 0.2
+)
+
+// recurringCadence is how often a series repeats. Cadences counted in days
+// repeat from the last booking, those counted in months on a day of the
+// month.
+type recurringCadence struct {
+	Name   string
+	Days   int
+	Months int
+	// PerYear is the number of bookings in a year
+	PerYear float64
+	// MinGap and MaxGap bound the median number of days between bookings
+	MinGap, MaxGap int
+	// LookbackDays is how much history counts towards the cadence, so that
+	// old price changes do not break short cadences
+	LookbackDays int
+	// MinOccurrences is how often a series must appear to count; for
+	// cadences counted in months it is the number of distinct months
+	MinOccurrences int
+	// MaxSilenceDays drops series that have stopped
+	MaxSilenceDays int
+}
+
+var (
+	cadenceWeekly    = recurringCadence{Name: "weekly", Days: 7, PerYear: 52, MinGap: 5, MaxGap: 9, LookbackDays: 180, MinOccurrences: 4, MaxSilenceDays: 14}
+	cadenceBiweekly  = recurringCadence{Name: "biweekly", Days: 14, PerYear: 26, MinGap: 11, MaxGap: 17, LookbackDays: 180, MinOccurrences: 3, MaxSilenceDays: 24}
+	cadenceMonthly   = recurringCadence{Name: "monthly", Months: 1, PerYear: 12, MinGap: 18, MaxGap: 45, LookbackDays: 180, MinOccurrences: 3, MaxSilenceDays: 45}
+	cadenceQuarterly = recurringCadence{Name: "quarterly", Months: 3, PerYear: 4, MinGap: 75, MaxGap: 105, LookbackDays: 400, MinOccurrences: 2, MaxSilenceDays: 135}
+	cadenceYearly    = recurringCadence{Name: "yearly", Months: 12, PerYear: 1, MinGap: 330, MaxGap: 400, LookbackDays: recurringLookbackDays, MinOccurrences: 2, MaxSilenceDays: 410}
+
+	// recurringCadences are the cadences detection looks for, shortest first
+	recurringCadences = []recurringCadence{cadenceWeekly, cadenceBiweekly, cadenceMonthly, cadenceQuarterly, cadenceYearly}
+)
+
+// recurringSeries is a group of transactions with the same type, category and
+// description that repeats at a regular cadence with a similar amount
+type recurringSeries struct {
+	// Key identifies the series, see recurringKey
+	Key         string
+	Type        model.TransactionType
+	CategoryID  uuid.UUID
+	Description string
+	Cadence     recurringCadence
+	// Amount is the median of the bookings within the cadence's lookback,
+	// DayOfMonth their median day for cadences counted in months and 0
+	// otherwise
+	Amount      float64
+	DayOfMonth  int
+	Occurrences int
+	LastDate    time.Time
+	// TransactionIDs also covers older bookings of the series
+	TransactionIDs []uuid.UUID
+}
+
+// detectRecurring finds weekly, biweekly, monthly, quarterly and yearly
+// series among transactions. today is a calendar date; series silent for too
+// long before it are ignored.
+func detectRecurring(transactions []model.Transaction, today time.Time) []recurringSeries {
+	groups := make(map[string][]model.Transaction)
+	for _, t := range transactions {
+		key := recurringKey(&t)
+		if key == "" {
+			continue
+		}
+		groups[key] = append(groups[key], t)
+	}
+
+	var series []recurringSeries
+	for key, group := range groups {
+		sort.SliceStable(group, func(i, j int) bool {
+			return group[i].TransactionDate.Before(group[j].TransactionDate)
+		})
+		cadence, recent, ok := groupCadence(group, today)
+		if !ok {
+			continue
+		}
+
+		amounts := make([]float64, 0, len(recent))
+		days := make([]float64, 0, len(recent))
+		for _, t := range recent {
+			amounts = append(amounts, t.Amount)
+			days = append(days, float64(t.TransactionDate.Day()))
+		}
+		last := recent[len(recent)-1].TransactionDate
+		amount := median(amounts)
+		if amount <= 0 || today.Sub(last) > time.Duration(cadence.MaxSilenceDays)*24*time.Hour {
+			continue
+		}
+		stable := true
+		for _, a := range amounts {
+			if math.Abs(a-amount)/amount > recurringMaxDeviation {
+				stable = false
+				break
+			}
+		}
+		if !stable {
+			continue
+		}
+
+		s := recurringSeries{
+			Key:         key,
+			Type:        group[0].Type,
+			CategoryID:  group[0].CategoryID,
+			Description: strings.TrimSpace(*recent[0].Description),
+			Cadence:     cadence,
+			Amount:      roundCents(amount),
+			Occurrences: len(recent),
+			LastDate:    last,
+		}
+		if cadence.Months > 0 {
+			s.DayOfMonth = int(math.Round(median(days)))
+		}
+		for _, t := range group {
+			s.TransactionIDs = append(s.TransactionIDs, t.ID)
+		}
+		series = append(series, s)
+	}
+
+	sort.Slice(series, func(i, j int) bool {
+		if series[i].DayOfMonth != series[j].DayOfMonth {
+			return series[i].DayOfMonth < series[j].DayOfMonth
+		}
+		if series[i].Description != series[j].Description {
+			return series[i].Description < series[j].Description
+		}
+		return series[i].Key < series[j].Key
+	})
+	return series
+}
+
+// groupCadence picks the shortest cadence that fits a group sorted by date
+// and returns the bookings within the cadence's lookback. The median gap
+// between the bookings must fit the cadence. Cadences counted in days need
+// every gap to fit; those counted in months allow one extra booking per
+// series.
+func groupCadence(group []model.Transaction, today time.Time) (recurringCadence, []model.Transaction, bool) {
+	for _, c := range recurringCadences {
+		since := today.AddDate(0, 0, -c.LookbackDays)
+		recent := group[sort.Search(len(group), func(i int) bool {
+			return !group[i].TransactionDate.Before(since)
+		}):]
+		if len(recent) < 2 {
+			continue
+		}
+		gaps := make([]float64, 0, len(recent)-1)
+		for i := 1; i < len(recent); i++ {
+			gaps = append(gaps, recent[i].TransactionDate.Sub(recent[i-1].TransactionDate).Hours()/24)
+		}
+		if gap := median(gaps); gap < float64(c.MinGap) || gap > float64(c.MaxGap) {
+			continue
+		}
+
+		if c.Days > 0 {
+			fits := len(recent) >= c.MinOccurrences
+			for _, g := range gaps {
+				if g < float64(c.MinGap) || g > float64(c.MaxGap) {
+					fits = false
+				}
+			}
+			if fits {
+				return c, recent, true
+			}
+			continue
+		}
+
+		periods := make(map[int]bool, len(recent))
+		for _, t := range recent {
+			periods[monthIndex(t.TransactionDate)/c.Months] = true
+		}
+		// More than one extra booking per series means it is not this cadence
+		if len(periods) >= c.MinOccurrences && len(recent) <= len(periods)+1 {
+			return c, recent, true
+		}
+	}
+	return recurringCadence{}, nil, false
+}
+
+// occursOn reports whether the series is due on the calendar date d. Days
+// past the end of a short month fall on its last day.
+func (s *recurringSeries) occursOn(d time.Time) bool {
+	if s.Cadence.Days > 0 {
+		days := int(math.Round(d.Sub(s.LastDate).Hours() / 24))
+		return days > 0 && days%s.Cadence.Days == 0
+	}
+	if (monthIndex(d)-monthIndex(s.LastDate))%s.Cadence.Months != 0 {
+		return false
+	}
+	lastDay := time.Date(d.Year(), d.Month()+1, 0, 0, 0, 0, 0, d.Location()).Day()
+	return d.Day() == min(s.DayOfMonth, lastDay)
+}
+
+// recurringKey groups transactions by type, category and description. Digits
+// are ignored so that changing invoice or reference numbers still match.
+func recurringKey(t *model.Transaction) string {
+	if t.Description == nil {
+		return ""
+	}
+	desc := strings.Join(strings.Fields(strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, *t.Description)), " ")
+	if desc == "" {
+		return ""
+	}
+	return string(t.Type) + "|" + t.CategoryID.String() + "|" + desc
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
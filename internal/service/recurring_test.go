@@ -0,0 +1,191 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/tyha2404/nexo-app-api/internal/model"
+)
+
+func monthlyTransactions(t *testing.T, txType model.TransactionType, description string, amounts []float64, dates ...string) []model.Transaction {
+	t.Helper()
+	transactions := make([]model.Transaction, 0, len(dates))
+	for i, d := range dates {
+		transactions = append(transactions, testTransaction(t, txType, amounts[i%len(amounts)], d, description))
+	}
+	return transactions
+}
+
+func TestDetectRecurring(t *testing.T) {
+	today := mustDate(t, "2024-06-20")
+	expense := model.TransactionTypeExpense
+
+	tests := []struct {
+		name         string
+		transactions []model.Transaction
+		want         int
+	}{
+		{"monthly", monthlyTransactions(t, expense, "Gym", []float64{30}, "2024-04-03", "2024-05-03", "2024-06-03"), 1},
+		{"two months only", monthlyTransactions(t, expense, "Gym", []float64{30}, "2024-05-03", "2024-06-03"), 0},
+		{"unstable amount", monthlyTransactions(t, expense, "Gym", []float64{30, 30, 60}, "2024-04-03", "2024-05-03", "2024-06-03"), 0},
+		{"small price change", monthlyTransactions(t, expense, "Gym", []float64{30, 30, 33}, "2024-04-03", "2024-05-03", "2024-06-03"), 1},
+		{"stopped", monthlyTransactions(t, expense, "Gym", []float64{30}, "2024-02-03", "2024-03-03", "2024-04-03"), 0},
+		{"twice a month at irregular gaps", monthlyTransactions(t, expense, "Gym", []float64{30}, "2024-04-03", "2024-04-10", "2024-05-03", "2024-05-10", "2024-06-03"), 0},
+		{"every two months", monthlyTransactions(t, expense, "Gym", []float64{30}, "2024-02-03", "2024-04-03", "2024-06-03"), 0},
+		{"weekly", monthlyTransactions(t, expense, "Lunch club", []float64{12}, "2024-05-24", "2024-05-31", "2024-06-07", "2024-06-14"), 1},
+		{"weekly too few", monthlyTransactions(t, expense, "Lunch club", []float64{12}, "2024-05-31", "2024-06-07", "2024-06-14"), 0},
+		{"weekly stopped", monthlyTransactions(t, expense, "Lunch club", []float64{12}, "2024-05-10", "2024-05-17", "2024-05-24", "2024-05-31"), 0},
+		{"weekly with a missed week", monthlyTransactions(t, expense, "Lunch club", []float64{12}, "2024-05-17", "2024-05-24", "2024-06-07", "2024-06-14"), 0},
+		{"one extra booking", monthlyTransactions(t, expense, "Gym", []float64{30}, "2024-04-03", "2024-05-03", "2024-05-04", "2024-06-03"), 1},
+		{"quarterly", monthlyTransactions(t, expense, "Water", []float64{80}, "2023-12-10", "2024-03-10", "2024-06-10"), 1},
+		{"quarterly stopped", monthlyTransactions(t, expense, "Water", []float64{80}, "2023-09-10", "2023-12-10"), 0},
+		{"yearly", monthlyTransactions(t, expense, "Insurance", []float64{480, 510}, "2023-01-15", "2024-01-15"), 1},
+		{"once", monthlyTransactions(t, expense, "Insurance", []float64{480}, "2024-01-15"), 0},
+		{"no description", monthlyTransactions(t, expense, "", []float64{30}, "2024-04-03", "2024-05-03", "2024-06-03"), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectRecurring(tt.transactions, today); len(got) != tt.want {
+				t.Errorf("detectRecurring found %d series, want %d", len(got), tt.want)
+			}
+		})
+	}
+
+	t.Run("series details", func(t *testing.T) {
+		transactions := []model.Transaction{
+			testTransaction(t, expense, 15.99, "2024-03-04", "NETFLIX 4411"),
+			testTransaction(t, expense, 15.99, "2024-04-05", "Netflix  8870"),
+			testTransaction(t, expense, 17.99, "2024-05-06", "netflix 1002"),
+			testTransaction(t, expense, 15.99, "2024-06-05", " Netflix 5123"),
+		}
+		got := detectRecurring(transactions, today)
+		if len(got) != 1 {
+			t.Fatalf("detectRecurring found %d series, want 1", len(got))
+		}
+		s := got[0]
+		if s.Cadence.Name != "monthly" || s.Amount != 15.99 || s.DayOfMonth != 5 || s.Occurrences != 4 || s.LastDate.Format("2006-01-02") != "2024-06-05" {
+			t.Errorf("series = %+v", s)
+		}
+		if s.Description != "NETFLIX 4411" || len(s.TransactionIDs) != 4 || s.Key != recurringKey(&transactions[0]) {
+			t.Errorf("series = %+v", s)
+		}
+	})
+
+	t.Run("old price change", func(t *testing.T) {
+		transactions := monthlyTransactions(t, expense, "Netflix", []float64{9.99},
+			"2023-09-05", "2023-10-05", "2023-11-05")
+		transactions = append(transactions, monthlyTransactions(t, expense, "Netflix", []float64{15.99},
+			"2024-01-05", "2024-02-05", "2024-03-05", "2024-04-05", "2024-05-05", "2024-06-05")...)
+		got := detectRecurring(transactions, today)
+		if len(got) != 1 {
+			t.Fatalf("detectRecurring found %d series, want 1", len(got))
+		}
+		if got[0].Amount != 15.99 || got[0].Occurrences != 6 || len(got[0].TransactionIDs) != 9 {
+			t.Errorf("series = %+v", got[0])
+		}
+	})
+
+	t.Run("biweekly salary", func(t *testing.T) {
+		transactions := monthlyTransactions(t, model.TransactionTypeIncome, "ACME payroll", []float64{2100, 2100, 2150},
+			"2024-04-05", "2024-04-19", "2024-05-03", "2024-05-17", "2024-05-31", "2024-06-14")
+		got := detectRecurring(transactions, today)
+		if len(got) != 1 {
+			t.Fatalf("detectRecurring found %d series, want 1", len(got))
+		}
+		s := got[0]
+		if s.Cadence.Name != "biweekly" || s.Amount != 2100 || s.DayOfMonth != 0 || s.Occurrences != 6 {
+			t.Errorf("series = %+v", s)
+		}
+		for day, want := range map[string]bool{"2024-06-21": false, "2024-06-28": true, "2024-07-05": false, "2024-07-12": true} {
+			if got := s.occursOn(mustDate(t, day)); got != want {
+				t.Errorf("occursOn(%s) = %v, want %v", day, got, want)
+			}
+		}
+	})
+
+	t.Run("types and order", func(t *testing.T) {
+		var transactions []model.Transaction
+		transactions = append(transactions, monthlyTransactions(t, expense, "Rent", []float64{900}, "2024-04-01", "2024-05-01", "2024-06-01")...)
+		transactions = append(transactions, monthlyTransactions(t, model.TransactionTypeIncome, "Salary", []float64{3000}, "2024-04-28", "2024-05-28", "2024-06-28")...)
+		transactions = append(transactions, monthlyTransactions(t, model.TransactionTypeIncome, "Rent", []float64{400}, "2024-04-01", "2024-05-01", "2024-06-01")...)
+		transactions = append(transactions, monthlyTransactions(t, expense, "Insurance", []float64{50}, "2024-04-01", "2024-05-01", "2024-06-01")...)
+
+		got := detectRecurring(transactions, today)
+		var order []string
+		for _, s := range got {
+			order = append(order, string(s.Type)+" "+s.Description)
+		}
+		want := []string{"EXPENSE Insurance", "EXPENSE Rent", "INCOME Rent", "INCOME Salary"}
+		if len(order) != len(want) {
+			t.Fatalf("series = %v, want %v", order, want)
+		}
+		for i := range want {
+			if order[i] != want[i] {
+				t.Fatalf("series = %v, want %v", order, want)
+			}
+		}
+	})
+}
+
+func TestRecurringKey(t *testing.T) {
+	rent := testTransaction(t, model.TransactionTypeExpense, 900, "2024-03-01", "Rent 03/2024")
+	tests := []struct {
+		name  string
+		other model.Transaction
+		same  bool
+	}{
+		{"other reference number", testTransaction(t, model.TransactionTypeExpense, 900, "2024-04-01", "RENT  04/2024"), true},
+		{"other type", testTransaction(t, model.TransactionTypeIncome, 900, "2024-04-01", "Rent 04/2024"), false},
+		{"other description", testTransaction(t, model.TransactionTypeExpense, 900, "2024-04-01", "Rental car"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := recurringKey(&rent) == recurringKey(&tt.other); got != tt.same {
+				t.Errorf("same key = %v, want %v", got, tt.same)
+			}
+		})
+	}
+
+	for _, description := range []string{"", "0815 4711"} {
+		tx := testTransaction(t, model.TransactionTypeExpense, 900, "2024-03-01", description)
+		if key := recurringKey(&tx); key != "" {
+			t.Errorf("recurringKey(%q) = %q, want none", description, key)
+		}
+	}
+}
+
+func TestRecurringSeriesOccursOn(t *testing.T) {
+	s := recurringSeries{Cadence: cadenceMonthly, DayOfMonth: 31, LastDate: mustDate(t, "2023-12-31")}
+	tests := []struct {
+		day  string
+		want bool
+	}{
+		{"2024-01-31", true},
+		{"2024-02-28", false},
+		{"2024-02-29", true},
+		{"2024-04-30", true},
+		{"2024-05-30", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.day, func(t *testing.T) {
+			if got := s.occursOn(mustDate(t, tt.day)); got != tt.want {
+				t.Errorf("occursOn = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMedian(t *testing.T) {
+	tests := []struct {
+		values []float64
+		want   float64
+	}{
+		{nil, 0},
+		{[]float64{3, 1, 2}, 2},
+		{[]float64{4, 1, 3, 2}, 2.5},
+	}
+	for _, tt := range tests {
+		if got := median(tt.values); got != tt.want {
+			t.Errorf("median(%v) = %v, want %v", tt.values, got, tt.want)
+		}
+	}
+}
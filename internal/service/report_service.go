@@ -0,0 +1,780 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/constant"
+	"github.com/tyha2404/nexo-app-api/internal/dto"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"github.com/tyha2404/nexo-app-api/internal/repository"
+)
+
+// Summary report periods
+const (
+	GroupByDay   = "day"
+	GroupByWeek  = "week"
+	GroupByMonth = "month"
+	GroupByYear  = "year"
+)
+
+const (
+	// maxForecastDays bounds how far ahead a forecast looks
+	maxForecastDays = 365
+	// minForecastLookbackDays and maxForecastLookbackDays bound the history
+	// the discretionary baseline is averaged over
+	minForecastLookbackDays = 30
+	maxForecastLookbackDays = 365
+	// forecastConfidence is the coverage of the forecast bands and
+	// forecastZScore the matching quantile of the normal distribution
+	forecastConfidence = 0.8
+	forecastZScore     = 1.2816
+)
+
+// budgetWarningShare is the share of a budget from which it is reported as
+// approaching its limit
+const budgetWarningShare = 0.8
+
+const (
+	// maxSummaryBuckets bounds the number of periods of a summary report
+	maxSummaryBuckets = 1000
+	// defaultReportPeriods is the number of periods a report covers without a start date
+	defaultReportPeriods = 12
+)
+
+type ReportService interface {
+	TagTotals(ctx context.Context, userID uuid.UUID, from, to *time.Time) ([]dto.TagTotalResponse, error)
+	// Summary sums income and expenses per day, week, month or year. from
+	// and to are inclusive calendar dates; without to the range ends today in
+	// loc, without from it starts twelve periods earlier.
+	Summary(ctx context.Context, userID uuid.UUID, from, to *time.Time, groupBy string, loc *time.Location) (*dto.SummaryReportResponse, error)
+	// Categories breaks income or expenses down by category and compares
+	// them with the previous period. from and to are inclusive calendar
+	// dates and default to the current month in loc. With rollUp the
+	// activity of subcategories is added to their top-level category.
+	Categories(ctx context.Context, userID uuid.UUID, from, to *time.Time, txType string, rollUp bool, loc *time.Location) (*dto.CategoryReportResponse, error)
+	// Forecast projects the balance for the given number of days from
+	// recurring transactions and the average discretionary spending of the
+	// last lookbackDays. Without a balance it starts from the net of all
+	// transactions.
+	Forecast(ctx context.Context, userID uuid.UUID, days, lookbackDays int, balance *float64, loc *time.Location) (*dto.ForecastResponse, error)
+	// NetWorth combines the transaction balance with the valuations of the
+	// user's assets and liabilities at the end of every period of the range
+	NetWorth(ctx context.Context, userID uuid.UUID, from, to *time.Time, groupBy string, loc *time.Location) (*dto.NetWorthReportResponse, error)
+	// Budgets compares each budget with the expenses and costs of its
+	// category and subcategories in the budget period containing asOf,
+	// which defaults to today in loc
+	Budgets(ctx context.Context, userID uuid.UUID, asOf *time.Time, loc *time.Location) (*dto.BudgetReportResponse, error)
+}
+
+type reportService struct {
+	reportRepo      repository.ReportRepo
+	transactionRepo repository.TransactionRepository
+	categoryRepo    repository.CategoryRepo
+	netWorthRepo    repository.NetWorthRepo
+}
+
+func NewReportService(
+	reportRepo repository.ReportRepo,
+	transactionRepo repository.TransactionRepository,
+	categoryRepo repository.CategoryRepo,
+	netWorthRepo repository.NetWorthRepo,
+) ReportService {
+	return &reportService{
+		reportRepo:      reportRepo,
+		transactionRepo: transactionRepo,
+		categoryRepo:    categoryRepo,
+		netWorthRepo:    netWorthRepo,
+	}
+}
+
+func (s *reportService) TagTotals(ctx context.Context, userID uuid.UUID, from, to *time.Time) ([]dto.TagTotalResponse, error) {
+	totals, err := s.reportRepo.TagTotals(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.TagTotalResponse, 0, len(totals))
+	for _, t := range totals {
+		responses = append(responses, dto.TagTotalResponse{
+			TagID:            t.TagID.String(),
+			Name:             t.Name,
+			Income:           t.Income,
+			Expense:          t.Expense,
+			TransactionCount: t.TransactionCount,
+			CostTotal:        t.CostTotal,
+			CostCount:        t.CostCount,
+		})
+	}
+	return responses, nil
+}
+
+func (s *reportService) Summary(ctx context.Context, userID uuid.UUID, from, to *time.Time, groupBy string, loc *time.Location) (*dto.SummaryReportResponse, error) {
+	start, end, err := periodRange(from, to, groupBy, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := s.reportRepo.PeriodTotals(ctx, userID, groupBy, start.Format("2006-01-02"), end.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	byPeriod := make(map[string]repository.PeriodTotal, len(totals))
+	for _, t := range totals {
+		byPeriod[t.PeriodStart.Format("2006-01-02")] = t
+	}
+
+	report := &dto.SummaryReportResponse{
+		From:     start.Format("2006-01-02"),
+		To:       end.Format("2006-01-02"),
+		GroupBy:  groupBy,
+		TimeZone: loc.String(),
+		Buckets:  []dto.SummaryBucketResponse{},
+	}
+	for bucket := periodStart(start, groupBy); !bucket.After(end); bucket = nextPeriod(bucket, groupBy) {
+		if len(report.Buckets) == maxSummaryBuckets {
+			return nil, fmt.Errorf("%w: the range spans more than %d periods, use a coarser groupBy", constant.ErrInvalidInput, maxSummaryBuckets)
+		}
+
+		t := byPeriod[bucket.Format("2006-01-02")]
+		report.Buckets = append(report.Buckets, dto.SummaryBucketResponse{
+			Period:           periodLabel(bucket, groupBy),
+			Start:            bucket.Format("2006-01-02"),
+			End:              nextPeriod(bucket, groupBy).AddDate(0, 0, -1).Format("2006-01-02"),
+			StartTime:        bucket.Format(time.RFC3339),
+			Income:           roundCents(t.Income),
+			Expense:          roundCents(t.Expense),
+			Net:              roundCents(t.Income - t.Expense),
+			TransactionCount: t.TransactionCount,
+		})
+		report.Totals.Income += t.Income
+		report.Totals.Expense += t.Expense
+		report.Totals.TransactionCount += t.TransactionCount
+	}
+	report.Totals.Net = roundCents(report.Totals.Income - report.Totals.Expense)
+	report.Totals.Income = roundCents(report.Totals.Income)
+	report.Totals.Expense = roundCents(report.Totals.Expense)
+	return report, nil
+}
+
+func (s *reportService) Categories(ctx context.Context, userID uuid.UUID, from, to *time.Time, txType string, rollUp bool, loc *time.Location) (*dto.CategoryReportResponse, error) {
+	if txType != string(model.TransactionTypeExpense) && txType != string(model.TransactionTypeIncome) {
+		return nil, fmt.Errorf("%w: type must be INCOME or EXPENSE", constant.ErrInvalidInput)
+	}
+
+	today := time.Now().In(loc)
+	start := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, loc)
+	if from != nil {
+		start = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+	}
+	end := start.AddDate(0, 1, -1)
+	if to != nil {
+		end = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, loc)
+	}
+	if start.After(end) {
+		return nil, fmt.Errorf("%w: from must not be after to", constant.ErrInvalidInput)
+	}
+	prevStart, prevEnd := previousPeriod(start, end)
+
+	current, err := s.categoryTotals(ctx, userID, txType, start, end)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := s.categoryTotals(ctx, userID, txType, prevStart, prevEnd)
+	if err != nil {
+		return nil, err
+	}
+	forest, err := loadCategoryForest(ctx, s.categoryRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+	if rollUp {
+		current = rollUpCategoryTotals(current, forest)
+		previous = rollUpCategoryTotals(previous, forest)
+	}
+
+	report := &dto.CategoryReportResponse{
+		Type:         txType,
+		RollUp:       rollUp,
+		From:         start.Format("2006-01-02"),
+		To:           end.Format("2006-01-02"),
+		TimeZone:     loc.String(),
+		PreviousFrom: prevStart.Format("2006-01-02"),
+		PreviousTo:   prevEnd.Format("2006-01-02"),
+		Categories:   []dto.CategoryShareResponse{},
+	}
+
+	previousByID := make(map[uuid.UUID]repository.CategoryTotal, len(previous))
+	for _, p := range previous {
+		previousByID[p.CategoryID] = p
+		report.PreviousTotal += p.TransactionTotal + p.CostTotal
+	}
+	for _, c := range current {
+		report.Total += c.TransactionTotal + c.CostTotal
+	}
+
+	seen := make(map[uuid.UUID]bool, len(current))
+	for _, c := range current {
+		seen[c.CategoryID] = true
+		total := c.TransactionTotal + c.CostTotal
+		prevTotal := previousByID[c.CategoryID].TransactionTotal + previousByID[c.CategoryID].CostTotal
+		share := 0.0
+		if report.Total != 0 {
+			share = roundCents(total / report.Total * 100)
+		}
+		report.Categories = append(report.Categories, dto.CategoryShareResponse{
+			CategoryID:       c.CategoryID.String(),
+			Name:             c.Name,
+			ParentID:         parentIDString(forest, c.CategoryID),
+			Total:            roundCents(total),
+			TransactionTotal: roundCents(c.TransactionTotal),
+			CostTotal:        roundCents(c.CostTotal),
+			Count:            c.TransactionCount + c.CostCount,
+			Share:            share,
+			PreviousTotal:    roundCents(prevTotal),
+			Change:           compareToPrevious(total, prevTotal),
+		})
+	}
+	for _, p := range previous {
+		if seen[p.CategoryID] {
+			continue
+		}
+		prevTotal := p.TransactionTotal + p.CostTotal
+		report.Categories = append(report.Categories, dto.CategoryShareResponse{
+			CategoryID:    p.CategoryID.String(),
+			Name:          p.Name,
+			ParentID:      parentIDString(forest, p.CategoryID),
+			PreviousTotal: roundCents(prevTotal),
+			Change:        compareToPrevious(0, prevTotal),
+		})
+	}
+
+	// Largest first, the order of a pie chart legend
+	sort.SliceStable(report.Categories, func(i, j int) bool {
+		a, b := report.Categories[i], report.Categories[j]
+		if a.Total != b.Total {
+			return a.Total > b.Total
+		}
+		if a.PreviousTotal != b.PreviousTotal {
+			return a.PreviousTotal > b.PreviousTotal
+		}
+		return a.Name < b.Name
+	})
+
+	report.Change = compareToPrevious(report.Total, report.PreviousTotal)
+	report.Total = roundCents(report.Total)
+	report.PreviousTotal = roundCents(report.PreviousTotal)
+	return report, nil
+}
+
+func (s *reportService) Forecast(ctx context.Context, userID uuid.UUID, days, lookbackDays int, balance *float64, loc *time.Location) (*dto.ForecastResponse, error) {
+	if days < 1 || days > maxForecastDays {
+		return nil, fmt.Errorf("%w: days must be between 1 and %d", constant.ErrInvalidInput, maxForecastDays)
+	}
+	if lookbackDays < minForecastLookbackDays || lookbackDays > maxForecastLookbackDays {
+		return nil, fmt.Errorf("%w: lookbackDays must be between %d and %d", constant.ErrInvalidInput, minForecastLookbackDays, maxForecastLookbackDays)
+	}
+
+	// Transaction dates are calendar dates, so all date math happens on
+	// midnight UTC once today is known in the user's time zone
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	history, err := s.transactionRepo.ListByDateRange(ctx, userID, today.AddDate(0, 0, -max(lookbackDays, recurringLookbackDays)), today)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := s.categoryRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	categoryNames := make(map[uuid.UUID]string, len(categories))
+	for _, c := range categories {
+		categoryNames[c.ID] = c.Name
+	}
+
+	forecast := &dto.ForecastResponse{
+		AsOf:          today.Format("2006-01-02"),
+		Days:          days,
+		LookbackDays:  lookbackDays,
+		TimeZone:      loc.String(),
+		BalanceSource: "provided",
+		Confidence:    forecastConfidence,
+		Recurring:     []dto.ForecastRecurringResponse{},
+		Baseline:      []dto.ForecastBaselineResponse{},
+		Series:        make([]dto.ForecastPointResponse, 0, days),
+	}
+	if balance != nil {
+		forecast.StartingBalance = *balance
+	} else {
+		forecast.BalanceSource = "transactions"
+		if forecast.StartingBalance, err = s.reportRepo.Balance(ctx, userID, forecast.AsOf); err != nil {
+			return nil, err
+		}
+	}
+
+	series := detectRecurring(history, today)
+	recurringIDs := make(map[uuid.UUID]bool)
+	for _, r := range series {
+		for _, id := range r.TransactionIDs {
+			recurringIDs[id] = true
+		}
+		forecast.Recurring = append(forecast.Recurring, dto.ForecastRecurringResponse{
+			Type:         string(r.Type),
+			CategoryID:   r.CategoryID.String(),
+			CategoryName: categoryNames[r.CategoryID],
+			Description:  r.Description,
+			Amount:       r.Amount,
+			Cadence:      r.Cadence.Name,
+			DayOfMonth:   r.DayOfMonth,
+			Occurrences:  r.Occurrences,
+			LastDate:     r.LastDate.Format("2006-01-02"),
+		})
+	}
+
+	// The baseline covers the full days before today. Irregular income is
+	// left out so that the forecast errs on the side of caution.
+	baselineStart := today.AddDate(0, 0, -lookbackDays)
+	daily := make(map[string]float64, lookbackDays)
+	perCategory := make(map[uuid.UUID]float64)
+	var spent float64
+	for _, t := range history {
+		if t.Type != model.TransactionTypeExpense || recurringIDs[t.ID] ||
+			t.TransactionDate.Before(baselineStart) || !t.TransactionDate.Before(today) {
+			continue
+		}
+		daily[t.TransactionDate.Format("2006-01-02")] += t.Amount
+		perCategory[t.CategoryID] += t.Amount
+		spent += t.Amount
+	}
+	mean := spent / float64(lookbackDays)
+	var variance float64
+	for d := baselineStart; d.Before(today); d = d.AddDate(0, 0, 1) {
+		diff := daily[d.Format("2006-01-02")] - mean
+		variance += diff * diff
+	}
+	stddev := math.Sqrt(variance / float64(lookbackDays))
+	forecast.DailyDiscretionary = roundCents(mean)
+
+	for categoryID, total := range perCategory {
+		forecast.Baseline = append(forecast.Baseline, dto.ForecastBaselineResponse{
+			CategoryID:   categoryID.String(),
+			CategoryName: categoryNames[categoryID],
+			DailyAverage: roundCents(total / float64(lookbackDays)),
+		})
+	}
+	sort.Slice(forecast.Baseline, func(i, j int) bool {
+		return forecast.Baseline[i].DailyAverage > forecast.Baseline[j].DailyAverage
+	})
+
+	// Daily spending is treated as independent, so the band widens with the
+	// square root of the days ahead
+	expected := forecast.StartingBalance
+	forecast.LowestBalance = expected
+	forecast.LowestBalanceDate = forecast.AsOf
+	for i := 1; i <= days; i++ {
+		date := today.AddDate(0, 0, i)
+		point := dto.ForecastPointResponse{Date: date.Format("2006-01-02"), Discretionary: forecast.DailyDiscretionary}
+		for _, r := range series {
+			if !r.occursOn(date) {
+				continue
+			}
+			if r.Type == model.TransactionTypeIncome {
+				point.Income += r.Amount
+			} else {
+				point.Expense += r.Amount
+			}
+		}
+		expected += point.Income - point.Expense - mean
+
+		band := forecastZScore * stddev * math.Sqrt(float64(i))
+		point.Balance = roundCents(expected)
+		point.Lower = roundCents(expected - band)
+		point.Upper = roundCents(expected + band)
+		point.Income = roundCents(point.Income)
+		point.Expense = roundCents(point.Expense)
+		forecast.Series = append(forecast.Series, point)
+
+		if point.Balance < forecast.LowestBalance {
+			forecast.LowestBalance = point.Balance
+			forecast.LowestBalanceDate = point.Date
+		}
+		if point.Balance < 0 && forecast.ShortfallDate == nil {
+			shortfall := point.Date
+			forecast.ShortfallDate = &shortfall
+		}
+	}
+	forecast.StartingBalance = roundCents(forecast.StartingBalance)
+	forecast.LowestBalance = roundCents(forecast.LowestBalance)
+	forecast.EndBalance = roundCents(expected)
+	return forecast, nil
+}
+
+func (s *reportService) NetWorth(ctx context.Context, userID uuid.UUID, from, to *time.Time, groupBy string, loc *time.Location) (*dto.NetWorthReportResponse, error) {
+	start, end, err := periodRange(from, to, groupBy, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cash at the end of each period is the balance before the range plus
+	// the activity of every period so far
+	cash, err := s.reportRepo.Balance(ctx, userID, start.AddDate(0, 0, -1).Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	totals, err := s.reportRepo.PeriodTotals(ctx, userID, groupBy, start.Format("2006-01-02"), end.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	byPeriod := make(map[string]repository.PeriodTotal, len(totals))
+	for _, t := range totals {
+		byPeriod[t.PeriodStart.Format("2006-01-02")] = t
+	}
+
+	items, err := s.netWorthRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	valuations, err := s.netWorthRepo.ListUserValuations(ctx, userID, end)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dto.NetWorthReportResponse{
+		From:     start.Format("2006-01-02"),
+		To:       end.Format("2006-01-02"),
+		GroupBy:  groupBy,
+		TimeZone: loc.String(),
+		Items:    []dto.NetWorthItemValueResponse{},
+		Series:   []dto.NetWorthPointResponse{},
+	}
+
+	// Valuations are sorted by date, so each point only needs to apply the
+	// ones dated since the previous point
+	kinds := make(map[uuid.UUID]model.NetWorthKind, len(items))
+	for _, item := range items {
+		kinds[item.ID] = item.Kind
+	}
+	current := make(map[uuid.UUID]*model.NetWorthValuation, len(items))
+	next := 0
+	for bucket := periodStart(start, groupBy); !bucket.After(end); bucket = nextPeriod(bucket, groupBy) {
+		if len(report.Series) == maxSummaryBuckets {
+			return nil, fmt.Errorf("%w: the range spans more than %d periods, use a coarser groupBy", constant.ErrInvalidInput, maxSummaryBuckets)
+		}
+
+		day := nextPeriod(bucket, groupBy).AddDate(0, 0, -1)
+		if day.After(end) {
+			day = end
+		}
+		date := day.Format("2006-01-02")
+		for ; next < len(valuations) && valuations[next].ValuedAt.Format("2006-01-02") <= date; next++ {
+			current[valuations[next].ItemID] = &valuations[next]
+		}
+
+		t := byPeriod[bucket.Format("2006-01-02")]
+		cash += t.Income - t.Expense
+		point := dto.NetWorthPointResponse{Date: date, Cash: roundCents(cash)}
+		for itemID, v := range current {
+			switch kinds[itemID] {
+			case model.NetWorthKindAsset:
+				point.Assets += v.Value
+			case model.NetWorthKindLiability:
+				point.Liabilities += v.Value
+			}
+		}
+		point.NetWorth = roundCents(cash + point.Assets - point.Liabilities)
+		point.Assets = roundCents(point.Assets)
+		point.Liabilities = roundCents(point.Liabilities)
+		report.Series = append(report.Series, point)
+	}
+
+	for _, item := range items {
+		value := dto.NetWorthItemValueResponse{ID: item.ID.String(), Name: item.Name, Kind: string(item.Kind)}
+		if v, ok := current[item.ID]; ok {
+			valuedAt := v.ValuedAt.Format("2006-01-02")
+			value.Value = &v.Value
+			value.ValuedAt = &valuedAt
+		}
+		report.Items = append(report.Items, value)
+	}
+
+	if len(report.Series) > 0 {
+		report.Current = report.Series[len(report.Series)-1]
+		report.Change = compareToPrevious(report.Current.NetWorth, report.Series[0].NetWorth)
+	}
+	return report, nil
+}
+
+func (s *reportService) Budgets(ctx context.Context, userID uuid.UUID, asOf *time.Time, loc *time.Location) (*dto.BudgetReportResponse, error) {
+	day := time.Now().In(loc)
+	if asOf != nil {
+		day = *asOf
+	}
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+
+	budgets, err := s.reportRepo.ListBudgets(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	forest, err := loadCategoryForest(ctx, s.categoryRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dto.BudgetReportResponse{
+		AsOf:     day.Format("2006-01-02"),
+		TimeZone: loc.String(),
+		Budgets:  []dto.BudgetStatusResponse{},
+	}
+
+	// Budgets sharing a period share the query of its totals
+	spentByPeriod := make(map[string]map[uuid.UUID]float64)
+	for _, b := range budgets {
+		start, end, ok := budgetPeriod(b, day)
+		if !ok {
+			continue
+		}
+		key := start.Format("2006-01-02") + "|" + end.Format("2006-01-02")
+		spent, ok := spentByPeriod[key]
+		if !ok {
+			totals, err := s.categoryTotals(ctx, userID, string(model.TransactionTypeExpense), start, end)
+			if err != nil {
+				return nil, err
+			}
+			spent = make(map[uuid.UUID]float64, len(totals))
+			for _, t := range totals {
+				spent[t.CategoryID] = t.TransactionTotal + t.CostTotal
+			}
+			spentByPeriod[key] = spent
+		}
+
+		covered := forest.subtree(b.CategoryID)
+		var total float64
+		for _, id := range covered {
+			total += spent[id]
+		}
+
+		status := dto.BudgetStatusResponse{
+			BudgetID:      b.ID.String(),
+			CategoryID:    b.CategoryID.String(),
+			Subcategories: len(covered) - 1,
+			PeriodType:    b.PeriodType,
+			PeriodStart:   start.Format("2006-01-02"),
+			PeriodEnd:     end.Format("2006-01-02"),
+			Amount:        roundCents(b.Amount),
+			Spent:         roundCents(total),
+			Remaining:     roundCents(b.Amount - total),
+			Status:        "ok",
+		}
+		if c, ok := forest.byID[b.CategoryID]; ok {
+			status.CategoryName = c.Name
+		}
+		if b.Amount > 0 {
+			status.PercentUsed = roundCents(total / b.Amount * 100)
+		}
+		switch {
+		case total > b.Amount:
+			status.Status = "over_limit"
+		case total >= b.Amount*budgetWarningShare:
+			status.Status = "approaching_limit"
+		}
+		report.Budgets = append(report.Budgets, status)
+	}
+
+	sort.SliceStable(report.Budgets, func(i, j int) bool {
+		return report.Budgets[i].CategoryName < report.Budgets[j].CategoryName
+	})
+	return report, nil
+}
+
+// budgetPeriod returns the inclusive range of the budget's period that
+// contains day. Monthly budgets restart on the day of month of their start,
+// or the month's last day when it is shorter. ok is false before the budget
+// starts.
+func budgetPeriod(b model.Budget, day time.Time) (start, end time.Time, ok bool) {
+	first := time.Date(b.PeriodStart.Year(), b.PeriodStart.Month(), b.PeriodStart.Day(), 0, 0, 0, 0, day.Location())
+	if day.Before(first) {
+		return time.Time{}, time.Time{}, false
+	}
+
+	months := 1
+	if b.PeriodType == "yearly" {
+		months = 12
+	}
+	elapsed := (day.Year()-first.Year())*12 + int(day.Month()-first.Month())
+	n := elapsed / months
+	start = addMonthsClamped(first, n*months)
+	if start.After(day) {
+		n--
+		start = addMonthsClamped(first, n*months)
+	}
+	end = addMonthsClamped(first, (n+1)*months).AddDate(0, 0, -1)
+	return start, end, true
+}
+
+// addMonthsClamped adds months to t, keeping the day of month within the
+// target month instead of overflowing into the next one
+func addMonthsClamped(t time.Time, months int) time.Time {
+	firstOfMonth := time.Date(t.Year(), t.Month()+time.Month(months), 1, 0, 0, 0, 0, t.Location())
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+	day := t.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(firstOfMonth.Year(), firstOfMonth.Month(), day, 0, 0, 0, 0, t.Location())
+}
+
+// rollUpCategoryTotals adds the activity of subcategories to their top-level
+// category. Activity of deleted categories is kept as it is.
+func rollUpCategoryTotals(totals []repository.CategoryTotal, forest *categoryForest) []repository.CategoryTotal {
+	var rolled []repository.CategoryTotal
+	index := make(map[uuid.UUID]int)
+	for _, t := range totals {
+		id := t.CategoryID
+		name := t.Name
+		if _, ok := forest.byID[id]; ok {
+			id = forest.rootOf(id)
+			name = forest.byID[id].Name
+		}
+		i, ok := index[id]
+		if !ok {
+			i = len(rolled)
+			index[id] = i
+			rolled = append(rolled, repository.CategoryTotal{CategoryID: id, Name: name})
+		}
+		rolled[i].TransactionTotal += t.TransactionTotal
+		rolled[i].TransactionCount += t.TransactionCount
+		rolled[i].CostTotal += t.CostTotal
+		rolled[i].CostCount += t.CostCount
+	}
+	return rolled
+}
+
+// parentIDString returns the active parent of a category as a string
+func parentIDString(forest *categoryForest, id uuid.UUID) *string {
+	parentID := forest.parentOf(id)
+	if parentID == uuid.Nil {
+		return nil
+	}
+	value := parentID.String()
+	return &value
+}
+
+// categoryTotals sums a range of calendar days in the start's time zone.
+// Expense reports include costs, which carry a timestamp instead of a date.
+func (s *reportService) categoryTotals(ctx context.Context, userID uuid.UUID, txType string, start, end time.Time) ([]repository.CategoryTotal, error) {
+	return s.reportRepo.CategoryTotals(ctx, userID, repository.CategoryTotalsFilter{
+		Type:         txType,
+		IncludeCosts: txType == string(model.TransactionTypeExpense),
+		From:         start.Format("2006-01-02"),
+		To:           end.Format("2006-01-02"),
+		CostsFrom:    start,
+		CostsTo:      end.AddDate(0, 0, 1),
+	})
+}
+
+// previousPeriod returns the period of the same length directly before the
+// given one. A range of whole calendar months is compared with the same
+// number of months before it, so March is compared with all of February.
+func previousPeriod(start, end time.Time) (time.Time, time.Time) {
+	if start.Day() == 1 && end.AddDate(0, 0, 1).Day() == 1 {
+		months := (end.Year()-start.Year())*12 + int(end.Month()-start.Month()) + 1
+		return start.AddDate(0, -months, 0), start.AddDate(0, 0, -1)
+	}
+	days := int(math.Round(end.Sub(start).Hours()/24)) + 1
+	return start.AddDate(0, 0, -days), start.AddDate(0, 0, -1)
+}
+
+// compareToPrevious computes the change from previous to current
+func compareToPrevious(current, previous float64) dto.ChangeResponse {
+	change := dto.ChangeResponse{Delta: roundCents(current - previous)}
+	if previous != 0 {
+		percent := roundCents((current - previous) / math.Abs(previous) * 100)
+		change.Percent = &percent
+	}
+	return change
+}
+
+// periodRange resolves the inclusive range of a report grouped by period.
+// Without to it ends today in loc, without from it covers twelve periods.
+func periodRange(from, to *time.Time, groupBy string, loc *time.Location) (time.Time, time.Time, error) {
+	switch groupBy {
+	case GroupByDay, GroupByWeek, GroupByMonth, GroupByYear:
+	default:
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: groupBy must be one of day, week, month, year", constant.ErrInvalidInput)
+	}
+
+	end := time.Now().In(loc)
+	if to != nil {
+		end = *to
+	}
+	end = time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, loc)
+
+	var start time.Time
+	if from != nil {
+		start = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+	} else {
+		start = periodStart(end, groupBy)
+		for i := 1; i < defaultReportPeriods; i++ {
+			start = periodStart(start.AddDate(0, 0, -1), groupBy)
+		}
+	}
+	if start.After(end) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: from must not be after to", constant.ErrInvalidInput)
+	}
+	return start, end, nil
+}
+
+// periodStart returns the first day of the period containing d. Weeks start
+// on Monday, matching date_trunc.
+func periodStart(d time.Time, groupBy string) time.Time {
+	year, month, day := d.Date()
+	switch groupBy {
+	case GroupByYear:
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, d.Location())
+	case GroupByMonth:
+		return time.Date(year, month, 1, 0, 0, 0, 0, d.Location())
+	case GroupByWeek:
+		sinceMonday := (int(d.Weekday()) + 6) % 7
+		return time.Date(year, month, day-sinceMonday, 0, 0, 0, 0, d.Location())
+	default:
+		return time.Date(year, month, day, 0, 0, 0, 0, d.Location())
+	}
+}
+
+// nextPeriod returns the first day of the period after the one starting at start
+func nextPeriod(start time.Time, groupBy string) time.Time {
+	switch groupBy {
+	case GroupByYear:
+		return start.AddDate(1, 0, 0)
+	case GroupByMonth:
+		return start.AddDate(0, 1, 0)
+	case GroupByWeek:
+		return start.AddDate(0, 0, 7)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+func periodLabel(start time.Time, groupBy string) string {
+	switch groupBy {
+	case GroupByYear:
+		return start.Format("2006")
+	case GroupByMonth:
+		return start.Format("2006-01")
+	case GroupByWeek:
+		year, week := start.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	default:
+		return start.Format("2006-01-02")
+	}
+}
+
+// roundCents removes the floating point noise of summed amounts
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tyha2404/nexo-app-api/internal/constant"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+)
+
+func TestPeriodStart(t *testing.T) {
+	tests := []struct {
+		day     string
+		groupBy string
+		want    string
+	}{
+		{"2024-03-15", GroupByDay, "2024-03-15"},
+		{"2024-03-13", GroupByWeek, "2024-03-11"},
+		{"2024-03-11", GroupByWeek, "2024-03-11"},
+		{"2024-03-17", GroupByWeek, "2024-03-11"},
+		{"2025-01-01", GroupByWeek, "2024-12-30"},
+		{"2024-03-15", GroupByMonth, "2024-03-01"},
+		{"2024-03-15", GroupByYear, "2024-01-01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.groupBy+" "+tt.day, func(t *testing.T) {
+			got := periodStart(mustDate(t, tt.day), tt.groupBy)
+			if got.Format("2006-01-02") != tt.want {
+				t.Errorf("periodStart = %s, want %s", got.Format("2006-01-02"), tt.want)
+			}
+		})
+	}
+}
+
+func TestNextPeriod(t *testing.T) {
+	tests := []struct {
+		start   string
+		groupBy string
+		want    string
+	}{
+		{"2024-02-28", GroupByDay, "2024-02-29"},
+		{"2024-12-30", GroupByWeek, "2025-01-06"},
+		{"2024-12-01", GroupByMonth, "2025-01-01"},
+		{"2024-01-01", GroupByYear, "2025-01-01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.groupBy+" "+tt.start, func(t *testing.T) {
+			got := nextPeriod(mustDate(t, tt.start), tt.groupBy)
+			if got.Format("2006-01-02") != tt.want {
+				t.Errorf("nextPeriod = %s, want %s", got.Format("2006-01-02"), tt.want)
+			}
+		})
+	}
+}
+
+func TestPeriodLabel(t *testing.T) {
+	tests := []struct {
+		start   string
+		groupBy string
+		want    string
+	}{
+		{"2024-03-15", GroupByDay, "2024-03-15"},
+		{"2024-03-11", GroupByWeek, "2024-W11"},
+		{"2024-12-30", GroupByWeek, "2025-W01"},
+		{"2021-01-04", GroupByWeek, "2021-W01"},
+		{"2020-12-28", GroupByWeek, "2020-W53"},
+		{"2024-03-01", GroupByMonth, "2024-03"},
+		{"2024-01-01", GroupByYear, "2024"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.groupBy+" "+tt.start, func(t *testing.T) {
+			if got := periodLabel(mustDate(t, tt.start), tt.groupBy); got != tt.want {
+				t.Errorf("periodLabel = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPeriodRange(t *testing.T) {
+	date := func(s string) *time.Time {
+		d := mustDate(t, s)
+		return &d
+	}
+	tests := []struct {
+		name      string
+		from, to  *time.Time
+		groupBy   string
+		wantStart string
+		wantEnd   string
+	}{
+		{"explicit range", date("2024-01-10"), date("2024-02-20"), GroupByDay, "2024-01-10", "2024-02-20"},
+		{"twelve months", nil, date("2024-03-15"), GroupByMonth, "2023-04-01", "2024-03-15"},
+		{"twelve weeks", nil, date("2024-03-13"), GroupByWeek, "2023-12-25", "2024-03-13"},
+		{"twelve years", nil, date("2024-03-15"), GroupByYear, "2013-01-01", "2024-03-15"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := periodRange(tt.from, tt.to, tt.groupBy, time.UTC)
+			if err != nil {
+				t.Fatalf("periodRange: %v", err)
+			}
+			if start.Format("2006-01-02") != tt.wantStart || end.Format("2006-01-02") != tt.wantEnd {
+				t.Errorf("periodRange = %s..%s, want %s..%s", start.Format("2006-01-02"), end.Format("2006-01-02"), tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+
+	t.Run("unknown groupBy", func(t *testing.T) {
+		if _, _, err := periodRange(nil, nil, "quarter", time.UTC); !errors.Is(err, constant.ErrInvalidInput) {
+			t.Errorf("err = %v, want ErrInvalidInput", err)
+		}
+	})
+	t.Run("from after to", func(t *testing.T) {
+		if _, _, err := periodRange(date("2024-03-02"), date("2024-03-01"), GroupByDay, time.UTC); !errors.Is(err, constant.ErrInvalidInput) {
+			t.Errorf("err = %v, want ErrInvalidInput", err)
+		}
+	})
+}
+
+func TestPreviousPeriod(t *testing.T) {
+	tests := []struct {
+		name       string
+		start, end string
+		wantStart  string
+		wantEnd    string
+	}{
+		{"whole month", "2024-03-01", "2024-03-31", "2024-02-01", "2024-02-29"},
+		{"whole quarter", "2024-04-01", "2024-06-30", "2024-01-01", "2024-03-31"},
+		{"days", "2024-03-10", "2024-03-16", "2024-03-03", "2024-03-09"},
+		{"partial month", "2024-03-01", "2024-03-15", "2024-02-15", "2024-02-29"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := previousPeriod(mustDate(t, tt.start), mustDate(t, tt.end))
+			if start.Format("2006-01-02") != tt.wantStart || end.Format("2006-01-02") != tt.wantEnd {
+				t.Errorf("previousPeriod = %s..%s, want %s..%s", start.Format("2006-01-02"), end.Format("2006-01-02"), tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestBudgetPeriod(t *testing.T) {
+	tests := []struct {
+		name       string
+		periodType string
+		start      string
+		day        string
+		wantStart  string
+		wantEnd    string
+	}{
+		{"monthly from the 31st", "monthly", "2024-01-31", "2024-02-15", "2024-01-31", "2024-02-28"},
+		{"monthly short month", "monthly", "2024-01-31", "2024-02-29", "2024-02-29", "2024-03-30"},
+		{"monthly mid month", "monthly", "2024-01-15", "2024-03-14", "2024-02-15", "2024-03-14"},
+		{"yearly", "yearly", "2023-04-01", "2024-03-31", "2023-04-01", "2024-03-31"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := model.Budget{PeriodType: tt.periodType, PeriodStart: mustDate(t, tt.start)}
+			start, end, ok := budgetPeriod(b, mustDate(t, tt.day))
+			if !ok {
+				t.Fatal("budgetPeriod: day reported before the budget start")
+			}
+			if start.Format("2006-01-02") != tt.wantStart || end.Format("2006-01-02") != tt.wantEnd {
+				t.Errorf("budgetPeriod = %s..%s, want %s..%s", start.Format("2006-01-02"), end.Format("2006-01-02"), tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+
+	t.Run("before the start", func(t *testing.T) {
+		b := model.Budget{PeriodType: "monthly", PeriodStart: mustDate(t, "2024-03-01")}
+		if _, _, ok := budgetPeriod(b, mustDate(t, "2024-02-29")); ok {
+			t.Error("budgetPeriod ok before the budget start")
+		}
+	})
+}
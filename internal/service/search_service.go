@@ -0,0 +1,313 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/constant"
+	"github.com/tyha2404/nexo-app-api/internal/dto"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"github.com/tyha2404/nexo-app-api/internal/repository"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+	// maxSearchQueryLength keeps the generated SQL and tsquery small
+	maxSearchQueryLength = 500
+)
+
+type SearchService interface {
+	// Search finds the user's transactions and costs matching q, which mixes
+	// free text with filters such as amount>50 or category:food
+	Search(ctx context.Context, userID uuid.UUID, q string, page, limit int) ([]dto.SearchResultResponse, int64, error)
+}
+
+type searchService struct {
+	searchRepo repository.SearchRepo
+}
+
+func NewSearchService(searchRepo repository.SearchRepo) SearchService {
+	return &searchService{
+		searchRepo: searchRepo,
+	}
+}
+
+func (s *searchService) Search(ctx context.Context, userID uuid.UUID, q string, page, limit int) ([]dto.SearchResultResponse, int64, error) {
+	if len(q) > maxSearchQueryLength {
+		return nil, 0, fmt.Errorf("%w: q must be at most %d characters", constant.ErrInvalidInput, maxSearchQueryLength)
+	}
+	filter, err := parseSearchQuery(q)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+	filter.Limit = limit
+	filter.Offset = (page - 1) * limit
+
+	hits, total, err := s.searchRepo.Search(ctx, userID, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	results := make([]dto.SearchResultResponse, 0, len(hits))
+	for i := range hits {
+		results = append(results, toSearchResultResponse(&hits[i]))
+	}
+	return results, total, nil
+}
+
+// parseSearchQuery splits a search into its free text, which becomes a
+// tsquery, and its filters. Supported filters are
+//
+//	amount>50, amount>=50, amount<50, amount<=50, amount:50, amount:10..50
+//	category:food (or cat:), tag:travel, type:income, in:costs
+//	from:2024-03-01, to:2024-03-31
+//
+// Filter values may be quoted, e.g. category:"eating out". Words match as
+// prefixes, "quoted words" as a phrase and -word excludes a word. Unknown
+// filter keys are searched as text.
+func parseSearchQuery(q string) (repository.SearchFilter, error) {
+	var filter repository.SearchFilter
+	var terms []string
+	var included int
+
+	for _, token := range splitSearchQuery(q) {
+		key, op, value, ok := splitSearchFilter(token)
+		if ok {
+			handled, err := applySearchFilter(&filter, key, op, value)
+			if err != nil {
+				return filter, err
+			}
+			if handled {
+				continue
+			}
+		}
+
+		negated := strings.HasPrefix(token, "-") && len(token) > 1
+		if negated {
+			token = token[1:]
+		}
+		words := searchTerms(strings.Trim(token, `"`))
+		if len(words) == 0 {
+			continue
+		}
+
+		var term string
+		if len(words) == 1 {
+			term = words[0] + ":*"
+		} else {
+			term = "(" + strings.Join(words, " <-> ") + ")"
+		}
+		if negated {
+			term = "!" + term
+		} else {
+			included++
+		}
+		terms = append(terms, term)
+	}
+
+	if len(terms) > 0 && included == 0 {
+		return filter, fmt.Errorf("%w: q needs at least one word that is not excluded", constant.ErrInvalidInput)
+	}
+	filter.Query = strings.Join(terms, " & ")
+
+	if filter.Query == "" && filter.Kind == "" && filter.Type == "" && len(filter.Amounts) == 0 &&
+		len(filter.Categories) == 0 && len(filter.Tags) == 0 && filter.From == nil && filter.To == nil {
+		return filter, fmt.Errorf("%w: q is required", constant.ErrInvalidInput)
+	}
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return filter, fmt.Errorf("%w: from must not be after to", constant.ErrInvalidInput)
+	}
+	return filter, nil
+}
+
+// splitSearchQuery splits a search at spaces outside double quotes
+func splitSearchQuery(q string) []string {
+	var tokens []string
+	var current strings.Builder
+	quoted := false
+	for _, r := range q {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			current.WriteRune(r)
+		case unicode.IsSpace(r) && !quoted:
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+	return tokens
+}
+
+// splitSearchFilter splits a token like amount>=50 or category:"eating out"
+// into its key, operator and unquoted value
+func splitSearchFilter(token string) (key, op, value string, ok bool) {
+	i := strings.IndexAny(token, ":<>=")
+	if i <= 0 {
+		return "", "", "", false
+	}
+	key = strings.ToLower(token[:i])
+	rest := token[i:]
+	for _, candidate := range []string{">=", "<=", ">", "<", "=", ":"} {
+		if strings.HasPrefix(rest, candidate) {
+			op = candidate
+			break
+		}
+	}
+	value = strings.Trim(rest[len(op):], `"`)
+	if value == "" {
+		return "", "", "", false
+	}
+	return key, op, value, true
+}
+
+// applySearchFilter adds a filter to the search. It reports false for keys
+// that are not filters, whose token is then searched as text.
+func applySearchFilter(filter *repository.SearchFilter, key, op, value string) (bool, error) {
+	if key == "amount" {
+		amounts, err := parseAmountFilter(op, value)
+		if err != nil {
+			return false, err
+		}
+		filter.Amounts = append(filter.Amounts, amounts...)
+		return true, nil
+	}
+	if op != ":" {
+		return false, nil
+	}
+
+	switch key {
+	case "category", "cat":
+		filter.Categories = append(filter.Categories, value)
+	case "tag":
+		filter.Tags = append(filter.Tags, value)
+	case "type":
+		t := strings.ToUpper(value)
+		if t != string(model.TransactionTypeIncome) && t != string(model.TransactionTypeExpense) {
+			return false, fmt.Errorf("%w: type must be income or expense", constant.ErrInvalidInput)
+		}
+		filter.Type = t
+	case "in":
+		switch strings.ToLower(value) {
+		case "transaction", "transactions":
+			filter.Kind = repository.SearchKindTransaction
+		case "cost", "costs":
+			filter.Kind = repository.SearchKindCost
+		default:
+			return false, fmt.Errorf("%w: in must be transactions or costs", constant.ErrInvalidInput)
+		}
+	case "from", "to":
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return false, fmt.Errorf("%w: %s must be YYYY-MM-DD", constant.ErrInvalidInput, key)
+		}
+		if key == "from" {
+			filter.From = &date
+		} else {
+			filter.To = &date
+		}
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// parseAmountFilter turns amount>50, amount:50 or amount:10..50 into
+// conditions on the amount
+func parseAmountFilter(op, value string) ([]repository.AmountCondition, error) {
+	parse := func(s string) (float64, error) {
+		amount, err := strconv.ParseFloat(s, 64)
+		if err != nil || amount < 0 {
+			return 0, fmt.Errorf("%w: invalid amount %q", constant.ErrInvalidInput, s)
+		}
+		return amount, nil
+	}
+
+	if op == ":" {
+		if low, high, isRange := strings.Cut(value, ".."); isRange {
+			var conds []repository.AmountCondition
+			if low != "" {
+				amount, err := parse(low)
+				if err != nil {
+					return nil, err
+				}
+				conds = append(conds, repository.AmountCondition{Op: ">=", Value: amount})
+			}
+			if high != "" {
+				amount, err := parse(high)
+				if err != nil {
+					return nil, err
+				}
+				conds = append(conds, repository.AmountCondition{Op: "<=", Value: amount})
+			}
+			return conds, nil
+		}
+		op = "="
+	}
+
+	amount, err := parse(value)
+	if err != nil {
+		return nil, err
+	}
+	return []repository.AmountCondition{{Op: op, Value: amount}}, nil
+}
+
+// searchTerms splits text into the lower-case words and numbers a tsquery is
+// built from. Anything else is dropped, which also keeps tsquery operators
+// out of the query.
+func searchTerms(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func toSearchResultResponse(h *repository.SearchHit) dto.SearchResultResponse {
+	result := dto.SearchResultResponse{
+		Kind:         h.Kind,
+		ID:           h.ID,
+		Date:         h.Date.Format(time.RFC3339),
+		Amount:       h.Amount,
+		Currency:     h.Currency,
+		Type:         h.Type,
+		Text:         h.Text,
+		Highlight:    h.Highlight,
+		CategoryID:   h.CategoryID,
+		CategoryName: h.CategoryName,
+		Rank:         h.Rank,
+	}
+	for i := range h.Tags {
+		result.Tags = append(result.Tags, *toTagResponse(&h.Tags[i]))
+	}
+	if h.MatchedText {
+		result.MatchedOn = append(result.MatchedOn, "text")
+	}
+	if h.MatchedCategory {
+		result.MatchedOn = append(result.MatchedOn, "category")
+	}
+	if h.MatchedTag {
+		result.MatchedOn = append(result.MatchedOn, "tag")
+	}
+	return result
+}
@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/constant"
+	"github.com/tyha2404/nexo-app-api/internal/dto"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"github.com/tyha2404/nexo-app-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Subscription statuses
+const (
+	subscriptionStatusDetected  = "detected"
+	subscriptionStatusConfirmed = "confirmed"
+	subscriptionStatusDismissed = "dismissed"
+)
+
+type SubscriptionService interface {
+	// List returns the recurring expenses detected in the user's history.
+	// Dismissed ones are left out unless asked for.
+	List(ctx context.Context, userID uuid.UUID, includeDismissed bool) (*dto.SubscriptionsResponse, error)
+	// Confirm turns a detected subscription into a bill of the same cadence.
+	// Bills repeat monthly at the most, so weekly and biweekly subscriptions
+	// cannot be confirmed.
+	Confirm(ctx context.Context, userID, id uuid.UUID, req dto.ConfirmSubscriptionRequest) (*dto.BillResponse, error)
+	// Dismiss hides a detected subscription from the list
+	Dismiss(ctx context.Context, userID, id uuid.UUID) error
+	// Undismiss shows a dismissed subscription again
+	Undismiss(ctx context.Context, userID, id uuid.UUID) error
+}
+
+type subscriptionService struct {
+	subscriptionRepo repository.SubscriptionRepo
+	transactionRepo  repository.TransactionRepository
+	categoryRepo     repository.CategoryRepo
+}
+
+func NewSubscriptionService(subscriptionRepo repository.SubscriptionRepo, transactionRepo repository.TransactionRepository, categoryRepo repository.CategoryRepo) SubscriptionService {
+	return &subscriptionService{
+		subscriptionRepo: subscriptionRepo,
+		transactionRepo:  transactionRepo,
+		categoryRepo:     categoryRepo,
+	}
+}
+
+func (s *subscriptionService) List(ctx context.Context, userID uuid.UUID, includeDismissed bool) (*dto.SubscriptionsResponse, error) {
+	today := goalToday(time.Now())
+	series, err := s.detect(ctx, userID, today)
+	if err != nil {
+		return nil, err
+	}
+	decisions, err := s.decisions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := s.categoryRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	categoryNames := make(map[uuid.UUID]string, len(categories))
+	for _, c := range categories {
+		categoryNames[c.ID] = c.Name
+	}
+
+	resp := &dto.SubscriptionsResponse{Subscriptions: []dto.SubscriptionResponse{}}
+	for i := range series {
+		subscription := toSubscriptionResponse(&series[i], userID, today)
+		subscription.CategoryName = categoryNames[series[i].CategoryID]
+		if decision, ok := decisions[subscriptionID(userID, &series[i])]; ok {
+			switch decision.Status {
+			case model.SubscriptionStatusConfirmed:
+				subscription.Status = subscriptionStatusConfirmed
+			case model.SubscriptionStatusDismissed:
+				subscription.Status = subscriptionStatusDismissed
+			}
+			if decision.BillID != nil {
+				billID := decision.BillID.String()
+				subscription.BillID = &billID
+			}
+		}
+		if subscription.Status == subscriptionStatusDismissed {
+			if !includeDismissed {
+				continue
+			}
+		} else {
+			resp.AnnualTotal += series[i].Amount * series[i].Cadence.PerYear
+		}
+		resp.Subscriptions = append(resp.Subscriptions, subscription)
+	}
+	resp.MonthlyTotal = roundCents(resp.AnnualTotal / 12)
+	resp.AnnualTotal = roundCents(resp.AnnualTotal)
+	return resp, nil
+}
+
+func (s *subscriptionService) Confirm(ctx context.Context, userID, id uuid.UUID, req dto.ConfirmSubscriptionRequest) (*dto.BillResponse, error) {
+	today := goalToday(time.Now())
+	series, err := s.getDetected(ctx, userID, id, today)
+	if err != nil {
+		return nil, err
+	}
+	decisions, err := s.decisions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if decision, ok := decisions[id]; ok && decision.Status == model.SubscriptionStatusConfirmed {
+		return nil, fmt.Errorf("%w: subscription is already confirmed", constant.ErrInvalidInput)
+	}
+	recurrence, ok := subscriptionBillRecurrence(series.Cadence)
+	if !ok {
+		return nil, fmt.Errorf("%w: a %s subscription cannot be tracked as a bill", constant.ErrInvalidInput, series.Cadence.Name)
+	}
+
+	nextCharge := subscriptionNextCharge(series, today)
+	bill := &model.Bill{
+		UserID:           userID,
+		Payee:            truncateRunes(series.Description, 100),
+		ExpectedAmount:   series.Amount,
+		DueDay:           series.DayOfMonth,
+		Recurrence:       recurrence,
+		StartDate:        nextCharge,
+		RemindDaysBefore: defaultBillRemindDays,
+		CategoryID:       &series.CategoryID,
+	}
+	if req.Payee != nil {
+		if bill.Payee = strings.TrimSpace(*req.Payee); bill.Payee == "" {
+			return nil, fmt.Errorf("%w: payee must not be blank", constant.ErrInvalidInput)
+		}
+	}
+	if req.RemindDaysBefore != nil {
+		bill.RemindDaysBefore = *req.RemindDaysBefore
+	}
+	if req.CategoryID != nil {
+		category, err := s.categoryRepo.GetByID(ctx, *req.CategoryID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if category == nil || category.UserID != userID {
+			return nil, fmt.Errorf("%w: category %s not found", constant.ErrInvalidInput, *req.CategoryID)
+		}
+		if err := checkCategoryKind(category, model.TransactionTypeExpense); err != nil {
+			return nil, err
+		}
+		bill.CategoryID = req.CategoryID
+	}
+
+	decision := &model.SubscriptionDecision{
+		ID:        id,
+		UserID:    userID,
+		Status:    model.SubscriptionStatusConfirmed,
+		CreatedAt: time.Now(),
+	}
+	if err := s.subscriptionRepo.Confirm(ctx, decision, bill); err != nil {
+		return nil, err
+	}
+
+	resp := toBillResponse(bill)
+	next := nextCharge.Format("2006-01-02")
+	resp.NextDueDate = &next
+	return &resp, nil
+}
+
+func (s *subscriptionService) Dismiss(ctx context.Context, userID, id uuid.UUID) error {
+	if _, err := s.getDetected(ctx, userID, id, goalToday(time.Now())); err != nil {
+		return err
+	}
+	decisions, err := s.decisions(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if decision, ok := decisions[id]; ok && decision.Status == model.SubscriptionStatusConfirmed {
+		return fmt.Errorf("%w: subscription is confirmed; delete its bill instead", constant.ErrInvalidInput)
+	}
+	return s.subscriptionRepo.Dismiss(ctx, &model.SubscriptionDecision{
+		ID:        id,
+		UserID:    userID,
+		Status:    model.SubscriptionStatusDismissed,
+		CreatedAt: time.Now(),
+	})
+}
+
+func (s *subscriptionService) Undismiss(ctx context.Context, userID, id uuid.UUID) error {
+	decisions, err := s.decisions(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if decision, ok := decisions[id]; !ok || decision.Status != model.SubscriptionStatusDismissed {
+		return constant.ErrNotFound
+	}
+	return s.subscriptionRepo.DeleteDecision(ctx, userID, id)
+}
+
+// detect returns the recurring expenses in the user's history
+func (s *subscriptionService) detect(ctx context.Context, userID uuid.UUID, today time.Time) ([]recurringSeries, error) {
+	history, err := s.transactionRepo.ListByDateRange(ctx, userID, today.AddDate(0, 0, -recurringLookbackDays), today)
+	if err != nil {
+		return nil, err
+	}
+	var series []recurringSeries
+	for _, r := range detectRecurring(history, today) {
+		if r.Type == model.TransactionTypeExpense {
+			series = append(series, r)
+		}
+	}
+	return series, nil
+}
+
+// getDetected returns the subscription with the given ID if it is still
+// detected in the user's history
+func (s *subscriptionService) getDetected(ctx context.Context, userID, id uuid.UUID, today time.Time) (*recurringSeries, error) {
+	series, err := s.detect(ctx, userID, today)
+	if err != nil {
+		return nil, err
+	}
+	for i := range series {
+		if subscriptionID(userID, &series[i]) == id {
+			return &series[i], nil
+		}
+	}
+	return nil, constant.ErrNotFound
+}
+
+func (s *subscriptionService) decisions(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]model.SubscriptionDecision, error) {
+	decisions, err := s.subscriptionRepo.ListDecisions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]model.SubscriptionDecision, len(decisions))
+	for _, d := range decisions {
+		byID[d.ID] = d
+	}
+	return byID, nil
+}
+
+// subscriptionID derives a stable ID from the user and the series key, so
+// that decisions survive new charges and need no detection table
+func subscriptionID(userID uuid.UUID, series *recurringSeries) uuid.UUID {
+	return uuid.NewSHA1(userID, []byte(series.Key))
+}
+
+// subscriptionNextCharge is the first day the series is due on after its
+// last charge that is not before today
+func subscriptionNextCharge(series *recurringSeries, today time.Time) time.Time {
+	next := series.LastDate.AddDate(0, 0, 1)
+	if today.After(next) {
+		next = today
+	}
+	for !series.occursOn(next) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// subscriptionBillRecurrence is the bill recurrence of a cadence, if bills
+// have one
+func subscriptionBillRecurrence(c recurringCadence) (model.BillRecurrence, bool) {
+	switch c.Months {
+	case 1:
+		return model.BillRecurrenceMonthly, true
+	case 3:
+		return model.BillRecurrenceQuarterly, true
+	case 12:
+		return model.BillRecurrenceYearly, true
+	default:
+		return "", false
+	}
+}
+
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return strings.TrimSpace(string(runes[:n]))
+}
+
+func toSubscriptionResponse(series *recurringSeries, userID uuid.UUID, today time.Time) dto.SubscriptionResponse {
+	return dto.SubscriptionResponse{
+		ID:             subscriptionID(userID, series).String(),
+		Description:    series.Description,
+		CategoryID:     series.CategoryID.String(),
+		Amount:         series.Amount,
+		Cadence:        series.Cadence.Name,
+		DayOfMonth:     series.DayOfMonth,
+		Occurrences:    series.Occurrences,
+		LastChargeDate: series.LastDate.Format("2006-01-02"),
+		NextChargeDate: subscriptionNextCharge(series, today).Format("2006-01-02"),
+		AnnualCost:     roundCents(series.Amount * series.Cadence.PerYear),
+		Status:         subscriptionStatusDetected,
+	}
+}
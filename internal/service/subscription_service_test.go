@@ -0,0 +1,144 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+)
+
+func TestSubscriptionNextCharge(t *testing.T) {
+	tests := []struct {
+		name  string
+		day   int
+		last  string
+		today string
+		want  string
+	}{
+		{"later this month", 20, "2024-05-20", "2024-06-10", "2024-06-20"},
+		{"due today", 20, "2024-05-20", "2024-06-20", "2024-06-20"},
+		{"charged this month", 5, "2024-06-05", "2024-06-10", "2024-07-05"},
+		{"charged late", 5, "2024-06-07", "2024-06-08", "2024-07-05"},
+		{"charge overdue", 5, "2024-04-05", "2024-06-10", "2024-07-05"},
+		{"short month", 31, "2024-01-31", "2024-02-01", "2024-02-29"},
+		{"after a short month", 31, "2024-02-29", "2024-03-01", "2024-03-31"},
+		{"new year", 15, "2024-12-15", "2024-12-20", "2025-01-15"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series := &recurringSeries{Cadence: cadenceMonthly, DayOfMonth: tt.day, LastDate: mustDate(t, tt.last)}
+			got := subscriptionNextCharge(series, mustDate(t, tt.today))
+			if got.Format("2006-01-02") != tt.want {
+				t.Errorf("subscriptionNextCharge = %s, want %s", got.Format("2006-01-02"), tt.want)
+			}
+		})
+	}
+}
+
+func TestSubscriptionNextChargeCadences(t *testing.T) {
+	tests := []struct {
+		name    string
+		cadence recurringCadence
+		day     int
+		last    string
+		today   string
+		want    string
+	}{
+		{"weekly", cadenceWeekly, 0, "2024-06-07", "2024-06-10", "2024-06-14"},
+		{"weekly due today", cadenceWeekly, 0, "2024-06-07", "2024-06-14", "2024-06-14"},
+		{"biweekly overdue", cadenceBiweekly, 0, "2024-05-31", "2024-06-20", "2024-06-28"},
+		{"quarterly", cadenceQuarterly, 31, "2024-01-31", "2024-02-10", "2024-04-30"},
+		{"yearly", cadenceYearly, 29, "2024-02-29", "2024-03-01", "2025-02-28"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series := &recurringSeries{Cadence: tt.cadence, DayOfMonth: tt.day, LastDate: mustDate(t, tt.last)}
+			got := subscriptionNextCharge(series, mustDate(t, tt.today))
+			if got.Format("2006-01-02") != tt.want {
+				t.Errorf("subscriptionNextCharge = %s, want %s", got.Format("2006-01-02"), tt.want)
+			}
+		})
+	}
+}
+
+func TestSubscriptionCadences(t *testing.T) {
+	today := mustDate(t, "2024-06-20")
+	tests := []struct {
+		name       string
+		dates      []string
+		cadence    string
+		recurrence model.BillRecurrence
+		annualCost float64
+	}{
+		{"weekly", []string{"2024-05-24", "2024-05-31", "2024-06-07", "2024-06-14"}, "weekly", "", 519.48},
+		{"monthly", []string{"2024-04-12", "2024-05-12", "2024-06-12"}, "monthly", model.BillRecurrenceMonthly, 119.88},
+		{"quarterly", []string{"2023-09-15", "2023-12-15", "2024-03-15", "2024-06-15"}, "quarterly", model.BillRecurrenceQuarterly, 39.96},
+		{"yearly", []string{"2023-03-02", "2024-03-01"}, "yearly", model.BillRecurrenceYearly, 9.99},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var charges []model.Transaction
+			for _, d := range tt.dates {
+				charges = append(charges, testTransaction(t, model.TransactionTypeExpense, 9.99, d, "Streaming Plus"))
+			}
+			series := detectRecurring(charges, today)
+			if len(series) != 1 {
+				t.Fatalf("detected %d series, want 1", len(series))
+			}
+			resp := toSubscriptionResponse(&series[0], uuid.New(), today)
+			if resp.Cadence != tt.cadence || resp.AnnualCost != tt.annualCost {
+				t.Errorf("cadence %s costing %v a year, want %s costing %v", resp.Cadence, resp.AnnualCost, tt.cadence, tt.annualCost)
+			}
+			recurrence, ok := subscriptionBillRecurrence(series[0].Cadence)
+			if recurrence != tt.recurrence || ok != (tt.recurrence != "") {
+				t.Errorf("bill recurrence = %q, %v, want %q", recurrence, ok, tt.recurrence)
+			}
+		})
+	}
+}
+
+func TestSubscriptionID(t *testing.T) {
+	userID := uuid.New()
+	charges := []model.Transaction{
+		testTransaction(t, model.TransactionTypeExpense, 9.99, "2024-04-02", "Spotify P1234"),
+		testTransaction(t, model.TransactionTypeExpense, 9.99, "2024-05-02", "Spotify P2345"),
+		testTransaction(t, model.TransactionTypeExpense, 9.99, "2024-06-02", "Spotify P3456"),
+	}
+	before := detectRecurring(charges, mustDate(t, "2024-06-10"))
+	charges = append(charges, testTransaction(t, model.TransactionTypeExpense, 10.99, "2024-07-02", "Spotify P4567"))
+	after := detectRecurring(charges, mustDate(t, "2024-07-10"))
+	if len(before) != 1 || len(after) != 1 {
+		t.Fatalf("detected %d and %d series, want 1 each", len(before), len(after))
+	}
+
+	id := subscriptionID(userID, &before[0])
+	if got := subscriptionID(userID, &after[0]); got != id {
+		t.Errorf("ID changed with a new charge: %s, want %s", got, id)
+	}
+	if got := subscriptionID(uuid.New(), &before[0]); got == id {
+		t.Error("two users share a subscription ID")
+	}
+	other := before[0]
+	other.Key = "EXPENSE|" + uuid.Nil.String() + "|netflix"
+	if got := subscriptionID(userID, &other); got == id {
+		t.Error("two series share a subscription ID")
+	}
+}
+
+func TestTruncateRunes(t *testing.T) {
+	tests := []struct {
+		s    string
+		n    int
+		want string
+	}{
+		{"Netflix", 10, "Netflix"},
+		{"Netflix", 7, "Netflix"},
+		{"Amazon Prime", 7, "Amazon"},
+		{"Überweisung", 4, "Über"},
+	}
+	for _, tt := range tests {
+		if got := truncateRunes(tt.s, tt.n); got != tt.want {
+			t.Errorf("truncateRunes(%q, %d) = %q, want %q", tt.s, tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/constant"
+	"github.com/tyha2404/nexo-app-api/internal/dto"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"github.com/tyha2404/nexo-app-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+type TagService interface {
+	CreateTag(ctx context.Context, userID uuid.UUID, req dto.CreateTagRequest) (*dto.TagResponse, error)
+	GetTag(ctx context.Context, userID, id uuid.UUID) (*dto.TagResponse, error)
+	ListTags(ctx context.Context, userID uuid.UUID) ([]dto.TagResponse, error)
+	UpdateTag(ctx context.Context, userID, id uuid.UUID, req dto.UpdateTagRequest) (*dto.TagResponse, error)
+	MergeTags(ctx context.Context, userID, sourceID, targetID uuid.UUID) (*dto.TagResponse, error)
+	DeleteTag(ctx context.Context, userID, id uuid.UUID) error
+}
+
+type tagService struct {
+	tagRepo repository.TagRepo
+}
+
+func NewTagService(tagRepo repository.TagRepo) TagService {
+	return &tagService{
+		tagRepo: tagRepo,
+	}
+}
+
+func (s *tagService) CreateTag(ctx context.Context, userID uuid.UUID, req dto.CreateTagRequest) (*dto.TagResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if err := s.ensureNameAvailable(ctx, userID, name, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	tag := &model.Tag{
+		UserID: userID,
+		Name:   name,
+		Color:  req.Color,
+	}
+	if err := s.tagRepo.Create(ctx, tag); err != nil {
+		return nil, err
+	}
+
+	return toTagResponse(tag), nil
+}
+
+func (s *tagService) GetTag(ctx context.Context, userID, id uuid.UUID) (*dto.TagResponse, error) {
+	tag, err := s.getOwnedTag(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return toTagResponse(tag), nil
+}
+
+func (s *tagService) ListTags(ctx context.Context, userID uuid.UUID) ([]dto.TagResponse, error) {
+	tags, err := s.tagRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.TagResponse, 0, len(tags))
+	for i := range tags {
+		responses = append(responses, *toTagResponse(&tags[i]))
+	}
+	return responses, nil
+}
+
+func (s *tagService) UpdateTag(ctx context.Context, userID, id uuid.UUID, req dto.UpdateTagRequest) (*dto.TagResponse, error) {
+	tag, err := s.getOwnedTag(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if err := s.ensureNameAvailable(ctx, userID, name, tag.ID); err != nil {
+			return nil, err
+		}
+		tag.Name = name
+	}
+	if req.Color != nil {
+		tag.Color = req.Color
+	}
+
+	if err := s.tagRepo.Update(ctx, tag); err != nil {
+		return nil, err
+	}
+
+	return toTagResponse(tag), nil
+}
+
+// MergeTags folds the source tag into the target tag. Every transaction and
+// cost linked to the source ends up linked to the target, and the source is
+// deleted.
+func (s *tagService) MergeTags(ctx context.Context, userID, sourceID, targetID uuid.UUID) (*dto.TagResponse, error) {
+	if sourceID == targetID {
+		return nil, fmt.Errorf("%w: cannot merge a tag into itself", constant.ErrInvalidInput)
+	}
+
+	if _, err := s.getOwnedTag(ctx, userID, sourceID); err != nil {
+		return nil, err
+	}
+	target, err := s.getOwnedTag(ctx, userID, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.tagRepo.Merge(ctx, sourceID, targetID); err != nil {
+		return nil, err
+	}
+
+	return toTagResponse(target), nil
+}
+
+func (s *tagService) DeleteTag(ctx context.Context, userID, id uuid.UUID) error {
+	if _, err := s.getOwnedTag(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.tagRepo.Delete(ctx, id)
+}
+
+// getOwnedTag loads a tag and hides it if it belongs to another user
+func (s *tagService) getOwnedTag(ctx context.Context, userID, id uuid.UUID) (*model.Tag, error) {
+	tag, err := s.tagRepo.GetByID(ctx, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, constant.ErrNotFound
+		}
+		return nil, err
+	}
+	if tag.UserID != userID {
+		return nil, constant.ErrNotFound
+	}
+	return tag, nil
+}
+
+// ensureNameAvailable rejects names already used by another tag of the user
+func (s *tagService) ensureNameAvailable(ctx context.Context, userID uuid.UUID, name string, selfID uuid.UUID) error {
+	if name == "" {
+		return fmt.Errorf("%w: tag name is required", constant.ErrInvalidInput)
+	}
+
+	existing, err := s.tagRepo.FindByName(ctx, userID, name)
+	if err != nil && err != constant.ErrNotFound {
+		return err
+	}
+	if existing != nil && existing.ID != selfID {
+		return constant.ErrTagAlreadyExists
+	}
+	return nil
+}
+
+// resolveTags loads the user's tags for ids and fails if any of them is
+// missing or owned by someone else
+func resolveTags(ctx context.Context, tagRepo repository.TagRepo, userID uuid.UUID, ids []uuid.UUID) ([]model.Tag, error) {
+	unique := make(map[uuid.UUID]struct{}, len(ids))
+	for _, id := range ids {
+		unique[id] = struct{}{}
+	}
+
+	tags, err := tagRepo.FindByIDs(ctx, userID, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) != len(unique) {
+		return nil, fmt.Errorf("%w: unknown tag", constant.ErrInvalidInput)
+	}
+	return tags, nil
+}
+
+func toTagResponse(t *model.Tag) *dto.TagResponse {
+	return &dto.TagResponse{
+		ID:        t.ID.String(),
+		UserID:    t.UserID.String(),
+		Name:      t.Name,
+		Color:     t.Color,
+		CreatedAt: t.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: t.UpdatedAt.Format(time.RFC3339),
+	}
+}
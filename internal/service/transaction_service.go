@@ -3,42 +3,63 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/constant"
 	"github.com/tyha2404/nexo-app-api/internal/dto"
 	"github.com/tyha2404/nexo-app-api/internal/model"
 	"github.com/tyha2404/nexo-app-api/internal/repository"
+	"gorm.io/gorm"
 )
 
 type TransactionService interface {
 	CreateTransaction(ctx context.Context, userID uuid.UUID, req dto.CreateTransactionRequest) (*dto.TransactionResponse, error)
 	GetTransaction(ctx context.Context, userID, id uuid.UUID) (*dto.TransactionResponse, error)
-	ListTransactions(ctx context.Context, userID uuid.UUID, page, limit int) ([]dto.TransactionResponse, int64, error)
+	ListTransactions(ctx context.Context, userID uuid.UUID, page, limit int, filters map[string]interface{}) ([]dto.TransactionResponse, int64, error)
 	UpdateTransaction(ctx context.Context, userID, id uuid.UUID, req dto.UpdateTransactionRequest) (*dto.TransactionResponse, error)
 	DeleteTransaction(ctx context.Context, userID, id uuid.UUID) error
+	// SuggestCategory ranks the user's categories for a transaction that has
+	// not been created yet
+	SuggestCategory(ctx context.Context, userID uuid.UUID, req dto.SuggestCategoryRequest) (*dto.CategorySuggestionsResponse, error)
+	// FindDuplicates groups likely duplicates dated within [from, to], which
+	// default to the last 90 days
+	FindDuplicates(ctx context.Context, userID uuid.UUID, from, to *time.Time, windowDays int) (*dto.DuplicatesResponse, error)
+	// MergeTransactions keeps the transaction id and folds the duplicates into it
+	MergeTransactions(ctx context.Context, userID, id uuid.UUID, req dto.MergeTransactionsRequest) (*dto.TransactionResponse, error)
 }
 
 type transactionService struct {
 	transactionRepo repository.TransactionRepository
 	categoryRepo    repository.CategoryRepo
+	tagRepo         repository.TagRepo
+	ruleRepo        repository.CategorizationRuleRepo
+	suggester       CategorySuggester
 }
 
-func NewTransactionService(transactionRepo repository.TransactionRepository, categoryRepo repository.CategoryRepo) TransactionService {
+func NewTransactionService(
+	transactionRepo repository.TransactionRepository,
+	categoryRepo repository.CategoryRepo,
+	tagRepo repository.TagRepo,
+	ruleRepo repository.CategorizationRuleRepo,
+	suggester CategorySuggester,
+) TransactionService {
 	return &transactionService{
 		transactionRepo: transactionRepo,
 		categoryRepo:    categoryRepo,
+		tagRepo:         tagRepo,
+		ruleRepo:        ruleRepo,
+		suggester:       suggester,
 	}
 }
 
 func (s *transactionService) CreateTransaction(ctx context.Context, userID uuid.UUID, req dto.CreateTransactionRequest) (*dto.TransactionResponse, error) {
-	// Verify category exists and belongs to user
-	category, err := s.categoryRepo.GetByID(ctx, req.CategoryID)
+	tags, err := resolveTags(ctx, s.tagRepo, userID, req.TagIDs)
 	if err != nil {
-		return nil, errors.New("category not found")
-	}
-	if category.UserID != userID {
-		return nil, errors.New("unauthorized access to category")
+		return nil, err
 	}
 
 	transaction := &model.Transaction{
@@ -48,11 +69,44 @@ func (s *transactionService) CreateTransaction(ctx context.Context, userID uuid.
 		Type:            model.TransactionType(req.Type),
 		Description:     req.Description,
 		TransactionDate: req.TransactionDate,
+		Tags:            tags,
+	}
+
+	// Rules only pick the category when the request leaves it out
+	rules, err := loadRuleEngine(ctx, s.ruleRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+	outcome := rules.evaluate(transaction)
+	outcome.applyTo(transaction, req.CategoryID == uuid.Nil)
+
+	var category *model.Category
+	switch {
+	case req.CategoryID != uuid.Nil:
+		// Verify category exists and belongs to user
+		category, err = s.categoryRepo.GetByID(ctx, req.CategoryID)
+		if err != nil {
+			return nil, errors.New("category not found")
+		}
+		if category.UserID != userID {
+			return nil, errors.New("unauthorized access to category")
+		}
+		if category.ArchivedAt != nil {
+			return nil, fmt.Errorf("%w: category %q is archived", constant.ErrInvalidInput, category.Name)
+		}
+	case outcome.Category != nil:
+		category = outcome.Category
+	default:
+		return nil, fmt.Errorf("%w: categoryId is required when no rule assigns a category", constant.ErrInvalidInput)
+	}
+	if err := checkCategoryKind(category, transaction.Type); err != nil {
+		return nil, err
 	}
 
 	if err := s.transactionRepo.Create(ctx, transaction); err != nil {
 		return nil, err
 	}
+	s.suggester.Learn(userID, transaction)
 
 	// Reload to get associations if needed (though we already have category)
 	transaction.Category = category
@@ -73,9 +127,9 @@ func (s *transactionService) GetTransaction(ctx context.Context, userID, id uuid
 	return s.toResponse(transaction), nil
 }
 
-func (s *transactionService) ListTransactions(ctx context.Context, userID uuid.UUID, page, limit int) ([]dto.TransactionResponse, int64, error) {
+func (s *transactionService) ListTransactions(ctx context.Context, userID uuid.UUID, page, limit int, filters map[string]interface{}) ([]dto.TransactionResponse, int64, error) {
 	offset := (page - 1) * limit
-	transactions, total, err := s.transactionRepo.ListByUserID(ctx, userID, limit, offset)
+	transactions, total, err := s.transactionRepo.ListByUserID(ctx, userID, limit, offset, filters)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -97,6 +151,7 @@ func (s *transactionService) UpdateTransaction(ctx context.Context, userID, id u
 	if transaction.UserID != userID {
 		return nil, errors.New("transaction not found")
 	}
+	before := *transaction
 
 	if req.CategoryID != nil {
 		category, err := s.categoryRepo.GetByID(ctx, *req.CategoryID)
@@ -106,6 +161,10 @@ func (s *transactionService) UpdateTransaction(ctx context.Context, userID, id u
 		if category.UserID != userID {
 			return nil, errors.New("unauthorized access to category")
 		}
+		// Keeping an archived category is fine, moving into one is not
+		if category.ArchivedAt != nil && category.ID != transaction.CategoryID {
+			return nil, fmt.Errorf("%w: category %q is archived", constant.ErrInvalidInput, category.Name)
+		}
 		transaction.CategoryID = *req.CategoryID
 		transaction.Category = category
 	}
@@ -123,9 +182,40 @@ func (s *transactionService) UpdateTransaction(ctx context.Context, userID, id u
 		transaction.TransactionDate = *req.TransactionDate
 	}
 
+	// Only a change of category or type has to fit the category's kind
+	if transaction.CategoryID != before.CategoryID || transaction.Type != before.Type {
+		category := transaction.Category
+		if category == nil {
+			category, err = s.categoryRepo.GetByID(ctx, transaction.CategoryID)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if err := checkCategoryKind(category, transaction.Type); err != nil {
+			return nil, err
+		}
+	}
+
+	var tags []model.Tag
+	if req.TagIDs != nil {
+		tags, err = resolveTags(ctx, s.tagRepo, userID, req.TagIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if err := s.transactionRepo.Update(ctx, transaction); err != nil {
 		return nil, err
 	}
+	s.suggester.Forget(userID, &before)
+	s.suggester.Learn(userID, transaction)
+
+	if req.TagIDs != nil {
+		if err := s.transactionRepo.ReplaceTags(ctx, transaction, tags); err != nil {
+			return nil, err
+		}
+		transaction.Tags = tags
+	}
 
 	return s.toResponse(transaction), nil
 }
@@ -140,7 +230,206 @@ func (s *transactionService) DeleteTransaction(ctx context.Context, userID, id u
 		return errors.New("transaction not found")
 	}
 
-	return s.transactionRepo.Delete(ctx, id)
+	if err := s.transactionRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.suggester.Forget(userID, transaction)
+	return nil
+}
+
+func (s *transactionService) SuggestCategory(ctx context.Context, userID uuid.UUID, req dto.SuggestCategoryRequest) (*dto.CategorySuggestionsResponse, error) {
+	sample := &model.Transaction{Description: &req.Description}
+	if req.Amount != nil {
+		sample.Amount = *req.Amount
+	}
+	if req.Type != nil {
+		sample.Type = model.TransactionType(*req.Type)
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 3
+	}
+
+	// Categories in the trash or archived are never suggested, nor are
+	// those meant for the other type
+	categories, err := s.categoryRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[uuid.UUID]string, len(categories))
+	for i := range categories {
+		c := &categories[i]
+		if c.ArchivedAt != nil || (req.Type != nil && checkCategoryKind(c, sample.Type) != nil) {
+			continue
+		}
+		names[c.ID] = c.Name
+	}
+
+	scores, trainedOn, err := s.suggester.Suggest(ctx, userID, sample, func(id uuid.UUID) bool {
+		_, ok := names[id]
+		return ok
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.CategorySuggestionsResponse{
+		TrainedOn:   trainedOn,
+		Suggestions: make([]dto.CategorySuggestionResponse, 0, limit),
+	}
+	for _, score := range scores {
+		if len(result.Suggestions) == limit {
+			break
+		}
+		result.Suggestions = append(result.Suggestions, dto.CategorySuggestionResponse{
+			CategoryID:   score.CategoryID,
+			CategoryName: names[score.CategoryID],
+			Confidence:   math.Round(score.Probability*1000) / 1000,
+		})
+	}
+	return result, nil
+}
+
+func (s *transactionService) FindDuplicates(ctx context.Context, userID uuid.UUID, from, to *time.Time, windowDays int) (*dto.DuplicatesResponse, error) {
+	if windowDays < 0 || windowDays > maxDuplicateWindowDays {
+		return nil, fmt.Errorf("%w: windowDays must be between 0 and %d", constant.ErrInvalidInput, maxDuplicateWindowDays)
+	}
+	end := truncateToDate(time.Now())
+	if to != nil {
+		end = truncateToDate(*to)
+	}
+	start := end.AddDate(0, 0, -90)
+	if from != nil {
+		start = truncateToDate(*from)
+	}
+	if start.After(end) {
+		return nil, fmt.Errorf("%w: from must not be after to", constant.ErrInvalidInput)
+	}
+
+	transactions, err := s.transactionRepo.ListWithTagsByDateRange(ctx, userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.DuplicatesResponse{
+		From:       start.Format("2006-01-02"),
+		To:         end.Format("2006-01-02"),
+		WindowDays: windowDays,
+		Groups:     []dto.DuplicateGroupResponse{},
+	}
+	for _, group := range groupDuplicates(transactions, windowDays) {
+		first := &transactions[group[0]]
+		resp := dto.DuplicateGroupResponse{
+			Type:   string(first.Type),
+			Amount: first.Amount,
+		}
+		keep := first
+		for _, i := range group {
+			t := &transactions[i]
+			resp.Transactions = append(resp.Transactions, *s.toResponse(t))
+			if betterToKeep(t, keep) {
+				keep = t
+			}
+		}
+		resp.SuggestedKeepID = keep.ID
+		result.Groups = append(result.Groups, resp)
+	}
+	return result, nil
+}
+
+func (s *transactionService) MergeTransactions(ctx context.Context, userID, id uuid.UUID, req dto.MergeTransactionsRequest) (*dto.TransactionResponse, error) {
+	keep, err := s.getOwnedTransaction(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var duplicates []*model.Transaction
+	seen := map[uuid.UUID]bool{id: true}
+	for _, duplicateID := range req.DuplicateIDs {
+		if seen[duplicateID] {
+			return nil, fmt.Errorf("%w: duplicateIds must be distinct and must not contain the kept transaction", constant.ErrInvalidInput)
+		}
+		seen[duplicateID] = true
+		duplicate, err := s.getOwnedTransaction(ctx, userID, duplicateID)
+		if err != nil {
+			return nil, err
+		}
+		duplicates = append(duplicates, duplicate)
+	}
+
+	// The kept transaction takes over what it lacks from the first duplicate
+	// that has it
+	before := *keep
+	for _, d := range duplicates {
+		if keep.Description == nil && d.Description != nil {
+			keep.Description = d.Description
+		}
+		if keep.ExternalID == nil && d.ExternalID != nil {
+			keep.ExternalID = d.ExternalID
+		}
+	}
+
+	if err := s.transactionRepo.Merge(ctx, keep, req.DuplicateIDs); err != nil {
+		return nil, err
+	}
+	s.suggester.Forget(userID, &before)
+	s.suggester.Learn(userID, keep)
+	for _, d := range duplicates {
+		s.suggester.Forget(userID, d)
+	}
+
+	merged, err := s.transactionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponse(merged), nil
+}
+
+// checkCategoryKind rejects filing a transaction under a category meant for
+// the other type, e.g. an expense under salary
+func checkCategoryKind(category *model.Category, txType model.TransactionType) error {
+	if category.Kind != nil && *category.Kind != txType {
+		return fmt.Errorf("%w: category %q is for %s transactions, not %s", constant.ErrInvalidInput,
+			category.Name, strings.ToLower(string(*category.Kind)), strings.ToLower(string(txType)))
+	}
+	return nil
+}
+
+// checkNewCategory rejects filing a new transaction under an archived
+// category or one meant for the other type
+func checkNewCategory(category *model.Category, txType model.TransactionType) error {
+	if category.ArchivedAt != nil {
+		return fmt.Errorf("%w: category %q is archived", constant.ErrInvalidInput, category.Name)
+	}
+	return checkCategoryKind(category, txType)
+}
+
+// getOwnedTransaction loads a transaction and hides it if it belongs to
+// another user
+func (s *transactionService) getOwnedTransaction(ctx context.Context, userID, id uuid.UUID) (*model.Transaction, error) {
+	transaction, err := s.transactionRepo.GetByID(ctx, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, constant.ErrNotFound
+		}
+		return nil, err
+	}
+	if transaction.UserID != userID {
+		return nil, constant.ErrNotFound
+	}
+	return transaction, nil
+}
+
+// betterToKeep reports whether a carries more information than b: a bank ID
+// first, then more tags, then an earlier creation
+func betterToKeep(a, b *model.Transaction) bool {
+	if (a.ExternalID != nil) != (b.ExternalID != nil) {
+		return a.ExternalID != nil
+	}
+	if len(a.Tags) != len(b.Tags) {
+		return len(a.Tags) > len(b.Tags)
+	}
+	return a.CreatedAt.Before(b.CreatedAt)
 }
 
 func (s *transactionService) toResponse(t *model.Transaction) *dto.TransactionResponse {
@@ -150,11 +439,16 @@ func (s *transactionService) toResponse(t *model.Transaction) *dto.TransactionRe
 	}
 
 	var deletedAt *string
-	if t.DeletedAt != nil {
-		formatted := (*t.DeletedAt).Format(time.RFC3339)
+	if t.DeletedAt.Valid {
+		formatted := t.DeletedAt.Time.Format(time.RFC3339)
 		deletedAt = &formatted
 	}
 
+	var tags []dto.TagResponse
+	for i := range t.Tags {
+		tags = append(tags, *toTagResponse(&t.Tags[i]))
+	}
+
 	return &dto.TransactionResponse{
 		ID:              t.ID,
 		UserID:          t.UserID,
@@ -163,6 +457,8 @@ func (s *transactionService) toResponse(t *model.Transaction) *dto.TransactionRe
 		Amount:          t.Amount,
 		Type:            string(t.Type),
 		Description:     t.Description,
+		AccountID:       t.AccountID,
+		Tags:            tags,
 		TransactionDate: t.TransactionDate.Format("2006-01-02"),
 		CreatedAt:       t.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:       t.UpdatedAt.Format(time.RFC3339),
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tyha2404/nexo-app-api/internal/constant"
+	"github.com/tyha2404/nexo-app-api/internal/dto"
+	"github.com/tyha2404/nexo-app-api/internal/model"
+	"github.com/tyha2404/nexo-app-api/internal/repository"
+	"github.com/tyha2404/nexo-app-api/internal/storage"
+	"gorm.io/gorm"
+)
+
+type TrashService interface {
+	// List returns the user's deleted records, optionally of a single type,
+	// and the total number of them
+	List(ctx context.Context, userID uuid.UUID, itemType string, page, limit int) ([]dto.TrashItemResponse, int64, error)
+	// Restore takes a deleted record out of the trash
+	Restore(ctx context.Context, userID uuid.UUID, itemType string, id uuid.UUID, req dto.RestoreTrashRequest) (*dto.RestoredItemResponse, error)
+	// PurgeExpired permanently deletes records whose retention period has
+	// ended and returns how many were removed
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+type trashService struct {
+	trashRepo    repository.TrashRepo
+	categoryRepo repository.CategoryRepo
+	suggester    CategorySuggester
+	store        storage.BlobStore
+	retention    time.Duration
+}
+
+func NewTrashService(
+	trashRepo repository.TrashRepo,
+	categoryRepo repository.CategoryRepo,
+	suggester CategorySuggester,
+	store storage.BlobStore,
+	retention time.Duration,
+) TrashService {
+	return &trashService{
+		trashRepo:    trashRepo,
+		categoryRepo: categoryRepo,
+		suggester:    suggester,
+		store:        store,
+		retention:    retention,
+	}
+}
+
+func (s *trashService) List(ctx context.Context, userID uuid.UUID, itemType string, page, limit int) ([]dto.TrashItemResponse, int64, error) {
+	types := repository.TrashTypes
+	if itemType != "" {
+		if !slices.Contains(repository.TrashTypes, itemType) {
+			return nil, 0, fmt.Errorf("%w: type must be one of %s", constant.ErrInvalidInput, strings.Join(repository.TrashTypes, ", "))
+		}
+		types = []string{itemType}
+	}
+
+	items, total, err := s.trashRepo.List(ctx, userID, types, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	resp := make([]dto.TrashItemResponse, 0, len(items))
+	for _, item := range items {
+		r := dto.TrashItemResponse{
+			Type:      item.Type,
+			ID:        item.ID.String(),
+			Label:     item.Label,
+			Amount:    item.Amount,
+			DeletedAt: item.DeletedAt.UTC().Format(time.RFC3339),
+			PurgeAt:   item.DeletedAt.Add(s.retention).UTC().Format(time.RFC3339),
+		}
+		if item.CategoryID != nil {
+			categoryID := item.CategoryID.String()
+			r.CategoryID = &categoryID
+		}
+		resp = append(resp, r)
+	}
+	return resp, total, nil
+}
+
+func (s *trashService) Restore(ctx context.Context, userID uuid.UUID, itemType string, id uuid.UUID, req dto.RestoreTrashRequest) (*dto.RestoredItemResponse, error) {
+	restored := &dto.RestoredItemResponse{Type: itemType, ID: id.String()}
+
+	var record interface{}
+	var updates map[string]interface{}
+	switch itemType {
+	case repository.TrashTypeTransactions:
+		var transaction model.Transaction
+		if err := s.findDeleted(ctx, userID, id, &transaction, &transaction.UserID); err != nil {
+			return nil, err
+		}
+		if err := s.requireActiveCategory(ctx, transaction.CategoryID); err != nil {
+			return nil, err
+		}
+		record = &transaction
+
+	case repository.TrashTypeCosts:
+		var cost model.Cost
+		if err := s.findDeleted(ctx, userID, id, &cost, &cost.UserID); err != nil {
+			return nil, err
+		}
+		if err := s.requireActiveCategory(ctx, cost.CategoryID); err != nil {
+			return nil, err
+		}
+		record = &cost
+
+	case repository.TrashTypeBudgets:
+		var budget model.Budget
+		if err := s.findDeleted(ctx, userID, id, &budget, &budget.UserID); err != nil {
+			return nil, err
+		}
+		if err := s.requireActiveCategory(ctx, budget.CategoryID); err != nil {
+			return nil, err
+		}
+		record = &budget
+
+	case repository.TrashTypeCategories:
+		var category model.Category
+		if err := s.findDeleted(ctx, userID, id, &category, &category.UserID); err != nil {
+			return nil, err
+		}
+		name, err := s.resolveCategoryName(ctx, &category, req)
+		if err != nil {
+			return nil, err
+		}
+		updates = make(map[string]interface{})
+		if name != category.Name {
+			updates["name"] = name
+		}
+		// A category whose parent is gone, or has been moved below it since,
+		// comes back at the top level
+		if category.ParentID != nil {
+			active, err := s.categoryRepo.ListByUserID(ctx, userID)
+			if err != nil {
+				return nil, err
+			}
+			forest := newCategoryForest(append(active, category))
+			if forest.checkParent(category.ID, *category.ParentID) != nil {
+				updates["parent_id"] = nil
+			}
+		}
+		restored.Name = &name
+		record = &category
+
+	default:
+		return nil, fmt.Errorf("%w: type must be one of %s", constant.ErrInvalidInput, strings.Join(repository.TrashTypes, ", "))
+	}
+
+	if err := s.trashRepo.Restore(ctx, record, updates); err != nil {
+		return nil, err
+	}
+	if transaction, ok := record.(*model.Transaction); ok {
+		s.suggester.Learn(userID, transaction)
+	}
+	return restored, nil
+}
+
+func (s *trashService) PurgeExpired(ctx context.Context) (int64, error) {
+	counts, keys, err := s.trashRepo.Purge(ctx, time.Now().Add(-s.retention))
+	if err != nil {
+		return 0, err
+	}
+
+	var purged int64
+	for _, t := range repository.TrashTypes {
+		purged += counts[t]
+	}
+
+	// The rows are gone, so a blob that cannot be deleted is only reported
+	var errs []error
+	for _, key := range keys {
+		if err := s.store.Delete(ctx, key); err != nil {
+			errs = append(errs, fmt.Errorf("delete blob %s: %w", key, err))
+		}
+	}
+	return purged, errors.Join(errs...)
+}
+
+// findDeleted loads a trashed record and hides records of other users
+func (s *trashService) findDeleted(ctx context.Context, userID, id uuid.UUID, dest interface{}, ownerID *uuid.UUID) error {
+	if err := s.trashRepo.FindDeleted(ctx, id, dest); err != nil {
+		return err
+	}
+	if *ownerID != userID {
+		return constant.ErrNotFound
+	}
+	return nil
+}
+
+// requireActiveCategory rejects restoring a record into a deleted category
+func (s *trashService) requireActiveCategory(ctx context.Context, categoryID uuid.UUID) error {
+	if _, err := s.categoryRepo.GetByID(ctx, categoryID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: category %s is in the trash, restore it first", constant.ErrInvalidInput, categoryID)
+		}
+		return err
+	}
+	return nil
+}
+
+// resolveCategoryName picks the name a category is restored under. Names
+// are unique among a user's active categories, so a name reused since the
+// deletion either fails the restore or is numbered, depending on the request.
+func (s *trashService) resolveCategoryName(ctx context.Context, category *model.Category, req dto.RestoreTrashRequest) (string, error) {
+	existing, err := s.categoryRepo.ListByUserID(ctx, category.UserID)
+	if err != nil {
+		return "", err
+	}
+	taken := make(map[string]bool, len(existing))
+	for _, c := range existing {
+		taken[strings.ToLower(c.Name)] = true
+	}
+
+	name := category.Name
+	if req.Name != nil {
+		name = strings.TrimSpace(*req.Name)
+		if name == "" {
+			return "", fmt.Errorf("%w: name must not be empty", constant.ErrInvalidInput)
+		}
+	}
+	if !taken[strings.ToLower(name)] {
+		return name, nil
+	}
+	if req.Name != nil || req.OnConflict != "rename" {
+		return "", fmt.Errorf("%w: a category named %q already exists, restore it under another name or with onConflict=rename", constant.ErrCategoryExists, name)
+	}
+
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s (%d)", name, n)
+		if len(candidate) > 50 {
+			return "", fmt.Errorf("%w: a category named %q already exists, restore it under another name", constant.ErrCategoryExists, name)
+		}
+		if !taken[strings.ToLower(candidate)] {
+			return candidate, nil
+		}
+	}
+}
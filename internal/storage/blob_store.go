@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/tyha2404/nexo-app-api/internal/config"
+)
+
+var (
+	// ErrObjectNotFound is returned when a key does not exist in the store
+	ErrObjectNotFound = errors.New("object not found")
+	// ErrInvalidSignature is returned when a signed URL is forged or expired
+	ErrInvalidSignature = errors.New("invalid or expired signature")
+)
+
+// BlobStore abstracts where attachment bytes are kept
+type BlobStore interface {
+	// Put stores size bytes read from r under key
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+
+	// Get opens the object stored under key
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete removes the object stored under key. Missing objects are not an error.
+	Delete(ctx context.Context, key string) error
+
+	// SignedURL returns a URL that allows downloading key until ttl elapses
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// URLVerifier is implemented by stores whose signed URLs are served by this
+// application rather than by the storage backend itself
+type URLVerifier interface {
+	Verify(key string, expires int64, signature string) error
+}
+
+// New builds the BlobStore selected by cfg.StorageDriver
+func New(cfg *config.Config) (BlobStore, error) {
+	switch cfg.StorageDriver {
+	case "local":
+		return NewLocalStore(cfg.StorageLocalPath, cfg.PublicBaseURL+"/api/v1/files", cfg.StorageSigningSecret)
+	case "s3":
+		return NewS3Store(S3Config{
+			Endpoint:     cfg.S3Endpoint,
+			Region:       cfg.S3Region,
+			Bucket:       cfg.S3Bucket,
+			AccessKey:    cfg.S3AccessKey,
+			SecretKey:    cfg.S3SecretKey,
+			UsePathStyle: cfg.S3UsePathStyle,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.StorageDriver)
+	}
+}
@@ -0,0 +1,31 @@
+// Package worker runs background maintenance jobs inside the API process.
+package worker
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// RunPeriodically calls job once right away and then every interval until
+// ctx is cancelled. A failing run is logged and retried on the next tick.
+func RunPeriodically(ctx context.Context, name string, interval time.Duration, log *zap.Logger, job func(ctx context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		start := time.Now()
+		if err := job(ctx); err != nil && ctx.Err() == nil {
+			log.Error("background job failed", zap.String("job", name), zap.Error(err))
+		} else {
+			log.Debug("background job finished", zap.String("job", name), zap.Duration("took", time.Since(start)))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}